@@ -1,22 +1,27 @@
 package config
 
 import (
+	"strings"
+
 	"github.com/spf13/viper"
 )
 
 type EnvsSchema struct {
-	HOST                  string
-	PORT                  int
-	GRPC_PORT             int
-	LOG_LEVEL             string
-	JWT_SECRET_KEY        string
-	JWT_EXP_HOURS         int
-	JWT_REFRESH_EXP_HOURS int
-	POSTGRESQL_HOST       string
-	POSTGRESQL_PORT       int
-	POSTGRESQL_USER       string
-	POSTGRESQL_PASSWORD   string
-	POSTGRESQL_DB         string
+	HOST                   string
+	PORT                   int
+	GRPC_PORT              int
+	LOG_LEVEL              string
+	JWT_SIGNING_ALG        string
+	JWT_ISSUER             string
+	JWT_AUDIENCE           string
+	JWT_KEY_ROTATION_HOURS int
+	JWT_EXP_HOURS          int
+	JWT_REFRESH_EXP_HOURS  int
+	POSTGRESQL_HOST        string
+	POSTGRESQL_PORT        int
+	POSTGRESQL_USER        string
+	POSTGRESQL_PASSWORD    string
+	POSTGRESQL_DB          string
 
 	INITIAL_USER_USERNAME  string
 	INITIAL_USER_PASSWORD  string
@@ -24,24 +29,72 @@ type EnvsSchema struct {
 	INITIAL_ADMIN_PASSWORD string
 
 	AUTHOR_GRPC_SERVICE string
+
+	MAILER        string // smtp or file
+	MAIL_FROM     string
+	MAIL_FILE_DIR string
+	SMTP_HOST     string
+	SMTP_PORT     int
+	SMTP_USERNAME string
+	SMTP_PASSWORD string
+
+	PASSWORD_RESET_URL         string // link sent by mail, the token is appended as ?token=
+	PASSWORD_RESET_EXP_MINUTES int
+
+	EMAIL_VERIFICATION_URL       string // link sent by mail, the token is appended as ?token=
+	EMAIL_VERIFICATION_EXP_HOURS int
+
+	TOTP_ISSUER                 string // account issuer shown in authenticator apps
+	TOTP_REQUIRED_FOR_ADMIN     bool
+	LOGIN_CHALLENGE_EXP_MINUTES int
+
+	LOGIN_ATTEMPT_STORE          string // postgres or memory
+	LOGIN_MAX_ACCOUNT_FAILURES   int    // failures before the account is locked
+	LOGIN_MAX_IP_FAILURES        int    // failures before the client ip is locked
+	LOGIN_BACKOFF_BASE_SECONDS   int    // wait after the first failure, doubled on each next one
+	LOGIN_LOCKOUT_MINUTES        int
+	LOGIN_FAILURE_WINDOW_MINUTES int // failures older than this are forgotten
+
+	TRUSTED_PROXIES []string // reverse proxies whose X-Forwarded-For gives the client ip, none by default
+
+	API_KEY_EXP_DAYS     int // expiry of keys created without expires_in_days
+	API_KEY_MAX_EXP_DAYS int
+
+	SERVICE_NAME              string // subject of the service tokens auth_service issues to itself
+	SERVICE_CLIENT_SECRETS    string // name=secret pairs separated by commas, one per calling service
+	SERVICE_TOKEN_EXP_MINUTES int
+
+	RECONCILE_GRACE_MINUTES int // sagas and users younger than this are left to finish on their own
+
+	EVENT_BROKER                 string // postgres, memory or none
+	EVENT_BROKER_POSTGRESQL_DB   string // shared by every service, notifications do not cross databases
+	EVENT_RELAY_INTERVAL_SECONDS int
+	EVENT_RELAY_BATCH_SIZE       int
+
+	GRPC_TLS_CERT_FILE string // with the key, served and presented to other services
+	GRPC_TLS_KEY_FILE  string
+	GRPC_TLS_CA_FILE   string // verifies the certificates of the other services, enables mtls
 }
 
 var Envs *EnvsSchema
 
 func envInitiator() {
 	Envs = &EnvsSchema{
-		HOST:                  viper.GetString("HOST"),
-		PORT:                  viper.GetInt("PORT"),
-		GRPC_PORT:             viper.GetInt("GRPC_PORT"),
-		LOG_LEVEL:             viper.GetString("LOG_LEVEL"),
-		JWT_SECRET_KEY:        viper.GetString("JWT_SECRET_KEY"),
-		JWT_EXP_HOURS:         viper.GetInt("JWT_EXP_HOURS"),
-		JWT_REFRESH_EXP_HOURS: viper.GetInt("JWT_REFRESH_EXP_HOURS"),
-		POSTGRESQL_HOST:       viper.GetString("POSTGRESQL_HOST"),
-		POSTGRESQL_PORT:       viper.GetInt("POSTGRESQL_PORT"),
-		POSTGRESQL_USER:       viper.GetString("POSTGRESQL_USER"),
-		POSTGRESQL_PASSWORD:   viper.GetString("POSTGRESQL_PASSWORD"),
-		POSTGRESQL_DB:         viper.GetString("POSTGRESQL_DB"),
+		HOST:                   viper.GetString("HOST"),
+		PORT:                   viper.GetInt("PORT"),
+		GRPC_PORT:              viper.GetInt("GRPC_PORT"),
+		LOG_LEVEL:              viper.GetString("LOG_LEVEL"),
+		JWT_SIGNING_ALG:        viper.GetString("JWT_SIGNING_ALG"),
+		JWT_ISSUER:             viper.GetString("JWT_ISSUER"),
+		JWT_AUDIENCE:           viper.GetString("JWT_AUDIENCE"),
+		JWT_KEY_ROTATION_HOURS: viper.GetInt("JWT_KEY_ROTATION_HOURS"),
+		JWT_EXP_HOURS:          viper.GetInt("JWT_EXP_HOURS"),
+		JWT_REFRESH_EXP_HOURS:  viper.GetInt("JWT_REFRESH_EXP_HOURS"),
+		POSTGRESQL_HOST:        viper.GetString("POSTGRESQL_HOST"),
+		POSTGRESQL_PORT:        viper.GetInt("POSTGRESQL_PORT"),
+		POSTGRESQL_USER:        viper.GetString("POSTGRESQL_USER"),
+		POSTGRESQL_PASSWORD:    viper.GetString("POSTGRESQL_PASSWORD"),
+		POSTGRESQL_DB:          viper.GetString("POSTGRESQL_DB"),
 
 		INITIAL_USER_USERNAME:  viper.GetString("INITIAL_USER_USERNAME"),
 		INITIAL_USER_PASSWORD:  viper.GetString("INITIAL_USER_PASSWORD"),
@@ -49,6 +102,51 @@ func envInitiator() {
 		INITIAL_ADMIN_PASSWORD: viper.GetString("INITIAL_ADMIN_PASSWORD"),
 
 		AUTHOR_GRPC_SERVICE: viper.GetString("AUTHOR_GRPC_SERVICE"),
+
+		MAILER:        viper.GetString("MAILER"),
+		MAIL_FROM:     viper.GetString("MAIL_FROM"),
+		MAIL_FILE_DIR: viper.GetString("MAIL_FILE_DIR"),
+		SMTP_HOST:     viper.GetString("SMTP_HOST"),
+		SMTP_PORT:     viper.GetInt("SMTP_PORT"),
+		SMTP_USERNAME: viper.GetString("SMTP_USERNAME"),
+		SMTP_PASSWORD: viper.GetString("SMTP_PASSWORD"),
+
+		PASSWORD_RESET_URL:         viper.GetString("PASSWORD_RESET_URL"),
+		PASSWORD_RESET_EXP_MINUTES: viper.GetInt("PASSWORD_RESET_EXP_MINUTES"),
+
+		EMAIL_VERIFICATION_URL:       viper.GetString("EMAIL_VERIFICATION_URL"),
+		EMAIL_VERIFICATION_EXP_HOURS: viper.GetInt("EMAIL_VERIFICATION_EXP_HOURS"),
+
+		TOTP_ISSUER:                 viper.GetString("TOTP_ISSUER"),
+		TOTP_REQUIRED_FOR_ADMIN:     viper.GetBool("TOTP_REQUIRED_FOR_ADMIN"),
+		LOGIN_CHALLENGE_EXP_MINUTES: viper.GetInt("LOGIN_CHALLENGE_EXP_MINUTES"),
+
+		LOGIN_ATTEMPT_STORE:          viper.GetString("LOGIN_ATTEMPT_STORE"),
+		LOGIN_MAX_ACCOUNT_FAILURES:   viper.GetInt("LOGIN_MAX_ACCOUNT_FAILURES"),
+		LOGIN_MAX_IP_FAILURES:        viper.GetInt("LOGIN_MAX_IP_FAILURES"),
+		LOGIN_BACKOFF_BASE_SECONDS:   viper.GetInt("LOGIN_BACKOFF_BASE_SECONDS"),
+		LOGIN_LOCKOUT_MINUTES:        viper.GetInt("LOGIN_LOCKOUT_MINUTES"),
+		LOGIN_FAILURE_WINDOW_MINUTES: viper.GetInt("LOGIN_FAILURE_WINDOW_MINUTES"),
+
+		TRUSTED_PROXIES: splitList(viper.GetString("TRUSTED_PROXIES")),
+
+		API_KEY_EXP_DAYS:     viper.GetInt("API_KEY_EXP_DAYS"),
+		API_KEY_MAX_EXP_DAYS: viper.GetInt("API_KEY_MAX_EXP_DAYS"),
+
+		SERVICE_NAME:              viper.GetString("SERVICE_NAME"),
+		SERVICE_CLIENT_SECRETS:    viper.GetString("SERVICE_CLIENT_SECRETS"),
+		SERVICE_TOKEN_EXP_MINUTES: viper.GetInt("SERVICE_TOKEN_EXP_MINUTES"),
+
+		RECONCILE_GRACE_MINUTES: viper.GetInt("RECONCILE_GRACE_MINUTES"),
+
+		EVENT_BROKER:                 viper.GetString("EVENT_BROKER"),
+		EVENT_BROKER_POSTGRESQL_DB:   viper.GetString("EVENT_BROKER_POSTGRESQL_DB"),
+		EVENT_RELAY_INTERVAL_SECONDS: viper.GetInt("EVENT_RELAY_INTERVAL_SECONDS"),
+		EVENT_RELAY_BATCH_SIZE:       viper.GetInt("EVENT_RELAY_BATCH_SIZE"),
+
+		GRPC_TLS_CERT_FILE: viper.GetString("GRPC_TLS_CERT_FILE"),
+		GRPC_TLS_KEY_FILE:  viper.GetString("GRPC_TLS_KEY_FILE"),
+		GRPC_TLS_CA_FILE:   viper.GetString("GRPC_TLS_CA_FILE"),
 	}
 }
 
@@ -59,5 +157,45 @@ func InitEnv(filepath string) {
 		logger.Warningf("error loading environment variables from %s: %w", filepath, err)
 	}
 	viper.AutomaticEnv()
+	viper.SetDefault("JWT_SIGNING_ALG", "RS256")
+	viper.SetDefault("JWT_ISSUER", "auth_service")
+	viper.SetDefault("JWT_AUDIENCE", "library_app")
+	viper.SetDefault("JWT_KEY_ROTATION_HOURS", 720)
+	viper.SetDefault("MAILER", "file")
+	viper.SetDefault("MAIL_FROM", "no-reply@library.local")
+	viper.SetDefault("MAIL_FILE_DIR", "./mails")
+	viper.SetDefault("SMTP_PORT", 587)
+	viper.SetDefault("PASSWORD_RESET_EXP_MINUTES", 30)
+	viper.SetDefault("EMAIL_VERIFICATION_EXP_HOURS", 48)
+	viper.SetDefault("TOTP_ISSUER", "Library App")
+	viper.SetDefault("TOTP_REQUIRED_FOR_ADMIN", false)
+	viper.SetDefault("LOGIN_CHALLENGE_EXP_MINUTES", 5)
+	viper.SetDefault("LOGIN_ATTEMPT_STORE", "postgres")
+	viper.SetDefault("LOGIN_MAX_ACCOUNT_FAILURES", 5)
+	viper.SetDefault("LOGIN_MAX_IP_FAILURES", 20)
+	viper.SetDefault("LOGIN_BACKOFF_BASE_SECONDS", 1)
+	viper.SetDefault("LOGIN_LOCKOUT_MINUTES", 15)
+	viper.SetDefault("LOGIN_FAILURE_WINDOW_MINUTES", 60)
+	viper.SetDefault("API_KEY_EXP_DAYS", 365)
+	viper.SetDefault("API_KEY_MAX_EXP_DAYS", 730)
+	viper.SetDefault("SERVICE_NAME", "auth_service")
+	viper.SetDefault("SERVICE_TOKEN_EXP_MINUTES", 15)
+	viper.SetDefault("RECONCILE_GRACE_MINUTES", 10)
+	viper.SetDefault("EVENT_BROKER", "postgres")
+	viper.SetDefault("EVENT_BROKER_POSTGRESQL_DB", "postgres")
+	viper.SetDefault("EVENT_RELAY_INTERVAL_SECONDS", 2)
+	viper.SetDefault("EVENT_RELAY_BATCH_SIZE", 100)
 	envInitiator()
 }
+
+// splitList splits a comma separated env, nil when it is empty.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
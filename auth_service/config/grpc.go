@@ -2,13 +2,17 @@ package config
 
 import (
 	author_grpc "auth_service/interface/grpc/genproto/author"
+	grpc_interceptor "auth_service/interface/grpc/interceptor"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
-func NewAuthorGrpcServiceClient() author_grpc.AuthorServiceClient {
-	conn, err := grpc.NewClient(Envs.AUTHOR_GRPC_SERVICE, grpc.WithTransportCredentials(insecure.NewCredentials()))
+func NewAuthorGrpcServiceClient(tokenSource *grpc_interceptor.ServiceTokenSource) author_grpc.AuthorServiceClient {
+	conn, err := grpc.NewClient(
+		Envs.AUTHOR_GRPC_SERVICE,
+		grpc.WithTransportCredentials(grpcClientCredentials()),
+		grpc.WithUnaryInterceptor(grpc_interceptor.IdentityUnaryClientInterceptor(tokenSource)),
+	)
 	if err != nil {
 		logger.Fatalf("Failed to connect to author grpc service: %v", err)
 	}
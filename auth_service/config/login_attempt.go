@@ -0,0 +1,20 @@
+package config
+
+import (
+	"auth_service/repository"
+
+	"gorm.io/gorm"
+)
+
+// NewLoginAttemptRepo picks where failed logins are counted from
+// LOGIN_ATTEMPT_STORE, memory only suits a single instance.
+func NewLoginAttemptRepo(gormDB *gorm.DB) repository.ILoginAttemptRepo {
+	switch Envs.LOGIN_ATTEMPT_STORE {
+	case "postgres":
+		return repository.NewLoginAttemptRepo(gormDB)
+	case "memory":
+		return repository.NewInMemoryLoginAttemptRepo()
+	}
+	logger.Fatalf("invalid LOGIN_ATTEMPT_STORE: %s", Envs.LOGIN_ATTEMPT_STORE)
+	return nil
+}
@@ -0,0 +1,16 @@
+package config
+
+import "auth_service/utils/mailer"
+
+// NewMailer picks the mail delivery from MAILER, file mailer writes mails to
+// MAIL_FILE_DIR so local setups work without a mail server.
+func NewMailer() mailer.Mailer {
+	switch Envs.MAILER {
+	case "smtp":
+		return mailer.NewSMTPMailer(Envs.SMTP_HOST, Envs.SMTP_PORT, Envs.SMTP_USERNAME, Envs.SMTP_PASSWORD, Envs.MAIL_FROM)
+	case "file":
+		return mailer.NewFileMailer(Envs.MAIL_FILE_DIR, Envs.MAIL_FROM)
+	}
+	logger.Fatalf("invalid MAILER: %s", Envs.MAILER)
+	return nil
+}
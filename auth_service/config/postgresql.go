@@ -7,46 +7,55 @@ import (
 	"gorm.io/gorm"
 )
 
-func NewPostgresqlDB() *gorm.DB {
-	// create instance of default database
-	dsn := fmt.Sprintf(
+func postgresqlDSN(dbName string) string {
+	return fmt.Sprintf(
 		"host=%s user=%s password=%s dbname=%s port=%v sslmode=disable TimeZone=Asia/Jakarta",
 		Envs.POSTGRESQL_HOST,
 		Envs.POSTGRESQL_USER,
 		Envs.POSTGRESQL_PASSWORD,
-		"postgres",
+		dbName,
 		Envs.POSTGRESQL_PORT,
 	)
+}
 
-	logger.Debugf("connecting to default database: postgres")
-	var err error
-	DB, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+func NewPostgresqlDB() *gorm.DB {
+	logger.Debugf("connecting to database: %s", Envs.POSTGRESQL_DB)
+	DB, err := gorm.Open(postgres.Open(postgresqlDSN(Envs.POSTGRESQL_DB)), &gorm.Config{})
 	if err != nil {
 		logger.Fatalf("failed to connect to the database: %v", err)
 	}
 
-	// create database from environment variable to ensure it exists
-	logger.Debugf("ensuring database: %s", Envs.POSTGRESQL_DB)
-	err = DB.Exec(fmt.Sprintf("CREATE DATABASE %s", Envs.POSTGRESQL_DB)).Error
+	return DB
+}
+
+// EnsurePostgresqlDB creates the database from environment variable if it does
+// not exist yet. only called before running migrations up.
+func EnsurePostgresqlDB() error {
+	logger.Debugf("connecting to default database: postgres")
+	DB, err := gorm.Open(postgres.Open(postgresqlDSN("postgres")), &gorm.Config{})
 	if err != nil {
-		logger.Warningf("Failed to create database: %v", err)
+		return fmt.Errorf("failed to connect to the default database: %v", err)
 	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
 
-	// recreate database using database name from environment variable
-	dsn = fmt.Sprintf(
-		"host=%s user=%s password=%s dbname=%s port=%v sslmode=disable TimeZone=Asia/Jakarta",
-		Envs.POSTGRESQL_HOST,
-		Envs.POSTGRESQL_USER,
-		Envs.POSTGRESQL_PASSWORD,
-		Envs.POSTGRESQL_DB,
-		Envs.POSTGRESQL_PORT,
-	)
+	var count int64
+	err = DB.Raw("SELECT COUNT(*) FROM pg_database WHERE datname = ?", Envs.POSTGRESQL_DB).Scan(&count).Error
+	if err != nil {
+		return fmt.Errorf("failed to check database: %v", err)
+	}
+	if count > 0 {
+		return nil
+	}
 
-	logger.Debugf("connecting to database: %s", dsn)
-	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	logger.Infof("creating database: %s", Envs.POSTGRESQL_DB)
+	err = DB.Exec(fmt.Sprintf("CREATE DATABASE %q", Envs.POSTGRESQL_DB)).Error
 	if err != nil {
-		logger.Fatalf("failed to connect to the database: %v", err)
+		return fmt.Errorf("failed to create database: %v", err)
 	}
 
-	return DB
+	return nil
 }
@@ -0,0 +1,63 @@
+package dto
+
+import "time"
+
+type CreateServiceAccountReq struct {
+	Username string `json:"username" binding:"required"`
+	Role     string `json:"role" binding:"required"`
+}
+
+type CreateServiceAccountRespData struct {
+	UUID      string    `json:"uuid"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CreateAPIKeyReq struct {
+	Name          string   `json:"name" binding:"required"`
+	Scopes        []string `json:"scopes"`          // permissions of the key, within those of the role
+	ExpiresInDays *int     `json:"expires_in_days"` // defaults to API_KEY_EXP_DAYS
+}
+
+type CreateAPIKeyRespData struct {
+	UUID      string    `json:"uuid"`
+	Name      string    `json:"name"`
+	Key       string    `json:"key"` // only returned once, send it in the X-API-Key header
+	Prefix    string    `json:"prefix"`
+	Scopes    []string  `json:"scopes"`
+	ExpiredAt time.Time `json:"expired_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type GetAPIKeyListRespDataItem struct {
+	UUID       string     `json:"uuid"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	Active     bool       `json:"active"`
+	ExpiredAt  time.Time  `json:"expired_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type RevokeAPIKeyRespData struct {
+	UUID      string    `json:"uuid"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+type ValidateAPIKeyReq struct {
+	APIKey string `json:"api_key" validate:"required"`
+}
+
+type ValidateAPIKeyRespData struct {
+	UUID          string   `json:"uuid"`
+	Username      string   `json:"username"`
+	Email         string   `json:"email"`
+	Role          string   `json:"role"`
+	EmailVerified bool     `json:"email_verified"`
+	Permissions   []string `json:"permissions"` // scopes of the key still granted to the role
+	APIKeyUUID    string   `json:"api_key_uuid"`
+}
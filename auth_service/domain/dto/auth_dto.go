@@ -1,16 +1,44 @@
 package dto
 
+import "time"
+
 type CurrentUser struct {
 	UUID     string `json:"uuid"`
 	Username string `json:"username"`
 	Role     string `json:"role"`
 	Email    string `json:"email"`
+
+	Permissions []string `json:"permissions"`
+
+	// access token claims, used to revoke the token on logout
+	TokenID   string    `json:"-"`
+	SessionID string    `json:"-"`
+	IssuedAt  time.Time `json:"-"`
+	ExpiredAt time.Time `json:"-"`
+
+	// set instead of the token claims when authenticated by an api key
+	APIKeyUUID string `json:"-"`
+
+	AccessToken string `json:"-"` // bearer token of the request, forwarded on grpc calls
+}
+
+func (currentUser CurrentUser) HasPermission(permission string) bool {
+	for _, p := range currentUser.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
 }
 
 type RegisterUserReq struct {
 	Email    string `json:"email" validate:"required,email"`
 	Username string `json:"username" validate:"required"`
 	Password string `json:"password" validate:"required"`
+
+	// filled by the handler from the request
+	Device string `json:"-"`
+	IP     string `json:"-"`
 }
 
 type RegisterUserRespData struct {
@@ -21,11 +49,40 @@ type RegisterUserRespData struct {
 type LoginReq struct {
 	UsernameOrEmail string `json:"username_or_email" validate:"required"`
 	Password        string `json:"password" validate:"required"`
+
+	// filled by the handler from the request
+	Device string `json:"-"`
+	IP     string `json:"-"`
 }
 
 type LoginRespData struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
+
+	// set instead of the tokens when the user has to pass 2fa, exchange the
+	// challenge token through /auth/login/2fa
+	TwoFactorRequired           bool   `json:"two_factor_required"`
+	TwoFactorEnrollmentRequired bool   `json:"two_factor_enrollment_required"` // enroll through /auth/login/2fa/enroll first
+	ChallengeToken              string `json:"challenge_token,omitempty"`
+}
+
+type LoginTwoFactorReq struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required"` // totp or recovery code
+
+	// filled by the handler from the request
+	Device string `json:"-"`
+	IP     string `json:"-"`
+}
+
+type LoginTwoFactorRespData struct {
+	AccessToken   string   `json:"access_token"`
+	RefreshToken  string   `json:"refresh_token"`
+	RecoveryCodes []string `json:"recovery_codes,omitempty"` // only when 2fa got enrolled by this login
+}
+
+type LoginTwoFactorEnrollReq struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
 }
 
 type CheckTokenReq struct {
@@ -33,17 +90,159 @@ type CheckTokenReq struct {
 }
 
 type CheckTokenRespData struct {
-	UUID     string `json:"uuid"`
-	Username string `json:"username"`
-	Role     string `json:"role"`
-	Email    string `json:"email"`
+	UUID      string    `json:"uuid"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	Email     string    `json:"email"`
+	TokenID   string    `json:"token_id"`
+	SessionID string    `json:"session_id"`
+	IssuedAt  time.Time `json:"issued_at"`
+	ExpiredAt time.Time `json:"expired_at"`
+
+	EmailVerified bool     `json:"email_verified"`
+	Permissions   []string `json:"permissions"` // of the current role of the user
 }
 
 type RefreshTokenReq struct {
 	RefreshToken string `json:"refresh_token" validate:"required"`
+
+	// filled by the handler from the request
+	Device string `json:"-"`
+	IP     string `json:"-"`
 }
 
 type RefreshTokenRespData struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
 }
+
+type LogoutReq struct {
+	RefreshToken string `json:"refresh_token"` // optional, invalidated together with the access token
+}
+
+type LogoutRespData struct {
+	UUID string `json:"uuid"`
+}
+
+type LogoutAllRespData struct {
+	UUID string `json:"uuid"`
+}
+
+type GetSessionListRespDataItem struct {
+	UUID       string    `json:"uuid"`
+	Device     string    `json:"device"`
+	IP         string    `json:"ip"`
+	Current    bool      `json:"current"` // session of the access token in use
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiredAt  time.Time `json:"expired_at"`
+}
+
+type RevokeSessionRespData struct {
+	UUID string `json:"uuid"`
+}
+
+// JWK is a public signing key as in RFC 7517.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type GetJWKSRespData struct {
+	Keys []JWK `json:"keys"`
+}
+
+type RotateSigningKeyRespData struct {
+	KID       string `json:"kid"`
+	Algorithm string `json:"algorithm"`
+}
+
+type ForgotPasswordReq struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ForgotPasswordRespData struct {
+	Email string `json:"email"` // same response whether the email is registered or not
+}
+
+type ResetPasswordReq struct {
+	Token    string `json:"token" validate:"required"` // reset token from the mail
+	Password string `json:"password" validate:"required"`
+}
+
+type ResetPasswordRespData struct {
+	UUID string `json:"uuid"`
+}
+
+type VerifyEmailReq struct {
+	Token string `json:"token" validate:"required"` // verification token from the mail
+}
+
+type VerifyEmailRespData struct {
+	UUID            string    `json:"uuid"`
+	Email           string    `json:"email"`
+	EmailVerifiedAt time.Time `json:"email_verified_at"`
+}
+
+type ResendVerificationEmailRespData struct {
+	Email string `json:"email"`
+}
+
+type EnrollTwoFactorRespData struct {
+	Secret     string `json:"secret"`      // base32 totp secret
+	OTPAuthURI string `json:"otpauth_uri"` // for authenticator apps, usually shown as qr code
+}
+
+type ConfirmTwoFactorReq struct {
+	Code string `json:"code" validate:"required"` // totp code of the enrolled secret
+}
+
+type ConfirmTwoFactorRespData struct {
+	RecoveryCodes []string `json:"recovery_codes"` // single use, only shown once
+}
+
+type DisableTwoFactorReq struct {
+	Code string `json:"code" validate:"required"` // totp or recovery code
+}
+
+type DisableTwoFactorRespData struct {
+	UUID string `json:"uuid"`
+}
+
+type RegenerateRecoveryCodesReq struct {
+	Code string `json:"code" validate:"required"` // totp code
+}
+
+// LoginBlockedRespData is the data of the 429 returned while failed logins
+// block the account or the client ip.
+type LoginBlockedRespData struct {
+	BlockedUntil      time.Time `json:"blocked_until"`
+	RetryAfterSeconds int       `json:"retry_after_seconds"`
+}
+
+type UnlockLoginReq struct {
+	UserUUID string `json:"user_uuid"` // account to unlock
+	IP       string `json:"ip"`        // client ip to unlock
+}
+
+type UnlockLoginRespData struct {
+	UserUUID string `json:"user_uuid,omitempty"`
+	IP       string `json:"ip,omitempty"`
+}
+
+type ReconcileRespData struct {
+	Items []ReconcileRespDataItem `json:"items"`
+}
+
+type ReconcileRespDataItem struct {
+	Kind   string `json:"kind"` // saga or user
+	UUID   string `json:"uuid"`
+	Action string `json:"action"` // completed, compensated, purged or failed
+	Detail string `json:"detail"`
+}
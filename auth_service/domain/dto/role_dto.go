@@ -0,0 +1,18 @@
+package dto
+
+type GetRoleListRespDataItem struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type PutRoleReq struct {
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions" binding:"required"` // replaces the current permissions
+}
+
+type PutRoleRespData struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+import "time"
+
+type IssueServiceTokenReq struct {
+	ServiceName   string `json:"service_name" validate:"required"`
+	ServiceSecret string `json:"service_secret" validate:"required"`
+}
+
+type IssueServiceTokenRespData struct {
+	Token     string    `json:"token"`
+	ExpiredAt time.Time `json:"expired_at"`
+}
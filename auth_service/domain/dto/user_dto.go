@@ -9,13 +9,17 @@ type GetUserByUUIDResp struct {
 	Username  string    `json:"username"`
 	Email     string    `json:"email"`
 	Role      string    `json:"role"`
+
+	Permissions []string `json:"permissions"` // of the current role of the user
 }
 
 type CreateUserReq struct {
 	Username string `json:"username" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
-	Role     string `json:"role" binding:"required,oneof=admin user"`
+	Role     string `json:"role" binding:"required"`
+
+	IdempotencyKey string `json:"-"` // set on grpc calls, a retry returns the same user
 }
 
 type CreateUserRespData struct {
@@ -31,7 +35,7 @@ type UpdateUserReq struct {
 	Username *string `json:"username"`
 	Email    *string `json:"email"`
 	Password *string `json:"password"`
-	Role     *string `json:"role" binding:"oneof=admin user"`
+	Role     *string `json:"role"`
 }
 
 type UpdateUserRespData struct {
@@ -51,3 +55,55 @@ type DeleteUserRespData struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+type GetUserListReq struct {
+	Query     string `form:"query"` // matches username or email
+	Role      string `form:"role"`
+	Page      int    `form:"page" default:"1"`
+	Limit     int    `form:"limit" default:"10"`
+	SortOrder string `form:"sort_order" default:"desc" binding:"omitempty,oneof=asc desc"`
+	SortBy    string `form:"sort_by" default:"created_at" binding:"omitempty,oneof=created_at updated_at username email"`
+}
+
+type GetUserListRespDataItem struct {
+	UUID             string    `json:"uuid"`
+	Username         string    `json:"username"`
+	Email            string    `json:"email"`
+	Role             string    `json:"role"`
+	EmailVerified    bool      `json:"email_verified"`
+	TwoFactorEnabled bool      `json:"two_factor_enabled"`
+	IsServiceAccount bool      `json:"is_service_account"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+type GetUserListRespData struct {
+	BasePaginatedData
+	Data []GetUserListRespDataItem `json:"data"`
+}
+
+type UserRepo_GetListParams struct {
+	Query     string
+	Role      string
+	Page      int
+	Limit     int
+	SortOrder string
+	SortBy    string
+}
+
+type GetMeRespData struct {
+	UUID             string    `json:"uuid"`
+	Username         string    `json:"username"`
+	Email            string    `json:"email"`
+	Role             string    `json:"role"`
+	EmailVerified    bool      `json:"email_verified"`
+	TwoFactorEnabled bool      `json:"two_factor_enabled"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+type UpdateMeReq struct {
+	Email           *string `json:"email"`
+	Password        *string `json:"password"` // new password, signs out every session
+	CurrentPassword string  `json:"current_password" binding:"required"`
+}
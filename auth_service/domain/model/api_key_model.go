@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// APIKey authenticates a service account without a login. only the hash of
+// the key is stored, the prefix is kept to tell keys apart.
+type APIKey struct {
+	gorm.Model
+	UUID       uuid.UUID  `gorm:"type:uuid;unique;not null" json:"uuid"`
+	UserUUID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_uuid"`
+	Name       string     `gorm:"type:varchar(100);not null" json:"name"`
+	Prefix     string     `gorm:"type:varchar(20);not null" json:"prefix"`
+	KeyHash    string     `gorm:"type:varchar(64);unique;not null" json:"-"`
+	ExpiredAt  time.Time  `gorm:"not null" json:"expired_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+
+	// the key only gets the scopes still granted to the role of its user
+	Scopes []APIKeyScope `gorm:"foreignKey:APIKeyUUID;references:UUID;constraint:OnDelete:CASCADE;" json:"scopes"`
+
+	User User `gorm:"foreignKey:UserUUID;references:UUID;constraint:OnDelete:CASCADE;" json:"-"`
+}
+
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && k.ExpiredAt.After(now)
+}
+
+func (k *APIKey) ScopeNames() []string {
+	scopes := []string{}
+	for _, scope := range k.Scopes {
+		scopes = append(scopes, scope.Permission)
+	}
+	return scopes
+}
+
+type APIKeyScope struct {
+	gorm.Model
+	APIKeyUUID uuid.UUID `gorm:"column:api_key_uuid;type:uuid;not null;uniqueIndex:idx_api_key_scopes_api_key_uuid_permission" json:"api_key_uuid"`
+	Permission string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_api_key_scopes_api_key_uuid_permission" json:"permission"`
+}
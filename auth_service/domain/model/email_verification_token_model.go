@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EmailVerificationToken confirms the user owns Email. only the sha256 of the
+// token is stored, the token itself is only ever sent by mail.
+type EmailVerificationToken struct {
+	gorm.Model
+	UUID      uuid.UUID  `gorm:"type:uuid;unique;not null" json:"uuid"`
+	UserUUID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_uuid"`
+	Email     string     `gorm:"not null" json:"email"` // address the token was sent to
+	TokenHash string     `gorm:"type:varchar(64);unique;not null" json:"-"`
+	ExpiredAt time.Time  `gorm:"not null" json:"expired_at"`
+	UsedAt    *time.Time `json:"used_at"`
+}
+
+func (token *EmailVerificationToken) IsUsable(now time.Time) bool {
+	return token.UsedAt == nil && now.Before(token.ExpiredAt)
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LoginAttempt counts the failed logins of an account or a client ip, see
+// LoginAttemptAccountKey and LoginAttemptIPKey.
+type LoginAttempt struct {
+	gorm.Model
+	Key          string     `gorm:"type:varchar(100);unique;not null" json:"key"`
+	Failures     int        `gorm:"not null;default:0" json:"failures"`
+	LastFailedAt time.Time  `gorm:"not null" json:"last_failed_at"`
+	BlockedUntil *time.Time `json:"blocked_until"`
+}
+
+func (attempt *LoginAttempt) IsBlocked(now time.Time) bool {
+	return attempt.BlockedUntil != nil && now.Before(*attempt.BlockedUntil)
+}
+
+func LoginAttemptAccountKey(userUUID string) string {
+	return "account:" + userUUID
+}
+
+func LoginAttemptIPKey(ip string) string {
+	return "ip:" + ip
+}
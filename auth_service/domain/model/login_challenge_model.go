@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LoginChallengeMaxAttempts is how many wrong 2fa codes a challenge takes
+// before it is burnt and the user has to log in again.
+const LoginChallengeMaxAttempts = 5
+
+// LoginChallenge is the first step of a 2fa login. the password is already
+// checked, the challenge token is exchanged with a 2fa code for real tokens.
+// only the sha256 of the challenge token is stored.
+type LoginChallenge struct {
+	gorm.Model
+	UUID      uuid.UUID  `gorm:"type:uuid;unique;not null" json:"uuid"`
+	UserUUID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_uuid"`
+	TokenHash string     `gorm:"type:varchar(64);unique;not null" json:"-"`
+	Device    string     `gorm:"type:text" json:"device"`
+	IP        string     `gorm:"type:varchar(45)" json:"ip"`
+	Attempts  int        `gorm:"not null;default:0" json:"attempts"`
+	ExpiredAt time.Time  `gorm:"not null" json:"expired_at"`
+	UsedAt    *time.Time `json:"used_at"`
+}
+
+func (challenge *LoginChallenge) IsUsable(now time.Time) bool {
+	return challenge.UsedAt == nil &&
+		challenge.Attempts < LoginChallengeMaxAttempts &&
+		now.Before(challenge.ExpiredAt)
+}
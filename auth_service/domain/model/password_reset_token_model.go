@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PasswordResetToken is a single use password reset token. only the sha256 of
+// the token is stored, the token itself is only ever sent by mail.
+type PasswordResetToken struct {
+	gorm.Model
+	UUID      uuid.UUID  `gorm:"type:uuid;unique;not null" json:"uuid"`
+	UserUUID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_uuid"`
+	TokenHash string     `gorm:"type:varchar(64);unique;not null" json:"-"`
+	ExpiredAt time.Time  `gorm:"not null" json:"expired_at"`
+	UsedAt    *time.Time `json:"used_at"`
+}
+
+func (token *PasswordResetToken) IsUsable(now time.Time) bool {
+	return token.UsedAt == nil && now.Before(token.ExpiredAt)
+}
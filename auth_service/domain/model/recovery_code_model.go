@@ -0,0 +1,17 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RecoveryCode is a single use 2fa code for when the authenticator is lost.
+// only the sha256 of the code is stored.
+type RecoveryCode struct {
+	gorm.Model
+	UserUUID uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_uuid"`
+	CodeHash string     `gorm:"type:varchar(64);not null" json:"-"`
+	UsedAt   *time.Time `json:"used_at"`
+}
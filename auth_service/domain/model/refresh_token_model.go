@@ -9,11 +9,13 @@ import (
 
 type RefreshToken struct {
 	gorm.Model
-	Token     string     `gorm:"type:text;unique;not null" json:"token"`
-	UserUUID  uuid.UUID  `gorm:"type:uuid;not null" json:"user_uuid"`
-	UsedAt    *time.Time `json:"used_at"`
-	ExpiredAt *time.Time `json:"expired_at"`
-	Invalid   bool       `json:"invalid"`
+	Token    string    `gorm:"type:text;unique;not null" json:"token"`
+	UserUUID uuid.UUID `gorm:"type:uuid;not null" json:"user_uuid"`
+	// nil only for tokens issued before sessions, those are invalidated
+	SessionUUID *uuid.UUID `gorm:"type:uuid;index" json:"session_uuid"`
+	UsedAt      *time.Time `json:"used_at"`
+	ExpiredAt   *time.Time `json:"expired_at"`
+	Invalid     bool       `json:"invalid"`
 
 	User User `gorm:"foreignKey:UserUUID;references:UUID;constraint:OnDelete:CASCADE;"`
 }
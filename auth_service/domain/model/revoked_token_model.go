@@ -0,0 +1,17 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RevokedToken is a revoked access token, identified by its jti claim. rows are
+// only needed until the token would have expired anyway.
+type RevokedToken struct {
+	gorm.Model
+	TokenID   string    `gorm:"type:text;unique;not null" json:"token_id"`
+	UserUUID  uuid.UUID `gorm:"type:uuid;not null;index" json:"user_uuid"`
+	ExpiredAt time.Time `gorm:"not null;index" json:"expired_at"`
+}
@@ -0,0 +1,98 @@
+package model
+
+import (
+	"regexp"
+
+	"gorm.io/gorm"
+)
+
+// seeded roles, admin always has every permission
+const (
+	RoleAdmin     = "admin"
+	RoleLibrarian = "librarian"
+	RoleUser      = "user"
+)
+
+// permissions checked by the services, named <resource>:<action>
+const (
+	PermissionUserRead     = "user:read"
+	PermissionUserManage   = "user:manage"
+	PermissionRoleManage   = "role:manage"
+	PermissionLoginUnlock  = "login:unlock"
+	PermissionAPIKeyManage = "api_key:manage"
+
+	PermissionAuthorCreate = "author:create"
+	PermissionAuthorUpdate = "author:update"
+	PermissionAuthorDelete = "author:delete"
+
+	PermissionCopyManage   = "copy:manage"
+	PermissionBorrowManage = "borrow:manage"
+	PermissionHoldManage   = "hold:manage"
+	PermissionFineManage   = "fine:manage"
+
+	// borrows of users having it use the extended renewal limit
+	PermissionBorrowRenewExtended = "borrow:renew_extended"
+
+	PermissionCategoryCreate = "category:create"
+	PermissionCategoryUpdate = "category:update"
+	PermissionCategoryDelete = "category:delete"
+)
+
+// Permissions lists every permission a role can be granted.
+var Permissions = []string{
+	PermissionUserRead,
+	PermissionUserManage,
+	PermissionRoleManage,
+	PermissionLoginUnlock,
+	PermissionAPIKeyManage,
+	PermissionAuthorCreate,
+	PermissionAuthorUpdate,
+	PermissionAuthorDelete,
+	PermissionCopyManage,
+	PermissionBorrowManage,
+	PermissionHoldManage,
+	PermissionFineManage,
+	PermissionBorrowRenewExtended,
+	PermissionCategoryCreate,
+	PermissionCategoryUpdate,
+	PermissionCategoryDelete,
+}
+
+var roleNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,49}$`)
+
+func IsValidPermission(permission string) bool {
+	for _, p := range Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+func IsValidRoleName(name string) bool {
+	return roleNameRegexp.MatchString(name)
+}
+
+// Role groups the permissions granted to the users having it, users.role
+// references its name.
+type Role struct {
+	gorm.Model
+	Name        string `gorm:"type:varchar(50);unique;not null" json:"name"`
+	Description string `gorm:"type:text" json:"description"`
+
+	Permissions []RolePermission `gorm:"foreignKey:RoleName;references:Name;constraint:OnDelete:CASCADE,OnUpdate:CASCADE;" json:"permissions"`
+}
+
+func (role *Role) PermissionNames() []string {
+	permissions := []string{}
+	for _, permission := range role.Permissions {
+		permissions = append(permissions, permission.Permission)
+	}
+	return permissions
+}
+
+type RolePermission struct {
+	gorm.Model
+	RoleName   string `gorm:"type:varchar(50);not null;uniqueIndex:idx_role_permissions_role_name_permission" json:"role_name"`
+	Permission string `gorm:"type:varchar(100);not null;uniqueIndex:idx_role_permissions_role_name_permission" json:"permission"`
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SagaNameRegister creates the user, then its author in author_service.
+const SagaNameRegister = "register"
+
+// Saga records the progress of an operation spanning several services, so the
+// reconciliation can finish it after a crash. see utils/saga for the statuses.
+type Saga struct {
+	gorm.Model
+	UUID         uuid.UUID  `gorm:"type:uuid;unique;not null" json:"uuid"`
+	Name         string     `gorm:"type:varchar(50);not null" json:"name"`
+	Status       string     `gorm:"type:varchar(20);not null" json:"status"`
+	Step         string     `gorm:"type:varchar(50);not null;default:''" json:"step"` // last completed step
+	ResourceUUID *uuid.UUID `gorm:"type:uuid" json:"resource_uuid"`                   // user created by the saga
+	Error        *string    `gorm:"type:text" json:"error"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	SecurityEventTypeRefreshTokenReuse = "refresh-token-reuse"
+	SecurityEventTypePasswordReset     = "password-reset"
+	SecurityEventTypeTwoFactorEnabled  = "2fa-enabled"
+	SecurityEventTypeTwoFactorDisabled = "2fa-disabled"
+	SecurityEventTypeRecoveryCodeUsed  = "recovery-code-used"
+	SecurityEventTypeAccountLocked     = "account-locked"
+	SecurityEventTypePasswordChanged   = "password-changed"
+	SecurityEventTypeEmailChanged      = "email-changed"
+	SecurityEventTypeAPIKeyCreated     = "api-key-created"
+	SecurityEventTypeAPIKeyRevoked     = "api-key-revoked"
+)
+
+// SecurityEvent records suspicious activity on a user account.
+type SecurityEvent struct {
+	gorm.Model
+	UUID        uuid.UUID  `gorm:"type:uuid;unique;not null" json:"uuid"`
+	UserUUID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_uuid"`
+	SessionUUID *uuid.UUID `gorm:"type:uuid" json:"session_uuid"`
+	Type        string     `gorm:"type:varchar(50);not null" json:"type"`
+	IP          string     `gorm:"type:varchar(45)" json:"ip"`
+	Device      string     `gorm:"type:text" json:"device"`
+	Detail      *string    `gorm:"type:text" json:"detail"`
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	SessionRevokedReasonLogout         = "logout"
+	SessionRevokedReasonLogoutAll      = "logout-all"
+	SessionRevokedReasonUser           = "revoked-by-user"
+	SessionRevokedReasonReuse          = "refresh-token-reuse"
+	SessionRevokedReasonPassword       = "password-reset"
+	SessionRevokedReasonPasswordChange = "password-change"
+)
+
+// Session is a refresh token family. every refresh rotates the token inside
+// the same session, presenting an already rotated token again revokes the
+// whole session.
+type Session struct {
+	gorm.Model
+	UUID          uuid.UUID  `gorm:"type:uuid;unique;not null" json:"uuid"`
+	UserUUID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_uuid"`
+	Device        string     `gorm:"type:text" json:"device"` // user agent of the client
+	IP            string     `gorm:"type:varchar(45)" json:"ip"`
+	LastUsedAt    time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"last_used_at"`
+	ExpiredAt     time.Time  `gorm:"not null" json:"expired_at"` // expiry of the latest refresh token
+	RevokedAt     *time.Time `json:"revoked_at"`
+	RevokedReason *string    `gorm:"type:varchar(50)" json:"revoked_reason"`
+
+	User          User           `gorm:"foreignKey:UserUUID;references:UUID;constraint:OnDelete:CASCADE;" json:"-"`
+	RefreshTokens []RefreshToken `gorm:"foreignKey:SessionUUID;references:UUID;constraint:OnDelete:CASCADE;" json:"-"`
+}
+
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && s.ExpiredAt.After(now)
+}
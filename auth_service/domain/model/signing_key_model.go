@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SigningKey is a key pair for access tokens. only the newest unretired key
+// signs, retired keys stay published until every token they signed expired.
+type SigningKey struct {
+	gorm.Model
+	KID        string     `gorm:"type:varchar(64);unique;not null" json:"kid"`
+	Algorithm  string     `gorm:"type:varchar(10);not null" json:"algorithm"`
+	PrivateKey string     `gorm:"type:text;not null" json:"-"`
+	PublicKey  string     `gorm:"type:text;not null" json:"public_key"`
+	RetiredAt  *time.Time `json:"retired_at"`
+	ExpiredAt  *time.Time `json:"expired_at"` // no longer published after this
+}
@@ -1,8 +1,10 @@
 package model
 
 import (
+	event_util "auth_service/utils/event"
 	validator_util "auth_service/utils/validator/user"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -16,9 +18,45 @@ type User struct {
 	Email    string    `json:"email"`
 	Role     string    `json:"role"`
 
+	// access tokens issued before this time are revoked, set by logout all
+	TokensValidAfter *time.Time `json:"tokens_valid_after"`
+
+	// nil until the user confirms the email with a verification token
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+
+	// totp secret is set on enrollment, 2fa is only on once the first code is
+	// confirmed. the last used step keeps codes from being used twice.
+	TOTPSecret       *string    `gorm:"column:totp_secret" json:"-"`
+	TOTPEnabledAt    *time.Time `gorm:"column:totp_enabled_at" json:"totp_enabled_at"`
+	TOTPLastUsedStep int64      `gorm:"column:totp_last_used_step;not null;default:0" json:"-"`
+
+	// service accounts have no email nor usable password, they authenticate
+	// with api keys only
+	IsServiceAccount bool `gorm:"not null;default:false" json:"is_service_account"`
+
+	// key of the grpc call that created the user, nil for other users
+	IdempotencyKey *string `gorm:"type:varchar(100);unique" json:"-"`
+
 	RefreshTokens []RefreshToken `gorm:"foreignKey:UserUUID;references:UUID;" json:"-"`
 }
 
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+func (u *User) IsTOTPEnabled() bool {
+	return u.TOTPEnabledAt != nil && u.TOTPSecret != nil
+}
+
+func (u *User) EventPayload() event_util.UserPayload {
+	return event_util.UserPayload{
+		UUID:     u.UUID.String(),
+		Username: u.Username,
+		Email:    u.Email,
+		Role:     u.Role,
+	}
+}
+
 func (u *User) Validate() (err error) {
 	// username
 	err = validator_util.ValidateUsername(u.Username)
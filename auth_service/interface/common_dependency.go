@@ -5,4 +5,13 @@ import ucase "auth_service/usecase"
 type CommonDependency struct {
 	AuthUcase ucase.IAuthUcase
 	UserUcase ucase.IUserUcase
+
+	SigningKeyUcase        ucase.ISigningKeyUcase
+	PasswordResetUcase     ucase.IPasswordResetUcase
+	EmailVerificationUcase ucase.IEmailVerificationUcase
+	TwoFactorUcase         ucase.ITwoFactorUcase
+	LoginThrottleUcase     ucase.ILoginThrottleUcase
+	RoleUcase              ucase.IRoleUcase
+	APIKeyUcase            ucase.IAPIKeyUcase
+	ServiceAuthUcase       ucase.IServiceAuthUcase
 }
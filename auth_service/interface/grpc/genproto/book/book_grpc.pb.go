@@ -19,8 +19,13 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	BookService_GetBookTotalByAuthorUUID_FullMethodName      = "/book_service.BookService/GetBookTotalByAuthorUUID"
-	BookService_BulkGetBookTotalByAuthorUUIDs_FullMethodName = "/book_service.BookService/BulkGetBookTotalByAuthorUUIDs"
+	BookService_GetBookTotalByAuthorUUID_FullMethodName         = "/book_service.BookService/GetBookTotalByAuthorUUID"
+	BookService_BulkGetBookTotalByAuthorUUIDs_FullMethodName    = "/book_service.BookService/BulkGetBookTotalByAuthorUUIDs"
+	BookService_GetBookTotalByCategoryUUID_FullMethodName       = "/book_service.BookService/GetBookTotalByCategoryUUID"
+	BookService_BulkGetBookTotalByCategoryUUIDs_FullMethodName  = "/book_service.BookService/BulkGetBookTotalByCategoryUUIDs"
+	BookService_GetActiveBorrowTotalByAuthorUUID_FullMethodName = "/book_service.BookService/GetActiveBorrowTotalByAuthorUUID"
+	BookService_ReassignBooksByAuthorUUID_FullMethodName        = "/book_service.BookService/ReassignBooksByAuthorUUID"
+	BookService_DeleteBooksByAuthorUUID_FullMethodName          = "/book_service.BookService/DeleteBooksByAuthorUUID"
 )
 
 // BookServiceClient is the client API for BookService service.
@@ -29,6 +34,12 @@ const (
 type BookServiceClient interface {
 	GetBookTotalByAuthorUUID(ctx context.Context, in *GetBookTotalByAuthorUUIDReq, opts ...grpc.CallOption) (*GetBookTotalByAuthorUUIDResp, error)
 	BulkGetBookTotalByAuthorUUIDs(ctx context.Context, in *BulkGetBookTotalByAuthorUUIDsReq, opts ...grpc.CallOption) (*BulkGetBookTotalByAuthorUUIDsResp, error)
+	GetBookTotalByCategoryUUID(ctx context.Context, in *GetBookTotalByCategoryUUIDReq, opts ...grpc.CallOption) (*GetBookTotalByCategoryUUIDResp, error)
+	BulkGetBookTotalByCategoryUUIDs(ctx context.Context, in *BulkGetBookTotalByCategoryUUIDsReq, opts ...grpc.CallOption) (*BulkGetBookTotalByCategoryUUIDsResp, error)
+	GetActiveBorrowTotalByAuthorUUID(ctx context.Context, in *GetActiveBorrowTotalByAuthorUUIDReq, opts ...grpc.CallOption) (*GetActiveBorrowTotalByAuthorUUIDResp, error)
+	// fail with FailedPrecondition while a book of the author is borrowed
+	ReassignBooksByAuthorUUID(ctx context.Context, in *ReassignBooksByAuthorUUIDReq, opts ...grpc.CallOption) (*ReassignBooksByAuthorUUIDResp, error)
+	DeleteBooksByAuthorUUID(ctx context.Context, in *DeleteBooksByAuthorUUIDReq, opts ...grpc.CallOption) (*DeleteBooksByAuthorUUIDResp, error)
 }
 
 type bookServiceClient struct {
@@ -59,12 +70,68 @@ func (c *bookServiceClient) BulkGetBookTotalByAuthorUUIDs(ctx context.Context, i
 	return out, nil
 }
 
+func (c *bookServiceClient) GetBookTotalByCategoryUUID(ctx context.Context, in *GetBookTotalByCategoryUUIDReq, opts ...grpc.CallOption) (*GetBookTotalByCategoryUUIDResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetBookTotalByCategoryUUIDResp)
+	err := c.cc.Invoke(ctx, BookService_GetBookTotalByCategoryUUID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) BulkGetBookTotalByCategoryUUIDs(ctx context.Context, in *BulkGetBookTotalByCategoryUUIDsReq, opts ...grpc.CallOption) (*BulkGetBookTotalByCategoryUUIDsResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BulkGetBookTotalByCategoryUUIDsResp)
+	err := c.cc.Invoke(ctx, BookService_BulkGetBookTotalByCategoryUUIDs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) GetActiveBorrowTotalByAuthorUUID(ctx context.Context, in *GetActiveBorrowTotalByAuthorUUIDReq, opts ...grpc.CallOption) (*GetActiveBorrowTotalByAuthorUUIDResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetActiveBorrowTotalByAuthorUUIDResp)
+	err := c.cc.Invoke(ctx, BookService_GetActiveBorrowTotalByAuthorUUID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) ReassignBooksByAuthorUUID(ctx context.Context, in *ReassignBooksByAuthorUUIDReq, opts ...grpc.CallOption) (*ReassignBooksByAuthorUUIDResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReassignBooksByAuthorUUIDResp)
+	err := c.cc.Invoke(ctx, BookService_ReassignBooksByAuthorUUID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) DeleteBooksByAuthorUUID(ctx context.Context, in *DeleteBooksByAuthorUUIDReq, opts ...grpc.CallOption) (*DeleteBooksByAuthorUUIDResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteBooksByAuthorUUIDResp)
+	err := c.cc.Invoke(ctx, BookService_DeleteBooksByAuthorUUID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // BookServiceServer is the server API for BookService service.
 // All implementations must embed UnimplementedBookServiceServer
 // for forward compatibility.
 type BookServiceServer interface {
 	GetBookTotalByAuthorUUID(context.Context, *GetBookTotalByAuthorUUIDReq) (*GetBookTotalByAuthorUUIDResp, error)
 	BulkGetBookTotalByAuthorUUIDs(context.Context, *BulkGetBookTotalByAuthorUUIDsReq) (*BulkGetBookTotalByAuthorUUIDsResp, error)
+	GetBookTotalByCategoryUUID(context.Context, *GetBookTotalByCategoryUUIDReq) (*GetBookTotalByCategoryUUIDResp, error)
+	BulkGetBookTotalByCategoryUUIDs(context.Context, *BulkGetBookTotalByCategoryUUIDsReq) (*BulkGetBookTotalByCategoryUUIDsResp, error)
+	GetActiveBorrowTotalByAuthorUUID(context.Context, *GetActiveBorrowTotalByAuthorUUIDReq) (*GetActiveBorrowTotalByAuthorUUIDResp, error)
+	// fail with FailedPrecondition while a book of the author is borrowed
+	ReassignBooksByAuthorUUID(context.Context, *ReassignBooksByAuthorUUIDReq) (*ReassignBooksByAuthorUUIDResp, error)
+	DeleteBooksByAuthorUUID(context.Context, *DeleteBooksByAuthorUUIDReq) (*DeleteBooksByAuthorUUIDResp, error)
 	mustEmbedUnimplementedBookServiceServer()
 }
 
@@ -81,6 +148,21 @@ func (UnimplementedBookServiceServer) GetBookTotalByAuthorUUID(context.Context,
 func (UnimplementedBookServiceServer) BulkGetBookTotalByAuthorUUIDs(context.Context, *BulkGetBookTotalByAuthorUUIDsReq) (*BulkGetBookTotalByAuthorUUIDsResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BulkGetBookTotalByAuthorUUIDs not implemented")
 }
+func (UnimplementedBookServiceServer) GetBookTotalByCategoryUUID(context.Context, *GetBookTotalByCategoryUUIDReq) (*GetBookTotalByCategoryUUIDResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBookTotalByCategoryUUID not implemented")
+}
+func (UnimplementedBookServiceServer) BulkGetBookTotalByCategoryUUIDs(context.Context, *BulkGetBookTotalByCategoryUUIDsReq) (*BulkGetBookTotalByCategoryUUIDsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BulkGetBookTotalByCategoryUUIDs not implemented")
+}
+func (UnimplementedBookServiceServer) GetActiveBorrowTotalByAuthorUUID(context.Context, *GetActiveBorrowTotalByAuthorUUIDReq) (*GetActiveBorrowTotalByAuthorUUIDResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetActiveBorrowTotalByAuthorUUID not implemented")
+}
+func (UnimplementedBookServiceServer) ReassignBooksByAuthorUUID(context.Context, *ReassignBooksByAuthorUUIDReq) (*ReassignBooksByAuthorUUIDResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReassignBooksByAuthorUUID not implemented")
+}
+func (UnimplementedBookServiceServer) DeleteBooksByAuthorUUID(context.Context, *DeleteBooksByAuthorUUIDReq) (*DeleteBooksByAuthorUUIDResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteBooksByAuthorUUID not implemented")
+}
 func (UnimplementedBookServiceServer) mustEmbedUnimplementedBookServiceServer() {}
 func (UnimplementedBookServiceServer) testEmbeddedByValue()                     {}
 
@@ -138,6 +220,96 @@ func _BookService_BulkGetBookTotalByAuthorUUIDs_Handler(srv interface{}, ctx con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BookService_GetBookTotalByCategoryUUID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBookTotalByCategoryUUIDReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).GetBookTotalByCategoryUUID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_GetBookTotalByCategoryUUID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).GetBookTotalByCategoryUUID(ctx, req.(*GetBookTotalByCategoryUUIDReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_BulkGetBookTotalByCategoryUUIDs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BulkGetBookTotalByCategoryUUIDsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).BulkGetBookTotalByCategoryUUIDs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_BulkGetBookTotalByCategoryUUIDs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).BulkGetBookTotalByCategoryUUIDs(ctx, req.(*BulkGetBookTotalByCategoryUUIDsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_GetActiveBorrowTotalByAuthorUUID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetActiveBorrowTotalByAuthorUUIDReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).GetActiveBorrowTotalByAuthorUUID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_GetActiveBorrowTotalByAuthorUUID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).GetActiveBorrowTotalByAuthorUUID(ctx, req.(*GetActiveBorrowTotalByAuthorUUIDReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_ReassignBooksByAuthorUUID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReassignBooksByAuthorUUIDReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).ReassignBooksByAuthorUUID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_ReassignBooksByAuthorUUID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).ReassignBooksByAuthorUUID(ctx, req.(*ReassignBooksByAuthorUUIDReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_DeleteBooksByAuthorUUID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteBooksByAuthorUUIDReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).DeleteBooksByAuthorUUID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_DeleteBooksByAuthorUUID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).DeleteBooksByAuthorUUID(ctx, req.(*DeleteBooksByAuthorUUIDReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // BookService_ServiceDesc is the grpc.ServiceDesc for BookService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -153,6 +325,26 @@ var BookService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "BulkGetBookTotalByAuthorUUIDs",
 			Handler:    _BookService_BulkGetBookTotalByAuthorUUIDs_Handler,
 		},
+		{
+			MethodName: "GetBookTotalByCategoryUUID",
+			Handler:    _BookService_GetBookTotalByCategoryUUID_Handler,
+		},
+		{
+			MethodName: "BulkGetBookTotalByCategoryUUIDs",
+			Handler:    _BookService_BulkGetBookTotalByCategoryUUIDs_Handler,
+		},
+		{
+			MethodName: "GetActiveBorrowTotalByAuthorUUID",
+			Handler:    _BookService_GetActiveBorrowTotalByAuthorUUID_Handler,
+		},
+		{
+			MethodName: "ReassignBooksByAuthorUUID",
+			Handler:    _BookService_ReassignBooksByAuthorUUID_Handler,
+		},
+		{
+			MethodName: "DeleteBooksByAuthorUUID",
+			Handler:    _BookService_DeleteBooksByAuthorUUID_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "book.proto",
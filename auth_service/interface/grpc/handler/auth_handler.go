@@ -3,6 +3,7 @@ package handler
 import (
 	"auth_service/domain/dto"
 	auth_grpc "auth_service/interface/grpc/genproto/auth"
+	grpc_interceptor "auth_service/interface/grpc/interceptor"
 	ucase "auth_service/usecase"
 	error_utils "auth_service/utils/error"
 	"context"
@@ -13,12 +14,15 @@ import (
 
 type AuthServiceHandler struct {
 	auth_grpc.UnimplementedAuthServiceServer
-	authUcase ucase.IAuthUcase
-	userUcase ucase.IUserUcase
+	authUcase        ucase.IAuthUcase
+	userUcase        ucase.IUserUcase
+	signingKeyUcase  ucase.ISigningKeyUcase
+	apiKeyUcase      ucase.IAPIKeyUcase
+	serviceAuthUcase ucase.IServiceAuthUcase
 }
 
-func NewAuthServiceHandler(authUcase ucase.IAuthUcase, userUcase ucase.IUserUcase) *AuthServiceHandler {
-	return &AuthServiceHandler{authUcase: authUcase, userUcase: userUcase}
+func NewAuthServiceHandler(authUcase ucase.IAuthUcase, userUcase ucase.IUserUcase, signingKeyUcase ucase.ISigningKeyUcase, apiKeyUcase ucase.IAPIKeyUcase, serviceAuthUcase ucase.IServiceAuthUcase) *AuthServiceHandler {
+	return &AuthServiceHandler{authUcase: authUcase, userUcase: userUcase, signingKeyUcase: signingKeyUcase, apiKeyUcase: apiKeyUcase, serviceAuthUcase: serviceAuthUcase}
 }
 
 func (h *AuthServiceHandler) CheckToken(ctx context.Context, req *auth_grpc.CheckTokenRequest) (*auth_grpc.CheckTokenResponse, error) {
@@ -40,10 +44,12 @@ func (h *AuthServiceHandler) CheckToken(ctx context.Context, req *auth_grpc.Chec
 	}
 
 	resp := &auth_grpc.CheckTokenResponse{
-		Uuid:     raw.UUID,
-		Username: raw.Username,
-		Email:    raw.Email,
-		Role:     raw.Role,
+		Uuid:          raw.UUID,
+		Username:      raw.Username,
+		Email:         raw.Email,
+		Role:          raw.Role,
+		EmailVerified: raw.EmailVerified,
+		Permissions:   raw.Permissions,
 	}
 
 	return resp, nil
@@ -68,10 +74,11 @@ func (h *AuthServiceHandler) GetUserByUUID(
 	}
 
 	resp := &auth_grpc.GetUserByUUIDResponse{
-		Uuid:     raw.UUID,
-		Username: raw.Username,
-		Email:    raw.Email,
-		Role:     raw.Role,
+		Uuid:        raw.UUID,
+		Username:    raw.Username,
+		Email:       raw.Email,
+		Role:        raw.Role,
+		Permissions: raw.Permissions,
 	}
 
 	return resp, nil
@@ -84,16 +91,24 @@ func (h *AuthServiceHandler) CreateUser(
 	// payload validation
 	if req.Username == "" ||
 		req.Email == "" ||
-		req.Password == "" ||
-		req.Role == "" {
+		req.Password == "" {
 		return nil, status.Error(codes.InvalidArgument, "fields required")
 	}
 
-	raw, err := h.userUcase.CreateUser(ctx, nil, dto.CreateUserReq{
+	// the requested role is only honored for a user the call is made for
+	// that may manage users
+	var currentUser *dto.CurrentUser
+	if caller, ok := grpc_interceptor.CallerFromContext(ctx); ok {
+		currentUser = caller.User
+	}
+
+	raw, err := h.userUcase.CreateUser(ctx, nil, currentUser, dto.CreateUserReq{
 		Username: req.Username,
 		Email:    req.Email,
 		Password: req.Password,
 		Role:     req.Role,
+
+		IdempotencyKey: req.IdempotencyKey,
 	})
 	if err != nil {
 		customErr, ok := err.(*error_utils.CustomErr)
@@ -145,7 +160,12 @@ func (h *AuthServiceHandler) UpdateUser(
 		dtoPayload.Role = &req.Role
 	}
 
-	raw, err := h.userUcase.UpdateUser(ctx, nil, req.Uuid, dtoPayload)
+	var currentUser *dto.CurrentUser
+	if caller, ok := grpc_interceptor.CallerFromContext(ctx); ok {
+		currentUser = caller.User
+	}
+
+	raw, err := h.userUcase.UpdateUser(ctx, nil, currentUser, req.Uuid, dtoPayload)
 	if err != nil {
 		customErr, ok := err.(*error_utils.CustomErr)
 		if ok {
@@ -173,7 +193,13 @@ func (h *AuthServiceHandler) DeleteUser(
 		return nil, status.Error(codes.InvalidArgument, "missing uuid")
 	}
 
-	raw, err := h.userUcase.DeleteUser(ctx, nil, req.Uuid)
+	var raw *dto.DeleteUserRespData
+	var err error
+	if req.Purge {
+		raw, err = h.userUcase.PurgeUser(ctx, req.Uuid)
+	} else {
+		raw, err = h.userUcase.DeleteUser(ctx, nil, req.Uuid)
+	}
 	if err != nil {
 		customErr, ok := err.(*error_utils.CustomErr)
 		if ok {
@@ -191,3 +217,95 @@ func (h *AuthServiceHandler) DeleteUser(
 
 	return resp, nil
 }
+
+func (h *AuthServiceHandler) GetJWKS(
+	ctx context.Context,
+	req *auth_grpc.GetJWKSRequest,
+) (*auth_grpc.GetJWKSResponse, error) {
+	raw, err := h.signingKeyUcase.GetJWKS()
+	if err != nil {
+		customErr, ok := err.(*error_utils.CustomErr)
+		if ok {
+			return nil, status.Errorf(customErr.GrpcCode, customErr.Error())
+		}
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	resp := &auth_grpc.GetJWKSResponse{
+		Keys: []*auth_grpc.JWK{},
+	}
+	for _, key := range raw.Keys {
+		resp.Keys = append(resp.Keys, &auth_grpc.JWK{
+			Kty: key.Kty,
+			Kid: key.Kid,
+			Use: key.Use,
+			Alg: key.Alg,
+			N:   key.N,
+			E:   key.E,
+			Crv: key.Crv,
+			X:   key.X,
+		})
+	}
+
+	return resp, nil
+}
+
+func (h *AuthServiceHandler) ValidateAPIKey(
+	ctx context.Context,
+	req *auth_grpc.ValidateAPIKeyRequest,
+) (*auth_grpc.ValidateAPIKeyResponse, error) {
+	// payload validation
+	if req.ApiKey == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing api key")
+	}
+
+	raw, err := h.apiKeyUcase.ValidateAPIKey(ctx, dto.ValidateAPIKeyReq{APIKey: req.ApiKey})
+	if err != nil {
+		customErr, ok := err.(*error_utils.CustomErr)
+		if ok {
+			return nil, status.Errorf(customErr.GrpcCode, customErr.Error())
+		}
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	resp := &auth_grpc.ValidateAPIKeyResponse{
+		Uuid:          raw.UUID,
+		Username:      raw.Username,
+		Email:         raw.Email,
+		Role:          raw.Role,
+		EmailVerified: raw.EmailVerified,
+		Permissions:   raw.Permissions,
+		ApiKeyUuid:    raw.APIKeyUUID,
+	}
+
+	return resp, nil
+}
+
+func (h *AuthServiceHandler) IssueServiceToken(
+	ctx context.Context,
+	req *auth_grpc.IssueServiceTokenRequest,
+) (*auth_grpc.IssueServiceTokenResponse, error) {
+	// payload validation
+	if req.ServiceName == "" || req.ServiceSecret == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing service name or secret")
+	}
+
+	raw, err := h.serviceAuthUcase.IssueServiceToken(dto.IssueServiceTokenReq{
+		ServiceName:   req.ServiceName,
+		ServiceSecret: req.ServiceSecret,
+	})
+	if err != nil {
+		customErr, ok := err.(*error_utils.CustomErr)
+		if ok {
+			return nil, status.Errorf(customErr.GrpcCode, customErr.Error())
+		}
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	resp := &auth_grpc.IssueServiceTokenResponse{
+		Token:     raw.Token,
+		ExpiredAt: raw.ExpiredAt.Unix(),
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,146 @@
+package grpc_interceptor
+
+import (
+	"auth_service/domain/dto"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testMethod            = "/test.Service/Call"
+	testServiceOnlyMethod = "/test.Service/ServiceOnly"
+	testPublicMethod      = "/test.Service/Public"
+)
+
+var testServerAuthConfig = ServerAuthConfig{
+	VerifyServiceToken: func(ctx context.Context, token string) (string, error) {
+		if token != "service-token" {
+			return "", errors.New("invalid token")
+		}
+		return "book_service", nil
+	},
+	VerifyUserToken: func(ctx context.Context, token string) (*dto.CurrentUser, error) {
+		if token != "user-token" {
+			return nil, errors.New("invalid token")
+		}
+		return &dto.CurrentUser{UUID: "user-1", Username: "user", Role: "user"}, nil
+	},
+	PublicMethods:      []string{testPublicMethod},
+	ServiceOnlyMethods: []string{testServiceOnlyMethod},
+}
+
+// serve runs the auth interceptor on a call with the given metadata and
+// returns the caller seen by the handler.
+func serve(method string, md metadata.MD) (*Caller, error) {
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+
+	var caller *Caller
+	_, err := AuthUnaryInterceptor(testServerAuthConfig)(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		caller, _ = CallerFromContext(ctx)
+		return nil, nil
+	})
+	return caller, err
+}
+
+func TestAuthUnaryInterceptor(t *testing.T) {
+	// no credentials
+	_, err := serve(testMethod, metadata.MD{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// invalid token
+	_, err = serve(testMethod, metadata.Pairs(MetadataAuthorization, "Bearer other-token"))
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// public method
+	caller, err := serve(testPublicMethod, metadata.MD{})
+	assert.NoError(t, err)
+	assert.Equal(t, "", caller.Service)
+	assert.NotEmpty(t, caller.RequestID)
+
+	// service token, the user is propagated
+	caller, err = serve(testServiceOnlyMethod, metadata.Pairs(
+		MetadataAuthorization, "Bearer service-token",
+		MetadataRequestID, "request-1",
+		MetadataUserUUID, "user-1",
+		MetadataUserRole, "admin",
+	))
+	assert.NoError(t, err)
+	assert.Equal(t, "book_service", caller.Service)
+	assert.Equal(t, "request-1", caller.RequestID)
+	assert.Equal(t, "user-1", caller.User.UUID)
+	assert.Equal(t, "admin", caller.User.Role)
+
+	// user headers are ignored without a service credential
+	caller, err = serve(testMethod, metadata.Pairs(
+		MetadataAuthorization, "Bearer user-token",
+		MetadataUserUUID, "user-2",
+		MetadataUserRole, "admin",
+	))
+	assert.NoError(t, err)
+	assert.Equal(t, "", caller.Service)
+	assert.Equal(t, "user-1", caller.User.UUID)
+	assert.Equal(t, "user", caller.User.Role)
+	assert.Equal(t, "user-token", caller.User.AccessToken)
+
+	// user token on a service only method
+	_, err = serve(testServiceOnlyMethod, metadata.Pairs(MetadataAuthorization, "Bearer user-token"))
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestIdentityUnaryClientInterceptor(t *testing.T) {
+	issued := 0
+	tokenSource := NewServiceTokenSource(func(ctx context.Context) (string, time.Time, error) {
+		issued++
+		return "service-token", time.Now().Add(15 * time.Minute), nil
+	})
+
+	// invoke returns the metadata the client interceptor sent
+	invoke := func(interceptor grpc.UnaryClientInterceptor, ctx context.Context, method string) metadata.MD {
+		var md metadata.MD
+		err := interceptor(ctx, method, nil, nil, nil, func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			md, _ = metadata.FromOutgoingContext(ctx)
+			return nil
+		})
+		assert.NoError(t, err)
+		return md
+	}
+
+	// the caller of a grpc handler is propagated with the service token
+	ctx := ContextWithCaller(context.Background(), &Caller{
+		Service:   "author_service",
+		User:      &dto.CurrentUser{UUID: "user-1", Username: "user", Role: "admin", AccessToken: "user-token"},
+		RequestID: "request-1",
+	})
+	md := invoke(IdentityUnaryClientInterceptor(tokenSource), ctx, testMethod)
+	assert.Equal(t, []string{"Bearer service-token"}, md.Get(MetadataAuthorization))
+	assert.Equal(t, []string{"request-1"}, md.Get(MetadataRequestID))
+	assert.Equal(t, []string{"user-1"}, md.Get(MetadataUserUUID))
+
+	// the next server sees the same caller, as the calling service
+	caller, err := serve(testMethod, md)
+	assert.NoError(t, err)
+	assert.Equal(t, "book_service", caller.Service)
+	assert.Equal(t, "request-1", caller.RequestID)
+	assert.Equal(t, "user-1", caller.User.UUID)
+	assert.Equal(t, "admin", caller.User.Role)
+
+	// the token is cached
+	invoke(IdentityUnaryClientInterceptor(tokenSource), ctx, testMethod)
+	assert.Equal(t, 1, issued)
+
+	// skipped methods go without the service token
+	md = invoke(IdentityUnaryClientInterceptor(tokenSource, testPublicMethod), context.Background(), testPublicMethod)
+	assert.Empty(t, md.Get(MetadataAuthorization))
+
+	// without a token source the access token of the user is forwarded
+	md = invoke(IdentityUnaryClientInterceptor(nil), ctx, testMethod)
+	assert.Equal(t, []string{"Bearer user-token"}, md.Get(MetadataAuthorization))
+}
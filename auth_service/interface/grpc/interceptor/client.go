@@ -0,0 +1,104 @@
+package grpc_interceptor
+
+import (
+	"auth_service/domain/dto"
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// service tokens are renewed this long before they expire
+const serviceTokenRenewBefore = time.Minute
+
+// ServiceTokenSource caches the service token of this service until it is
+// about to expire.
+type ServiceTokenSource struct {
+	issue func(ctx context.Context) (string, time.Time, error)
+
+	mu        sync.Mutex
+	token     string
+	expiredAt time.Time
+}
+
+func NewServiceTokenSource(issue func(ctx context.Context) (string, time.Time, error)) *ServiceTokenSource {
+	return &ServiceTokenSource{issue: issue}
+}
+
+func (source *ServiceTokenSource) Token(ctx context.Context) (string, error) {
+	source.mu.Lock()
+	defer source.mu.Unlock()
+
+	if source.token != "" && time.Now().Before(source.expiredAt.Add(-serviceTokenRenewBefore)) {
+		return source.token, nil
+	}
+
+	token, expiredAt, err := source.issue(ctx)
+	if err != nil {
+		return "", err
+	}
+	source.token = token
+	source.expiredAt = expiredAt
+	return token, nil
+}
+
+// IdentityUnaryClientInterceptor sends the request id and the user the call
+// is made for. the call is authenticated by the service token, or by the
+// access token of the user when tokenSource is nil, in which case mtls is
+// expected to identify the service. skipMethods are sent without the service
+// token.
+func IdentityUnaryClientInterceptor(tokenSource *ServiceTokenSource, skipMethods ...string) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		user, requestID := callerIdentity(ctx)
+
+		pairs := []string{}
+		if requestID != "" {
+			pairs = append(pairs, MetadataRequestID, requestID)
+		}
+		if user != nil {
+			pairs = append(pairs,
+				MetadataUserUUID, user.UUID,
+				MetadataUserUsername, user.Username,
+				MetadataUserRole, user.Role,
+			)
+		}
+
+		if tokenSource != nil && !containsMethod(skipMethods, method) {
+			token, err := tokenSource.Token(ctx)
+			if err != nil {
+				logger.Errorf("error getting service token: %v", err)
+				return err
+			}
+			pairs = append(pairs, MetadataAuthorization, "Bearer "+token)
+		} else if user != nil && user.AccessToken != "" {
+			pairs = append(pairs, MetadataAuthorization, "Bearer "+user.AccessToken)
+		}
+
+		ctx = metadata.AppendToOutgoingContext(ctx, pairs...)
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// callerIdentity reads the caller of a grpc handler, or the current user and
+// request id a rest handler passed along with its gin context.
+func callerIdentity(ctx context.Context) (*dto.CurrentUser, string) {
+	if caller, ok := CallerFromContext(ctx); ok {
+		return caller.User, caller.RequestID
+	}
+
+	requestID, _ := ctx.Value("requestID").(string)
+	currentUser, ok := ctx.Value("currentUser").(dto.CurrentUser)
+	if !ok {
+		return nil, requestID
+	}
+	return &currentUser, requestID
+}
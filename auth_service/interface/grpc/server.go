@@ -2,9 +2,12 @@ package grpc
 
 import (
 	"auth_service/config"
+	"auth_service/domain/dto"
 	interface_pkg "auth_service/interface"
 	auth_grpc "auth_service/interface/grpc/genproto/auth"
 	"auth_service/interface/grpc/handler"
+	grpc_interceptor "auth_service/interface/grpc/interceptor"
+	"context"
 	"fmt"
 	"log"
 	"net"
@@ -15,6 +18,63 @@ import (
 
 var logger = logging.MustGetLogger("main")
 
+// NewServer creates the grpc server with the service handler registered.
+// every rpc but the public ones requires a service credential or a forwarded
+// access token.
+func NewServer(commonDependencies interface_pkg.CommonDependency, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts, grpc.ChainUnaryInterceptor(
+		grpc_interceptor.RecoveryUnaryInterceptor(),
+		grpc_interceptor.AuthUnaryInterceptor(serverAuthConfig(commonDependencies)),
+	))
+	grpcServer := grpc.NewServer(opts...)
+
+	// register service handler
+	authServiceHandler := handler.NewAuthServiceHandler(commonDependencies.AuthUcase, commonDependencies.UserUcase, commonDependencies.SigningKeyUcase, commonDependencies.APIKeyUcase, commonDependencies.ServiceAuthUcase)
+	auth_grpc.RegisterAuthServiceServer(grpcServer, authServiceHandler)
+
+	return grpcServer
+}
+
+// serverAuthConfig checks access tokens through the auth ucase, so revoked
+// tokens are refused as well.
+func serverAuthConfig(commonDependencies interface_pkg.CommonDependency) grpc_interceptor.ServerAuthConfig {
+	return grpc_interceptor.ServerAuthConfig{
+		VerifyServiceToken: func(ctx context.Context, token string) (string, error) {
+			return commonDependencies.ServiceAuthUcase.VerifyServiceToken(token)
+		},
+		VerifyUserToken: func(ctx context.Context, token string) (*dto.CurrentUser, error) {
+			claims, err := commonDependencies.AuthUcase.CheckToken(dto.CheckTokenReq{AccessToken: token})
+			if err != nil {
+				return nil, err
+			}
+			return &dto.CurrentUser{
+				UUID:        claims.UUID,
+				Username:    claims.Username,
+				Role:        claims.Role,
+				Email:       claims.Email,
+				Permissions: claims.Permissions,
+				TokenID:     claims.TokenID,
+				SessionID:   claims.SessionID,
+				IssuedAt:    claims.IssuedAt,
+				ExpiredAt:   claims.ExpiredAt,
+			}, nil
+		},
+		// keys are public, and services trade their secret for a token
+		PublicMethods: []string{
+			auth_grpc.AuthService_GetJWKS_FullMethodName,
+			auth_grpc.AuthService_IssueServiceToken_FullMethodName,
+		},
+		// account management and api key checks are for the other services
+		ServiceOnlyMethods: []string{
+			auth_grpc.AuthService_GetUserByUUID_FullMethodName,
+			auth_grpc.AuthService_CreateUser_FullMethodName,
+			auth_grpc.AuthService_UpdateUser_FullMethodName,
+			auth_grpc.AuthService_DeleteUser_FullMethodName,
+			auth_grpc.AuthService_ValidateAPIKey_FullMethodName,
+		},
+	}
+}
+
 func SetupServer(commonDependencies interface_pkg.CommonDependency) {
 	// setup listener
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%v", config.Envs.GRPC_PORT))
@@ -23,12 +83,7 @@ func SetupServer(commonDependencies interface_pkg.CommonDependency) {
 	}
 
 	// new grpc server
-	grpcServer := grpc.NewServer()
-
-	// register service handler
-	logger.Debugf("ucase: %v", commonDependencies.AuthUcase)
-	authServiceHandler := handler.NewAuthServiceHandler(commonDependencies.AuthUcase, commonDependencies.UserUcase)
-	auth_grpc.RegisterAuthServiceServer(grpcServer, authServiceHandler)
+	grpcServer := NewServer(commonDependencies, config.NewGrpcServerOptions()...)
 
 	// Start the server
 	fmt.Printf("Starting gRPC server on port :%v...", config.Envs.GRPC_PORT)
@@ -0,0 +1,91 @@
+package grpc
+
+import (
+	interface_pkg "auth_service/interface"
+	auth_grpc "auth_service/interface/grpc/genproto/auth"
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func newBufconnClient(t *testing.T, grpcServer *grpc.Server) *grpc.ClientConn {
+	lis := bufconn.Listen(1024 * 1024)
+	go grpcServer.Serve(lis)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial bufnet: %v", err)
+	}
+	return conn
+}
+
+// TestServer_ServesEveryRPC starts the grpc server on an in-memory listener
+// and calls every rpc declared in auth.proto. ucases are left empty, so calls
+// that reach a handler fail with Internal, but never with Unimplemented.
+func TestServer_ServesEveryRPC(t *testing.T) {
+	grpcServer := NewServer(interface_pkg.CommonDependency{})
+	defer grpcServer.Stop()
+	conn := newBufconnClient(t, grpcServer)
+	defer conn.Close()
+
+	services := auth_grpc.File_auth_proto.Services()
+	if services.Len() == 0 {
+		t.Fatal("no services declared in auth.proto")
+	}
+	for i := 0; i < services.Len(); i++ {
+		service := services.Get(i)
+		methods := service.Methods()
+		for j := 0; j < methods.Len(); j++ {
+			method := methods.Get(j)
+			fullMethod := fmt.Sprintf("/%s/%s", service.FullName(), method.Name())
+			t.Run(fullMethod, func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+
+				req := dynamicpb.NewMessage(method.Input())
+				resp := dynamicpb.NewMessage(method.Output())
+				err := conn.Invoke(ctx, fullMethod, req, resp)
+				if status.Code(err) == codes.Unimplemented {
+					t.Errorf("%s is not served: %v", fullMethod, err)
+				}
+			})
+		}
+	}
+}
+
+// TestServer_RequiresCredentials checks account management can not be called
+// without a service credential.
+func TestServer_RequiresCredentials(t *testing.T) {
+	grpcServer := NewServer(interface_pkg.CommonDependency{})
+	defer grpcServer.Stop()
+	conn := newBufconnClient(t, grpcServer)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := auth_grpc.NewAuthServiceClient(conn)
+	_, err := client.CreateUser(ctx, &auth_grpc.CreateUserReq{Username: "test"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("CreateUser without credentials: got %v, want Unauthenticated", err)
+	}
+	_, err = client.DeleteUser(ctx, &auth_grpc.DeleteUserReq{Uuid: "test"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("DeleteUser without credentials: got %v, want Unauthenticated", err)
+	}
+}
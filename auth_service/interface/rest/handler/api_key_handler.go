@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"auth_service/domain/dto"
+	ucase "auth_service/usecase"
+	"auth_service/utils/http_response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyHandler struct {
+	respWriter  http_response.IHttpResponseWriter
+	apiKeyUcase ucase.IAPIKeyUcase
+}
+
+func NewAPIKeyHandler(respWriter http_response.IHttpResponseWriter, apiKeyUcase ucase.IAPIKeyUcase) APIKeyHandler {
+	return APIKeyHandler{
+		respWriter:  respWriter,
+		apiKeyUcase: apiKeyUcase,
+	}
+}
+
+// Create Service Account
+// @Summary create a user for machine clients, it authenticates with api keys only
+// @Tags API Keys
+// @Success 200 {object} dto.BaseJSONResp{data=dto.CreateServiceAccountRespData}
+// @Router /service-accounts [post]
+// @param payload  body  dto.CreateServiceAccountReq  true "payload"
+// @Security BearerAuth
+func (h *APIKeyHandler) CreateServiceAccount(ctx *gin.Context) {
+	var payload dto.CreateServiceAccountReq
+	err := ctx.ShouldBindJSON(&payload)
+	if err != nil {
+		h.respWriter.HTTPJson(ctx, 400, "invalid payload", err.Error(), nil)
+		return
+	}
+
+	data, err := h.apiKeyUcase.CreateServiceAccount(ctx, payload)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	h.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// Get API Key List
+// @Summary list the api keys of a service account
+// @Tags API Keys
+// @Success 200 {object} dto.BaseJSONResp{data=[]dto.GetAPIKeyListRespDataItem}
+// @Router /service-accounts/{uuid}/api-keys [get]
+// @param uuid  path  string  true "service account uuid"
+// @Security BearerAuth
+func (h *APIKeyHandler) GetAPIKeyList(ctx *gin.Context) {
+	data, err := h.apiKeyUcase.GetAPIKeyList(ctx, ctx.Param("uuid"))
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	h.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// Create API Key
+// @Summary create an api key for a service account, the key is only returned once
+// @Tags API Keys
+// @Success 200 {object} dto.BaseJSONResp{data=dto.CreateAPIKeyRespData}
+// @Router /service-accounts/{uuid}/api-keys [post]
+// @param uuid  path  string  true "service account uuid"
+// @param payload  body  dto.CreateAPIKeyReq  true "payload"
+// @Security BearerAuth
+func (h *APIKeyHandler) CreateAPIKey(ctx *gin.Context) {
+	var payload dto.CreateAPIKeyReq
+	err := ctx.ShouldBindJSON(&payload)
+	if err != nil {
+		h.respWriter.HTTPJson(ctx, 400, "invalid payload", err.Error(), nil)
+		return
+	}
+
+	data, err := h.apiKeyUcase.CreateAPIKey(ctx, ctx.Param("uuid"), payload)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	h.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// Revoke API Key
+// @Summary revoke an api key, other services accept it until their cache expires
+// @Tags API Keys
+// @Success 200 {object} dto.BaseJSONResp{data=dto.RevokeAPIKeyRespData}
+// @Router /api-keys/{api_key_uuid}/revoke [post]
+// @param api_key_uuid  path  string  true "api key uuid"
+// @Security BearerAuth
+func (h *APIKeyHandler) RevokeAPIKey(ctx *gin.Context) {
+	data, err := h.apiKeyUcase.RevokeAPIKey(ctx, ctx.Param("api_key_uuid"))
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	h.respWriter.HTTPJsonOK(ctx, data)
+}
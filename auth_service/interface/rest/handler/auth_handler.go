@@ -3,7 +3,10 @@ package handler
 import (
 	"auth_service/domain/dto"
 	ucase "auth_service/usecase"
+	error_utils "auth_service/utils/error"
+	"auth_service/utils/helper"
 	"auth_service/utils/http_response"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -37,6 +40,9 @@ func (h *AuthHandler) Register(ctx *gin.Context) {
 		return
 	}
 
+	payload.Device = ctx.Request.UserAgent()
+	payload.IP = ctx.ClientIP()
+
 	data, err := h.authUcase.Register(ctx, payload)
 	if err != nil {
 		h.respWriter.HTTPCustomErr(ctx, err)
@@ -60,7 +66,64 @@ func (h *AuthHandler) Login(ctx *gin.Context) {
 		return
 	}
 
+	payload.Device = ctx.Request.UserAgent()
+	payload.IP = ctx.ClientIP()
+
 	data, err := h.authUcase.Login(payload)
+	if err != nil {
+		if customErr, ok := err.(*error_utils.CustomErr); ok {
+			if blocked, ok := customErr.Data.(dto.LoginBlockedRespData); ok {
+				ctx.Header("Retry-After", strconv.Itoa(blocked.RetryAfterSeconds))
+			}
+		}
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	h.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// Login Two Factor
+// @Summary finish a 2fa login with the challenge token and a totp or recovery code
+// @Tags Auth
+// @Success 200 {object} dto.BaseJSONResp{data=dto.LoginTwoFactorRespData}
+// @Router /auth/login/2fa [post]
+// @param payload  body  dto.LoginTwoFactorReq  true "payload"
+func (h *AuthHandler) LoginTwoFactor(ctx *gin.Context) {
+	var payload dto.LoginTwoFactorReq
+	err := ctx.ShouldBindJSON(&payload)
+	if err != nil {
+		h.respWriter.HTTPJson(ctx, 400, "invalid payload", err.Error(), nil)
+		return
+	}
+
+	payload.Device = ctx.Request.UserAgent()
+	payload.IP = ctx.ClientIP()
+
+	data, err := h.authUcase.LoginTwoFactor(payload)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	h.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// Login Two Factor Enroll
+// @Summary get a totp secret during a login that requires 2fa enrollment
+// @Tags Auth
+// @Success 200 {object} dto.BaseJSONResp{data=dto.EnrollTwoFactorRespData}
+// @Router /auth/login/2fa/enroll [post]
+// @param payload  body  dto.LoginTwoFactorEnrollReq  true "payload"
+func (h *AuthHandler) LoginTwoFactorEnroll(ctx *gin.Context) {
+	var payload dto.LoginTwoFactorEnrollReq
+	err := ctx.ShouldBindJSON(&payload)
+	if err != nil {
+		h.respWriter.HTTPJson(ctx, 400, "invalid payload", err.Error(), nil)
+		return
+	}
+
+	data, err := h.authUcase.LoginTwoFactorEnroll(payload)
 	if err != nil {
 		h.respWriter.HTTPCustomErr(ctx, err)
 		return
@@ -82,6 +145,9 @@ func (h *AuthHandler) RefreshToken(ctx *gin.Context) {
 		return
 	}
 
+	payload.Device = ctx.Request.UserAgent()
+	payload.IP = ctx.ClientIP()
+
 	data, err := h.authUcase.RefreshToken(payload)
 	if err != nil {
 		h.respWriter.HTTPCustomErr(ctx, err)
@@ -112,3 +178,102 @@ func (h *AuthHandler) CheckToken(ctx *gin.Context) {
 
 	h.respWriter.HTTPJsonOK(ctx, data)
 }
+
+// Logout
+// @Summary revoke current access token and optionally its refresh token
+// @Tags Auth
+// @Success 200 {object} dto.BaseJSONResp{data=dto.LogoutRespData}
+// @Router /auth/logout [post]
+// @param payload  body  dto.LogoutReq  false "payload"
+// @Security BearerAuth
+func (h *AuthHandler) Logout(ctx *gin.Context) {
+	var payload dto.LogoutReq
+	if ctx.Request.ContentLength > 0 {
+		err := ctx.ShouldBindJSON(&payload)
+		if err != nil {
+			h.respWriter.HTTPJson(ctx, 400, "invalid payload", err.Error(), nil)
+			return
+		}
+	}
+
+	currentUser, err := helper.GetCurrentUserFromGinCtx(ctx)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	data, err := h.authUcase.Logout(*currentUser, payload)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	h.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// Logout All
+// @Summary revoke every access and refresh token of current user
+// @Tags Auth
+// @Success 200 {object} dto.BaseJSONResp{data=dto.LogoutAllRespData}
+// @Router /auth/logout-all [post]
+// @Security BearerAuth
+func (h *AuthHandler) LogoutAll(ctx *gin.Context) {
+	currentUser, err := helper.GetCurrentUserFromGinCtx(ctx)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	data, err := h.authUcase.LogoutAll(*currentUser)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	h.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// Get Session List
+// @Summary list active sessions of current user
+// @Tags Auth
+// @Success 200 {object} dto.BaseJSONResp{data=[]dto.GetSessionListRespDataItem}
+// @Router /auth/sessions [get]
+// @Security BearerAuth
+func (h *AuthHandler) GetSessionList(ctx *gin.Context) {
+	currentUser, err := helper.GetCurrentUserFromGinCtx(ctx)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	data, err := h.authUcase.GetSessionList(*currentUser)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	h.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// Revoke Session
+// @Summary revoke a session of current user and its refresh and access tokens
+// @Tags Auth
+// @Success 200 {object} dto.BaseJSONResp{data=dto.RevokeSessionRespData}
+// @Router /auth/sessions/{session_uuid}/revoke [post]
+// @param session_uuid  path  string  true "session uuid"
+// @Security BearerAuth
+func (h *AuthHandler) RevokeSession(ctx *gin.Context) {
+	currentUser, err := helper.GetCurrentUserFromGinCtx(ctx)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	data, err := h.authUcase.RevokeSession(*currentUser, ctx.Param("session_uuid"))
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	h.respWriter.HTTPJsonOK(ctx, data)
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"auth_service/domain/dto"
+	ucase "auth_service/usecase"
+	"auth_service/utils/helper"
+	"auth_service/utils/http_response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EmailVerificationHandler struct {
+	respWriter             http_response.IHttpResponseWriter
+	emailVerificationUcase ucase.IEmailVerificationUcase
+}
+
+func NewEmailVerificationHandler(respWriter http_response.IHttpResponseWriter, emailVerificationUcase ucase.IEmailVerificationUcase) EmailVerificationHandler {
+	return EmailVerificationHandler{
+		respWriter:             respWriter,
+		emailVerificationUcase: emailVerificationUcase,
+	}
+}
+
+// Verify Email
+// @Summary confirm the email with the verification token sent on registration
+// @Tags Auth
+// @Success 200 {object} dto.BaseJSONResp{data=dto.VerifyEmailRespData}
+// @Router /auth/verify-email [post]
+// @param payload  body  dto.VerifyEmailReq  true "payload"
+func (h *EmailVerificationHandler) VerifyEmail(ctx *gin.Context) {
+	var payload dto.VerifyEmailReq
+	err := ctx.ShouldBindJSON(&payload)
+	if err != nil {
+		h.respWriter.HTTPJson(ctx, 400, "invalid payload", err.Error(), nil)
+		return
+	}
+
+	data, err := h.emailVerificationUcase.VerifyEmail(ctx, payload)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	h.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// Resend Verification Email
+// @Summary mail a new verification token, earlier tokens stop working
+// @Tags Auth
+// @Success 200 {object} dto.BaseJSONResp{data=dto.ResendVerificationEmailRespData}
+// @Router /auth/verify-email/resend [post]
+// @Security BearerAuth
+func (h *EmailVerificationHandler) ResendVerificationEmail(ctx *gin.Context) {
+	currentUser, err := helper.GetCurrentUserFromGinCtx(ctx)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	data, err := h.emailVerificationUcase.ResendVerificationEmail(ctx, *currentUser)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	h.respWriter.HTTPJsonOK(ctx, data)
+}
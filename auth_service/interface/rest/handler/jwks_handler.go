@@ -0,0 +1,37 @@
+package handler
+
+import (
+	ucase "auth_service/usecase"
+	"auth_service/utils/http_response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JWKSHandler struct {
+	respWriter      http_response.IHttpResponseWriter
+	signingKeyUcase ucase.ISigningKeyUcase
+}
+
+func NewJWKSHandler(respWriter http_response.IHttpResponseWriter, signingKeyUcase ucase.ISigningKeyUcase) JWKSHandler {
+	return JWKSHandler{
+		respWriter:      respWriter,
+		signingKeyUcase: signingKeyUcase,
+	}
+}
+
+// Get JWKS
+// @Summary public keys to verify access tokens, served as a plain JWK set (RFC 7517)
+// @Tags Auth
+// @Success 200 {object} dto.GetJWKSRespData
+// @Router /.well-known/jwks.json [get]
+func (h *JWKSHandler) GetJWKS(ctx *gin.Context) {
+	data, err := h.signingKeyUcase.GetJWKS()
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	// not wrapped in the base response, jwks clients expect the bare key set
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(200, data)
+}
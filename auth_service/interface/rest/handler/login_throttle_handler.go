@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"auth_service/domain/dto"
+	ucase "auth_service/usecase"
+	"auth_service/utils/http_response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LoginThrottleHandler struct {
+	respWriter         http_response.IHttpResponseWriter
+	loginThrottleUcase ucase.ILoginThrottleUcase
+}
+
+func NewLoginThrottleHandler(respWriter http_response.IHttpResponseWriter, loginThrottleUcase ucase.ILoginThrottleUcase) LoginThrottleHandler {
+	return LoginThrottleHandler{
+		respWriter:         respWriter,
+		loginThrottleUcase: loginThrottleUcase,
+	}
+}
+
+// Unlock Login
+// @Summary clear the failed logins of an account and/or client ip (admin only)
+// @Tags Auth
+// @Success 200 {object} dto.BaseJSONResp{data=dto.UnlockLoginRespData}
+// @Router /auth/lockouts/unlock [post]
+// @param payload  body  dto.UnlockLoginReq  true "payload"
+// @Security BearerAuth
+func (h *LoginThrottleHandler) Unlock(ctx *gin.Context) {
+	var payload dto.UnlockLoginReq
+	err := ctx.ShouldBindJSON(&payload)
+	if err != nil {
+		h.respWriter.HTTPJson(ctx, 400, "invalid payload", err.Error(), nil)
+		return
+	}
+
+	data, err := h.loginThrottleUcase.Unlock(payload)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	h.respWriter.HTTPJsonOK(ctx, data)
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"auth_service/domain/dto"
+	ucase "auth_service/usecase"
+	"auth_service/utils/http_response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PasswordResetHandler struct {
+	respWriter         http_response.IHttpResponseWriter
+	passwordResetUcase ucase.IPasswordResetUcase
+}
+
+func NewPasswordResetHandler(respWriter http_response.IHttpResponseWriter, passwordResetUcase ucase.IPasswordResetUcase) PasswordResetHandler {
+	return PasswordResetHandler{
+		respWriter:         respWriter,
+		passwordResetUcase: passwordResetUcase,
+	}
+}
+
+// Forgot Password
+// @Summary mail a single use password reset token, responds the same for unknown emails
+// @Tags Auth
+// @Success 200 {object} dto.BaseJSONResp{data=dto.ForgotPasswordRespData}
+// @Router /auth/password/forgot [post]
+// @param payload  body  dto.ForgotPasswordReq  true "payload"
+func (h *PasswordResetHandler) ForgotPassword(ctx *gin.Context) {
+	var payload dto.ForgotPasswordReq
+	err := ctx.ShouldBindJSON(&payload)
+	if err != nil {
+		h.respWriter.HTTPJson(ctx, 400, "invalid payload", err.Error(), nil)
+		return
+	}
+
+	data, err := h.passwordResetUcase.ForgotPassword(ctx, payload)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	h.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// Reset Password
+// @Summary set a new password with a reset token, signs out every session
+// @Tags Auth
+// @Success 200 {object} dto.BaseJSONResp{data=dto.ResetPasswordRespData}
+// @Router /auth/password/reset [post]
+// @param payload  body  dto.ResetPasswordReq  true "payload"
+func (h *PasswordResetHandler) ResetPassword(ctx *gin.Context) {
+	var payload dto.ResetPasswordReq
+	err := ctx.ShouldBindJSON(&payload)
+	if err != nil {
+		h.respWriter.HTTPJson(ctx, 400, "invalid payload", err.Error(), nil)
+		return
+	}
+
+	data, err := h.passwordResetUcase.ResetPassword(ctx, payload)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	h.respWriter.HTTPJsonOK(ctx, data)
+}
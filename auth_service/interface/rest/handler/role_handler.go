@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"auth_service/domain/dto"
+	ucase "auth_service/usecase"
+	"auth_service/utils/http_response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RoleHandler struct {
+	respWriter http_response.IHttpResponseWriter
+	roleUcase  ucase.IRoleUcase
+}
+
+func NewRoleHandler(respWriter http_response.IHttpResponseWriter, roleUcase ucase.IRoleUcase) RoleHandler {
+	return RoleHandler{
+		respWriter: respWriter,
+		roleUcase:  roleUcase,
+	}
+}
+
+// Get Role List
+// @Summary list roles with their permissions
+// @Tags Roles
+// @Success 200 {object} dto.BaseJSONResp{data=[]dto.GetRoleListRespDataItem}
+// @Router /roles [get]
+// @Security BearerAuth
+func (h *RoleHandler) GetRoleList(ctx *gin.Context) {
+	data, err := h.roleUcase.GetRoleList(ctx)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	h.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// Get Permission List
+// @Summary list every permission a role can be granted
+// @Tags Roles
+// @Success 200 {object} dto.BaseJSONResp{data=[]string}
+// @Router /permissions [get]
+// @Security BearerAuth
+func (h *RoleHandler) GetPermissionList(ctx *gin.Context) {
+	h.respWriter.HTTPJsonOK(ctx, h.roleUcase.GetPermissionList(ctx))
+}
+
+// Put Role
+// @Summary create a role or replace its permissions, the admin role can not be changed
+// @Tags Roles
+// @Success 200 {object} dto.BaseJSONResp{data=dto.PutRoleRespData}
+// @Router /roles/{name} [put]
+// @param payload  body  dto.PutRoleReq  true "payload"
+// @Security BearerAuth
+func (h *RoleHandler) PutRole(ctx *gin.Context) {
+	var payload dto.PutRoleReq
+	err := ctx.ShouldBindJSON(&payload)
+	if err != nil {
+		h.respWriter.HTTPJson(ctx, 400, "invalid payload", err.Error(), nil)
+		return
+	}
+
+	data, err := h.roleUcase.PutRole(ctx, ctx.Param("name"), payload)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	h.respWriter.HTTPJsonOK(ctx, data)
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"auth_service/domain/dto"
+	ucase "auth_service/usecase"
+	"auth_service/utils/helper"
+	"auth_service/utils/http_response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TwoFactorHandler struct {
+	respWriter     http_response.IHttpResponseWriter
+	twoFactorUcase ucase.ITwoFactorUcase
+}
+
+func NewTwoFactorHandler(respWriter http_response.IHttpResponseWriter, twoFactorUcase ucase.ITwoFactorUcase) TwoFactorHandler {
+	return TwoFactorHandler{
+		respWriter:     respWriter,
+		twoFactorUcase: twoFactorUcase,
+	}
+}
+
+// Enroll Two Factor
+// @Summary get a new totp secret, 2fa is enabled once a code of it is confirmed
+// @Tags Auth
+// @Success 200 {object} dto.BaseJSONResp{data=dto.EnrollTwoFactorRespData}
+// @Router /auth/2fa/enroll [post]
+// @Security BearerAuth
+func (h *TwoFactorHandler) Enroll(ctx *gin.Context) {
+	currentUser, err := helper.GetCurrentUserFromGinCtx(ctx)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	data, err := h.twoFactorUcase.Enroll(*currentUser)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	h.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// Confirm Two Factor
+// @Summary enable 2fa with a code of the enrolled secret
+// @Tags Auth
+// @Success 200 {object} dto.BaseJSONResp{data=dto.ConfirmTwoFactorRespData}
+// @Router /auth/2fa/confirm [post]
+// @param payload  body  dto.ConfirmTwoFactorReq  true "payload"
+// @Security BearerAuth
+func (h *TwoFactorHandler) Confirm(ctx *gin.Context) {
+	currentUser, err := helper.GetCurrentUserFromGinCtx(ctx)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	var payload dto.ConfirmTwoFactorReq
+	err = ctx.ShouldBindJSON(&payload)
+	if err != nil {
+		h.respWriter.HTTPJson(ctx, 400, "invalid payload", err.Error(), nil)
+		return
+	}
+
+	data, err := h.twoFactorUcase.Confirm(*currentUser, payload)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	h.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// Disable Two Factor
+// @Summary disable 2fa with a totp or recovery code
+// @Tags Auth
+// @Success 200 {object} dto.BaseJSONResp{data=dto.DisableTwoFactorRespData}
+// @Router /auth/2fa/disable [post]
+// @param payload  body  dto.DisableTwoFactorReq  true "payload"
+// @Security BearerAuth
+func (h *TwoFactorHandler) Disable(ctx *gin.Context) {
+	currentUser, err := helper.GetCurrentUserFromGinCtx(ctx)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	var payload dto.DisableTwoFactorReq
+	err = ctx.ShouldBindJSON(&payload)
+	if err != nil {
+		h.respWriter.HTTPJson(ctx, 400, "invalid payload", err.Error(), nil)
+		return
+	}
+
+	data, err := h.twoFactorUcase.Disable(*currentUser, payload)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	h.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// Regenerate Recovery Codes
+// @Summary replace the recovery codes, earlier codes stop working
+// @Tags Auth
+// @Success 200 {object} dto.BaseJSONResp{data=dto.ConfirmTwoFactorRespData}
+// @Router /auth/2fa/recovery-codes [post]
+// @param payload  body  dto.RegenerateRecoveryCodesReq  true "payload"
+// @Security BearerAuth
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(ctx *gin.Context) {
+	currentUser, err := helper.GetCurrentUserFromGinCtx(ctx)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	var payload dto.RegenerateRecoveryCodesReq
+	err = ctx.ShouldBindJSON(&payload)
+	if err != nil {
+		h.respWriter.HTTPJson(ctx, 400, "invalid payload", err.Error(), nil)
+		return
+	}
+
+	data, err := h.twoFactorUcase.RegenerateRecoveryCodes(*currentUser, payload)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	h.respWriter.HTTPJsonOK(ctx, data)
+}
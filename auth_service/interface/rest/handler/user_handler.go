@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"auth_service/domain/dto"
+	ucase "auth_service/usecase"
+	"auth_service/utils/helper"
+	"auth_service/utils/http_response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UserHandler struct {
+	respWriter http_response.IHttpResponseWriter
+	userUcase  ucase.IUserUcase
+}
+
+func NewUserHandler(respWriter http_response.IHttpResponseWriter, userUcase ucase.IUserUcase) UserHandler {
+	return UserHandler{
+		respWriter: respWriter,
+		userUcase:  userUcase,
+	}
+}
+
+// Get User List
+// @Summary list users with search, role filter and pagination (admin only)
+// @Tags Users
+// @Success 200 {object} dto.BaseJSONResp{data=dto.GetUserListRespData}
+// @Router /users [get]
+// @param query  query  dto.GetUserListReq  true "query"
+// @Security BearerAuth
+func (h *UserHandler) GetUserList(ctx *gin.Context) {
+	var queries dto.GetUserListReq
+	err := ctx.ShouldBindQuery(&queries)
+	if err != nil {
+		h.respWriter.HTTPJson(ctx, 400, "invalid query", err.Error(), nil)
+		return
+	}
+
+	data, err := h.userUcase.GetUserList(ctx, queries)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	h.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// Create User
+// @Summary create a user with any role (admin only)
+// @Tags Users
+// @Success 200 {object} dto.BaseJSONResp{data=dto.CreateUserRespData}
+// @Router /users [post]
+// @param payload  body  dto.CreateUserReq  true "payload"
+// @Security BearerAuth
+func (h *UserHandler) CreateUser(ctx *gin.Context) {
+	var payload dto.CreateUserReq
+	err := ctx.ShouldBindJSON(&payload)
+	if err != nil {
+		h.respWriter.HTTPJson(ctx, 400, "invalid payload", err.Error(), nil)
+		return
+	}
+
+	currentUser, err := helper.GetCurrentUserFromGinCtx(ctx)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	data, err := h.userUcase.CreateUser(ctx, ctx, currentUser, payload)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	h.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// Get User
+// @Summary get a user (admin only)
+// @Tags Users
+// @Success 200 {object} dto.BaseJSONResp{data=dto.GetUserByUUIDResp}
+// @Router /users/{uuid} [get]
+// @Security BearerAuth
+func (h *UserHandler) GetUser(ctx *gin.Context) {
+	data, err := h.userUcase.GetByUUID(ctx, ctx, ctx.Param("uuid"))
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	h.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// Update User
+// @Summary update a user, only the given fields change (admin only)
+// @Tags Users
+// @Success 200 {object} dto.BaseJSONResp{data=dto.UpdateUserRespData}
+// @Router /users/{uuid} [patch]
+// @param payload  body  dto.UpdateUserReq  true "payload"
+// @Security BearerAuth
+func (h *UserHandler) UpdateUser(ctx *gin.Context) {
+	var payload dto.UpdateUserReq
+	err := ctx.ShouldBindJSON(&payload)
+	if err != nil {
+		h.respWriter.HTTPJson(ctx, 400, "invalid payload", err.Error(), nil)
+		return
+	}
+
+	currentUser, err := helper.GetCurrentUserFromGinCtx(ctx)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	data, err := h.userUcase.UpdateUser(ctx, ctx, currentUser, ctx.Param("uuid"), payload)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	h.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// Delete User
+// @Summary delete a user (admin only)
+// @Tags Users
+// @Success 200 {object} dto.BaseJSONResp{data=dto.DeleteUserRespData}
+// @Router /users/{uuid} [delete]
+// @Security BearerAuth
+func (h *UserHandler) DeleteUser(ctx *gin.Context) {
+	data, err := h.userUcase.DeleteUser(ctx, ctx, ctx.Param("uuid"))
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	h.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// Get Me
+// @Summary get the current user
+// @Tags Users
+// @Success 200 {object} dto.BaseJSONResp{data=dto.GetMeRespData}
+// @Router /me [get]
+// @Security BearerAuth
+func (h *UserHandler) GetMe(ctx *gin.Context) {
+	currentUser, err := helper.GetCurrentUserFromGinCtx(ctx)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	data, err := h.userUcase.GetMe(ctx, *currentUser)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	h.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// Update Me
+// @Summary change the email or password of the current user, requires the current password
+// @Tags Users
+// @Success 200 {object} dto.BaseJSONResp{data=dto.GetMeRespData}
+// @Router /me [patch]
+// @param payload  body  dto.UpdateMeReq  true "payload"
+// @Security BearerAuth
+func (h *UserHandler) UpdateMe(ctx *gin.Context) {
+	currentUser, err := helper.GetCurrentUserFromGinCtx(ctx)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	var payload dto.UpdateMeReq
+	err = ctx.ShouldBindJSON(&payload)
+	if err != nil {
+		h.respWriter.HTTPJson(ctx, 400, "invalid payload", err.Error(), nil)
+		return
+	}
+
+	data, err := h.userUcase.UpdateMe(ctx, *currentUser, payload)
+	if err != nil {
+		h.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	h.respWriter.HTTPJsonOK(ctx, data)
+}
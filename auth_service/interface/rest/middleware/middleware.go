@@ -0,0 +1,160 @@
+package rest_middleware
+
+import (
+	"auth_service/domain/dto"
+	ucase "auth_service/usecase"
+	"auth_service/utils/http_response"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AuthMiddleware checks the bearer token through the auth ucase, so revoked
+// tokens are rejected as well. service accounts send an X-API-Key header
+// instead.
+func AuthMiddleware(respWriter http_response.IHttpResponseWriter, authUcase ucase.IAuthUcase, apiKeyUcase ucase.IAPIKeyUcase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey := c.GetHeader("X-API-Key")
+		if apiKey != "" {
+			apiKeyData, err := apiKeyUcase.ValidateAPIKey(c, dto.ValidateAPIKeyReq{APIKey: apiKey})
+			if err != nil {
+				respWriter.HTTPCustomErr(c, err)
+				c.Abort()
+				return
+			}
+
+			c.Set("currentUser", dto.CurrentUser{
+				UUID:        apiKeyData.UUID,
+				Username:    apiKeyData.Username,
+				Role:        apiKeyData.Role,
+				Email:       apiKeyData.Email,
+				Permissions: apiKeyData.Permissions,
+				APIKeyUUID:  apiKeyData.APIKeyUUID,
+			})
+			c.Next()
+			return
+		}
+
+		token := c.GetHeader("Authorization")
+		if token == "" || !strings.HasPrefix(token, "Bearer ") {
+			respWriter.HTTPJson(
+				c, 401, "unauthorized", "invalid token", nil,
+			)
+			c.Abort()
+			return
+		}
+
+		token = strings.TrimPrefix(token, "Bearer ")
+
+		claims, err := authUcase.CheckToken(dto.CheckTokenReq{AccessToken: token})
+		if err != nil {
+			respWriter.HTTPCustomErr(c, err)
+			c.Abort()
+			return
+		}
+
+		c.Set("currentUser", dto.CurrentUser{
+			UUID:        claims.UUID,
+			Username:    claims.Username,
+			Role:        claims.Role,
+			Email:       claims.Email,
+			Permissions: claims.Permissions,
+			TokenID:     claims.TokenID,
+			SessionID:   claims.SessionID,
+			IssuedAt:    claims.IssuedAt,
+			ExpiredAt:   claims.ExpiredAt,
+			AccessToken: token,
+		})
+		c.Next()
+	}
+}
+
+// SessionOnlyMiddleware must run after AuthMiddleware, it refuses api keys on
+// routes that act on the login session or the credentials of a person.
+func SessionOnlyMiddleware(respWriter http_response.IHttpResponseWriter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentUserRaw, ok := c.Get("currentUser")
+		if !ok {
+			respWriter.HTTPJson(
+				c, 500, "internal service error", "current user not found", nil,
+			)
+			c.Abort()
+			return
+		}
+
+		currentUser, ok := currentUserRaw.(dto.CurrentUser)
+		if !ok {
+			respWriter.HTTPJson(
+				c, 500, "internal service error", "current user missmatched", nil,
+			)
+			c.Abort()
+			return
+		}
+
+		if currentUser.APIKeyUUID != "" {
+			respWriter.HTTPJson(
+				c, 403, "forbidden", "not available with an api key", nil,
+			)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequirePermission must run after AuthMiddleware, the current user needs
+// every given permission.
+func RequirePermission(respWriter http_response.IHttpResponseWriter, permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentUserRaw, ok := c.Get("currentUser")
+		if !ok {
+			respWriter.HTTPJson(
+				c, 500, "internal service error", "current user not found", nil,
+			)
+			c.Abort()
+			return
+		}
+
+		currentUser, ok := currentUserRaw.(dto.CurrentUser)
+		if !ok {
+			respWriter.HTTPJson(
+				c, 500, "internal service error", "current user missmatched", nil,
+			)
+			c.Abort()
+			return
+		}
+
+		for _, permission := range permissions {
+			if !currentUser.HasPermission(permission) {
+				respWriter.HTTPJson(
+					c, 403, "forbidden", "missing permission: "+permission, nil,
+				)
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// requestIDMaxLength bounds request ids sent by clients, they end up in every
+// log line and grpc call.
+const requestIDMaxLength = 64
+
+// RequestIDMiddleware keeps the X-Request-ID of the client or generates one.
+// it is returned in the response and forwarded on grpc calls.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader("X-Request-ID")
+		if requestID == "" || len(requestID) > requestIDMaxLength {
+			requestID = uuid.New().String()
+		}
+
+		c.Set("requestID", requestID)
+		c.Header("X-Request-ID", requestID)
+		c.Next()
+	}
+}
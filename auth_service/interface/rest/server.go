@@ -3,8 +3,10 @@ package rest
 import (
 	"auth_service/config"
 	"auth_service/domain/dto"
+	"auth_service/domain/model"
 	interface_pkg "auth_service/interface"
 	"auth_service/interface/rest/handler"
+	rest_middleware "auth_service/interface/rest/middleware"
 	"auth_service/utils/http_response"
 	"fmt"
 
@@ -21,6 +23,11 @@ var logger = logging.MustGetLogger("rest")
 func SetupServer(commonDependencies interface_pkg.CommonDependency) {
 	// logger.Debug(1)
 	router := gin.Default()
+	// the client ip limits logins, only trust X-Forwarded-For from known proxies
+	if err := router.SetTrustedProxies(config.Envs.TRUSTED_PROXIES); err != nil {
+		logger.Fatalf("invalid TRUSTED_PROXIES: %v", err)
+	}
+	router.Use(rest_middleware.RequestIDMiddleware())
 
 	// logger.Debug(2)
 
@@ -29,6 +36,18 @@ func SetupServer(commonDependencies interface_pkg.CommonDependency) {
 	// handlers
 	authHandler := handler.NewAuthHandler(responseWriter, commonDependencies.AuthUcase)
 	_ = authHandler
+	jwksHandler := handler.NewJWKSHandler(responseWriter, commonDependencies.SigningKeyUcase)
+	passwordResetHandler := handler.NewPasswordResetHandler(responseWriter, commonDependencies.PasswordResetUcase)
+	emailVerificationHandler := handler.NewEmailVerificationHandler(responseWriter, commonDependencies.EmailVerificationUcase)
+	twoFactorHandler := handler.NewTwoFactorHandler(responseWriter, commonDependencies.TwoFactorUcase)
+	loginThrottleHandler := handler.NewLoginThrottleHandler(responseWriter, commonDependencies.LoginThrottleUcase)
+	userHandler := handler.NewUserHandler(responseWriter, commonDependencies.UserUcase)
+	roleHandler := handler.NewRoleHandler(responseWriter, commonDependencies.RoleUcase)
+	apiKeyHandler := handler.NewAPIKeyHandler(responseWriter, commonDependencies.APIKeyUcase)
+
+	// middlewares
+	authMiddleware := rest_middleware.AuthMiddleware(responseWriter, commonDependencies.AuthUcase, commonDependencies.APIKeyUcase)
+	sessionOnlyMiddleware := rest_middleware.SessionOnlyMiddleware(responseWriter)
 
 	// register routes
 	router.GET("/ping", func(c *gin.Context) {
@@ -37,10 +56,50 @@ func SetupServer(commonDependencies interface_pkg.CommonDependency) {
 			Message: "pong",
 		})
 	})
+	router.GET("/.well-known/jwks.json", jwksHandler.GetJWKS)
 	router.POST("/auth/register", authHandler.Register)
 	router.POST("/auth/login", authHandler.Login)
+	router.POST("/auth/login/2fa", authHandler.LoginTwoFactor)
+	router.POST("/auth/login/2fa/enroll", authHandler.LoginTwoFactorEnroll)
 	router.POST("/auth/check-token", authHandler.CheckToken)
 	router.POST("/auth/refresh-token", authHandler.RefreshToken)
+	router.POST("/auth/password/forgot", passwordResetHandler.ForgotPassword)
+	router.POST("/auth/password/reset", passwordResetHandler.ResetPassword)
+	router.POST("/auth/verify-email", emailVerificationHandler.VerifyEmail)
+	router.POST("/auth/verify-email/resend", authMiddleware, sessionOnlyMiddleware, emailVerificationHandler.ResendVerificationEmail)
+	router.POST("/auth/2fa/enroll", authMiddleware, sessionOnlyMiddleware, twoFactorHandler.Enroll)
+	router.POST("/auth/2fa/confirm", authMiddleware, sessionOnlyMiddleware, twoFactorHandler.Confirm)
+	router.POST("/auth/2fa/disable", authMiddleware, sessionOnlyMiddleware, twoFactorHandler.Disable)
+	router.POST("/auth/2fa/recovery-codes", authMiddleware, sessionOnlyMiddleware, twoFactorHandler.RegenerateRecoveryCodes)
+	router.POST("/auth/lockouts/unlock", authMiddleware, rest_middleware.RequirePermission(responseWriter, model.PermissionLoginUnlock), loginThrottleHandler.Unlock)
+	router.POST("/auth/logout", authMiddleware, sessionOnlyMiddleware, authHandler.Logout)
+	router.POST("/auth/logout-all", authMiddleware, sessionOnlyMiddleware, authHandler.LogoutAll)
+	router.GET("/auth/sessions", authMiddleware, sessionOnlyMiddleware, authHandler.GetSessionList)
+	router.POST("/auth/sessions/:session_uuid/revoke", authMiddleware, sessionOnlyMiddleware, authHandler.RevokeSession)
+
+	router.GET("/me", authMiddleware, userHandler.GetMe)
+	router.PATCH("/me", authMiddleware, sessionOnlyMiddleware, userHandler.UpdateMe)
+
+	requireUserRead := rest_middleware.RequirePermission(responseWriter, model.PermissionUserRead)
+	requireUserManage := rest_middleware.RequirePermission(responseWriter, model.PermissionUserManage)
+	userRouter := router.Group("/users").Use(authMiddleware)
+	userRouter.GET("", requireUserRead, userHandler.GetUserList)
+	userRouter.POST("", requireUserManage, userHandler.CreateUser)
+	userRouter.GET("/:uuid", requireUserRead, userHandler.GetUser)
+	userRouter.PATCH("/:uuid", requireUserManage, userHandler.UpdateUser)
+	userRouter.DELETE("/:uuid", requireUserManage, userHandler.DeleteUser)
+
+	requireRoleManage := rest_middleware.RequirePermission(responseWriter, model.PermissionRoleManage)
+	router.GET("/permissions", authMiddleware, requireRoleManage, roleHandler.GetPermissionList)
+	router.GET("/roles", authMiddleware, requireRoleManage, roleHandler.GetRoleList)
+	router.PUT("/roles/:name", authMiddleware, requireRoleManage, roleHandler.PutRole)
+
+	// api keys can not be used to create more keys
+	requireAPIKeyManage := rest_middleware.RequirePermission(responseWriter, model.PermissionAPIKeyManage)
+	router.POST("/service-accounts", authMiddleware, sessionOnlyMiddleware, requireAPIKeyManage, apiKeyHandler.CreateServiceAccount)
+	router.GET("/service-accounts/:uuid/api-keys", authMiddleware, sessionOnlyMiddleware, requireAPIKeyManage, apiKeyHandler.GetAPIKeyList)
+	router.POST("/service-accounts/:uuid/api-keys", authMiddleware, sessionOnlyMiddleware, requireAPIKeyManage, apiKeyHandler.CreateAPIKey)
+	router.POST("/api-keys/:api_key_uuid/revoke", authMiddleware, sessionOnlyMiddleware, requireAPIKeyManage, apiKeyHandler.RevokeAPIKey)
 
 	// swagger
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
@@ -2,19 +2,24 @@ package main
 
 import (
 	"auth_service/config"
-	"auth_service/domain/model"
 	interface_pkg "auth_service/interface"
 	"auth_service/interface/grpc"
+	grpc_interceptor "auth_service/interface/grpc/interceptor"
 	"auth_service/interface/rest"
+	"auth_service/migrations"
 	"auth_service/repository"
 	ucase "auth_service/usecase"
 	"auth_service/utils/helper"
+	"auth_service/utils/migrator"
 	seeder_util "auth_service/utils/seeder/user"
+	"context"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/op/go-logging"
+	"gorm.io/gorm"
 )
 
 func init() {
@@ -32,39 +37,77 @@ var logger = logging.MustGetLogger("main")
 func main() {
 	logger.Debugf("Envs: %v", helper.PrettyJson(config.Envs))
 
-	gormDB := config.NewPostgresqlDB()
-	authorGrpcServiceClient := config.NewAuthorGrpcServiceClient()
-
-	// migrations
-	err := gormDB.AutoMigrate(
-		&model.User{},
-		&model.RefreshToken{},
-	)
-	if err != nil {
-		logger.Fatalf("failed to migrate database: %v", err)
+	// the database itself is only created when migrating up
+	for _, arg := range os.Args[1:] {
+		if arg == "--migrate=up" {
+			err := config.EnsurePostgresqlDB()
+			if err != nil {
+				logger.Fatalf("failed to ensure database: %v", err)
+			}
+		}
 	}
+	gormDB := config.NewPostgresqlDB()
+	mailer := config.NewMailer()
 
 	// prepare dependencies
 	// repositories
 	userRepo := repository.NewUserRepo(gormDB)
 	refreshTokenRepo := repository.NewRefreshTokenRepo(gormDB)
+	revokedTokenRepo := repository.NewRevokedTokenRepo(gormDB)
+	sessionRepo := repository.NewSessionRepo(gormDB)
+	securityEventRepo := repository.NewSecurityEventRepo(gormDB)
+	signingKeyRepo := repository.NewSigningKeyRepo(gormDB)
+	passwordResetTokenRepo := repository.NewPasswordResetTokenRepo(gormDB)
+	emailVerificationTokenRepo := repository.NewEmailVerificationTokenRepo(gormDB)
+	recoveryCodeRepo := repository.NewRecoveryCodeRepo(gormDB)
+	loginChallengeRepo := repository.NewLoginChallengeRepo(gormDB)
+	loginAttemptRepo := config.NewLoginAttemptRepo(gormDB)
+	roleRepo := repository.NewRoleRepo(gormDB)
+	apiKeyRepo := repository.NewAPIKeyRepo(gormDB)
+	sagaRepo := repository.NewSagaRepo(gormDB)
+	outboxRepo := repository.NewOutboxRepo(gormDB)
 
 	// ucases
-	authUcase := ucase.NewAuthUcase(userRepo, refreshTokenRepo, authorGrpcServiceClient)
-	userUcase := ucase.NewUserUcase(userRepo)
+	signingKeyUcase := ucase.NewSigningKeyUcase(signingKeyRepo)
+	serviceAuthUcase := ucase.NewServiceAuthUcase(signingKeyUcase)
+
+	// auth_service signs its own service token for the calls to the other services
+	serviceTokenSource := grpc_interceptor.NewServiceTokenSource(func(ctx context.Context) (string, time.Time, error) {
+		return serviceAuthUcase.GenerateServiceToken(config.Envs.SERVICE_NAME)
+	})
+	authorGrpcServiceClient := config.NewAuthorGrpcServiceClient(serviceTokenSource)
+
+	emailVerificationUcase := ucase.NewEmailVerificationUcase(userRepo, emailVerificationTokenRepo, mailer)
+	twoFactorUcase := ucase.NewTwoFactorUcase(userRepo, recoveryCodeRepo, securityEventRepo)
+	loginThrottleUcase := ucase.NewLoginThrottleUcase(userRepo, loginAttemptRepo, securityEventRepo)
+	authUcase := ucase.NewAuthUcase(userRepo, refreshTokenRepo, revokedTokenRepo, sessionRepo, securityEventRepo, signingKeyUcase, emailVerificationUcase, twoFactorUcase, loginChallengeRepo, loginThrottleUcase, roleRepo, sagaRepo, authorGrpcServiceClient)
+	userUcase := ucase.NewUserUcase(userRepo, roleRepo, refreshTokenRepo, sessionRepo, securityEventRepo, emailVerificationUcase)
+	roleUcase := ucase.NewRoleUcase(roleRepo)
+	apiKeyUcase := ucase.NewAPIKeyUcase(userRepo, roleRepo, apiKeyRepo, securityEventRepo)
+	reconcileUcase := ucase.NewReconcileUcase(userRepo, sagaRepo, authorGrpcServiceClient)
+	passwordResetUcase := ucase.NewPasswordResetUcase(userRepo, refreshTokenRepo, sessionRepo, securityEventRepo, passwordResetTokenRepo, mailer)
 
 	dependencies := interface_pkg.CommonDependency{
-		AuthUcase: authUcase,
-		UserUcase: userUcase,
+		AuthUcase:              authUcase,
+		UserUcase:              userUcase,
+		SigningKeyUcase:        signingKeyUcase,
+		PasswordResetUcase:     passwordResetUcase,
+		EmailVerificationUcase: emailVerificationUcase,
+		TwoFactorUcase:         twoFactorUcase,
+		LoginThrottleUcase:     loginThrottleUcase,
+		RoleUcase:              roleUcase,
+		APIKeyUcase:            apiKeyUcase,
+		ServiceAuthUcase:       serviceAuthUcase,
 	}
 
 	args := os.Args
 	if len(args) == 1 { // run as a rest server
+		startOutboxRelay(outboxRepo)
 		logger.Info("starting rest server...")
 		rest.SetupServer(dependencies)
 	} else if len(args) > 1 {
 		validArgVariables := []string{"server"}
-		validPreRunArgVariables := []string{"seed"}
+		validPreRunArgVariables := []string{"seed", "migrate", "jwt-key", "reconcile"}
 
 		// validate args
 		variables := validArgVariables
@@ -114,9 +157,11 @@ func main() {
 
 				switch value {
 				case "rest":
+					startOutboxRelay(outboxRepo)
 					logger.Info("starting rest server...")
 					rest.SetupServer(dependencies)
 				case "grpc":
+					startOutboxRelay(outboxRepo)
 					logger.Info("starting grpc server...")
 					grpc.SetupServer(dependencies)
 				default:
@@ -128,12 +173,92 @@ func main() {
 
 				switch value {
 				case "user":
-					err = seeder_util.SeedUser(userRepo, authorGrpcServiceClient)
+					err := seeder_util.SeedUser(userRepo, authorGrpcServiceClient)
 					if err != nil {
 						logger.Fatalf("failed to seed user: %v", err)
 					}
 				}
+			} else if strings.Contains(arg, fmt.Sprintf("--%s=", "jwt-key")) {
+				value := strings.Split(arg, "=")[1]
+
+				switch value {
+				case "rotate":
+					data, err := signingKeyUcase.RotateSigningKey()
+					if err != nil {
+						logger.Fatalf("failed to rotate signing key: %v", err)
+					}
+					logger.Infof("signing key rotated, kid: %s", data.KID)
+				default:
+					logger.Fatalf("invalid argument: %s", arg)
+				}
+			} else if strings.Contains(arg, fmt.Sprintf("--%s=", "reconcile")) {
+				value := strings.Split(arg, "=")[1]
+
+				switch value {
+				case "users":
+					data, err := reconcileUcase.ReconcileUsers(context.Background())
+					if err != nil {
+						logger.Fatalf("failed to reconcile users: %v", err)
+					}
+					for _, item := range data.Items {
+						logger.Infof("%s %s: %s, %s", item.Kind, item.UUID, item.Action, item.Detail)
+					}
+					logger.Infof("users reconciled, %d items", len(data.Items))
+				default:
+					logger.Fatalf("invalid argument: %s", arg)
+				}
+			} else if strings.Contains(arg, fmt.Sprintf("--%s=", "migrate")) {
+				value := strings.Split(arg, "=")[1]
+				runMigration(gormDB, value)
+			}
+		}
+	}
+}
+
+// startOutboxRelay publishes the outbox events in the background while the
+// server runs. the relays of several instances take turns through a lock.
+func startOutboxRelay(outboxRepo repository.IOutboxRepo) {
+	go func() {
+		// blocks until the broker is reachable
+		broker := config.NewEventBroker(context.Background())
+		if broker == nil {
+			logger.Warning("no event broker, domain events stay in the outbox")
+			return
+		}
+		outboxUcase := ucase.NewOutboxUcase(outboxRepo, broker)
+		outboxUcase.RunRelay(context.Background())
+	}()
+}
+
+// runMigration applies, rolls back or lists the versioned sql migrations in
+// ./migrations.
+func runMigration(gormDB *gorm.DB, value string) {
+	switch value {
+	case "up":
+		logger.Info("applying migrations...")
+		err := migrator.Up(gormDB, migrations.FS)
+		if err != nil {
+			logger.Fatalf("failed to apply migrations: %v", err)
+		}
+	case "down":
+		logger.Info("rolling back latest migration...")
+		err := migrator.Down(gormDB, migrations.FS)
+		if err != nil {
+			logger.Fatalf("failed to roll back migration: %v", err)
+		}
+	case "status":
+		statuses, err := migrator.Status(gormDB, migrations.FS)
+		if err != nil {
+			logger.Fatalf("failed to get migration status: %v", err)
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
 			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, appliedAt)
 		}
+	default:
+		logger.Fatalf("invalid migrate argument: %s", value)
 	}
 }
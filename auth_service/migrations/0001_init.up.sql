@@ -0,0 +1,27 @@
+-- baseline schema, previously created by gorm AutoMigrate. IF NOT EXISTS keeps
+-- it safe to apply on databases that were already auto migrated.
+CREATE TABLE IF NOT EXISTS users (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    uuid uuid NOT NULL CONSTRAINT uni_users_uuid UNIQUE,
+    username text NOT NULL CONSTRAINT uni_users_username UNIQUE,
+    password text NOT NULL,
+    email text,
+    role text
+);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    token text NOT NULL CONSTRAINT uni_refresh_tokens_token UNIQUE,
+    user_uuid uuid NOT NULL CONSTRAINT fk_users_refresh_tokens REFERENCES users (uuid) ON DELETE CASCADE,
+    used_at timestamptz,
+    expired_at timestamptz,
+    invalid boolean
+);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_deleted_at ON refresh_tokens (deleted_at);
@@ -0,0 +1,14 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tokens_valid_after timestamptz;
+
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    token_id text NOT NULL CONSTRAINT uni_revoked_tokens_token_id UNIQUE,
+    user_uuid uuid NOT NULL,
+    expired_at timestamptz NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_deleted_at ON revoked_tokens (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_user_uuid ON revoked_tokens (user_uuid);
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expired_at ON revoked_tokens (expired_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS security_events;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS session_uuid;
+DROP TABLE IF EXISTS sessions;
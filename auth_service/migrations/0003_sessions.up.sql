@@ -0,0 +1,39 @@
+CREATE TABLE IF NOT EXISTS sessions (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    uuid uuid NOT NULL CONSTRAINT uni_sessions_uuid UNIQUE,
+    user_uuid uuid NOT NULL CONSTRAINT fk_sessions_user REFERENCES users (uuid) ON DELETE CASCADE,
+    device text,
+    ip varchar(45),
+    last_used_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expired_at timestamptz NOT NULL,
+    revoked_at timestamptz,
+    revoked_reason varchar(50)
+);
+CREATE INDEX IF NOT EXISTS idx_sessions_deleted_at ON sessions (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_sessions_user_uuid ON sessions (user_uuid);
+
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS session_uuid uuid
+    CONSTRAINT fk_sessions_refresh_tokens REFERENCES sessions (uuid) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_session_uuid ON refresh_tokens (session_uuid);
+
+-- tokens issued before sessions can not be rotated, users log in again
+UPDATE refresh_tokens SET invalid = true WHERE session_uuid IS NULL;
+
+CREATE TABLE IF NOT EXISTS security_events (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    uuid uuid NOT NULL CONSTRAINT uni_security_events_uuid UNIQUE,
+    user_uuid uuid NOT NULL,
+    session_uuid uuid,
+    type varchar(50) NOT NULL,
+    ip varchar(45),
+    device text,
+    detail text
+);
+CREATE INDEX IF NOT EXISTS idx_security_events_deleted_at ON security_events (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_security_events_user_uuid ON security_events (user_uuid);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS signing_keys (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    kid varchar(64) NOT NULL CONSTRAINT uni_signing_keys_kid UNIQUE,
+    algorithm varchar(10) NOT NULL,
+    private_key text NOT NULL,
+    public_key text NOT NULL,
+    retired_at timestamptz,
+    expired_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_signing_keys_deleted_at ON signing_keys (deleted_at);
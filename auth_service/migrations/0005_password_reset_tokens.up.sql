@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    uuid uuid NOT NULL CONSTRAINT uni_password_reset_tokens_uuid UNIQUE,
+    user_uuid uuid NOT NULL CONSTRAINT fk_users_password_reset_tokens REFERENCES users (uuid) ON DELETE CASCADE,
+    token_hash varchar(64) NOT NULL CONSTRAINT uni_password_reset_tokens_token_hash UNIQUE,
+    expired_at timestamptz NOT NULL,
+    used_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_deleted_at ON password_reset_tokens (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user_uuid ON password_reset_tokens (user_uuid);
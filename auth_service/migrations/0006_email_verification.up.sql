@@ -0,0 +1,19 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at timestamptz;
+
+-- users registered before verification existed keep borrowing
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS email_verification_tokens (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    uuid uuid NOT NULL CONSTRAINT uni_email_verification_tokens_uuid UNIQUE,
+    user_uuid uuid NOT NULL CONSTRAINT fk_users_email_verification_tokens REFERENCES users (uuid) ON DELETE CASCADE,
+    email text NOT NULL,
+    token_hash varchar(64) NOT NULL CONSTRAINT uni_email_verification_tokens_token_hash UNIQUE,
+    expired_at timestamptz NOT NULL,
+    used_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_email_verification_tokens_deleted_at ON email_verification_tokens (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_email_verification_tokens_user_uuid ON email_verification_tokens (user_uuid);
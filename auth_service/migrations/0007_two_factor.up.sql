@@ -0,0 +1,32 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_secret text;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_enabled_at timestamptz;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_last_used_step bigint NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    user_uuid uuid NOT NULL CONSTRAINT fk_users_recovery_codes REFERENCES users (uuid) ON DELETE CASCADE,
+    code_hash varchar(64) NOT NULL,
+    used_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_recovery_codes_deleted_at ON recovery_codes (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_recovery_codes_user_uuid ON recovery_codes (user_uuid);
+
+CREATE TABLE IF NOT EXISTS login_challenges (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    uuid uuid NOT NULL CONSTRAINT uni_login_challenges_uuid UNIQUE,
+    user_uuid uuid NOT NULL CONSTRAINT fk_users_login_challenges REFERENCES users (uuid) ON DELETE CASCADE,
+    token_hash varchar(64) NOT NULL CONSTRAINT uni_login_challenges_token_hash UNIQUE,
+    device text,
+    ip varchar(45),
+    attempts integer NOT NULL DEFAULT 0,
+    expired_at timestamptz NOT NULL,
+    used_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_login_challenges_deleted_at ON login_challenges (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_login_challenges_user_uuid ON login_challenges (user_uuid);
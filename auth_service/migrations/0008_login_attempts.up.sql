@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS login_attempts (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    key varchar(100) NOT NULL CONSTRAINT uni_login_attempts_key UNIQUE,
+    failures integer NOT NULL DEFAULT 0,
+    last_failed_at timestamptz NOT NULL,
+    blocked_until timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_deleted_at ON login_attempts (deleted_at);
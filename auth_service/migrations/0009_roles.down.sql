@@ -0,0 +1,5 @@
+ALTER TABLE users DROP CONSTRAINT IF EXISTS fk_users_role;
+ALTER TABLE users ALTER COLUMN role DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN role DROP NOT NULL;
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS roles;
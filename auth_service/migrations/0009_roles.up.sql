@@ -0,0 +1,60 @@
+CREATE TABLE IF NOT EXISTS roles (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    name varchar(50) NOT NULL CONSTRAINT uni_roles_name UNIQUE,
+    description text
+);
+CREATE INDEX IF NOT EXISTS idx_roles_deleted_at ON roles (deleted_at);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    role_name varchar(50) NOT NULL CONSTRAINT fk_roles_permissions REFERENCES roles (name) ON DELETE CASCADE ON UPDATE CASCADE,
+    permission varchar(100) NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_role_permissions_deleted_at ON role_permissions (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_role_permissions_role_name_permission ON role_permissions (role_name, permission);
+
+INSERT INTO roles (created_at, updated_at, name, description) VALUES
+    (now(), now(), 'admin', 'every permission'),
+    (now(), now(), 'librarian', 'runs the library desk: copies, borrows, holds, fines and categories'),
+    (now(), now(), 'user', 'borrows books')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (created_at, updated_at, role_name, permission)
+SELECT now(), now(), seed.role_name, seed.permission
+FROM (VALUES
+    ('admin', 'user:read'),
+    ('admin', 'user:manage'),
+    ('admin', 'role:manage'),
+    ('admin', 'login:unlock'),
+    ('admin', 'author:create'),
+    ('admin', 'author:update'),
+    ('admin', 'author:delete'),
+    ('admin', 'copy:manage'),
+    ('admin', 'borrow:manage'),
+    ('admin', 'hold:manage'),
+    ('admin', 'fine:manage'),
+    ('admin', 'category:create'),
+    ('admin', 'category:update'),
+    ('admin', 'category:delete'),
+    ('librarian', 'user:read'),
+    ('librarian', 'copy:manage'),
+    ('librarian', 'borrow:manage'),
+    ('librarian', 'hold:manage'),
+    ('librarian', 'fine:manage'),
+    ('librarian', 'category:create'),
+    ('librarian', 'category:update'),
+    ('librarian', 'category:delete')
+) AS seed (role_name, permission)
+ON CONFLICT (role_name, permission) DO NOTHING;
+
+-- every user needs an existing role
+UPDATE users SET role = 'user' WHERE role IS NULL OR role NOT IN (SELECT name FROM roles);
+ALTER TABLE users ALTER COLUMN role SET NOT NULL;
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'user';
+ALTER TABLE users ADD CONSTRAINT fk_users_role FOREIGN KEY (role) REFERENCES roles (name) ON UPDATE CASCADE;
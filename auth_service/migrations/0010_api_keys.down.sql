@@ -0,0 +1,4 @@
+DELETE FROM role_permissions WHERE permission = 'api_key:manage';
+DROP TABLE IF EXISTS api_key_scopes;
+DROP TABLE IF EXISTS api_keys;
+ALTER TABLE users DROP COLUMN IF EXISTS is_service_account;
@@ -0,0 +1,33 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_service_account boolean NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    uuid uuid NOT NULL CONSTRAINT uni_api_keys_uuid UNIQUE,
+    user_uuid uuid NOT NULL CONSTRAINT fk_api_keys_user REFERENCES users (uuid) ON DELETE CASCADE,
+    name varchar(100) NOT NULL,
+    prefix varchar(20) NOT NULL,
+    key_hash varchar(64) NOT NULL CONSTRAINT uni_api_keys_key_hash UNIQUE,
+    expired_at timestamptz NOT NULL,
+    last_used_at timestamptz,
+    revoked_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_api_keys_deleted_at ON api_keys (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_uuid ON api_keys (user_uuid);
+
+CREATE TABLE IF NOT EXISTS api_key_scopes (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    api_key_uuid uuid NOT NULL CONSTRAINT fk_api_keys_scopes REFERENCES api_keys (uuid) ON DELETE CASCADE,
+    permission varchar(100) NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_api_key_scopes_deleted_at ON api_key_scopes (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_key_scopes_api_key_uuid_permission ON api_key_scopes (api_key_uuid, permission);
+
+INSERT INTO role_permissions (created_at, updated_at, role_name, permission)
+VALUES (now(), now(), 'admin', 'api_key:manage')
+ON CONFLICT (role_name, permission) DO NOTHING;
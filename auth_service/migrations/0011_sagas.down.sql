@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS sagas;
+DROP INDEX IF EXISTS idx_users_idempotency_key;
+ALTER TABLE users DROP COLUMN IF EXISTS idempotency_key;
@@ -0,0 +1,19 @@
+-- users created over grpc keep the idempotency key of the call, a retried
+-- call returns the same user
+ALTER TABLE users ADD COLUMN IF NOT EXISTS idempotency_key varchar(100);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_idempotency_key ON users (idempotency_key);
+
+CREATE TABLE IF NOT EXISTS sagas (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    uuid uuid NOT NULL CONSTRAINT uni_sagas_uuid UNIQUE,
+    name varchar(50) NOT NULL,
+    status varchar(20) NOT NULL,
+    step varchar(50) NOT NULL DEFAULT '',
+    resource_uuid uuid,
+    error text
+);
+CREATE INDEX IF NOT EXISTS idx_sagas_deleted_at ON sagas (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_sagas_status_updated_at ON sagas (status, updated_at);
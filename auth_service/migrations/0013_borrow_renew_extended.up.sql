@@ -0,0 +1,4 @@
+-- admins kept the extended renewal limit of the hardcoded admin role
+INSERT INTO role_permissions (created_at, updated_at, role_name, permission)
+VALUES (now(), now(), 'admin', 'borrow:renew_extended')
+ON CONFLICT (role_name, permission) DO NOTHING;
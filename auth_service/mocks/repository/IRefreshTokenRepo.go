@@ -6,6 +6,8 @@ import (
 	model "auth_service/domain/model"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // IRefreshTokenRepo is an autogenerated mock type for the IRefreshTokenRepo type
@@ -97,6 +99,24 @@ func (_m *IRefreshTokenRepo) InvalidateManyByUserUUID(userUUID string) error {
 	return r0
 }
 
+// MarkUsed provides a mock function with given fields: refresh_token, now
+func (_m *IRefreshTokenRepo) MarkUsed(refresh_token *model.RefreshToken, now time.Time) error {
+	ret := _m.Called(refresh_token, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkUsed")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.RefreshToken, time.Time) error); ok {
+		r0 = rf(refresh_token, now)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: refresh_token
 func (_m *IRefreshTokenRepo) Update(refresh_token *model.RefreshToken) error {
 	ret := _m.Called(refresh_token)
@@ -3,9 +3,12 @@
 package mocks
 
 import (
+	dto "auth_service/domain/dto"
 	model "auth_service/domain/model"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // IUserRepo is an autogenerated mock type for the IUserRepo type
@@ -13,6 +16,52 @@ type IUserRepo struct {
 	mock.Mock
 }
 
+// ClearIdempotencyKey provides a mock function with given fields: uuid
+func (_m *IUserRepo) ClearIdempotencyKey(uuid string) error {
+	ret := _m.Called(uuid)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClearIdempotencyKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(uuid)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CountGetList provides a mock function with given fields: params
+func (_m *IUserRepo) CountGetList(params dto.UserRepo_GetListParams) (int64, error) {
+	ret := _m.Called(params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountGetList")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(dto.UserRepo_GetListParams) (int64, error)); ok {
+		return rf(params)
+	}
+	if rf, ok := ret.Get(0).(func(dto.UserRepo_GetListParams) int64); ok {
+		r0 = rf(params)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(dto.UserRepo_GetListParams) error); ok {
+		r1 = rf(params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: user
 func (_m *IUserRepo) Create(user *model.User) error {
 	ret := _m.Called(user)
@@ -79,6 +128,36 @@ func (_m *IUserRepo) GetByEmail(email string) (*model.User, error) {
 	return r0, r1
 }
 
+// GetByIdempotencyKey provides a mock function with given fields: key
+func (_m *IUserRepo) GetByIdempotencyKey(key string) (*model.User, error) {
+	ret := _m.Called(key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByIdempotencyKey")
+	}
+
+	var r0 *model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*model.User, error)); ok {
+		return rf(key)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.User); ok {
+		r0 = rf(key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByUUID provides a mock function with given fields: uuid
 func (_m *IUserRepo) GetByUUID(uuid string) (*model.User, error) {
 	ret := _m.Called(uuid)
@@ -139,6 +218,84 @@ func (_m *IUserRepo) GetByUsername(username string) (*model.User, error) {
 	return r0, r1
 }
 
+// GetList provides a mock function with given fields: params
+func (_m *IUserRepo) GetList(params dto.UserRepo_GetListParams) ([]model.User, error) {
+	ret := _m.Called(params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetList")
+	}
+
+	var r0 []model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(dto.UserRepo_GetListParams) ([]model.User, error)); ok {
+		return rf(params)
+	}
+	if rf, ok := ret.Get(0).(func(dto.UserRepo_GetListParams) []model.User); ok {
+		r0 = rf(params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(dto.UserRepo_GetListParams) error); ok {
+		r1 = rf(params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetListWithIdempotencyKey provides a mock function with given fields: createdBefore
+func (_m *IUserRepo) GetListWithIdempotencyKey(createdBefore time.Time) ([]model.User, error) {
+	ret := _m.Called(createdBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetListWithIdempotencyKey")
+	}
+
+	var r0 []model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) ([]model.User, error)); ok {
+		return rf(createdBefore)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) []model.User); ok {
+		r0 = rf(createdBefore)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(createdBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Purge provides a mock function with given fields: uuid
+func (_m *IUserRepo) Purge(uuid string) error {
+	ret := _m.Called(uuid)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Purge")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(uuid)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: user
 func (_m *IUserRepo) Update(user *model.User) error {
 	ret := _m.Called(user)
@@ -157,6 +314,24 @@ func (_m *IUserRepo) Update(user *model.User) error {
 	return r0
 }
 
+// UseTOTPStep provides a mock function with given fields: user, step
+func (_m *IUserRepo) UseTOTPStep(user *model.User, step int64) error {
+	ret := _m.Called(user, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseTOTPStep")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.User, int64) error); ok {
+		r0 = rf(user, step)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewIUserRepo creates a new instance of IUserRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewIUserRepo(t interface {
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"auth_service/domain/model"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type APIKeyRepo struct {
+	db *gorm.DB
+}
+
+type IAPIKeyRepo interface {
+	Create(apiKey *model.APIKey) error
+	GetByUUID(uuid string) (*model.APIKey, error)
+	GetByKeyHash(keyHash string) (*model.APIKey, error)
+	GetListByUserUUID(userUUID string) ([]model.APIKey, error)
+	// TouchLastUsed only writes when last_used_at is older than notAfter, so
+	// busy keys do not update on every request.
+	TouchLastUsed(apiKey *model.APIKey, now time.Time, notAfter time.Time) error
+	Revoke(apiKey *model.APIKey, now time.Time) error
+}
+
+func NewAPIKeyRepo(db *gorm.DB) IAPIKeyRepo {
+	return &APIKeyRepo{db: db}
+}
+
+func (repo *APIKeyRepo) Create(apiKey *model.APIKey) error {
+	err := repo.db.Create(apiKey).Error
+	if err != nil {
+		return errors.New("failed to create: " + err.Error())
+	}
+	return nil
+}
+
+func (repo *APIKeyRepo) GetByUUID(uuid string) (*model.APIKey, error) {
+	var apiKey model.APIKey
+	if err := repo.db.Preload("Scopes").First(&apiKey, "uuid = ?", uuid).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("not found")
+		}
+		return nil, errors.New("failed to get: " + err.Error())
+	}
+	return &apiKey, nil
+}
+
+func (repo *APIKeyRepo) GetByKeyHash(keyHash string) (*model.APIKey, error) {
+	var apiKey model.APIKey
+	if err := repo.db.Preload("Scopes").First(&apiKey, "key_hash = ?", keyHash).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("not found")
+		}
+		return nil, errors.New("failed to get: " + err.Error())
+	}
+	return &apiKey, nil
+}
+
+func (repo *APIKeyRepo) GetListByUserUUID(userUUID string) ([]model.APIKey, error) {
+	var apiKeys []model.APIKey
+	err := repo.db.Preload("Scopes").
+		Where("user_uuid = ?", userUUID).
+		Order("created_at desc").
+		Find(&apiKeys).Error
+	if err != nil {
+		return nil, errors.New("failed to get: " + err.Error())
+	}
+	return apiKeys, nil
+}
+
+func (repo *APIKeyRepo) TouchLastUsed(apiKey *model.APIKey, now time.Time, notAfter time.Time) error {
+	result := repo.db.Model(&model.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", apiKey.ID, notAfter).
+		Update("last_used_at", now)
+	if result.Error != nil {
+		return errors.New("failed to update: " + result.Error.Error())
+	}
+	if result.RowsAffected > 0 {
+		apiKey.LastUsedAt = &now
+	}
+	return nil
+}
+
+// Revoke sets revoked_at only when the key is not revoked yet.
+func (repo *APIKeyRepo) Revoke(apiKey *model.APIKey, now time.Time) error {
+	result := repo.db.Model(&model.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", apiKey.ID).
+		Update("revoked_at", now)
+	if result.Error != nil {
+		return errors.New("failed to update: " + result.Error.Error())
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("already revoked")
+	}
+	apiKey.RevokedAt = &now
+	return nil
+}
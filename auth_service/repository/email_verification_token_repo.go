@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"auth_service/domain/model"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type EmailVerificationTokenRepo struct {
+	db *gorm.DB
+}
+
+type IEmailVerificationTokenRepo interface {
+	Create(token *model.EmailVerificationToken) error
+	GetByTokenHash(tokenHash string) (*model.EmailVerificationToken, error)
+	MarkUsed(token *model.EmailVerificationToken, now time.Time) error
+	InvalidateManyByUserUUID(userUUID string, now time.Time) error
+}
+
+func NewEmailVerificationTokenRepo(db *gorm.DB) IEmailVerificationTokenRepo {
+	return &EmailVerificationTokenRepo{db: db}
+}
+
+func (repo *EmailVerificationTokenRepo) Create(token *model.EmailVerificationToken) error {
+	err := repo.db.Create(token).Error
+	if err != nil {
+		return errors.New("failed to create: " + err.Error())
+	}
+	return nil
+}
+
+func (repo *EmailVerificationTokenRepo) GetByTokenHash(tokenHash string) (*model.EmailVerificationToken, error) {
+	var token model.EmailVerificationToken
+	if err := repo.db.First(&token, "token_hash = ?", tokenHash).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("not found")
+		}
+		return nil, errors.New("failed to get: " + err.Error())
+	}
+	return &token, nil
+}
+
+// MarkUsed sets used_at only when the token is still usable, so the same token
+// can not verify twice.
+func (repo *EmailVerificationTokenRepo) MarkUsed(token *model.EmailVerificationToken, now time.Time) error {
+	result := repo.db.Model(&model.EmailVerificationToken{}).
+		Where("id = ? AND used_at IS NULL AND expired_at > ?", token.ID, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return errors.New("failed to update: " + result.Error.Error())
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("already used")
+	}
+	token.UsedAt = &now
+	return nil
+}
+
+// InvalidateManyByUserUUID marks every unused token of the user as used, so
+// only the latest requested token works.
+func (repo *EmailVerificationTokenRepo) InvalidateManyByUserUUID(userUUID string, now time.Time) error {
+	err := repo.db.Model(&model.EmailVerificationToken{}).
+		Where("user_uuid = ? AND used_at IS NULL", userUUID).
+		Update("used_at", now).Error
+	if err != nil {
+		return errors.New("failed to update: " + err.Error())
+	}
+	return nil
+}
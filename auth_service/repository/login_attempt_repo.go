@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"auth_service/domain/model"
+	"errors"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ILoginAttemptRepo interface {
+	GetByKey(key string) (*model.LoginAttempt, error)
+	// RegisterFailure counts a failed login of the key, the count starts over
+	// when the last failure is older than windowStart.
+	RegisterFailure(key string, now time.Time, windowStart time.Time) (*model.LoginAttempt, error)
+	Block(key string, blockedUntil time.Time) error
+	Reset(key string) error
+}
+
+// LoginAttemptRepo keeps the counters in postgres, shared by every instance
+// of the service.
+type LoginAttemptRepo struct {
+	db *gorm.DB
+}
+
+func NewLoginAttemptRepo(db *gorm.DB) ILoginAttemptRepo {
+	return &LoginAttemptRepo{db: db}
+}
+
+func (repo *LoginAttemptRepo) GetByKey(key string) (*model.LoginAttempt, error) {
+	var attempt model.LoginAttempt
+	if err := repo.db.First(&attempt, "key = ?", key).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("not found")
+		}
+		return nil, errors.New("failed to get: " + err.Error())
+	}
+	return &attempt, nil
+}
+
+// RegisterFailure upserts the counter in one statement, so concurrent
+// failures are all counted.
+func (repo *LoginAttemptRepo) RegisterFailure(key string, now time.Time, windowStart time.Time) (*model.LoginAttempt, error) {
+	var attempt model.LoginAttempt
+	err := repo.db.Raw(`
+		INSERT INTO login_attempts (created_at, updated_at, key, failures, last_failed_at)
+		VALUES (@now, @now, @key, 1, @now)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN login_attempts.last_failed_at < @window_start THEN 1 ELSE login_attempts.failures + 1 END,
+			last_failed_at = @now,
+			updated_at = @now
+		RETURNING *`,
+		map[string]interface{}{
+			"key":          key,
+			"now":          now,
+			"window_start": windowStart,
+		},
+	).Scan(&attempt).Error
+	if err != nil {
+		return nil, errors.New("failed to upsert: " + err.Error())
+	}
+	return &attempt, nil
+}
+
+func (repo *LoginAttemptRepo) Block(key string, blockedUntil time.Time) error {
+	err := repo.db.Model(&model.LoginAttempt{}).
+		Where("key = ?", key).
+		Update("blocked_until", blockedUntil).Error
+	if err != nil {
+		return errors.New("failed to update: " + err.Error())
+	}
+	return nil
+}
+
+func (repo *LoginAttemptRepo) Reset(key string) error {
+	err := repo.db.Unscoped().Where("key = ?", key).Delete(&model.LoginAttempt{}).Error
+	if err != nil {
+		return errors.New("failed to delete: " + err.Error())
+	}
+	return nil
+}
+
+// InMemoryLoginAttemptRepo keeps the counters in the process, they are lost
+// on restart and not shared between instances. counters whose failures left
+// the window and that are not blocked are pruned once per window.
+type InMemoryLoginAttemptRepo struct {
+	mu       sync.Mutex
+	attempts map[string]model.LoginAttempt
+	prunedAt time.Time
+}
+
+func NewInMemoryLoginAttemptRepo() ILoginAttemptRepo {
+	return &InMemoryLoginAttemptRepo{attempts: map[string]model.LoginAttempt{}}
+}
+
+func (repo *InMemoryLoginAttemptRepo) GetByKey(key string) (*model.LoginAttempt, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	attempt, ok := repo.attempts[key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return &attempt, nil
+}
+
+func (repo *InMemoryLoginAttemptRepo) RegisterFailure(key string, now time.Time, windowStart time.Time) (*model.LoginAttempt, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if repo.prunedAt.Before(windowStart) {
+		repo.prune(now, windowStart)
+	}
+
+	attempt, ok := repo.attempts[key]
+	if !ok {
+		attempt = model.LoginAttempt{Key: key}
+		attempt.CreatedAt = now
+	}
+	if attempt.LastFailedAt.Before(windowStart) {
+		attempt.Failures = 0
+	}
+	attempt.Failures++
+	attempt.LastFailedAt = now
+	attempt.UpdatedAt = now
+	repo.attempts[key] = attempt
+
+	return &attempt, nil
+}
+
+func (repo *InMemoryLoginAttemptRepo) Block(key string, blockedUntil time.Time) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	attempt, ok := repo.attempts[key]
+	if !ok {
+		return nil
+	}
+	attempt.BlockedUntil = &blockedUntil
+	repo.attempts[key] = attempt
+	return nil
+}
+
+func (repo *InMemoryLoginAttemptRepo) Reset(key string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	delete(repo.attempts, key)
+	return nil
+}
+
+// prune forgets the counters that would start over on the next failure.
+func (repo *InMemoryLoginAttemptRepo) prune(now time.Time, windowStart time.Time) {
+	for key, attempt := range repo.attempts {
+		if attempt.LastFailedAt.Before(windowStart) && !attempt.IsBlocked(now) {
+			delete(repo.attempts, key)
+		}
+	}
+	repo.prunedAt = now
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemoryLoginAttemptRepo_RegisterFailure(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	window := time.Minute * 15
+
+	tests := []struct {
+		name         string
+		failuresAt   []time.Time
+		wantFailures int
+	}{
+		{
+			name:         "first_failure",
+			failuresAt:   []time.Time{now},
+			wantFailures: 1,
+		},
+		{
+			name:         "failures_within_window_add_up",
+			failuresAt:   []time.Time{now, now.Add(time.Minute), now.Add(time.Minute * 2)},
+			wantFailures: 3,
+		},
+		{
+			name:         "failure_after_window_starts_over",
+			failuresAt:   []time.Time{now, now.Add(time.Minute), now.Add(time.Minute + window + time.Second)},
+			wantFailures: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewInMemoryLoginAttemptRepo()
+
+			for _, failedAt := range tt.failuresAt {
+				_, err := repo.RegisterFailure("ip:127.0.0.1", failedAt, failedAt.Add(-window))
+				assert.NoError(t, err)
+			}
+
+			attempt, err := repo.GetByKey("ip:127.0.0.1")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantFailures, attempt.Failures)
+			assert.Equal(t, tt.failuresAt[len(tt.failuresAt)-1], attempt.LastFailedAt)
+		})
+	}
+}
+
+func TestInMemoryLoginAttemptRepo_BlockAndReset(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := NewInMemoryLoginAttemptRepo()
+	key := "account:6f1c2a5e-3b4d-4c8e-9a7f-1d2e3f4a5b6c"
+
+	_, err := repo.RegisterFailure(key, now, now.Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.NoError(t, repo.Block(key, now.Add(time.Minute)))
+
+	attempt, err := repo.GetByKey(key)
+	assert.NoError(t, err)
+	assert.True(t, attempt.IsBlocked(now))
+	assert.False(t, attempt.IsBlocked(now.Add(time.Minute)))
+
+	assert.NoError(t, repo.Reset(key))
+	_, err = repo.GetByKey(key)
+	assert.EqualError(t, err, "not found")
+}
+
+func TestInMemoryLoginAttemptRepo_PrunesExpiredCounters(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	window := time.Minute * 15
+	repo := NewInMemoryLoginAttemptRepo()
+
+	_, err := repo.RegisterFailure("ip:10.0.0.1", now, now.Add(-window))
+	assert.NoError(t, err)
+	_, err = repo.RegisterFailure("ip:10.0.0.2", now, now.Add(-window))
+	assert.NoError(t, err)
+	assert.NoError(t, repo.Block("ip:10.0.0.2", now.Add(time.Hour)))
+
+	later := now.Add(window + time.Minute)
+	_, err = repo.RegisterFailure("ip:10.0.0.3", later, later.Add(-window))
+	assert.NoError(t, err)
+
+	_, err = repo.GetByKey("ip:10.0.0.1")
+	assert.EqualError(t, err, "not found", "expired counter is pruned")
+	_, err = repo.GetByKey("ip:10.0.0.2")
+	assert.NoError(t, err, "blocked counter is kept")
+	_, err = repo.GetByKey("ip:10.0.0.3")
+	assert.NoError(t, err)
+}
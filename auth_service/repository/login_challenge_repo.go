@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"auth_service/domain/model"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type LoginChallengeRepo struct {
+	db *gorm.DB
+}
+
+type ILoginChallengeRepo interface {
+	Create(challenge *model.LoginChallenge) error
+	GetByTokenHash(tokenHash string) (*model.LoginChallenge, error)
+	IncrementAttempts(challenge *model.LoginChallenge) error
+	MarkUsed(challenge *model.LoginChallenge, now time.Time) error
+}
+
+func NewLoginChallengeRepo(db *gorm.DB) ILoginChallengeRepo {
+	return &LoginChallengeRepo{db: db}
+}
+
+func (repo *LoginChallengeRepo) Create(challenge *model.LoginChallenge) error {
+	err := repo.db.Create(challenge).Error
+	if err != nil {
+		return errors.New("failed to create: " + err.Error())
+	}
+	return nil
+}
+
+func (repo *LoginChallengeRepo) GetByTokenHash(tokenHash string) (*model.LoginChallenge, error) {
+	var challenge model.LoginChallenge
+	if err := repo.db.First(&challenge, "token_hash = ?", tokenHash).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("not found")
+		}
+		return nil, errors.New("failed to get: " + err.Error())
+	}
+	return &challenge, nil
+}
+
+func (repo *LoginChallengeRepo) IncrementAttempts(challenge *model.LoginChallenge) error {
+	err := repo.db.Model(&model.LoginChallenge{}).
+		Where("id = ?", challenge.ID).
+		Update("attempts", gorm.Expr("attempts + 1")).Error
+	if err != nil {
+		return errors.New("failed to update: " + err.Error())
+	}
+	challenge.Attempts++
+	return nil
+}
+
+// MarkUsed sets used_at only when the challenge is still unused, so it can
+// not be exchanged for tokens twice.
+func (repo *LoginChallengeRepo) MarkUsed(challenge *model.LoginChallenge, now time.Time) error {
+	result := repo.db.Model(&model.LoginChallenge{}).
+		Where("id = ? AND used_at IS NULL", challenge.ID).
+		Update("used_at", now)
+	if result.Error != nil {
+		return errors.New("failed to update: " + result.Error.Error())
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("already used")
+	}
+	challenge.UsedAt = &now
+	return nil
+}
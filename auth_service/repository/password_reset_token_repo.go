@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"auth_service/domain/model"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PasswordResetTokenRepo struct {
+	db *gorm.DB
+}
+
+type IPasswordResetTokenRepo interface {
+	Create(token *model.PasswordResetToken) error
+	GetByTokenHash(tokenHash string) (*model.PasswordResetToken, error)
+	MarkUsed(token *model.PasswordResetToken, now time.Time) error
+	InvalidateManyByUserUUID(userUUID string, now time.Time) error
+}
+
+func NewPasswordResetTokenRepo(db *gorm.DB) IPasswordResetTokenRepo {
+	return &PasswordResetTokenRepo{db: db}
+}
+
+func (repo *PasswordResetTokenRepo) Create(token *model.PasswordResetToken) error {
+	err := repo.db.Create(token).Error
+	if err != nil {
+		return errors.New("failed to create: " + err.Error())
+	}
+	return nil
+}
+
+func (repo *PasswordResetTokenRepo) GetByTokenHash(tokenHash string) (*model.PasswordResetToken, error) {
+	var token model.PasswordResetToken
+	if err := repo.db.First(&token, "token_hash = ?", tokenHash).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("not found")
+		}
+		return nil, errors.New("failed to get: " + err.Error())
+	}
+	return &token, nil
+}
+
+// MarkUsed sets used_at only when the token is still usable, so the same token
+// can not reset the password twice.
+func (repo *PasswordResetTokenRepo) MarkUsed(token *model.PasswordResetToken, now time.Time) error {
+	result := repo.db.Model(&model.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL AND expired_at > ?", token.ID, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return errors.New("failed to update: " + result.Error.Error())
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("already used")
+	}
+	token.UsedAt = &now
+	return nil
+}
+
+// InvalidateManyByUserUUID marks every unused token of the user as used, so
+// only the latest requested token works.
+func (repo *PasswordResetTokenRepo) InvalidateManyByUserUUID(userUUID string, now time.Time) error {
+	err := repo.db.Model(&model.PasswordResetToken{}).
+		Where("user_uuid = ? AND used_at IS NULL", userUUID).
+		Update("used_at", now).Error
+	if err != nil {
+		return errors.New("failed to update: " + err.Error())
+	}
+	return nil
+}
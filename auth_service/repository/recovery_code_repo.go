@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"auth_service/domain/model"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RecoveryCodeRepo struct {
+	db *gorm.DB
+}
+
+type IRecoveryCodeRepo interface {
+	ReplaceManyByUserUUID(userUUID string, recoveryCodes []model.RecoveryCode) error
+	DeleteManyByUserUUID(userUUID string) error
+	Use(userUUID string, codeHash string, now time.Time) error
+}
+
+func NewRecoveryCodeRepo(db *gorm.DB) IRecoveryCodeRepo {
+	return &RecoveryCodeRepo{db: db}
+}
+
+// ReplaceManyByUserUUID drops every earlier code of the user, so only the
+// latest generated set works.
+func (repo *RecoveryCodeRepo) ReplaceManyByUserUUID(userUUID string, recoveryCodes []model.RecoveryCode) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Where("user_uuid = ?", userUUID).Delete(&model.RecoveryCode{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(&recoveryCodes).Error
+	})
+	if err != nil {
+		return errors.New("failed to replace: " + err.Error())
+	}
+	return nil
+}
+
+func (repo *RecoveryCodeRepo) DeleteManyByUserUUID(userUUID string) error {
+	err := repo.db.Unscoped().Where("user_uuid = ?", userUUID).Delete(&model.RecoveryCode{}).Error
+	if err != nil {
+		return errors.New("failed to delete: " + err.Error())
+	}
+	return nil
+}
+
+// Use marks the matching unused code as used, returns not found when there
+// is none.
+func (repo *RecoveryCodeRepo) Use(userUUID string, codeHash string, now time.Time) error {
+	result := repo.db.Model(&model.RecoveryCode{}).
+		Where("user_uuid = ? AND code_hash = ? AND used_at IS NULL", userUUID, codeHash).
+		Update("used_at", now)
+	if result.Error != nil {
+		return errors.New("failed to update: " + result.Error.Error())
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("not found")
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"auth_service/domain/model"
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -17,6 +18,7 @@ type IRefreshTokenRepo interface {
 	Update(refresh_token *model.RefreshToken) error
 	Delete(id string) error
 	InvalidateManyByUserUUID(userUUID string) error
+	MarkUsed(refresh_token *model.RefreshToken, now time.Time) error
 }
 
 func NewRefreshTokenRepo(db *gorm.DB) IRefreshTokenRepo {
@@ -75,3 +77,19 @@ func (repo *RefreshTokenRepo) InvalidateManyByUserUUID(userUUID string) error {
 	err := repo.db.Model(&model.RefreshToken{}).Where("invalid = ? AND user_uuid = ?", false, userUUID).Update("invalid", true).Error
 	return err
 }
+
+// MarkUsed sets used_at only when the token is still unused, so concurrent
+// refreshes with the same token can not both succeed.
+func (repo *RefreshTokenRepo) MarkUsed(refresh_token *model.RefreshToken, now time.Time) error {
+	result := repo.db.Model(&model.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL", refresh_token.ID).
+		Update("used_at", now)
+	if result.Error != nil {
+		return errors.New("failed to update: " + result.Error.Error())
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("already used")
+	}
+	refresh_token.UsedAt = &now
+	return nil
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"auth_service/domain/model"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RevokedTokenRepo struct {
+	db *gorm.DB
+}
+
+type IRevokedTokenRepo interface {
+	Create(revokedToken *model.RevokedToken) error
+	IsRevoked(tokenID string) (bool, error)
+	DeleteExpired(now time.Time) error
+}
+
+func NewRevokedTokenRepo(db *gorm.DB) IRevokedTokenRepo {
+	return &RevokedTokenRepo{db: db}
+}
+
+// Create is a no-op when the token is already revoked.
+func (repo *RevokedTokenRepo) Create(revokedToken *model.RevokedToken) error {
+	err := repo.db.Clauses(clause.OnConflict{DoNothing: true}).Create(revokedToken).Error
+	if err != nil {
+		return errors.New("failed to create: " + err.Error())
+	}
+	return nil
+}
+
+func (repo *RevokedTokenRepo) IsRevoked(tokenID string) (bool, error) {
+	var count int64
+	err := repo.db.Model(&model.RevokedToken{}).Where("token_id = ?", tokenID).Count(&count).Error
+	if err != nil {
+		return false, errors.New("failed to count: " + err.Error())
+	}
+	return count > 0, nil
+}
+
+// DeleteExpired removes revocations of tokens that are expired by now and
+// would be rejected anyway.
+func (repo *RevokedTokenRepo) DeleteExpired(now time.Time) error {
+	err := repo.db.Unscoped().Where("expired_at < ?", now).Delete(&model.RevokedToken{}).Error
+	if err != nil {
+		return errors.New("failed to delete: " + err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"auth_service/domain/model"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type RoleRepo struct {
+	db *gorm.DB
+}
+
+type IRoleRepo interface {
+	GetByName(name string) (*model.Role, error)
+	GetList() ([]model.Role, error)
+	// Save creates or updates the role and replaces its permissions.
+	Save(role *model.Role, permissions []string) error
+}
+
+func NewRoleRepo(db *gorm.DB) IRoleRepo {
+	return &RoleRepo{db: db}
+}
+
+func (repo *RoleRepo) GetByName(name string) (*model.Role, error) {
+	var role model.Role
+	if err := repo.db.Preload("Permissions").First(&role, "name = ?", name).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("not found")
+		}
+		return nil, errors.New("failed to get: " + err.Error())
+	}
+	return &role, nil
+}
+
+func (repo *RoleRepo) GetList() ([]model.Role, error) {
+	var roles []model.Role
+	err := repo.db.Preload("Permissions").Order("name asc").Find(&roles).Error
+	if err != nil {
+		return nil, errors.New("failed to get: " + err.Error())
+	}
+	return roles, nil
+}
+
+func (repo *RoleRepo) Save(role *model.Role, permissions []string) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Omit("Permissions").Save(role).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Unscoped().Where("role_name = ?", role.Name).Delete(&model.RolePermission{}).Error
+		if err != nil {
+			return err
+		}
+
+		role.Permissions = []model.RolePermission{}
+		for _, permission := range permissions {
+			role.Permissions = append(role.Permissions, model.RolePermission{
+				RoleName:   role.Name,
+				Permission: permission,
+			})
+		}
+		if len(role.Permissions) == 0 {
+			return nil
+		}
+		return tx.Create(&role.Permissions).Error
+	})
+	if err != nil {
+		return errors.New("failed to save: " + err.Error())
+	}
+	return nil
+}
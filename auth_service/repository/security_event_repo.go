@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"auth_service/domain/model"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type SecurityEventRepo struct {
+	db *gorm.DB
+}
+
+type ISecurityEventRepo interface {
+	Create(securityEvent *model.SecurityEvent) error
+}
+
+func NewSecurityEventRepo(db *gorm.DB) ISecurityEventRepo {
+	return &SecurityEventRepo{db: db}
+}
+
+func (repo *SecurityEventRepo) Create(securityEvent *model.SecurityEvent) error {
+	err := repo.db.Create(securityEvent).Error
+	if err != nil {
+		return errors.New("failed to create: " + err.Error())
+	}
+	return nil
+}
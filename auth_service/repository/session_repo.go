@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"auth_service/domain/model"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SessionRepo struct {
+	db *gorm.DB
+}
+
+type ISessionRepo interface {
+	Create(session *model.Session) error
+	GetByUUID(uuid string) (*model.Session, error)
+	GetActiveListByUserUUID(userUUID string, now time.Time) ([]model.Session, error)
+	Update(session *model.Session) error
+	Revoke(uuid string, reason string, now time.Time) error
+	RevokeManyByUserUUID(userUUID string, reason string, now time.Time) error
+}
+
+func NewSessionRepo(db *gorm.DB) ISessionRepo {
+	return &SessionRepo{db: db}
+}
+
+func (repo *SessionRepo) Create(session *model.Session) error {
+	err := repo.db.Create(session).Error
+	if err != nil {
+		return errors.New("failed to create: " + err.Error())
+	}
+	return nil
+}
+
+func (repo *SessionRepo) GetByUUID(uuid string) (*model.Session, error) {
+	var session model.Session
+	if err := repo.db.First(&session, "uuid = ?", uuid).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("not found")
+		}
+		return nil, errors.New("failed to get: " + err.Error())
+	}
+	return &session, nil
+}
+
+func (repo *SessionRepo) GetActiveListByUserUUID(userUUID string, now time.Time) ([]model.Session, error) {
+	var sessions []model.Session
+	err := repo.db.
+		Where("user_uuid = ? AND revoked_at IS NULL AND expired_at > ?", userUUID, now).
+		Order("last_used_at desc").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, errors.New("failed to get list: " + err.Error())
+	}
+	return sessions, nil
+}
+
+func (repo *SessionRepo) Update(session *model.Session) error {
+	err := repo.db.Save(session).Error
+	if err != nil {
+		return errors.New("failed to update: " + err.Error())
+	}
+	return nil
+}
+
+// Revoke marks the session revoked and invalidates every refresh token of it.
+func (repo *SessionRepo) Revoke(uuid string, reason string, now time.Time) error {
+	return repo.revoke("uuid", uuid, reason, now)
+}
+
+func (repo *SessionRepo) RevokeManyByUserUUID(userUUID string, reason string, now time.Time) error {
+	return repo.revoke("user_uuid", userUUID, reason, now)
+}
+
+func (repo *SessionRepo) revoke(column string, value string, reason string, now time.Time) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var sessionUUIDs []string
+		err := tx.Model(&model.Session{}).
+			Where(fmt.Sprintf("%s = ? AND revoked_at IS NULL", column), value).
+			Pluck("uuid", &sessionUUIDs).Error
+		if err != nil {
+			return err
+		}
+		if len(sessionUUIDs) == 0 {
+			return nil
+		}
+
+		err = tx.Model(&model.Session{}).
+			Where("uuid IN ?", sessionUUIDs).
+			Updates(map[string]interface{}{"revoked_at": now, "revoked_reason": reason}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&model.RefreshToken{}).
+			Where("session_uuid IN ? AND invalid = ?", sessionUUIDs, false).
+			Update("invalid", true).Error
+	})
+	if err != nil {
+		return errors.New("failed to revoke: " + err.Error())
+	}
+	return nil
+}
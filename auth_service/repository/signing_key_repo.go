@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"auth_service/domain/model"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SigningKeyRepo struct {
+	db *gorm.DB
+}
+
+type ISigningKeyRepo interface {
+	GetActive() (*model.SigningKey, error)
+	GetPublishedList(now time.Time) ([]model.SigningKey, error)
+	Rotate(newKey *model.SigningKey, rotateBefore time.Time, retiredExpiredAt time.Time) (*model.SigningKey, error)
+}
+
+func NewSigningKeyRepo(db *gorm.DB) ISigningKeyRepo {
+	return &SigningKeyRepo{db: db}
+}
+
+// GetActive returns the newest key that is not retired.
+func (repo *SigningKeyRepo) GetActive() (*model.SigningKey, error) {
+	var signingKey model.SigningKey
+	err := repo.db.Where("retired_at IS NULL").Order("created_at desc").First(&signingKey).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("not found")
+		}
+		return nil, errors.New("failed to get: " + err.Error())
+	}
+	return &signingKey, nil
+}
+
+func (repo *SigningKeyRepo) GetPublishedList(now time.Time) ([]model.SigningKey, error) {
+	var signingKeys []model.SigningKey
+	err := repo.db.
+		Where("expired_at IS NULL OR expired_at > ?", now).
+		Order("created_at desc").
+		Find(&signingKeys).Error
+	if err != nil {
+		return nil, errors.New("failed to get list: " + err.Error())
+	}
+	return signingKeys, nil
+}
+
+// Rotate stores newKey and retires every other key, unless the active key uses
+// the same algorithm and was created after rotateBefore, then it is kept. the table
+// is locked so concurrent instances do not retire each other's keys. returns
+// the key that is active afterwards.
+func (repo *SigningKeyRepo) Rotate(newKey *model.SigningKey, rotateBefore time.Time, retiredExpiredAt time.Time) (*model.SigningKey, error) {
+	var active *model.SigningKey
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("LOCK TABLE signing_keys IN EXCLUSIVE MODE").Error; err != nil {
+			return err
+		}
+
+		var current model.SigningKey
+		err := tx.Where("retired_at IS NULL").Order("created_at desc").First(&current).Error
+		if err == nil && current.Algorithm == newKey.Algorithm && current.CreatedAt.After(rotateBefore) {
+			active = &current
+			return nil
+		}
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return err
+		}
+
+		if err := tx.Create(newKey).Error; err != nil {
+			return err
+		}
+
+		err = tx.Model(&model.SigningKey{}).
+			Where("kid <> ? AND retired_at IS NULL", newKey.KID).
+			Updates(map[string]interface{}{"retired_at": newKey.CreatedAt, "expired_at": retiredExpiredAt}).Error
+		if err != nil {
+			return err
+		}
+
+		active = newKey
+		return nil
+	})
+	if err != nil {
+		return nil, errors.New("failed to rotate: " + err.Error())
+	}
+	return active, nil
+}
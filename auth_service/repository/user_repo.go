@@ -1,8 +1,12 @@
 package repository
 
 import (
+	"auth_service/domain/dto"
 	"auth_service/domain/model"
+	event_util "auth_service/utils/event"
 	"errors"
+	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -16,8 +20,21 @@ type IUserRepo interface {
 	GetByUUID(uuid string) (*model.User, error)
 	GetByUsername(username string) (*model.User, error)
 	GetByEmail(email string) (*model.User, error)
+	GetByIdempotencyKey(key string) (*model.User, error)
+	// GetListWithIdempotencyKey returns the users created over grpc before
+	// createdBefore whose saga is not known to be complete yet.
+	GetListWithIdempotencyKey(createdBefore time.Time) ([]model.User, error)
+	// ClearIdempotencyKey marks the saga that created the user as complete,
+	// the user is no longer reconciled.
+	ClearIdempotencyKey(uuid string) error
 	Update(user *model.User) error
 	Delete(id string) error
+	// Purge removes the user for good, so a failed registration does not keep
+	// the username or email.
+	Purge(uuid string) error
+	UseTOTPStep(user *model.User, step int64) error
+	GetList(params dto.UserRepo_GetListParams) ([]model.User, error)
+	CountGetList(params dto.UserRepo_GetListParams) (int64, error)
 }
 
 func NewUserRepo(db *gorm.DB) IUserRepo {
@@ -25,7 +42,13 @@ func NewUserRepo(db *gorm.DB) IUserRepo {
 }
 
 func (repo *UserRepo) Create(user *model.User) error {
-	err := repo.db.Create(user).Error
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(user).Error
+		if err != nil {
+			return err
+		}
+		return createOutboxEvent(tx, event_util.TypeUserCreated, user.UUID, user.EventPayload())
+	})
 	if err != nil {
 		return errors.New("failed to create user")
 	}
@@ -65,13 +88,63 @@ func (repo *UserRepo) GetByEmail(email string) (*model.User, error) {
 	return &user, nil
 }
 
+func (repo *UserRepo) GetByIdempotencyKey(key string) (*model.User, error) {
+	var user model.User
+	if err := repo.db.First(&user, "idempotency_key = ?", key).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("not found")
+		}
+		return nil, errors.New("failed to get: " + err.Error())
+	}
+	return &user, nil
+}
+
+func (repo *UserRepo) GetListWithIdempotencyKey(createdBefore time.Time) ([]model.User, error) {
+	var users []model.User
+	err := repo.db.
+		Where("idempotency_key IS NOT NULL AND created_at < ?", createdBefore).
+		Order("id asc").
+		Find(&users).Error
+	if err != nil {
+		return nil, errors.New("failed to get: " + err.Error())
+	}
+	return users, nil
+}
+
+func (repo *UserRepo) ClearIdempotencyKey(uuid string) error {
+	err := repo.db.Model(&model.User{}).
+		Where("uuid = ?", uuid).
+		UpdateColumn("idempotency_key", nil).Error
+	if err != nil {
+		return errors.New("failed to update: " + err.Error())
+	}
+	return nil
+}
+
 func (repo *UserRepo) Update(user *model.User) error {
-	err := repo.db.Save(user).Error
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Save(user).Error
+		if err != nil {
+			return err
+		}
+		return createOutboxEvent(tx, event_util.TypeUserUpdated, user.UUID, user.EventPayload())
+	})
 	return err
 }
 
 func (repo *UserRepo) Delete(id string) error {
-	err := repo.db.Delete(&model.User{}, "id = ?", id).Error
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var user model.User
+		err := tx.First(&user, "uuid = ?", id).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Delete(&user).Error
+		if err != nil {
+			return err
+		}
+		return createOutboxEvent(tx, event_util.TypeUserDeleted, user.UUID, user.EventPayload())
+	})
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return errors.New("not found")
@@ -80,3 +153,95 @@ func (repo *UserRepo) Delete(id string) error {
 	}
 	return err
 }
+
+func (repo *UserRepo) Purge(uuid string) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var user model.User
+		err := tx.Unscoped().First(&user, "uuid = ?", uuid).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Unscoped().Delete(&user).Error
+		if err != nil {
+			return err
+		}
+		// a soft deleted user already had its event
+		if user.DeletedAt.Valid {
+			return nil
+		}
+		return createOutboxEvent(tx, event_util.TypeUserDeleted, user.UUID, user.EventPayload())
+	})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.New("not found")
+		}
+		return errors.New("failed to delete: " + err.Error())
+	}
+	return nil
+}
+
+// UseTOTPStep records the step of an accepted totp code, only when it is newer
+// than the last used one. a code can therefore be used once, even by
+// concurrent logins.
+func (repo *UserRepo) UseTOTPStep(user *model.User, step int64) error {
+	result := repo.db.Model(&model.User{}).
+		Where("uuid = ? AND totp_last_used_step < ?", user.UUID, step).
+		Update("totp_last_used_step", step)
+	if result.Error != nil {
+		return errors.New("failed to update: " + result.Error.Error())
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("already used")
+	}
+	user.TOTPLastUsedStep = step
+	return nil
+}
+
+func (repo *UserRepo) GetList(params dto.UserRepo_GetListParams) ([]model.User, error) {
+	// validate param
+	if params.SortOrder != "asc" && params.SortOrder != "desc" {
+		return nil, fmt.Errorf("invalid sort order")
+	}
+
+	var models []model.User
+
+	tx := repo.filterGetList(repo.db.Model(&models), params)
+
+	if params.Page > 0 && params.Limit > 0 {
+		offset := (params.Page - 1) * params.Limit
+		tx = tx.Offset(offset).Limit(params.Limit)
+	}
+
+	if params.SortOrder != "" && params.SortBy != "" {
+		tx = tx.Order(fmt.Sprintf("%s %s", params.SortBy, params.SortOrder))
+	}
+
+	err := tx.Find(&models).Error
+	if err != nil {
+		return nil, errors.New("failed to get: " + err.Error())
+	}
+
+	return models, nil
+}
+
+func (repo *UserRepo) CountGetList(params dto.UserRepo_GetListParams) (int64, error) {
+	var count int64
+	err := repo.filterGetList(repo.db.Model(&model.User{}), params).Count(&count).Error
+	if err != nil {
+		return 0, errors.New("failed to count: " + err.Error())
+	}
+
+	return count, nil
+}
+
+func (repo *UserRepo) filterGetList(tx *gorm.DB, params dto.UserRepo_GetListParams) *gorm.DB {
+	if params.Role != "" {
+		tx = tx.Where("role = ?", params.Role)
+	}
+
+	if params.Query != "" {
+		tx = tx.Where("username ILIKE ? OR email ILIKE ?", "%"+params.Query+"%", "%"+params.Query+"%")
+	}
+
+	return tx
+}
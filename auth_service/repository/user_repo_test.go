@@ -1,11 +1,13 @@
 package repository
 
 import (
+	"auth_service/domain/dto"
 	"auth_service/domain/model"
 	mocks "auth_service/mocks/repository"
 	"errors"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -22,9 +24,8 @@ func TestUserRepo_Create(t *testing.T) {
 			name: "success_create_user",
 			args: args{
 				&model.User{
-					UUID:     "test-uuid",
+					UUID:     uuid.MustParse("6f1c2a5e-3b4d-4c8e-9a7f-1d2e3f4a5b6c"),
 					Username: "test",
-					Fullname: "test",
 					Email:    "test",
 					Password: "test",
 				},
@@ -72,9 +73,8 @@ func TestUserRepo_GetByUUID(t *testing.T) {
 				uuid: "test-uuid",
 			},
 			want: &model.User{
-				UUID:     "test-uuid",
+				UUID:     uuid.MustParse("6f1c2a5e-3b4d-4c8e-9a7f-1d2e3f4a5b6c"),
 				Username: "test",
-				Fullname: "test",
 				Email:    "test",
 				Password: "test",
 			},
@@ -113,3 +113,18 @@ func TestUserRepo_GetByUUID(t *testing.T) {
 		})
 	}
 }
+
+func TestUserRepo_GetList_InvalidSortOrder(t *testing.T) {
+	// rejected before any query, so no database is needed
+	repo := NewUserRepo(nil)
+
+	got, err := repo.GetList(dto.UserRepo_GetListParams{
+		Page:      1,
+		Limit:     10,
+		SortOrder: "random",
+		SortBy:    "created_at",
+	})
+
+	assert.EqualError(t, err, "invalid sort order")
+	assert.Nil(t, got)
+}
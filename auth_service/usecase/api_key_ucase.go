@@ -0,0 +1,477 @@
+package ucase
+
+import (
+	"auth_service/config"
+	"auth_service/domain/dto"
+	"auth_service/domain/model"
+	"auth_service/repository"
+	bcrypt_util "auth_service/utils/bcrypt"
+	error_utils "auth_service/utils/error"
+	"auth_service/utils/helper"
+	token_util "auth_service/utils/token"
+	validator_util "auth_service/utils/validator/user"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+)
+
+// api keys start with this, so leaked keys are easy to spot
+const apiKeyPrefix = "lk_"
+
+// length of the key start stored in clear to tell keys apart
+const apiKeyDisplayLength = 11
+
+// last_used_at is written at most once per this interval per key
+const apiKeyLastUsedResolution = time.Minute
+
+type APIKeyUcase struct {
+	userRepo          repository.IUserRepo
+	roleRepo          repository.IRoleRepo
+	apiKeyRepo        repository.IAPIKeyRepo
+	securityEventRepo repository.ISecurityEventRepo
+}
+
+type IAPIKeyUcase interface {
+	CreateServiceAccount(ctx context.Context, payload dto.CreateServiceAccountReq) (*dto.CreateServiceAccountRespData, error)
+	CreateAPIKey(ctx context.Context, userUUID string, payload dto.CreateAPIKeyReq) (*dto.CreateAPIKeyRespData, error)
+	GetAPIKeyList(ctx context.Context, userUUID string) ([]dto.GetAPIKeyListRespDataItem, error)
+	RevokeAPIKey(ctx context.Context, apiKeyUUID string) (*dto.RevokeAPIKeyRespData, error)
+
+	// used by the auth middlewares of every service
+	ValidateAPIKey(ctx context.Context, payload dto.ValidateAPIKeyReq) (*dto.ValidateAPIKeyRespData, error)
+}
+
+func NewAPIKeyUcase(
+	userRepo repository.IUserRepo,
+	roleRepo repository.IRoleRepo,
+	apiKeyRepo repository.IAPIKeyRepo,
+	securityEventRepo repository.ISecurityEventRepo,
+) IAPIKeyUcase {
+	return &APIKeyUcase{
+		userRepo:          userRepo,
+		roleRepo:          roleRepo,
+		apiKeyRepo:        apiKeyRepo,
+		securityEventRepo: securityEventRepo,
+	}
+}
+
+// CreateServiceAccount creates a user without email nor usable password, it
+// can only authenticate with the api keys created for it.
+func (ucase *APIKeyUcase) CreateServiceAccount(ctx context.Context, payload dto.CreateServiceAccountReq) (*dto.CreateServiceAccountRespData, error) {
+	// validate input
+	err := validator_util.ValidateUsername(payload.Username)
+	if err != nil {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.InvalidArgument,
+			Message:  err.Error(),
+		}
+	}
+
+	_, err = ucase.roleRepo.GetByName(payload.Role)
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, &error_utils.CustomErr{
+				HttpCode: 400,
+				GrpcCode: codes.InvalidArgument,
+				Message:  "invalid role",
+			}
+		}
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	user, _ := ucase.userRepo.GetByUsername(payload.Username)
+	if user != nil {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.AlreadyExists,
+			Message:  fmt.Sprintf("user with username %s already exists", payload.Username),
+		}
+	}
+
+	// the password is never given out, it only fills the column
+	randomPassword, err := token_util.Generate()
+	if err != nil {
+		logger.Errorf("error generating password: %v", err)
+		return nil, err
+	}
+	password, err := bcrypt_util.Hash(randomPassword)
+	if err != nil {
+		logger.Errorf("error hashing password: %v", err)
+		return nil, err
+	}
+
+	timeNow := helper.TimeNowUTC()
+	user = &model.User{
+		UUID:             uuid.New(),
+		Username:         payload.Username,
+		Password:         password,
+		Role:             payload.Role,
+		EmailVerifiedAt:  &timeNow,
+		IsServiceAccount: true,
+	}
+	err = ucase.userRepo.Create(user)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	return &dto.CreateServiceAccountRespData{
+		UUID:      user.UUID.String(),
+		Username:  user.Username,
+		Role:      user.Role,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}, nil
+}
+
+// CreateAPIKey creates a key for a service account. the key is only returned
+// here, the store keeps its hash.
+func (ucase *APIKeyUcase) CreateAPIKey(ctx context.Context, userUUID string, payload dto.CreateAPIKeyReq) (*dto.CreateAPIKeyRespData, error) {
+	user, err := ucase.getServiceAccount(userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// validate input
+	name := strings.TrimSpace(payload.Name)
+	if name == "" || len(name) > 100 {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.InvalidArgument,
+			Message:  "invalid name",
+			Detail:   "use 1 to 100 characters",
+		}
+	}
+
+	expiresInDays := config.Envs.API_KEY_EXP_DAYS
+	if payload.ExpiresInDays != nil {
+		expiresInDays = *payload.ExpiresInDays
+	}
+	if expiresInDays < 1 || expiresInDays > config.Envs.API_KEY_MAX_EXP_DAYS {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.InvalidArgument,
+			Message:  "invalid expires_in_days",
+			Detail:   fmt.Sprintf("use 1 to %d days", config.Envs.API_KEY_MAX_EXP_DAYS),
+		}
+	}
+
+	rolePermissions, err := ucase.getRolePermissions(user.Role)
+	if err != nil {
+		return nil, err
+	}
+	scopes := []model.APIKeyScope{}
+	seen := map[string]bool{}
+	for _, scope := range payload.Scopes {
+		if !model.IsValidPermission(scope) {
+			return nil, &error_utils.CustomErr{
+				HttpCode: 400,
+				GrpcCode: codes.InvalidArgument,
+				Message:  fmt.Sprintf("invalid scope: %s", scope),
+			}
+		}
+		if !containsString(rolePermissions, scope) {
+			return nil, &error_utils.CustomErr{
+				HttpCode: 400,
+				GrpcCode: codes.InvalidArgument,
+				Message:  fmt.Sprintf("scope not granted to role %s: %s", user.Role, scope),
+			}
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, model.APIKeyScope{Permission: scope})
+		}
+	}
+
+	// generate key
+	secret, err := token_util.Generate()
+	if err != nil {
+		logger.Errorf("error generating api key: %v", err)
+		return nil, err
+	}
+	key := apiKeyPrefix + secret
+
+	apiKeyUUID := uuid.New()
+	for i := range scopes {
+		scopes[i].APIKeyUUID = apiKeyUUID
+	}
+	apiKey := &model.APIKey{
+		UUID:      apiKeyUUID,
+		UserUUID:  user.UUID,
+		Name:      name,
+		Prefix:    key[:apiKeyDisplayLength],
+		KeyHash:   token_util.Hash(key),
+		ExpiredAt: helper.TimeNowUTC().AddDate(0, 0, expiresInDays),
+		Scopes:    scopes,
+	}
+	err = ucase.apiKeyRepo.Create(apiKey)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	ucase.createSecurityEvent(apiKey, model.SecurityEventTypeAPIKeyCreated)
+
+	return &dto.CreateAPIKeyRespData{
+		UUID:      apiKey.UUID.String(),
+		Name:      apiKey.Name,
+		Key:       key,
+		Prefix:    apiKey.Prefix,
+		Scopes:    apiKey.ScopeNames(),
+		ExpiredAt: apiKey.ExpiredAt,
+		CreatedAt: apiKey.CreatedAt,
+	}, nil
+}
+
+func (ucase *APIKeyUcase) GetAPIKeyList(ctx context.Context, userUUID string) ([]dto.GetAPIKeyListRespDataItem, error) {
+	_, err := ucase.getServiceAccount(userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKeys, err := ucase.apiKeyRepo.GetListByUserUUID(userUUID)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	timeNow := helper.TimeNowUTC()
+	res := []dto.GetAPIKeyListRespDataItem{}
+	for _, apiKey := range apiKeys {
+		res = append(res, dto.GetAPIKeyListRespDataItem{
+			UUID:       apiKey.UUID.String(),
+			Name:       apiKey.Name,
+			Prefix:     apiKey.Prefix,
+			Scopes:     apiKey.ScopeNames(),
+			Active:     apiKey.IsActive(timeNow),
+			ExpiredAt:  apiKey.ExpiredAt,
+			LastUsedAt: apiKey.LastUsedAt,
+			RevokedAt:  apiKey.RevokedAt,
+			CreatedAt:  apiKey.CreatedAt,
+		})
+	}
+
+	return res, nil
+}
+
+// RevokeAPIKey stops the key right away in auth_service. the other services
+// cache validated keys, they accept it until their cache entry expires.
+func (ucase *APIKeyUcase) RevokeAPIKey(ctx context.Context, apiKeyUUID string) (*dto.RevokeAPIKeyRespData, error) {
+	apiKey, err := ucase.apiKeyRepo.GetByUUID(apiKeyUUID)
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, &error_utils.CustomErr{
+				HttpCode: 404,
+				GrpcCode: codes.NotFound,
+				Message:  "api key not found",
+			}
+		}
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	err = ucase.apiKeyRepo.Revoke(apiKey, helper.TimeNowUTC())
+	if err != nil {
+		if err.Error() == "already revoked" {
+			return nil, &error_utils.CustomErr{
+				HttpCode: 400,
+				GrpcCode: codes.FailedPrecondition,
+				Message:  "api key already revoked",
+			}
+		}
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	ucase.createSecurityEvent(apiKey, model.SecurityEventTypeAPIKeyRevoked)
+
+	return &dto.RevokeAPIKeyRespData{
+		UUID:      apiKey.UUID.String(),
+		RevokedAt: *apiKey.RevokedAt,
+	}, nil
+}
+
+// ValidateAPIKey resolves the service account of an active key. the key gets
+// its scopes that the role of the account still grants.
+func (ucase *APIKeyUcase) ValidateAPIKey(ctx context.Context, payload dto.ValidateAPIKeyReq) (*dto.ValidateAPIKeyRespData, error) {
+	if !strings.HasPrefix(payload.APIKey, apiKeyPrefix) {
+		return nil, invalidAPIKeyErr("malformed api key")
+	}
+
+	apiKey, err := ucase.apiKeyRepo.GetByKeyHash(token_util.Hash(payload.APIKey))
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, invalidAPIKeyErr("api key not found")
+		}
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	timeNow := helper.TimeNowUTC()
+	if apiKey.RevokedAt != nil {
+		return nil, invalidAPIKeyErr("api key is revoked")
+	}
+	if !apiKey.IsActive(timeNow) {
+		return nil, invalidAPIKeyErr("api key is expired")
+	}
+
+	user, err := ucase.userRepo.GetByUUID(apiKey.UserUUID.String())
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, invalidAPIKeyErr("service account not found")
+		}
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	rolePermissions, err := ucase.getRolePermissions(user.Role)
+	if err != nil {
+		return nil, err
+	}
+	permissions := []string{}
+	for _, scope := range apiKey.ScopeNames() {
+		if containsString(rolePermissions, scope) {
+			permissions = append(permissions, scope)
+		}
+	}
+
+	// failing to track usage does not fail the request
+	err = ucase.apiKeyRepo.TouchLastUsed(apiKey, timeNow, timeNow.Add(-apiKeyLastUsedResolution))
+	if err != nil {
+		logger.Errorf("error updating api key last used: %v", err)
+	}
+
+	return &dto.ValidateAPIKeyRespData{
+		UUID:          user.UUID.String(),
+		Username:      user.Username,
+		Email:         user.Email,
+		Role:          user.Role,
+		EmailVerified: user.IsEmailVerified(),
+		Permissions:   permissions,
+		APIKeyUUID:    apiKey.UUID.String(),
+	}, nil
+}
+
+func (ucase *APIKeyUcase) getServiceAccount(userUUID string) (*model.User, error) {
+	user, err := ucase.userRepo.GetByUUID(userUUID)
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, &error_utils.CustomErr{
+				HttpCode: 404,
+				GrpcCode: codes.NotFound,
+				Message:  "user not found",
+			}
+		}
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+	if !user.IsServiceAccount {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.FailedPrecondition,
+			Message:  "user is not a service account",
+			Detail:   "api keys are only issued to service accounts",
+		}
+	}
+	return user, nil
+}
+
+// getRolePermissions returns no permission for roles that do not exist.
+func (ucase *APIKeyUcase) getRolePermissions(roleName string) ([]string, error) {
+	role, err := ucase.roleRepo.GetByName(roleName)
+	if err != nil {
+		if err.Error() == "not found" {
+			return []string{}, nil
+		}
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+	return role.PermissionNames(), nil
+}
+
+func (ucase *APIKeyUcase) createSecurityEvent(apiKey *model.APIKey, eventType string) {
+	detail := fmt.Sprintf("%s (%s)", apiKey.Name, apiKey.Prefix)
+	err := ucase.securityEventRepo.Create(&model.SecurityEvent{
+		UUID:     uuid.New(),
+		UserUUID: apiKey.UserUUID,
+		Type:     eventType,
+		Detail:   &detail,
+	})
+	if err != nil {
+		logger.Errorf("error creating security event: %v", err)
+	}
+}
+
+func invalidAPIKeyErr(detail string) error {
+	return &error_utils.CustomErr{
+		HttpCode: 401,
+		GrpcCode: codes.Unauthenticated,
+		Message:  "Invalid API Key",
+		Detail:   detail,
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,190 @@
+package ucase
+
+import (
+	"auth_service/config"
+	"auth_service/domain/dto"
+	"auth_service/domain/model"
+	mocks "auth_service/mocks/repository"
+	"auth_service/repository"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeAPIKeyRepo keeps api keys by uuid, calling any method not listed here
+// panics.
+type fakeAPIKeyRepo struct {
+	repository.IAPIKeyRepo
+	apiKeys map[string]*model.APIKey
+}
+
+func (repo *fakeAPIKeyRepo) Create(apiKey *model.APIKey) error {
+	apiKey.ID = uint(len(repo.apiKeys) + 1)
+	repo.apiKeys[apiKey.UUID.String()] = apiKey
+	return nil
+}
+
+func (repo *fakeAPIKeyRepo) GetByUUID(uuid string) (*model.APIKey, error) {
+	apiKey, ok := repo.apiKeys[uuid]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return apiKey, nil
+}
+
+func (repo *fakeAPIKeyRepo) GetByKeyHash(keyHash string) (*model.APIKey, error) {
+	for _, apiKey := range repo.apiKeys {
+		if apiKey.KeyHash == keyHash {
+			return apiKey, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (repo *fakeAPIKeyRepo) TouchLastUsed(apiKey *model.APIKey, now time.Time, notAfter time.Time) error {
+	if apiKey.LastUsedAt == nil || apiKey.LastUsedAt.Before(notAfter) {
+		apiKey.LastUsedAt = &now
+	}
+	return nil
+}
+
+func (repo *fakeAPIKeyRepo) Revoke(apiKey *model.APIKey, now time.Time) error {
+	if apiKey.RevokedAt != nil {
+		return errors.New("already revoked")
+	}
+	apiKey.RevokedAt = &now
+	return nil
+}
+
+type apiKeyTestEnv struct {
+	ucase             IAPIKeyUcase
+	apiKeyRepo        *fakeAPIKeyRepo
+	securityEventRepo *fakeSecurityEventRepo
+	role              *model.Role
+	serviceAccount    *model.User
+}
+
+// newAPIKeyTestEnv has a service account whose role grants copy:manage and
+// borrow:manage.
+func newAPIKeyTestEnv(t *testing.T) *apiKeyTestEnv {
+	config.Envs = &config.EnvsSchema{API_KEY_EXP_DAYS: 90, API_KEY_MAX_EXP_DAYS: 365}
+
+	role := &model.Role{
+		Name: "kiosk",
+		Permissions: []model.RolePermission{
+			{RoleName: "kiosk", Permission: model.PermissionCopyManage},
+			{RoleName: "kiosk", Permission: model.PermissionBorrowManage},
+		},
+	}
+	serviceAccount := &model.User{UUID: uuid.New(), Username: "kiosk-1", Role: role.Name, IsServiceAccount: true}
+	userRepo := mocks.NewIUserRepo(t)
+	userRepo.On("GetByUUID", serviceAccount.UUID.String()).Return(serviceAccount, nil).Maybe()
+	roleRepo := mocks.NewIRoleRepo(t)
+	roleRepo.On("GetByName", role.Name).Return(role, nil).Maybe()
+
+	env := &apiKeyTestEnv{
+		apiKeyRepo:        &fakeAPIKeyRepo{apiKeys: map[string]*model.APIKey{}},
+		securityEventRepo: &fakeSecurityEventRepo{},
+		role:              role,
+		serviceAccount:    serviceAccount,
+	}
+	env.ucase = NewAPIKeyUcase(userRepo, roleRepo, env.apiKeyRepo, env.securityEventRepo)
+	return env
+}
+
+func (env *apiKeyTestEnv) createAPIKey(t *testing.T, scopes ...string) *dto.CreateAPIKeyRespData {
+	t.Helper()
+	resp, err := env.ucase.CreateAPIKey(context.Background(), env.serviceAccount.UUID.String(), dto.CreateAPIKeyReq{Name: "kiosk", Scopes: scopes})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return resp
+}
+
+func TestAPIKeyUcase_CreateAPIKey(t *testing.T) {
+	env := newAPIKeyTestEnv(t)
+
+	resp := env.createAPIKey(t, model.PermissionCopyManage, model.PermissionCopyManage)
+	assert.Equal(t, []string{model.PermissionCopyManage}, resp.Scopes)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, 90), resp.ExpiredAt, time.Minute)
+
+	// only the hash of the key is stored
+	stored := env.apiKeyRepo.apiKeys[resp.UUID]
+	assert.NotEqual(t, resp.Key, stored.KeyHash)
+	assert.NotContains(t, stored.KeyHash, resp.Key)
+	assert.Equal(t, resp.Key[:len(stored.Prefix)], stored.Prefix)
+	assert.Equal(t, []string{model.SecurityEventTypeAPIKeyCreated}, env.securityEventRepo.types)
+
+	expiresInDays := 400
+	tests := []struct {
+		name    string
+		payload dto.CreateAPIKeyReq
+	}{
+		{"scope_not_granted_to_the_role", dto.CreateAPIKeyReq{Name: "kiosk", Scopes: []string{model.PermissionUserManage}}},
+		{"unknown_scope", dto.CreateAPIKeyReq{Name: "kiosk", Scopes: []string{"books:burn"}}},
+		{"expiry_above_max", dto.CreateAPIKeyReq{Name: "kiosk", ExpiresInDays: &expiresInDays}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := env.ucase.CreateAPIKey(context.Background(), env.serviceAccount.UUID.String(), tt.payload)
+			assertHttpCode(t, err, 400)
+		})
+	}
+	assert.Len(t, env.apiKeyRepo.apiKeys, 1)
+}
+
+func TestAPIKeyUcase_ValidateAPIKey(t *testing.T) {
+	env := newAPIKeyTestEnv(t)
+	resp := env.createAPIKey(t, model.PermissionCopyManage, model.PermissionBorrowManage)
+
+	validated, err := env.ucase.ValidateAPIKey(context.Background(), dto.ValidateAPIKeyReq{APIKey: resp.Key})
+	assert.NoError(t, err)
+	assert.Equal(t, env.serviceAccount.UUID.String(), validated.UUID)
+	assert.Equal(t, resp.UUID, validated.APIKeyUUID)
+	assert.ElementsMatch(t, []string{model.PermissionCopyManage, model.PermissionBorrowManage}, validated.Permissions)
+	assert.NotNil(t, env.apiKeyRepo.apiKeys[resp.UUID].LastUsedAt)
+
+	// a scope the role no longer grants is dropped from the key
+	env.role.Permissions = env.role.Permissions[:1]
+	validated, err = env.ucase.ValidateAPIKey(context.Background(), dto.ValidateAPIKeyReq{APIKey: resp.Key})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{model.PermissionCopyManage}, validated.Permissions)
+
+	for _, apiKey := range []string{"not-a-key", "lk_unknown"} {
+		_, err = env.ucase.ValidateAPIKey(context.Background(), dto.ValidateAPIKeyReq{APIKey: apiKey})
+		assertHttpCode(t, err, 401)
+	}
+}
+
+func TestAPIKeyUcase_ValidateAPIKey_Expired(t *testing.T) {
+	env := newAPIKeyTestEnv(t)
+	resp := env.createAPIKey(t)
+	env.apiKeyRepo.apiKeys[resp.UUID].ExpiredAt = time.Now().UTC().Add(-time.Second)
+
+	_, err := env.ucase.ValidateAPIKey(context.Background(), dto.ValidateAPIKeyReq{APIKey: resp.Key})
+	assertHttpCode(t, err, 401)
+	assert.Nil(t, env.apiKeyRepo.apiKeys[resp.UUID].LastUsedAt)
+}
+
+func TestAPIKeyUcase_RevokeAPIKey(t *testing.T) {
+	env := newAPIKeyTestEnv(t)
+	resp := env.createAPIKey(t)
+
+	revoked, err := env.ucase.RevokeAPIKey(context.Background(), resp.UUID)
+	assert.NoError(t, err)
+	assert.Equal(t, resp.UUID, revoked.UUID)
+	assert.Equal(t, []string{model.SecurityEventTypeAPIKeyCreated, model.SecurityEventTypeAPIKeyRevoked}, env.securityEventRepo.types)
+
+	_, err = env.ucase.ValidateAPIKey(context.Background(), dto.ValidateAPIKeyReq{APIKey: resp.Key})
+	assertHttpCode(t, err, 401)
+
+	_, err = env.ucase.RevokeAPIKey(context.Background(), resp.UUID)
+	assertHttpCode(t, err, 400)
+
+	_, err = env.ucase.RevokeAPIKey(context.Background(), uuid.New().String())
+	assertHttpCode(t, err, 404)
+}
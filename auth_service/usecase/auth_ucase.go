@@ -10,7 +10,10 @@ import (
 	error_utils "auth_service/utils/error"
 	"auth_service/utils/helper"
 	jwt_util "auth_service/utils/jwt"
+	saga_util "auth_service/utils/saga"
+	token_util "auth_service/utils/token"
 	validator_util "auth_service/utils/validator/user"
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -24,24 +27,60 @@ import (
 type AuthUcase struct {
 	userRepo                repository.IUserRepo
 	refreshTokenRepo        repository.IRefreshTokenRepo
+	revokedTokenRepo        repository.IRevokedTokenRepo
+	sessionRepo             repository.ISessionRepo
+	securityEventRepo       repository.ISecurityEventRepo
+	signingKeyUcase         ISigningKeyUcase
+	emailVerificationUcase  IEmailVerificationUcase
+	twoFactorUcase          ITwoFactorUcase
+	loginChallengeRepo      repository.ILoginChallengeRepo
+	loginThrottleUcase      ILoginThrottleUcase
+	roleRepo                repository.IRoleRepo
+	sagaRepo                repository.ISagaRepo
 	authorGrpcServiceClient author_grpc.AuthorServiceClient
 }
 
 type IAuthUcase interface {
 	Register(ctx *gin.Context, payload dto.RegisterUserReq) (*dto.RegisterUserRespData, error)
 	Login(payload dto.LoginReq) (*dto.LoginRespData, error)
+	LoginTwoFactor(payload dto.LoginTwoFactorReq) (*dto.LoginTwoFactorRespData, error)
+	LoginTwoFactorEnroll(payload dto.LoginTwoFactorEnrollReq) (*dto.EnrollTwoFactorRespData, error)
 	RefreshToken(payload dto.RefreshTokenReq) (*dto.RefreshTokenRespData, error)
 	CheckToken(payload dto.CheckTokenReq) (*dto.CheckTokenRespData, error)
+	Logout(currentUser dto.CurrentUser, payload dto.LogoutReq) (*dto.LogoutRespData, error)
+	LogoutAll(currentUser dto.CurrentUser) (*dto.LogoutAllRespData, error)
+	GetSessionList(currentUser dto.CurrentUser) ([]dto.GetSessionListRespDataItem, error)
+	RevokeSession(currentUser dto.CurrentUser, sessionUUID string) (*dto.RevokeSessionRespData, error)
 }
 
 func NewAuthUcase(
 	userRepo repository.IUserRepo,
 	refreshTokenRepo repository.IRefreshTokenRepo,
+	revokedTokenRepo repository.IRevokedTokenRepo,
+	sessionRepo repository.ISessionRepo,
+	securityEventRepo repository.ISecurityEventRepo,
+	signingKeyUcase ISigningKeyUcase,
+	emailVerificationUcase IEmailVerificationUcase,
+	twoFactorUcase ITwoFactorUcase,
+	loginChallengeRepo repository.ILoginChallengeRepo,
+	loginThrottleUcase ILoginThrottleUcase,
+	roleRepo repository.IRoleRepo,
+	sagaRepo repository.ISagaRepo,
 	authorGrpcServiceClient author_grpc.AuthorServiceClient,
 ) IAuthUcase {
 	return &AuthUcase{
 		userRepo:                userRepo,
 		refreshTokenRepo:        refreshTokenRepo,
+		revokedTokenRepo:        revokedTokenRepo,
+		sessionRepo:             sessionRepo,
+		securityEventRepo:       securityEventRepo,
+		signingKeyUcase:         signingKeyUcase,
+		emailVerificationUcase:  emailVerificationUcase,
+		twoFactorUcase:          twoFactorUcase,
+		loginChallengeRepo:      loginChallengeRepo,
+		loginThrottleUcase:      loginThrottleUcase,
+		roleRepo:                roleRepo,
+		sagaRepo:                sagaRepo,
 		authorGrpcServiceClient: authorGrpcServiceClient,
 	}
 }
@@ -102,72 +141,89 @@ func (s *AuthUcase) Register(ctx *gin.Context, payload dto.RegisterUserReq) (*dt
 		return nil, err
 	}
 
-	// create author through author service
-	_, err = s.authorGrpcServiceClient.CreateAuthor(
-		ctx, &author_grpc.CreateAuthorReq{
-			FirstName: payload.Username,
-			LastName:  "",
-			BirthDate: "",
-			Bio:       "",
-		},
-	)
-	grpcCode := status.Code(err)
-
-	if grpcCode != codes.OK || err != nil {
-		logger.Errorf("error creating author: %v", err)
-		return nil, &error_utils.CustomErr{
-			HttpCode: 500,
-			Message:  "error creating author",
-			Detail:   err,
-		}
-	}
-
-	// create user
 	user = &model.User{
 		UUID:     uuid.New(),
 		Username: payload.Username,
 		Password: password,
 		Email:    payload.Email,
-		Role:     "user",
+		Role:     model.RoleUser,
 	}
 	err = user.Validate()
 	if err != nil {
 		return nil, err
 	}
 
-	err = s.userRepo.Create(user)
-	if err != nil {
-		return nil, err
+	// the user, its author in author service and the session are created by a
+	// saga, a failed step undoes the previous ones so registering can be retried
+	userUUID := user.UUID
+	saga := &model.Saga{
+		UUID:         uuid.New(),
+		Name:         model.SagaNameRegister,
+		ResourceUUID: &userUUID,
 	}
-
-	// generate token
-	token, err := jwt_util.GenerateJwtToken(user, config.Envs.JWT_SECRET_KEY, config.Envs.JWT_EXP_HOURS, nil)
+	var accessToken, refreshToken string
+	err = saga_util.Run(ctx, []saga_util.Step{
+		{
+			Name: "create_user",
+			Action: func(ctx context.Context) error {
+				return s.userRepo.Create(user)
+			},
+			Compensate: func(ctx context.Context) error {
+				return s.userRepo.Purge(user.UUID.String())
+			},
+		},
+		{
+			Name: "create_author",
+			Action: func(ctx context.Context) error {
+				return saga_util.RetryTransient(ctx, func(ctx context.Context) error {
+					_, err := s.authorGrpcServiceClient.CreateAuthor(
+						ctx, &author_grpc.CreateAuthorReq{
+							UserUuid:       user.UUID.String(),
+							FirstName:      payload.Username,
+							IdempotencyKey: saga.UUID.String(),
+						},
+					)
+					return err
+				})
+			},
+			Compensate: func(ctx context.Context) error {
+				_, err := s.authorGrpcServiceClient.PurgeAuthorByUserUUID(
+					ctx, &author_grpc.PurgeAuthorByUserUUIDReq{UserUuid: user.UUID.String()},
+				)
+				if status.Code(err) == codes.NotFound {
+					return nil
+				}
+				return err
+			},
+		},
+		{
+			Name: "start_session",
+			Action: func(ctx context.Context) error {
+				var err error
+				accessToken, refreshToken, err = s.startSession(user, payload.Device, payload.IP)
+				return err
+			},
+		},
+	}, sagaRecorder(s.sagaRepo, saga))
 	if err != nil {
-		logger.Errorf("error generating token: %v", err)
-		return nil, err
+		logger.Errorf("error registering user: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "registration failed, please try again",
+			Detail:   err.Error(),
+		}
 	}
 
-	// invalidate old refresh token
-	s.refreshTokenRepo.InvalidateManyByUserUUID(user.UUID.String())
-
-	// create refresh token
-	refreshTokenExpiredAt := helper.TimeNowUTC().Add(time.Hour * time.Duration(config.Envs.JWT_REFRESH_EXP_HOURS))
-	newRefreshTokenObj := model.RefreshToken{
-		Token:     uuid.New().String(),
-		UserUUID:  user.UUID,
-		UsedAt:    nil,
-		ExpiredAt: &refreshTokenExpiredAt,
-	}
-	logger.Debugf("new refresh token: %+v", newRefreshTokenObj)
-	err = s.refreshTokenRepo.Create(&newRefreshTokenObj)
+	// users start unverified, a failed mail can be resent later
+	err = s.emailVerificationUcase.SendVerificationEmail(ctx, user)
 	if err != nil {
-		logger.Errorf("error creating refresh token: %v", err)
-		return nil, err
+		logger.Errorf("error sending verification mail: %v", err)
 	}
 
 	resp := &dto.RegisterUserRespData{
-		AccessToken:  token,
-		RefreshToken: newRefreshTokenObj.Token,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
 	}
 	return resp, nil
 }
@@ -204,6 +260,12 @@ func (s *AuthUcase) Login(payload dto.LoginReq) (*dto.LoginRespData, error) {
 		}
 	}
 
+	// refuse early while the client ip is blocked
+	err = s.loginThrottleUcase.Check(nil, payload.IP)
+	if err != nil {
+		return nil, err
+	}
+
 	// check if user exists
 	var existing_user *model.User
 	if strings.Contains(payload.UsernameOrEmail, "@") {
@@ -211,8 +273,14 @@ func (s *AuthUcase) Login(payload dto.LoginReq) (*dto.LoginRespData, error) {
 	} else {
 		existing_user, _ = s.userRepo.GetByUsername(payload.UsernameOrEmail)
 	}
+	// service accounts only authenticate with api keys
+	if existing_user != nil && existing_user.IsServiceAccount {
+		logger.Errorf("login refused for service account %s", existing_user.UUID.String())
+		existing_user = nil
+	}
 	if existing_user == nil {
 		logger.Errorf("user not found")
+		s.loginThrottleUcase.RegisterFailure(nil, payload.IP, payload.Device)
 		return nil, &error_utils.CustomErr{
 			HttpCode: 401,
 			Message:  "Invalid Credentials",
@@ -220,50 +288,101 @@ func (s *AuthUcase) Login(payload dto.LoginReq) (*dto.LoginRespData, error) {
 	}
 	logger.Debugf("user by username or email: %v", helper.PrettyJson(existing_user))
 
+	err = s.loginThrottleUcase.Check(existing_user, payload.IP)
+	if err != nil {
+		return nil, err
+	}
+
 	// check password
 	if !bcrypt_util.Compare(payload.Password, existing_user.Password) {
 		logger.Errorf("invalid password")
+		s.loginThrottleUcase.RegisterFailure(existing_user, payload.IP, payload.Device)
 		return nil, &error_utils.CustomErr{
 			HttpCode: 401,
 			Message:  "Invalid Credentials",
 		}
 	}
+	s.loginThrottleUcase.RegisterSuccess(existing_user)
 
-	// generate token
-	token, err := jwt_util.GenerateJwtToken(existing_user, config.Envs.JWT_SECRET_KEY, config.Envs.JWT_EXP_HOURS, nil)
+	// the second step exchanges the challenge token for the real tokens
+	if s.twoFactorUcase.IsRequired(existing_user) {
+		return s.startLoginChallenge(existing_user, payload.Device, payload.IP)
+	}
+
+	// start session
+	accessToken, refreshToken, err := s.startSession(existing_user, payload.Device, payload.IP)
 	if err != nil {
-		logger.Errorf("error generating token: %v", err)
 		return nil, err
 	}
 
-	// invalidate old refresh token
-	err = s.refreshTokenRepo.InvalidateManyByUserUUID(existing_user.UUID.String())
+	return &dto.LoginRespData{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// LoginTwoFactor finishes a 2fa login. users without 2fa enabled yet confirm
+// their enrollment with the code and get their recovery codes here.
+func (s *AuthUcase) LoginTwoFactor(payload dto.LoginTwoFactorReq) (*dto.LoginTwoFactorRespData, error) {
+	challenge, user, err := s.getLoginChallenge(payload.ChallengeToken)
 	if err != nil {
-		logger.Errorf("error invalidating old refresh token: %v", err)
 		return nil, err
 	}
 
-	// create refresh token
-	refreshTokenExpiredAt := helper.TimeNowUTC().Add(time.Hour * time.Duration(config.Envs.JWT_REFRESH_EXP_HOURS))
-	newRefreshTokenObj := model.RefreshToken{
-		Token:     uuid.New().String(),
-		UserUUID:  existing_user.UUID,
-		UsedAt:    nil,
-		ExpiredAt: &refreshTokenExpiredAt,
+	// verify code
+	var recoveryCodes []string
+	if user.IsTOTPEnabled() {
+		err = s.twoFactorUcase.VerifyCode(user, payload.Code)
+	} else {
+		recoveryCodes, err = s.twoFactorUcase.ConfirmEnrollment(user, payload.Code)
 	}
-	logger.Debugf("new refresh token: %+v", helper.PrettyJson(newRefreshTokenObj))
-	err = s.refreshTokenRepo.Create(&newRefreshTokenObj)
 	if err != nil {
-		logger.Errorf("error creating refresh token: %v", err)
+		incrementErr := s.loginChallengeRepo.IncrementAttempts(challenge)
+		if incrementErr != nil {
+			logger.Errorf("error incrementing challenge attempts: %v", incrementErr)
+		}
 		return nil, err
 	}
 
-	return &dto.LoginRespData{
-		AccessToken:  token,
-		RefreshToken: newRefreshTokenObj.Token,
+	// consume challenge, fails when a concurrent request used it first
+	err = s.loginChallengeRepo.MarkUsed(challenge, helper.TimeNowUTC())
+	if err != nil {
+		if err.Error() == "already used" {
+			return nil, invalidChallengeTokenErr()
+		}
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	// start session
+	accessToken, refreshToken, err := s.startSession(user, payload.Device, payload.IP)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.LoginTwoFactorRespData{
+		AccessToken:   accessToken,
+		RefreshToken:  refreshToken,
+		RecoveryCodes: recoveryCodes,
 	}, nil
 }
 
+// LoginTwoFactorEnroll gives users that must use 2fa but have not enabled it
+// yet a totp secret to confirm through LoginTwoFactor.
+func (s *AuthUcase) LoginTwoFactorEnroll(payload dto.LoginTwoFactorEnrollReq) (*dto.EnrollTwoFactorRespData, error) {
+	_, user, err := s.getLoginChallenge(payload.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.twoFactorUcase.BeginEnrollment(user)
+}
+
 func (s *AuthUcase) RefreshToken(payload dto.RefreshTokenReq) (*dto.RefreshTokenRespData, error) {
 	// get refresh token
 	refreshToken, err := s.refreshTokenRepo.GetByToken(payload.RefreshToken)
@@ -275,6 +394,21 @@ func (s *AuthUcase) RefreshToken(payload dto.RefreshTokenReq) (*dto.RefreshToken
 		}
 	}
 
+	// tokens issued before sessions can not be rotated
+	if refreshToken.SessionUUID == nil {
+		logger.Errorf("refresh token has no session")
+		return nil, &error_utils.CustomErr{
+			HttpCode: 401,
+			Message:  "Invalid Refresh Token",
+		}
+	}
+
+	// an already rotated token presented again means it was stolen, either by
+	// the attacker or by the user after the attacker rotated it first
+	if refreshToken.UsedAt != nil {
+		return nil, s.handleRefreshTokenReuse(refreshToken, payload)
+	}
+
 	// check if refresh token is expired
 	if refreshToken.ExpiredAt != nil {
 		if refreshToken.ExpiredAt.Before(helper.TimeNowUTC()) {
@@ -286,18 +420,26 @@ func (s *AuthUcase) RefreshToken(payload dto.RefreshTokenReq) (*dto.RefreshToken
 		}
 	}
 
-	// check if refresh token is used
-	if refreshToken.UsedAt != nil {
-		logger.Errorf("refresh token is used")
+	// check if refresh token is valid
+	if refreshToken.Invalid {
+		logger.Errorf("refresh token is invalid")
 		return nil, &error_utils.CustomErr{
 			HttpCode: 401,
 			Message:  "Invalid Refresh Token",
 		}
 	}
 
-	// check if refresh token is valid
-	if refreshToken.Invalid {
-		logger.Errorf("refresh token is invalid")
+	// check session
+	session, err := s.sessionRepo.GetByUUID(refreshToken.SessionUUID.String())
+	if err != nil {
+		logger.Errorf("session not found: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 401,
+			Message:  "Invalid Refresh Token",
+		}
+	}
+	if session.RevokedAt != nil {
+		logger.Errorf("session is revoked")
 		return nil, &error_utils.CustomErr{
 			HttpCode: 401,
 			Message:  "Invalid Refresh Token",
@@ -306,9 +448,11 @@ func (s *AuthUcase) RefreshToken(payload dto.RefreshTokenReq) (*dto.RefreshToken
 
 	// mark refresh token as used
 	timeNow := helper.TimeNowUTC()
-	refreshToken.UsedAt = &timeNow
-	err = s.refreshTokenRepo.Update(refreshToken)
+	err = s.refreshTokenRepo.MarkUsed(refreshToken, timeNow)
 	if err != nil {
+		if err.Error() == "already used" {
+			return nil, s.handleRefreshTokenReuse(refreshToken, payload)
+		}
 		logger.Errorf("error updating refresh token: %v", err)
 		return nil, err
 	}
@@ -324,31 +468,24 @@ func (s *AuthUcase) RefreshToken(payload dto.RefreshTokenReq) (*dto.RefreshToken
 		}
 	}
 
-	// generate token
-	token, err := jwt_util.GenerateJwtToken(user, config.Envs.JWT_SECRET_KEY, config.Envs.JWT_EXP_HOURS, nil)
+	// rotate refresh token inside the session
+	newRefreshTokenObj, err := s.createRefreshToken(user, session)
 	if err != nil {
-		logger.Errorf("error generating token: %v", err)
 		return nil, err
 	}
 
-	// invalidate old refresh token
-	err = s.refreshTokenRepo.InvalidateManyByUserUUID(user.UUID.String())
+	session.LastUsedAt = timeNow
+	session.IP = payload.IP
+	session.ExpiredAt = *newRefreshTokenObj.ExpiredAt
+	err = s.sessionRepo.Update(session)
 	if err != nil {
-		logger.Errorf("error invalidating old refresh token: %v", err)
+		logger.Errorf("error updating session: %v", err)
 		return nil, err
 	}
 
-	// create refresh token
-	refreshTokenExpiredAt := helper.TimeNowUTC().Add(time.Hour * time.Duration(config.Envs.JWT_REFRESH_EXP_HOURS))
-	newRefreshTokenObj := model.RefreshToken{
-		Token:     uuid.New().String(),
-		UserUUID:  user.UUID,
-		UsedAt:    nil,
-		ExpiredAt: &refreshTokenExpiredAt,
-	}
-	err = s.refreshTokenRepo.Create(&newRefreshTokenObj)
+	// generate token
+	token, err := s.generateAccessToken(user, session)
 	if err != nil {
-		logger.Errorf("error creating refresh token: %v", err)
 		return nil, err
 	}
 
@@ -359,23 +496,540 @@ func (s *AuthUcase) RefreshToken(payload dto.RefreshTokenReq) (*dto.RefreshToken
 }
 
 func (s *AuthUcase) CheckToken(payload dto.CheckTokenReq) (*dto.CheckTokenRespData, error) {
-	claims, err := jwt_util.ValidateJWT(payload.AccessToken, config.Envs.JWT_SECRET_KEY)
+	claims, err := jwt_util.ValidateJWT(payload.AccessToken, s.signingKeyUcase.GetPublicKey, claimsConfig())
 	if err != nil || claims == nil {
 		logger.Errorf("error validating token: %v", err)
 		return nil, &error_utils.CustomErr{
 			HttpCode: 401,
 			GrpcCode: codes.Unauthenticated,
 			Message:  "Invalid Access Token",
+			Detail:   fmt.Sprint(err),
+		}
+	}
+
+	// tokens without jti can not be revoked
+	if claims.TokenID == "" {
+		logger.Errorf("token has no jti")
+		return nil, &error_utils.CustomErr{
+			HttpCode: 401,
+			GrpcCode: codes.Unauthenticated,
+			Message:  "Invalid Access Token",
+			Detail:   "missing token id",
+		}
+	}
+
+	// check if token is revoked
+	revoked, err := s.revokedTokenRepo.IsRevoked(claims.TokenID)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+	if revoked {
+		logger.Errorf("token is revoked")
+		return nil, &error_utils.CustomErr{
+			HttpCode: 401,
+			GrpcCode: codes.Unauthenticated,
+			Message:  "Invalid Access Token",
+			Detail:   "token is revoked",
+		}
+	}
+
+	// check if every token of the user is revoked. the other services verify
+	// tokens locally, revocations only apply to the calls checked here.
+	user, err := s.userRepo.GetByUUID(claims.UUID)
+	if err != nil {
+		if err.Error() == "not found" {
+			logger.Errorf("user not found")
+			return nil, &error_utils.CustomErr{
+				HttpCode: 401,
+				GrpcCode: codes.Unauthenticated,
+				Message:  "Invalid Access Token",
+				Detail:   "user not found",
+			}
+		}
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+	if user.TokensValidAfter != nil && claims.IssuedAt.Before(*user.TokensValidAfter) {
+		logger.Errorf("token is revoked by logout all")
+		return nil, &error_utils.CustomErr{
+			HttpCode: 401,
+			GrpcCode: codes.Unauthenticated,
+			Message:  "Invalid Access Token",
+			Detail:   "token is revoked",
+		}
+	}
+
+	// check if the session of the token is revoked
+	if claims.SessionID != "" {
+		session, err := s.sessionRepo.GetByUUID(claims.SessionID)
+		if err != nil && err.Error() != "not found" {
+			logger.Errorf("err: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: codes.Internal,
+				Message:  "internal server error",
+				Detail:   err.Error(),
+			}
+		}
+		if session == nil || session.RevokedAt != nil {
+			logger.Errorf("session is revoked")
+			return nil, &error_utils.CustomErr{
+				HttpCode: 401,
+				GrpcCode: codes.Unauthenticated,
+				Message:  "Invalid Access Token",
+				Detail:   "session is revoked",
+			}
+		}
+	}
+
+	// resolved now, not from the token, so role changes apply right away
+	permissions, err := s.getRolePermissions(user.Role)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
 			Detail:   err.Error(),
 		}
 	}
 
 	resp := &dto.CheckTokenRespData{
-		UUID:     claims.UUID,
-		Username: claims.Username,
-		Role:     claims.Role,
-		Email:    claims.Email,
+		UUID:      claims.UUID,
+		Username:  claims.Username,
+		Role:      claims.Role,
+		Email:     claims.Email,
+		TokenID:   claims.TokenID,
+		SessionID: claims.SessionID,
+		IssuedAt:  claims.IssuedAt,
+		ExpiredAt: claims.ExpiredAt,
+
+		EmailVerified: user.IsEmailVerified(),
+		Permissions:   permissions,
 	}
 
 	return resp, nil
 }
+
+func (s *AuthUcase) Logout(currentUser dto.CurrentUser, payload dto.LogoutReq) (*dto.LogoutRespData, error) {
+	// validate refresh token
+	var refreshToken *model.RefreshToken
+	if payload.RefreshToken != "" {
+		var err error
+		refreshToken, err = s.refreshTokenRepo.GetByToken(payload.RefreshToken)
+		if err != nil || refreshToken.UserUUID.String() != currentUser.UUID {
+			logger.Errorf("refresh token not found: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 400,
+				GrpcCode: codes.InvalidArgument,
+				Message:  "Invalid Refresh Token",
+			}
+		}
+	}
+
+	// revoke access token
+	err := s.revokeAccessToken(currentUser)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	// revoke session of the access token
+	if currentUser.SessionID != "" {
+		err = s.sessionRepo.Revoke(currentUser.SessionID, model.SessionRevokedReasonLogout, helper.TimeNowUTC())
+		if err != nil {
+			logger.Errorf("error revoking session: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: codes.Internal,
+				Message:  "internal server error",
+				Detail:   err.Error(),
+			}
+		}
+	}
+
+	// invalidate refresh token
+	if refreshToken != nil && !refreshToken.Invalid {
+		refreshToken.Invalid = true
+		err = s.refreshTokenRepo.Update(refreshToken)
+		if err != nil {
+			logger.Errorf("error updating refresh token: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: codes.Internal,
+				Message:  "internal server error",
+				Detail:   err.Error(),
+			}
+		}
+	}
+
+	return &dto.LogoutRespData{
+		UUID: currentUser.UUID,
+	}, nil
+}
+
+func (s *AuthUcase) LogoutAll(currentUser dto.CurrentUser) (*dto.LogoutAllRespData, error) {
+	user, err := s.userRepo.GetByUUID(currentUser.UUID)
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, &error_utils.CustomErr{
+				HttpCode: 404,
+				GrpcCode: codes.NotFound,
+				Message:  "user not found",
+			}
+		}
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	// revoke every access token issued until now
+	validAfter := jwt_util.TokensValidAfter(helper.TimeNowUTC())
+	user.TokensValidAfter = &validAfter
+	err = s.userRepo.Update(user)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	// revoke sessions, which invalidates their refresh tokens
+	err = s.sessionRepo.RevokeManyByUserUUID(user.UUID.String(), model.SessionRevokedReasonLogoutAll, helper.TimeNowUTC())
+	if err != nil {
+		logger.Errorf("error revoking sessions: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	return &dto.LogoutAllRespData{
+		UUID: user.UUID.String(),
+	}, nil
+}
+
+func (s *AuthUcase) GetSessionList(currentUser dto.CurrentUser) ([]dto.GetSessionListRespDataItem, error) {
+	sessions, err := s.sessionRepo.GetActiveListByUserUUID(currentUser.UUID, helper.TimeNowUTC())
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	res := []dto.GetSessionListRespDataItem{}
+	for _, session := range sessions {
+		res = append(res, dto.GetSessionListRespDataItem{
+			UUID:       session.UUID.String(),
+			Device:     session.Device,
+			IP:         session.IP,
+			Current:    session.UUID.String() == currentUser.SessionID,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiredAt:  session.ExpiredAt,
+		})
+	}
+
+	return res, nil
+}
+
+func (s *AuthUcase) RevokeSession(currentUser dto.CurrentUser, sessionUUID string) (*dto.RevokeSessionRespData, error) {
+	session, err := s.sessionRepo.GetByUUID(sessionUUID)
+	if err != nil && err.Error() != "not found" {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	// sessions of other users are reported as not found
+	if session == nil || session.UserUUID.String() != currentUser.UUID {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 404,
+			GrpcCode: codes.NotFound,
+			Message:  "session not found",
+		}
+	}
+
+	if session.RevokedAt == nil {
+		err = s.sessionRepo.Revoke(session.UUID.String(), model.SessionRevokedReasonUser, helper.TimeNowUTC())
+		if err != nil {
+			logger.Errorf("error revoking session: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: codes.Internal,
+				Message:  "internal server error",
+				Detail:   err.Error(),
+			}
+		}
+	}
+
+	return &dto.RevokeSessionRespData{
+		UUID: session.UUID.String(),
+	}, nil
+}
+
+// startLoginChallenge creates the short lived challenge of a login that still
+// needs a 2fa code.
+func (s *AuthUcase) startLoginChallenge(user *model.User, device string, ip string) (*dto.LoginRespData, error) {
+	token, err := token_util.Generate()
+	if err != nil {
+		logger.Errorf("error generating challenge token: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	challenge := &model.LoginChallenge{
+		UUID:      uuid.New(),
+		UserUUID:  user.UUID,
+		TokenHash: token_util.Hash(token),
+		Device:    device,
+		IP:        ip,
+		ExpiredAt: helper.TimeNowUTC().Add(time.Minute * time.Duration(config.Envs.LOGIN_CHALLENGE_EXP_MINUTES)),
+	}
+	err = s.loginChallengeRepo.Create(challenge)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	return &dto.LoginRespData{
+		TwoFactorRequired:           true,
+		TwoFactorEnrollmentRequired: !user.IsTOTPEnabled(),
+		ChallengeToken:              token,
+	}, nil
+}
+
+func (s *AuthUcase) getLoginChallenge(challengeToken string) (*model.LoginChallenge, *model.User, error) {
+	challenge, err := s.loginChallengeRepo.GetByTokenHash(token_util.Hash(challengeToken))
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, nil, invalidChallengeTokenErr()
+		}
+		logger.Errorf("err: %v", err)
+		return nil, nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+	if !challenge.IsUsable(helper.TimeNowUTC()) {
+		return nil, nil, invalidChallengeTokenErr()
+	}
+
+	user, err := s.userRepo.GetByUUID(challenge.UserUUID.String())
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, nil, invalidChallengeTokenErr()
+		}
+		logger.Errorf("err: %v", err)
+		return nil, nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	return challenge, user, nil
+}
+
+func invalidChallengeTokenErr() error {
+	return &error_utils.CustomErr{
+		HttpCode: 401,
+		GrpcCode: codes.Unauthenticated,
+		Message:  "Invalid Challenge Token",
+		Detail:   "challenge token is invalid, used or expired, log in again",
+	}
+}
+
+// startSession creates a new session for the user with its first refresh
+// token, and an access token bound to it.
+func (s *AuthUcase) startSession(user *model.User, device string, ip string) (string, string, error) {
+	timeNow := helper.TimeNowUTC()
+	session := &model.Session{
+		UUID:       uuid.New(),
+		UserUUID:   user.UUID,
+		Device:     device,
+		IP:         ip,
+		LastUsedAt: timeNow,
+		ExpiredAt:  timeNow.Add(time.Hour * time.Duration(config.Envs.JWT_REFRESH_EXP_HOURS)),
+	}
+	err := s.sessionRepo.Create(session)
+	if err != nil {
+		logger.Errorf("error creating session: %v", err)
+		return "", "", err
+	}
+
+	refreshToken, err := s.createRefreshToken(user, session)
+	if err != nil {
+		return "", "", err
+	}
+
+	accessToken, err := s.generateAccessToken(user, session)
+	if err != nil {
+		return "", "", err
+	}
+
+	return accessToken, refreshToken.Token, nil
+}
+
+func (s *AuthUcase) createRefreshToken(user *model.User, session *model.Session) (*model.RefreshToken, error) {
+	refreshTokenExpiredAt := helper.TimeNowUTC().Add(time.Hour * time.Duration(config.Envs.JWT_REFRESH_EXP_HOURS))
+	newRefreshTokenObj := model.RefreshToken{
+		Token:       uuid.New().String(),
+		UserUUID:    user.UUID,
+		SessionUUID: &session.UUID,
+		UsedAt:      nil,
+		ExpiredAt:   &refreshTokenExpiredAt,
+	}
+	err := s.refreshTokenRepo.Create(&newRefreshTokenObj)
+	if err != nil {
+		logger.Errorf("error creating refresh token: %v", err)
+		return nil, err
+	}
+
+	return &newRefreshTokenObj, nil
+}
+
+func (s *AuthUcase) generateAccessToken(user *model.User, session *model.Session) (string, error) {
+	signingKey, err := s.signingKeyUcase.GetSigningKey()
+	if err != nil {
+		logger.Errorf("error getting signing key: %v", err)
+		return "", err
+	}
+
+	// permissions are embedded as they are now, role changes apply on refresh
+	permissions, err := s.getRolePermissions(user.Role)
+	if err != nil {
+		logger.Errorf("error getting role permissions: %v", err)
+		return "", err
+	}
+
+	tokenId := uuid.New().String()
+	sessionId := session.UUID.String()
+	token, err := jwt_util.GenerateJwtToken(user, permissions, signingKey, claimsConfig(), config.Envs.JWT_EXP_HOURS, &tokenId, &sessionId)
+	if err != nil {
+		logger.Errorf("error generating token: %v", err)
+		return "", err
+	}
+
+	return token, nil
+}
+
+// getRolePermissions returns no permission for roles that do not exist.
+func (s *AuthUcase) getRolePermissions(roleName string) ([]string, error) {
+	role, err := s.roleRepo.GetByName(roleName)
+	if err != nil {
+		if err.Error() == "not found" {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+	return role.PermissionNames(), nil
+}
+
+func claimsConfig() jwt_util.ClaimsConfig {
+	return jwt_util.ClaimsConfig{
+		Issuer:   config.Envs.JWT_ISSUER,
+		Audience: config.Envs.JWT_AUDIENCE,
+	}
+}
+
+// handleRefreshTokenReuse revokes the whole session of a replayed refresh
+// token and records a security event. always returns the error to respond.
+func (s *AuthUcase) handleRefreshTokenReuse(refreshToken *model.RefreshToken, payload dto.RefreshTokenReq) error {
+	logger.Warningf("refresh token reuse detected, session: %s", refreshToken.SessionUUID.String())
+
+	err := s.sessionRepo.Revoke(refreshToken.SessionUUID.String(), model.SessionRevokedReasonReuse, helper.TimeNowUTC())
+	if err != nil {
+		logger.Errorf("error revoking session: %v", err)
+	}
+
+	detail := fmt.Sprintf("refresh token %d presented again after rotation", refreshToken.ID)
+	err = s.securityEventRepo.Create(&model.SecurityEvent{
+		UUID:        uuid.New(),
+		UserUUID:    refreshToken.UserUUID,
+		SessionUUID: refreshToken.SessionUUID,
+		Type:        model.SecurityEventTypeRefreshTokenReuse,
+		IP:          payload.IP,
+		Device:      payload.Device,
+		Detail:      &detail,
+	})
+	if err != nil {
+		logger.Errorf("error creating security event: %v", err)
+	}
+
+	return &error_utils.CustomErr{
+		HttpCode: 401,
+		Message:  "Invalid Refresh Token",
+		Detail:   "refresh token reuse detected, session revoked",
+	}
+}
+
+// revokeAccessToken stores the jti of the current access token until it
+// expires, and drops revocations that are no longer needed.
+func (s *AuthUcase) revokeAccessToken(currentUser dto.CurrentUser) error {
+	userUUID, err := uuid.Parse(currentUser.UUID)
+	if err != nil {
+		return err
+	}
+
+	err = s.revokedTokenRepo.Create(&model.RevokedToken{
+		TokenID:   currentUser.TokenID,
+		UserUUID:  userUUID,
+		ExpiredAt: currentUser.ExpiredAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = s.revokedTokenRepo.DeleteExpired(helper.TimeNowUTC())
+	if err != nil {
+		logger.Warningf("failed to delete expired revoked tokens: %v", err)
+	}
+
+	return nil
+}
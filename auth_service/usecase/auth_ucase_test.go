@@ -0,0 +1,195 @@
+package ucase
+
+import (
+	"auth_service/config"
+	"auth_service/domain/dto"
+	"auth_service/domain/model"
+	mocks "auth_service/mocks/repository"
+	"auth_service/repository"
+	error_utils "auth_service/utils/error"
+	jwt_util "auth_service/utils/jwt"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRefreshTokenRepo keeps refresh tokens by token, calling any method not
+// listed here panics.
+type fakeRefreshTokenRepo struct {
+	repository.IRefreshTokenRepo
+	tokens map[string]*model.RefreshToken
+}
+
+func (repo *fakeRefreshTokenRepo) Create(refreshToken *model.RefreshToken) error {
+	refreshToken.ID = uint(len(repo.tokens) + 1)
+	repo.tokens[refreshToken.Token] = refreshToken
+	return nil
+}
+
+func (repo *fakeRefreshTokenRepo) GetByToken(token string) (*model.RefreshToken, error) {
+	refreshToken, ok := repo.tokens[token]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	// the repo returns a fresh row on every call
+	copied := *refreshToken
+	return &copied, nil
+}
+
+func (repo *fakeRefreshTokenRepo) MarkUsed(refreshToken *model.RefreshToken, now time.Time) error {
+	stored := repo.tokens[refreshToken.Token]
+	if stored.UsedAt != nil {
+		return errors.New("already used")
+	}
+	stored.UsedAt = &now
+	refreshToken.UsedAt = &now
+	return nil
+}
+
+// fakeSigningKeyUcase signs with a single generated key.
+type fakeSigningKeyUcase struct {
+	ISigningKeyUcase
+	signingKey *jwt_util.SigningKey
+	publicKey  *jwt_util.PublicKey
+}
+
+func newFakeSigningKeyUcase(t *testing.T) *fakeSigningKeyUcase {
+	privatePem, publicPem, err := jwt_util.GenerateKeyPair(jwt_util.AlgorithmEdDSA)
+	assert.NoError(t, err)
+	signingKey, err := jwt_util.ParseSigningKey("test", jwt_util.AlgorithmEdDSA, privatePem)
+	assert.NoError(t, err)
+	publicKey, err := jwt_util.ParsePublicKey("test", jwt_util.AlgorithmEdDSA, publicPem)
+	assert.NoError(t, err)
+	return &fakeSigningKeyUcase{signingKey: signingKey, publicKey: publicKey}
+}
+
+func (ucase *fakeSigningKeyUcase) GetSigningKey() (*jwt_util.SigningKey, error) {
+	return ucase.signingKey, nil
+}
+
+func (ucase *fakeSigningKeyUcase) GetPublicKey(kid string) (*jwt_util.PublicKey, error) {
+	if kid != ucase.publicKey.KID {
+		return nil, errors.New("not found")
+	}
+	return ucase.publicKey, nil
+}
+
+type refreshTokenTestEnv struct {
+	ucase             IAuthUcase
+	refreshTokenRepo  *fakeRefreshTokenRepo
+	sessionRepo       *fakeSessionRepo
+	securityEventRepo *fakeSecurityEventRepo
+	signingKeyUcase   *fakeSigningKeyUcase
+	session           *model.Session
+	token             string
+}
+
+// newRefreshTokenTestEnv starts a session of one user holding one unused
+// refresh token.
+func newRefreshTokenTestEnv(t *testing.T) *refreshTokenTestEnv {
+	config.Envs = &config.EnvsSchema{
+		JWT_ISSUER:            "auth_service",
+		JWT_AUDIENCE:          "library_app",
+		JWT_EXP_HOURS:         1,
+		JWT_REFRESH_EXP_HOURS: 24,
+	}
+
+	user := &model.User{UUID: uuid.New(), Username: "user", Role: model.RoleUser}
+	userRepo := mocks.NewIUserRepo(t)
+	userRepo.On("GetByUUID", user.UUID.String()).Return(user, nil).Maybe()
+	roleRepo := mocks.NewIRoleRepo(t)
+	roleRepo.On("GetByName", model.RoleUser).Return(nil, errors.New("not found")).Maybe()
+
+	now := time.Now().UTC()
+	expiredAt := now.Add(time.Hour)
+	session := &model.Session{UUID: uuid.New(), UserUUID: user.UUID, ExpiredAt: expiredAt}
+	refreshToken := &model.RefreshToken{
+		Token:       uuid.New().String(),
+		UserUUID:    user.UUID,
+		SessionUUID: &session.UUID,
+		ExpiredAt:   &expiredAt,
+	}
+
+	env := &refreshTokenTestEnv{
+		refreshTokenRepo:  &fakeRefreshTokenRepo{tokens: map[string]*model.RefreshToken{}},
+		sessionRepo:       &fakeSessionRepo{sessions: map[string]*model.Session{session.UUID.String(): session}},
+		securityEventRepo: &fakeSecurityEventRepo{},
+		signingKeyUcase:   newFakeSigningKeyUcase(t),
+		session:           session,
+		token:             refreshToken.Token,
+	}
+	env.refreshTokenRepo.Create(refreshToken)
+	env.ucase = NewAuthUcase(userRepo, env.refreshTokenRepo, nil, env.sessionRepo, env.securityEventRepo,
+		env.signingKeyUcase, nil, nil, nil, nil, roleRepo, nil, nil)
+	return env
+}
+
+func TestAuthUcase_RefreshToken_Rotation(t *testing.T) {
+	env := newRefreshTokenTestEnv(t)
+
+	resp, err := env.ucase.RefreshToken(dto.RefreshTokenReq{RefreshToken: env.token, IP: "10.0.0.1"})
+	assert.NoError(t, err)
+	assert.NotEqual(t, env.token, resp.RefreshToken)
+
+	// the old token is spent, the new one belongs to the same session
+	assert.NotNil(t, env.refreshTokenRepo.tokens[env.token].UsedAt)
+	rotated := env.refreshTokenRepo.tokens[resp.RefreshToken]
+	assert.NotNil(t, rotated)
+	assert.Nil(t, rotated.UsedAt)
+	assert.Equal(t, env.session.UUID, *rotated.SessionUUID)
+	assert.Equal(t, "10.0.0.1", env.session.IP)
+	assert.Equal(t, *rotated.ExpiredAt, env.session.ExpiredAt)
+
+	currentUser, err := jwt_util.ValidateJWT(resp.AccessToken, env.signingKeyUcase.GetPublicKey, claimsConfig())
+	assert.NoError(t, err)
+	assert.Equal(t, env.session.UUID.String(), currentUser.SessionID)
+
+	// the rotated token keeps rotating
+	_, err = env.ucase.RefreshToken(dto.RefreshTokenReq{RefreshToken: resp.RefreshToken})
+	assert.NoError(t, err)
+	assert.Empty(t, env.securityEventRepo.types)
+}
+
+func TestAuthUcase_RefreshToken_Reuse(t *testing.T) {
+	env := newRefreshTokenTestEnv(t)
+
+	resp, err := env.ucase.RefreshToken(dto.RefreshTokenReq{RefreshToken: env.token})
+	assert.NoError(t, err)
+
+	// presenting the rotated token again revokes the whole family
+	_, err = env.ucase.RefreshToken(dto.RefreshTokenReq{RefreshToken: env.token})
+	assertHttpCode(t, err, 401)
+	assert.NotNil(t, env.session.RevokedAt)
+	assert.Equal(t, model.SessionRevokedReasonReuse, *env.session.RevokedReason)
+	assert.Equal(t, []string{model.SecurityEventTypeRefreshTokenReuse}, env.securityEventRepo.types)
+
+	// so the token issued by the rotation is dead too
+	_, err = env.ucase.RefreshToken(dto.RefreshTokenReq{RefreshToken: resp.RefreshToken})
+	assertHttpCode(t, err, 401)
+	assert.Len(t, env.securityEventRepo.types, 1)
+}
+
+func TestAuthUcase_RefreshToken_Revoked(t *testing.T) {
+	env := newRefreshTokenTestEnv(t)
+	err := env.sessionRepo.Revoke(env.session.UUID.String(), model.SessionRevokedReasonLogout, time.Now().UTC())
+	assert.NoError(t, err)
+
+	_, err = env.ucase.RefreshToken(dto.RefreshTokenReq{RefreshToken: env.token})
+	assertHttpCode(t, err, 401)
+	assert.Nil(t, env.refreshTokenRepo.tokens[env.token].UsedAt)
+	assert.Empty(t, env.securityEventRepo.types)
+
+	_, err = env.ucase.RefreshToken(dto.RefreshTokenReq{RefreshToken: "unknown"})
+	assertHttpCode(t, err, 401)
+}
+
+func assertHttpCode(t *testing.T, err error, want int) {
+	t.Helper()
+	customErr, ok := err.(*error_utils.CustomErr)
+	if assert.True(t, ok, "expected a custom error, got %v", err) {
+		assert.Equal(t, want, customErr.HttpCode)
+	}
+}
@@ -0,0 +1,214 @@
+package ucase
+
+import (
+	"auth_service/config"
+	"auth_service/domain/dto"
+	"auth_service/domain/model"
+	"auth_service/repository"
+	error_utils "auth_service/utils/error"
+	"auth_service/utils/helper"
+	"auth_service/utils/mailer"
+	token_util "auth_service/utils/token"
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+)
+
+type EmailVerificationUcase struct {
+	userRepo                   repository.IUserRepo
+	emailVerificationTokenRepo repository.IEmailVerificationTokenRepo
+	mailer                     mailer.Mailer
+}
+
+type IEmailVerificationUcase interface {
+	SendVerificationEmail(ctx context.Context, user *model.User) error
+	ResendVerificationEmail(ctx context.Context, currentUser dto.CurrentUser) (*dto.ResendVerificationEmailRespData, error)
+	VerifyEmail(ctx context.Context, payload dto.VerifyEmailReq) (*dto.VerifyEmailRespData, error)
+}
+
+func NewEmailVerificationUcase(
+	userRepo repository.IUserRepo,
+	emailVerificationTokenRepo repository.IEmailVerificationTokenRepo,
+	mailer mailer.Mailer,
+) IEmailVerificationUcase {
+	return &EmailVerificationUcase{
+		userRepo:                   userRepo,
+		emailVerificationTokenRepo: emailVerificationTokenRepo,
+		mailer:                     mailer,
+	}
+}
+
+// SendVerificationEmail mails a new verification token for the current email
+// of the user, earlier tokens stop working.
+func (ucase *EmailVerificationUcase) SendVerificationEmail(ctx context.Context, user *model.User) error {
+	timeNow := helper.TimeNowUTC()
+	err := ucase.emailVerificationTokenRepo.InvalidateManyByUserUUID(user.UUID.String(), timeNow)
+	if err != nil {
+		return err
+	}
+
+	token, err := token_util.Generate()
+	if err != nil {
+		return err
+	}
+
+	verificationToken := &model.EmailVerificationToken{
+		UUID:      uuid.New(),
+		UserUUID:  user.UUID,
+		Email:     user.Email,
+		TokenHash: token_util.Hash(token),
+		ExpiredAt: timeNow.Add(time.Hour * time.Duration(config.Envs.EMAIL_VERIFICATION_EXP_HOURS)),
+	}
+	err = ucase.emailVerificationTokenRepo.Create(verificationToken)
+	if err != nil {
+		return err
+	}
+
+	return ucase.mailer.Send(ctx, emailVerificationMessage(user, token, verificationToken.ExpiredAt))
+}
+
+func (ucase *EmailVerificationUcase) ResendVerificationEmail(ctx context.Context, currentUser dto.CurrentUser) (*dto.ResendVerificationEmailRespData, error) {
+	user, err := ucase.userRepo.GetByUUID(currentUser.UUID)
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, &error_utils.CustomErr{
+				HttpCode: 404,
+				GrpcCode: codes.NotFound,
+				Message:  "user not found",
+			}
+		}
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	if user.IsEmailVerified() {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.FailedPrecondition,
+			Message:  "email already verified",
+		}
+	}
+
+	err = ucase.SendVerificationEmail(ctx, user)
+	if err != nil {
+		logger.Errorf("error sending verification mail: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   "failed to send verification mail",
+		}
+	}
+
+	return &dto.ResendVerificationEmailRespData{
+		Email: user.Email,
+	}, nil
+}
+
+func (ucase *EmailVerificationUcase) VerifyEmail(ctx context.Context, payload dto.VerifyEmailReq) (*dto.VerifyEmailRespData, error) {
+	invalidTokenErr := &error_utils.CustomErr{
+		HttpCode: 400,
+		GrpcCode: codes.InvalidArgument,
+		Message:  "Invalid Verification Token",
+		Detail:   "verification token is invalid, used or expired",
+	}
+
+	// find token
+	verificationToken, err := ucase.emailVerificationTokenRepo.GetByTokenHash(token_util.Hash(payload.Token))
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, invalidTokenErr
+		}
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	timeNow := helper.TimeNowUTC()
+	if !verificationToken.IsUsable(timeNow) {
+		return nil, invalidTokenErr
+	}
+
+	user, err := ucase.userRepo.GetByUUID(verificationToken.UserUUID.String())
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, invalidTokenErr
+		}
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	// the email changed since the token was sent
+	if user.Email != verificationToken.Email {
+		return nil, invalidTokenErr
+	}
+
+	err = ucase.emailVerificationTokenRepo.MarkUsed(verificationToken, timeNow)
+	if err != nil {
+		if err.Error() == "already used" {
+			return nil, invalidTokenErr
+		}
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	if !user.IsEmailVerified() {
+		user.EmailVerifiedAt = &timeNow
+		err = ucase.userRepo.Update(user)
+		if err != nil {
+			logger.Errorf("err: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: codes.Internal,
+				Message:  "internal server error",
+				Detail:   err.Error(),
+			}
+		}
+	}
+
+	return &dto.VerifyEmailRespData{
+		UUID:            user.UUID.String(),
+		Email:           user.Email,
+		EmailVerifiedAt: *user.EmailVerifiedAt,
+	}, nil
+}
+
+func emailVerificationMessage(user *model.User, token string, expiredAt time.Time) mailer.Message {
+	instruction := fmt.Sprintf("Use this token to verify your email:\n\n%s", token)
+	if config.Envs.EMAIL_VERIFICATION_URL != "" {
+		link := config.Envs.EMAIL_VERIFICATION_URL + "?" + url.Values{"token": {token}}.Encode()
+		instruction = fmt.Sprintf("Open this link to verify your email:\n\n%s", link)
+	}
+
+	return mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\n%s\n\nIt expires at %s. Until your email is verified you can log in, but not borrow books.\n",
+			user.Username, instruction, expiredAt.Format(time.RFC1123),
+		),
+	}
+}
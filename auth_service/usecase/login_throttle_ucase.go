@@ -0,0 +1,205 @@
+package ucase
+
+import (
+	"auth_service/config"
+	"auth_service/domain/dto"
+	"auth_service/domain/model"
+	"auth_service/repository"
+	error_utils "auth_service/utils/error"
+	"auth_service/utils/helper"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+)
+
+type LoginThrottleUcase struct {
+	userRepo          repository.IUserRepo
+	loginAttemptRepo  repository.ILoginAttemptRepo
+	securityEventRepo repository.ISecurityEventRepo
+}
+
+type ILoginThrottleUcase interface {
+	Unlock(payload dto.UnlockLoginReq) (*dto.UnlockLoginRespData, error)
+
+	// used by the login flow, user is nil when no account matched
+	Check(user *model.User, ip string) error
+	RegisterFailure(user *model.User, ip string, device string)
+	RegisterSuccess(user *model.User)
+}
+
+func NewLoginThrottleUcase(
+	userRepo repository.IUserRepo,
+	loginAttemptRepo repository.ILoginAttemptRepo,
+	securityEventRepo repository.ISecurityEventRepo,
+) ILoginThrottleUcase {
+	return &LoginThrottleUcase{
+		userRepo:          userRepo,
+		loginAttemptRepo:  loginAttemptRepo,
+		securityEventRepo: securityEventRepo,
+	}
+}
+
+// Unlock clears the failed logins of an account, a client ip or both.
+func (ucase *LoginThrottleUcase) Unlock(payload dto.UnlockLoginReq) (*dto.UnlockLoginRespData, error) {
+	if payload.UserUUID == "" && payload.IP == "" {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.InvalidArgument,
+			Message:  "user_uuid or ip is required",
+		}
+	}
+
+	keys := []string{}
+	if payload.UserUUID != "" {
+		_, err := ucase.userRepo.GetByUUID(payload.UserUUID)
+		if err != nil {
+			if err.Error() == "not found" {
+				return nil, &error_utils.CustomErr{
+					HttpCode: 404,
+					GrpcCode: codes.NotFound,
+					Message:  "user not found",
+				}
+			}
+			logger.Errorf("err: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: codes.Internal,
+				Message:  "internal server error",
+				Detail:   err.Error(),
+			}
+		}
+		keys = append(keys, model.LoginAttemptAccountKey(payload.UserUUID))
+	}
+	if payload.IP != "" {
+		keys = append(keys, model.LoginAttemptIPKey(payload.IP))
+	}
+
+	for _, key := range keys {
+		err := ucase.loginAttemptRepo.Reset(key)
+		if err != nil {
+			logger.Errorf("err: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: codes.Internal,
+				Message:  "internal server error",
+				Detail:   err.Error(),
+			}
+		}
+	}
+
+	return &dto.UnlockLoginRespData{
+		UserUUID: payload.UserUUID,
+		IP:       payload.IP,
+	}, nil
+}
+
+// Check refuses the login while the client ip or the account is blocked.
+// store errors do not block logins, they are only logged.
+func (ucase *LoginThrottleUcase) Check(user *model.User, ip string) error {
+	timeNow := helper.TimeNowUTC()
+	for _, key := range loginAttemptKeys(user, ip) {
+		attempt, err := ucase.loginAttemptRepo.GetByKey(key)
+		if err != nil {
+			if err.Error() != "not found" {
+				logger.Errorf("error getting login attempts: %v", err)
+			}
+			continue
+		}
+
+		if attempt.IsBlocked(timeNow) {
+			return loginBlockedErr(attempt, timeNow)
+		}
+	}
+	return nil
+}
+
+// RegisterFailure counts a failed login and blocks the keys for a backoff
+// that doubles on each failure, or for the whole lockout once the maximum is
+// reached.
+func (ucase *LoginThrottleUcase) RegisterFailure(user *model.User, ip string, device string) {
+	timeNow := helper.TimeNowUTC()
+	windowStart := timeNow.Add(-time.Minute * time.Duration(config.Envs.LOGIN_FAILURE_WINDOW_MINUTES))
+
+	for _, key := range loginAttemptKeys(user, ip) {
+		attempt, err := ucase.loginAttemptRepo.RegisterFailure(key, timeNow, windowStart)
+		if err != nil {
+			logger.Errorf("error registering login failure: %v", err)
+			continue
+		}
+
+		maxFailures := config.Envs.LOGIN_MAX_IP_FAILURES
+		if key != model.LoginAttemptIPKey(ip) {
+			maxFailures = config.Envs.LOGIN_MAX_ACCOUNT_FAILURES
+		}
+
+		err = ucase.loginAttemptRepo.Block(key, timeNow.Add(loginBackoff(attempt.Failures, maxFailures)))
+		if err != nil {
+			logger.Errorf("error blocking login: %v", err)
+			continue
+		}
+
+		if user != nil && attempt.Failures == maxFailures && key == model.LoginAttemptAccountKey(user.UUID.String()) {
+			logger.Warningf("account %s locked after %d failed logins", user.UUID.String(), attempt.Failures)
+			err = ucase.securityEventRepo.Create(&model.SecurityEvent{
+				UUID:     uuid.New(),
+				UserUUID: user.UUID,
+				Type:     model.SecurityEventTypeAccountLocked,
+				IP:       ip,
+				Device:   device,
+			})
+			if err != nil {
+				logger.Errorf("error creating security event: %v", err)
+			}
+		}
+	}
+}
+
+// RegisterSuccess forgets the failed logins of the account. the ip counter is
+// kept, otherwise one valid account would reset guesses on the others.
+func (ucase *LoginThrottleUcase) RegisterSuccess(user *model.User) {
+	err := ucase.loginAttemptRepo.Reset(model.LoginAttemptAccountKey(user.UUID.String()))
+	if err != nil {
+		logger.Errorf("error resetting login attempts: %v", err)
+	}
+}
+
+func loginAttemptKeys(user *model.User, ip string) []string {
+	keys := []string{model.LoginAttemptIPKey(ip)}
+	if user != nil {
+		keys = append(keys, model.LoginAttemptAccountKey(user.UUID.String()))
+	}
+	return keys
+}
+
+// loginBackoff is how long logins are refused after the given number of
+// failures: base, 2*base, 4*base... capped by the lockout, and the full
+// lockout from maxFailures on.
+func loginBackoff(failures int, maxFailures int) time.Duration {
+	lockout := time.Minute * time.Duration(config.Envs.LOGIN_LOCKOUT_MINUTES)
+	if failures >= maxFailures {
+		return lockout
+	}
+
+	backoff := time.Second * time.Duration(config.Envs.LOGIN_BACKOFF_BASE_SECONDS) * time.Duration(math.Pow(2, float64(failures-1)))
+	if backoff > lockout {
+		return lockout
+	}
+	return backoff
+}
+
+func loginBlockedErr(attempt *model.LoginAttempt, now time.Time) error {
+	retryAfter := int(math.Ceil(attempt.BlockedUntil.Sub(now).Seconds()))
+	return &error_utils.CustomErr{
+		HttpCode: 429,
+		GrpcCode: codes.ResourceExhausted,
+		Message:  "Too Many Login Attempts",
+		Detail:   fmt.Sprintf("too many failed logins, retry in %d seconds", retryAfter),
+		Data: dto.LoginBlockedRespData{
+			BlockedUntil:      *attempt.BlockedUntil,
+			RetryAfterSeconds: retryAfter,
+		},
+	}
+}
@@ -0,0 +1,280 @@
+package ucase
+
+import (
+	"auth_service/config"
+	"auth_service/domain/dto"
+	"auth_service/domain/model"
+	"auth_service/repository"
+	bcrypt_util "auth_service/utils/bcrypt"
+	error_utils "auth_service/utils/error"
+	"auth_service/utils/helper"
+	jwt_util "auth_service/utils/jwt"
+	"auth_service/utils/mailer"
+	token_util "auth_service/utils/token"
+	validator_util "auth_service/utils/validator/user"
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+)
+
+type PasswordResetUcase struct {
+	userRepo               repository.IUserRepo
+	refreshTokenRepo       repository.IRefreshTokenRepo
+	sessionRepo            repository.ISessionRepo
+	securityEventRepo      repository.ISecurityEventRepo
+	passwordResetTokenRepo repository.IPasswordResetTokenRepo
+	mailer                 mailer.Mailer
+}
+
+type IPasswordResetUcase interface {
+	ForgotPassword(ctx context.Context, payload dto.ForgotPasswordReq) (*dto.ForgotPasswordRespData, error)
+	ResetPassword(ctx context.Context, payload dto.ResetPasswordReq) (*dto.ResetPasswordRespData, error)
+}
+
+func NewPasswordResetUcase(
+	userRepo repository.IUserRepo,
+	refreshTokenRepo repository.IRefreshTokenRepo,
+	sessionRepo repository.ISessionRepo,
+	securityEventRepo repository.ISecurityEventRepo,
+	passwordResetTokenRepo repository.IPasswordResetTokenRepo,
+	mailer mailer.Mailer,
+) IPasswordResetUcase {
+	return &PasswordResetUcase{
+		userRepo:               userRepo,
+		refreshTokenRepo:       refreshTokenRepo,
+		sessionRepo:            sessionRepo,
+		securityEventRepo:      securityEventRepo,
+		passwordResetTokenRepo: passwordResetTokenRepo,
+		mailer:                 mailer,
+	}
+}
+
+// ForgotPassword mails a reset token to the user. the response is the same
+// whether the email is registered or not, so it can not be used to probe for
+// accounts.
+func (ucase *PasswordResetUcase) ForgotPassword(ctx context.Context, payload dto.ForgotPasswordReq) (*dto.ForgotPasswordRespData, error) {
+	err := validator_util.ValidateEmail(payload.Email)
+	if err != nil {
+		logger.Errorf("error validating email: %s", err.Error())
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.InvalidArgument,
+			Message:  err.Error(),
+		}
+	}
+
+	resp := &dto.ForgotPasswordRespData{
+		Email: payload.Email,
+	}
+
+	user, err := ucase.userRepo.GetByEmail(payload.Email)
+	if err != nil || user == nil {
+		logger.Infof("password reset requested for unknown email: %s", payload.Email)
+		return resp, nil
+	}
+
+	// only the latest requested token stays usable
+	timeNow := helper.TimeNowUTC()
+	err = ucase.passwordResetTokenRepo.InvalidateManyByUserUUID(user.UUID.String(), timeNow)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	token, err := token_util.Generate()
+	if err != nil {
+		logger.Errorf("error generating reset token: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	resetToken := &model.PasswordResetToken{
+		UUID:      uuid.New(),
+		UserUUID:  user.UUID,
+		TokenHash: token_util.Hash(token),
+		ExpiredAt: timeNow.Add(time.Minute * time.Duration(config.Envs.PASSWORD_RESET_EXP_MINUTES)),
+	}
+	err = ucase.passwordResetTokenRepo.Create(resetToken)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	err = ucase.mailer.Send(ctx, passwordResetMessage(user, token, resetToken.ExpiredAt))
+	if err != nil {
+		logger.Errorf("error sending password reset mail: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   "failed to send password reset mail",
+		}
+	}
+
+	return resp, nil
+}
+
+// ResetPassword consumes the reset token and sets the new password. every
+// session of the user is signed out.
+func (ucase *PasswordResetUcase) ResetPassword(ctx context.Context, payload dto.ResetPasswordReq) (*dto.ResetPasswordRespData, error) {
+	err := validator_util.ValidatePassword(payload.Password)
+	if err != nil {
+		logger.Errorf("error validating password: %s", err.Error())
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.InvalidArgument,
+			Message:  err.Error(),
+		}
+	}
+
+	invalidTokenErr := &error_utils.CustomErr{
+		HttpCode: 400,
+		GrpcCode: codes.InvalidArgument,
+		Message:  "Invalid Reset Token",
+		Detail:   "reset token is invalid, used or expired",
+	}
+
+	// find token
+	resetToken, err := ucase.passwordResetTokenRepo.GetByTokenHash(token_util.Hash(payload.Token))
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, invalidTokenErr
+		}
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	timeNow := helper.TimeNowUTC()
+	if !resetToken.IsUsable(timeNow) {
+		return nil, invalidTokenErr
+	}
+
+	user, err := ucase.userRepo.GetByUUID(resetToken.UserUUID.String())
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, invalidTokenErr
+		}
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	// consume token, fails when a concurrent reset used it first
+	err = ucase.passwordResetTokenRepo.MarkUsed(resetToken, timeNow)
+	if err != nil {
+		if err.Error() == "already used" {
+			return nil, invalidTokenErr
+		}
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	// set password and revoke every access token issued until now
+	password, err := bcrypt_util.Hash(payload.Password)
+	if err != nil {
+		logger.Errorf("error hashing password: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+	validAfter := jwt_util.TokensValidAfter(timeNow)
+	user.Password = password
+	user.TokensValidAfter = &validAfter
+	err = ucase.userRepo.Update(user)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	// invalidate refresh tokens and the sessions they belong to
+	err = ucase.refreshTokenRepo.InvalidateManyByUserUUID(user.UUID.String())
+	if err != nil {
+		logger.Errorf("error invalidating refresh tokens: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+	err = ucase.sessionRepo.RevokeManyByUserUUID(user.UUID.String(), model.SessionRevokedReasonPassword, timeNow)
+	if err != nil {
+		logger.Errorf("error revoking sessions: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	err = ucase.securityEventRepo.Create(&model.SecurityEvent{
+		UUID:     uuid.New(),
+		UserUUID: user.UUID,
+		Type:     model.SecurityEventTypePasswordReset,
+	})
+	if err != nil {
+		logger.Errorf("error creating security event: %v", err)
+	}
+
+	return &dto.ResetPasswordRespData{
+		UUID: user.UUID.String(),
+	}, nil
+}
+
+func passwordResetMessage(user *model.User, token string, expiredAt time.Time) mailer.Message {
+	instruction := fmt.Sprintf("Use this token to reset your password:\n\n%s", token)
+	if config.Envs.PASSWORD_RESET_URL != "" {
+		link := config.Envs.PASSWORD_RESET_URL + "?" + url.Values{"token": {token}}.Encode()
+		instruction = fmt.Sprintf("Open this link to reset your password:\n\n%s", link)
+	}
+
+	return mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\n%s\n\nIt expires at %s and can only be used once. If you did not request a password reset, you can ignore this mail.\n",
+			user.Username, instruction, expiredAt.Format(time.RFC1123),
+		),
+	}
+}
@@ -0,0 +1,193 @@
+package ucase
+
+import (
+	"auth_service/config"
+	"auth_service/domain/dto"
+	"auth_service/domain/model"
+	author_grpc "auth_service/interface/grpc/genproto/author"
+	"auth_service/repository"
+	error_utils "auth_service/utils/error"
+	"auth_service/utils/helper"
+	saga_util "auth_service/utils/saga"
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type ReconcileUcase struct {
+	userRepo                repository.IUserRepo
+	sagaRepo                repository.ISagaRepo
+	authorGrpcServiceClient author_grpc.AuthorServiceClient
+}
+
+type IReconcileUcase interface {
+	// ReconcileUsers finishes the registrations left behind by a crash or a
+	// failed compensation, and removes the users author_service created for
+	// an author that was never created.
+	ReconcileUsers(ctx context.Context) (*dto.ReconcileRespData, error)
+}
+
+func NewReconcileUcase(
+	userRepo repository.IUserRepo,
+	sagaRepo repository.ISagaRepo,
+	authorGrpcServiceClient author_grpc.AuthorServiceClient,
+) IReconcileUcase {
+	return &ReconcileUcase{
+		userRepo:                userRepo,
+		sagaRepo:                sagaRepo,
+		authorGrpcServiceClient: authorGrpcServiceClient,
+	}
+}
+
+func (ucase *ReconcileUcase) ReconcileUsers(ctx context.Context) (*dto.ReconcileRespData, error) {
+	// sagas and users younger than this may still be in progress
+	staleBefore := helper.TimeNowUTC().Add(-time.Minute * time.Duration(config.Envs.RECONCILE_GRACE_MINUTES))
+	resp := &dto.ReconcileRespData{Items: []dto.ReconcileRespDataItem{}}
+
+	sagas, err := ucase.sagaRepo.GetStaleList(model.SagaNameRegister, staleBefore)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+	for i := range sagas {
+		resp.Items = append(resp.Items, ucase.reconcileRegisterSaga(ctx, &sagas[i]))
+	}
+
+	users, err := ucase.userRepo.GetListWithIdempotencyKey(staleBefore)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+	for _, user := range users {
+		hasAuthor, err := ucase.hasAuthor(ctx, user.UUID.String())
+		if err != nil {
+			resp.Items = append(resp.Items, dto.ReconcileRespDataItem{
+				Kind: "user", UUID: user.UUID.String(), Action: "failed", Detail: err.Error(),
+			})
+			continue
+		}
+		if hasAuthor {
+			// the saga of author_service completed, its retries are long over
+			err = ucase.userRepo.ClearIdempotencyKey(user.UUID.String())
+			if err != nil {
+				resp.Items = append(resp.Items, dto.ReconcileRespDataItem{
+					Kind: "user", UUID: user.UUID.String(), Action: "failed", Detail: err.Error(),
+				})
+			}
+			continue
+		}
+
+		// the saga of author_service that created the user never got to the author
+		err = ucase.userRepo.Purge(user.UUID.String())
+		if err != nil && err.Error() != "not found" {
+			resp.Items = append(resp.Items, dto.ReconcileRespDataItem{
+				Kind: "user", UUID: user.UUID.String(), Action: "failed", Detail: err.Error(),
+			})
+			continue
+		}
+		resp.Items = append(resp.Items, dto.ReconcileRespDataItem{
+			Kind: "user", UUID: user.UUID.String(), Action: "purged", Detail: "user without author",
+		})
+	}
+
+	return resp, nil
+}
+
+// reconcileRegisterSaga completes a registration interrupted once the user and
+// its author exist, otherwise it undoes whatever the saga created.
+func (ucase *ReconcileUcase) reconcileRegisterSaga(ctx context.Context, saga *model.Saga) dto.ReconcileRespDataItem {
+	item := dto.ReconcileRespDataItem{Kind: "saga", UUID: saga.UUID.String()}
+	if saga.ResourceUUID == nil {
+		saga.Status = saga_util.StatusCompensated
+		item.Action = saga_util.StatusCompensated
+		item.Detail = "nothing was created"
+		ucase.updateSaga(saga)
+		return item
+	}
+	userUUID := saga.ResourceUUID.String()
+
+	hasUser := true
+	_, err := ucase.userRepo.GetByUUID(userUUID)
+	if err != nil {
+		if err.Error() != "not found" {
+			item.Action = "failed"
+			item.Detail = err.Error()
+			return item
+		}
+		hasUser = false
+	}
+
+	hasAuthor, err := ucase.hasAuthor(ctx, userUUID)
+	if err != nil {
+		item.Action = "failed"
+		item.Detail = err.Error()
+		return item
+	}
+
+	// only an interrupted saga is finished, a failed one was already reported
+	// as failed to the user
+	if saga.Status == saga_util.StatusStarted && hasUser && hasAuthor {
+		saga.Status = saga_util.StatusCompleted
+		item.Action = saga_util.StatusCompleted
+		item.Detail = "user and author exist"
+		ucase.updateSaga(saga)
+		return item
+	}
+
+	if hasAuthor {
+		_, err = ucase.authorGrpcServiceClient.PurgeAuthorByUserUUID(
+			ctx, &author_grpc.PurgeAuthorByUserUUIDReq{UserUuid: userUUID},
+		)
+		if err != nil && status.Code(err) != codes.NotFound {
+			item.Action = "failed"
+			item.Detail = err.Error()
+			return item
+		}
+	}
+	if hasUser {
+		err = ucase.userRepo.Purge(userUUID)
+		if err != nil && err.Error() != "not found" {
+			item.Action = "failed"
+			item.Detail = err.Error()
+			return item
+		}
+	}
+
+	saga.Status = saga_util.StatusCompensated
+	item.Action = saga_util.StatusCompensated
+	item.Detail = "removed the user and author of the registration"
+	ucase.updateSaga(saga)
+	return item
+}
+
+func (ucase *ReconcileUcase) hasAuthor(ctx context.Context, userUUID string) (bool, error) {
+	_, err := ucase.authorGrpcServiceClient.GetAuthorByUserUUID(
+		ctx, &author_grpc.GetAuthorByUserUUIDReq{UserUuid: userUUID},
+	)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (ucase *ReconcileUcase) updateSaga(saga *model.Saga) {
+	err := ucase.sagaRepo.Update(saga)
+	if err != nil {
+		logger.Errorf("error updating saga %s: %v", saga.UUID.String(), err)
+	}
+}
@@ -0,0 +1,76 @@
+package ucase
+
+import (
+	"auth_service/config"
+	"auth_service/domain/model"
+	author_grpc "auth_service/interface/grpc/genproto/author"
+	mocks "auth_service/mocks/repository"
+	"auth_service/repository"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeSagaRepo has no stale saga, calling any other method panics.
+type fakeSagaRepo struct {
+	repository.ISagaRepo
+}
+
+func (repo *fakeSagaRepo) GetStaleList(name string, updatedBefore time.Time) ([]model.Saga, error) {
+	return nil, nil
+}
+
+// fakeAuthorClient knows the authors of the users in userUUIDs.
+type fakeAuthorClient struct {
+	author_grpc.AuthorServiceClient
+	userUUIDs map[string]bool
+	calls     int
+}
+
+func (c *fakeAuthorClient) GetAuthorByUserUUID(
+	ctx context.Context,
+	in *author_grpc.GetAuthorByUserUUIDReq,
+	opts ...grpc.CallOption,
+) (*author_grpc.GetAuthorByUserUUIDResp, error) {
+	c.calls++
+	if !c.userUUIDs[in.UserUuid] {
+		return nil, status.Error(codes.NotFound, "author not found")
+	}
+	return &author_grpc.GetAuthorByUserUUIDResp{UserUuid: in.UserUuid}, nil
+}
+
+func TestReconcileUcase_ReconcileUsers(t *testing.T) {
+	config.Envs = &config.EnvsSchema{RECONCILE_GRACE_MINUTES: 10}
+
+	withAuthor := model.User{UUID: uuid.New()}
+	withoutAuthor := model.User{UUID: uuid.New()}
+	authorClient := &fakeAuthorClient{userUUIDs: map[string]bool{withAuthor.UUID.String(): true}}
+
+	userRepo := mocks.NewIUserRepo(t)
+	userRepo.On("GetListWithIdempotencyKey", mock.Anything).Return([]model.User{withAuthor, withoutAuthor}, nil).Once()
+	userRepo.On("ClearIdempotencyKey", withAuthor.UUID.String()).Return(nil).Once()
+	userRepo.On("Purge", withoutAuthor.UUID.String()).Return(nil).Once()
+
+	ucase := NewReconcileUcase(userRepo, &fakeSagaRepo{}, authorClient)
+
+	resp, err := ucase.ReconcileUsers(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, resp.Items, 1) {
+		assert.Equal(t, withoutAuthor.UUID.String(), resp.Items[0].UUID)
+		assert.Equal(t, "purged", resp.Items[0].Action)
+	}
+
+	// the completed saga is not checked again
+	userRepo.On("GetListWithIdempotencyKey", mock.Anything).Return([]model.User{}, nil).Once()
+	resp, err = ucase.ReconcileUsers(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Items)
+	assert.Equal(t, 2, authorClient.calls)
+}
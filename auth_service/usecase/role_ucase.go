@@ -0,0 +1,124 @@
+package ucase
+
+import (
+	"auth_service/domain/dto"
+	"auth_service/domain/model"
+	"auth_service/repository"
+	error_utils "auth_service/utils/error"
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+)
+
+type RoleUcase struct {
+	roleRepo repository.IRoleRepo
+}
+
+type IRoleUcase interface {
+	GetRoleList(ctx context.Context) ([]dto.GetRoleListRespDataItem, error)
+	GetPermissionList(ctx context.Context) []string
+	PutRole(ctx context.Context, name string, payload dto.PutRoleReq) (*dto.PutRoleRespData, error)
+}
+
+func NewRoleUcase(roleRepo repository.IRoleRepo) IRoleUcase {
+	return &RoleUcase{roleRepo: roleRepo}
+}
+
+func (ucase *RoleUcase) GetRoleList(ctx context.Context) ([]dto.GetRoleListRespDataItem, error) {
+	roles, err := ucase.roleRepo.GetList()
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	res := []dto.GetRoleListRespDataItem{}
+	for _, role := range roles {
+		res = append(res, dto.GetRoleListRespDataItem{
+			Name:        role.Name,
+			Description: role.Description,
+			Permissions: role.PermissionNames(),
+		})
+	}
+
+	return res, nil
+}
+
+func (ucase *RoleUcase) GetPermissionList(ctx context.Context) []string {
+	return model.Permissions
+}
+
+// PutRole creates the role or replaces its description and permissions. the
+// admin role can not be changed, so nobody locks the admins out.
+func (ucase *RoleUcase) PutRole(ctx context.Context, name string, payload dto.PutRoleReq) (*dto.PutRoleRespData, error) {
+	// validate input
+	if !model.IsValidRoleName(name) {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.InvalidArgument,
+			Message:  "invalid role name",
+			Detail:   "use 2 to 50 lowercase letters, digits, - or _",
+		}
+	}
+	if name == model.RoleAdmin {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.FailedPrecondition,
+			Message:  "admin role can not be changed",
+		}
+	}
+
+	permissions := []string{}
+	seen := map[string]bool{}
+	for _, permission := range payload.Permissions {
+		if !model.IsValidPermission(permission) {
+			return nil, &error_utils.CustomErr{
+				HttpCode: 400,
+				GrpcCode: codes.InvalidArgument,
+				Message:  fmt.Sprintf("invalid permission: %s", permission),
+			}
+		}
+		if !seen[permission] {
+			seen[permission] = true
+			permissions = append(permissions, permission)
+		}
+	}
+
+	// get existing role
+	role, err := ucase.roleRepo.GetByName(name)
+	if err != nil {
+		if err.Error() != "not found" {
+			logger.Errorf("err: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: codes.Internal,
+				Message:  "internal server error",
+				Detail:   err.Error(),
+			}
+		}
+		role = &model.Role{Name: name}
+	}
+	role.Description = payload.Description
+
+	err = ucase.roleRepo.Save(role, permissions)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	return &dto.PutRoleRespData{
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: role.PermissionNames(),
+	}, nil
+}
@@ -0,0 +1,31 @@
+package ucase
+
+import (
+	"auth_service/domain/model"
+	"auth_service/repository"
+	saga_util "auth_service/utils/saga"
+)
+
+// sagaRecorder saves every change of state of the saga. failures are only
+// logged, the reconciliation picks up sagas left behind anyway.
+func sagaRecorder(sagaRepo repository.ISagaRepo, saga *model.Saga) func(state saga_util.State) {
+	return func(state saga_util.State) {
+		saga.Status = state.Status
+		saga.Step = state.Step
+		saga.Error = nil
+		if state.Err != nil {
+			message := state.Err.Error()
+			saga.Error = &message
+		}
+
+		var err error
+		if saga.ID == 0 {
+			err = sagaRepo.Create(saga)
+		} else {
+			err = sagaRepo.Update(saga)
+		}
+		if err != nil {
+			logger.Errorf("error recording saga %s: %v", saga.UUID.String(), err)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package ucase
+
+import (
+	"auth_service/config"
+	"auth_service/domain/dto"
+	error_utils "auth_service/utils/error"
+	jwt_util "auth_service/utils/jwt"
+	"crypto/subtle"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+type ServiceAuthUcase struct {
+	signingKeyUcase ISigningKeyUcase
+	clientSecrets   map[string]string
+}
+
+type IServiceAuthUcase interface {
+	IssueServiceToken(payload dto.IssueServiceTokenReq) (*dto.IssueServiceTokenRespData, error)
+
+	// used by auth_service itself, for its own grpc calls and the ones it serves
+	GenerateServiceToken(serviceName string) (string, time.Time, error)
+	VerifyServiceToken(token string) (string, error)
+}
+
+func NewServiceAuthUcase(signingKeyUcase ISigningKeyUcase) IServiceAuthUcase {
+	return &ServiceAuthUcase{
+		signingKeyUcase: signingKeyUcase,
+		clientSecrets:   parseServiceClientSecrets(config.Envs.SERVICE_CLIENT_SECRETS),
+	}
+}
+
+// IssueServiceToken trades the secret of a service for a short lived service
+// token.
+func (ucase *ServiceAuthUcase) IssueServiceToken(payload dto.IssueServiceTokenReq) (*dto.IssueServiceTokenRespData, error) {
+	secret, ok := ucase.clientSecrets[payload.ServiceName]
+	if !ok || subtle.ConstantTimeCompare([]byte(secret), []byte(payload.ServiceSecret)) != 1 {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 401,
+			GrpcCode: codes.Unauthenticated,
+			Message:  "Invalid Service Credentials",
+		}
+	}
+
+	token, expiredAt, err := ucase.GenerateServiceToken(payload.ServiceName)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	return &dto.IssueServiceTokenRespData{
+		Token:     token,
+		ExpiredAt: expiredAt,
+	}, nil
+}
+
+func (ucase *ServiceAuthUcase) GenerateServiceToken(serviceName string) (string, time.Time, error) {
+	signingKey, err := ucase.signingKeyUcase.GetSigningKey()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return jwt_util.GenerateServiceToken(serviceName, signingKey, config.Envs.JWT_ISSUER, config.Envs.SERVICE_TOKEN_EXP_MINUTES)
+}
+
+func (ucase *ServiceAuthUcase) VerifyServiceToken(token string) (string, error) {
+	return jwt_util.ValidateServiceToken(token, ucase.signingKeyUcase.GetPublicKey, config.Envs.JWT_ISSUER)
+}
+
+// parseServiceClientSecrets reads "name=secret,name=secret", services with an
+// empty secret are left out so they can not get a token.
+func parseServiceClientSecrets(raw string) map[string]string {
+	secrets := map[string]string{}
+	for _, pair := range strings.Split(raw, ",") {
+		name, secret, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || name == "" || secret == "" {
+			continue
+		}
+		secrets[name] = secret
+	}
+	return secrets
+}
@@ -0,0 +1,198 @@
+package ucase
+
+import (
+	"auth_service/config"
+	"auth_service/domain/dto"
+	"auth_service/domain/model"
+	"auth_service/repository"
+	error_utils "auth_service/utils/error"
+	"auth_service/utils/helper"
+	jwt_util "auth_service/utils/jwt"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+)
+
+// keys are reloaded from the database at this interval, so every instance
+// picks up keys rotated by another one
+const signingKeyReloadInterval = time.Minute
+
+type SigningKeyUcase struct {
+	signingKeyRepo repository.ISigningKeyRepo
+
+	mu         sync.RWMutex
+	activeKey  *jwt_util.SigningKey
+	publicKeys map[string]*jwt_util.PublicKey
+	loadedAt   time.Time
+}
+
+type ISigningKeyUcase interface {
+	GetSigningKey() (*jwt_util.SigningKey, error)
+	GetPublicKey(kid string) (*jwt_util.PublicKey, error)
+	GetJWKS() (*dto.GetJWKSRespData, error)
+	RotateSigningKey() (*dto.RotateSigningKeyRespData, error)
+}
+
+func NewSigningKeyUcase(signingKeyRepo repository.ISigningKeyRepo) ISigningKeyUcase {
+	return &SigningKeyUcase{
+		signingKeyRepo: signingKeyRepo,
+		publicKeys:     map[string]*jwt_util.PublicKey{},
+	}
+}
+
+func (ucase *SigningKeyUcase) GetSigningKey() (*jwt_util.SigningKey, error) {
+	if err := ucase.reloadIfStale(); err != nil {
+		return nil, err
+	}
+
+	ucase.mu.RLock()
+	defer ucase.mu.RUnlock()
+	return ucase.activeKey, nil
+}
+
+func (ucase *SigningKeyUcase) GetPublicKey(kid string) (*jwt_util.PublicKey, error) {
+	if err := ucase.reloadIfStale(); err != nil {
+		return nil, err
+	}
+
+	ucase.mu.RLock()
+	publicKey, ok := ucase.publicKeys[kid]
+	ucase.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown kid: %s", kid)
+	}
+	return publicKey, nil
+}
+
+func (ucase *SigningKeyUcase) GetJWKS() (*dto.GetJWKSRespData, error) {
+	if err := ucase.reloadIfStale(); err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	ucase.mu.RLock()
+	defer ucase.mu.RUnlock()
+
+	res := &dto.GetJWKSRespData{
+		Keys: []dto.JWK{},
+	}
+	for _, publicKey := range ucase.publicKeys {
+		res.Keys = append(res.Keys, publicKey.JWK())
+	}
+
+	return res, nil
+}
+
+// RotateSigningKey replaces the active key right away, e.g. after a leak.
+func (ucase *SigningKeyUcase) RotateSigningKey() (*dto.RotateSigningKeyRespData, error) {
+	active, err := ucase.rotate(helper.TimeNowUTC().Add(time.Hour))
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	if err := ucase.reload(); err != nil {
+		logger.Errorf("err: %v", err)
+	}
+
+	return &dto.RotateSigningKeyRespData{
+		KID:       active.KID,
+		Algorithm: active.Algorithm,
+	}, nil
+}
+
+func (ucase *SigningKeyUcase) reloadIfStale() error {
+	ucase.mu.RLock()
+	stale := ucase.activeKey == nil || time.Since(ucase.loadedAt) > signingKeyReloadInterval
+	ucase.mu.RUnlock()
+	if !stale {
+		return nil
+	}
+
+	return ucase.reload()
+}
+
+// reload loads the active and published keys, rotating first when the active
+// key is missing or older than JWT_KEY_ROTATION_HOURS.
+func (ucase *SigningKeyUcase) reload() error {
+	timeNow := helper.TimeNowUTC()
+	rotateBefore := timeNow.Add(-time.Hour * time.Duration(config.Envs.JWT_KEY_ROTATION_HOURS))
+
+	active, err := ucase.signingKeyRepo.GetActive()
+	if err != nil && err.Error() != "not found" {
+		return err
+	}
+	if active == nil || active.Algorithm != config.Envs.JWT_SIGNING_ALG || !active.CreatedAt.After(rotateBefore) {
+		active, err = ucase.rotate(rotateBefore)
+		if err != nil {
+			return err
+		}
+	}
+
+	activeKey, err := jwt_util.ParseSigningKey(active.KID, active.Algorithm, active.PrivateKey)
+	if err != nil {
+		return fmt.Errorf("failed to parse signing key %s: %v", active.KID, err)
+	}
+
+	published, err := ucase.signingKeyRepo.GetPublishedList(timeNow)
+	if err != nil {
+		return err
+	}
+	publicKeys := map[string]*jwt_util.PublicKey{}
+	for _, signingKey := range published {
+		publicKey, err := jwt_util.ParsePublicKey(signingKey.KID, signingKey.Algorithm, signingKey.PublicKey)
+		if err != nil {
+			logger.Errorf("failed to parse public key %s: %v", signingKey.KID, err)
+			continue
+		}
+		publicKeys[signingKey.KID] = publicKey
+	}
+
+	ucase.mu.Lock()
+	ucase.activeKey = activeKey
+	ucase.publicKeys = publicKeys
+	ucase.loadedAt = timeNow
+	ucase.mu.Unlock()
+
+	return nil
+}
+
+// rotate creates a key with JWT_SIGNING_ALG unless the active key is newer than
+// rotateBefore. retired keys stay published as long as an access token lives.
+func (ucase *SigningKeyUcase) rotate(rotateBefore time.Time) (*model.SigningKey, error) {
+	privatePem, publicPem, err := jwt_util.GenerateKeyPair(config.Envs.JWT_SIGNING_ALG)
+	if err != nil {
+		return nil, err
+	}
+
+	newKey := &model.SigningKey{
+		KID:        uuid.New().String(),
+		Algorithm:  config.Envs.JWT_SIGNING_ALG,
+		PrivateKey: privatePem,
+		PublicKey:  publicPem,
+	}
+	retiredExpiredAt := helper.TimeNowUTC().Add(time.Hour * time.Duration(config.Envs.JWT_EXP_HOURS))
+
+	active, err := ucase.signingKeyRepo.Rotate(newKey, rotateBefore, retiredExpiredAt)
+	if err != nil {
+		return nil, err
+	}
+	if active.KID == newKey.KID {
+		logger.Infof("rotated signing key, new kid: %s", active.KID)
+	}
+
+	return active, nil
+}
@@ -0,0 +1,405 @@
+package ucase
+
+import (
+	"auth_service/config"
+	"auth_service/domain/dto"
+	"auth_service/domain/model"
+	"auth_service/repository"
+	error_utils "auth_service/utils/error"
+	"auth_service/utils/helper"
+	token_util "auth_service/utils/token"
+	totp_util "auth_service/utils/totp"
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+)
+
+const recoveryCodeCount = 10
+
+type TwoFactorUcase struct {
+	userRepo          repository.IUserRepo
+	recoveryCodeRepo  repository.IRecoveryCodeRepo
+	securityEventRepo repository.ISecurityEventRepo
+}
+
+type ITwoFactorUcase interface {
+	Enroll(currentUser dto.CurrentUser) (*dto.EnrollTwoFactorRespData, error)
+	Confirm(currentUser dto.CurrentUser, payload dto.ConfirmTwoFactorReq) (*dto.ConfirmTwoFactorRespData, error)
+	Disable(currentUser dto.CurrentUser, payload dto.DisableTwoFactorReq) (*dto.DisableTwoFactorRespData, error)
+	RegenerateRecoveryCodes(currentUser dto.CurrentUser, payload dto.RegenerateRecoveryCodesReq) (*dto.ConfirmTwoFactorRespData, error)
+
+	// used by the login flow
+	IsRequired(user *model.User) bool
+	BeginEnrollment(user *model.User) (*dto.EnrollTwoFactorRespData, error)
+	ConfirmEnrollment(user *model.User, code string) ([]string, error)
+	VerifyCode(user *model.User, code string) error
+}
+
+func NewTwoFactorUcase(
+	userRepo repository.IUserRepo,
+	recoveryCodeRepo repository.IRecoveryCodeRepo,
+	securityEventRepo repository.ISecurityEventRepo,
+) ITwoFactorUcase {
+	return &TwoFactorUcase{
+		userRepo:          userRepo,
+		recoveryCodeRepo:  recoveryCodeRepo,
+		securityEventRepo: securityEventRepo,
+	}
+}
+
+func (ucase *TwoFactorUcase) Enroll(currentUser dto.CurrentUser) (*dto.EnrollTwoFactorRespData, error) {
+	user, err := ucase.getUser(currentUser.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ucase.BeginEnrollment(user)
+}
+
+func (ucase *TwoFactorUcase) Confirm(currentUser dto.CurrentUser, payload dto.ConfirmTwoFactorReq) (*dto.ConfirmTwoFactorRespData, error) {
+	user, err := ucase.getUser(currentUser.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := ucase.ConfirmEnrollment(user, payload.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ConfirmTwoFactorRespData{
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+func (ucase *TwoFactorUcase) Disable(currentUser dto.CurrentUser, payload dto.DisableTwoFactorReq) (*dto.DisableTwoFactorRespData, error) {
+	user, err := ucase.getUser(currentUser.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsTOTPEnabled() {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.FailedPrecondition,
+			Message:  "2fa not enabled",
+		}
+	}
+	if isTwoFactorMandatory(user) {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 403,
+			GrpcCode: codes.PermissionDenied,
+			Message:  "forbidden",
+			Detail:   "2fa is mandatory for the " + user.Role + " role",
+		}
+	}
+
+	err = ucase.VerifyCode(user, payload.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPSecret = nil
+	user.TOTPEnabledAt = nil
+	err = ucase.userRepo.Update(user)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	err = ucase.recoveryCodeRepo.DeleteManyByUserUUID(user.UUID.String())
+	if err != nil {
+		logger.Errorf("error deleting recovery codes: %v", err)
+	}
+	ucase.recordSecurityEvent(user, model.SecurityEventTypeTwoFactorDisabled)
+
+	return &dto.DisableTwoFactorRespData{
+		UUID: user.UUID.String(),
+	}, nil
+}
+
+func (ucase *TwoFactorUcase) RegenerateRecoveryCodes(currentUser dto.CurrentUser, payload dto.RegenerateRecoveryCodesReq) (*dto.ConfirmTwoFactorRespData, error) {
+	user, err := ucase.getUser(currentUser.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsTOTPEnabled() {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.FailedPrecondition,
+			Message:  "2fa not enabled",
+		}
+	}
+
+	// only a totp code, a recovery code can not renew the set it belongs to
+	err = ucase.verifyTOTPCode(user, payload.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := ucase.generateRecoveryCodes(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ConfirmTwoFactorRespData{
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+// IsRequired tells if the login of the user needs a second step, either
+// because 2fa is enabled or because it is mandatory for the role.
+func (ucase *TwoFactorUcase) IsRequired(user *model.User) bool {
+	return user.IsTOTPEnabled() || isTwoFactorMandatory(user)
+}
+
+// BeginEnrollment sets a new pending totp secret. 2fa is only enabled once a
+// code of it is confirmed.
+func (ucase *TwoFactorUcase) BeginEnrollment(user *model.User) (*dto.EnrollTwoFactorRespData, error) {
+	if user.IsTOTPEnabled() {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.FailedPrecondition,
+			Message:  "2fa already enabled",
+		}
+	}
+
+	secret, err := totp_util.GenerateSecret()
+	if err != nil {
+		logger.Errorf("error generating totp secret: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	user.TOTPSecret = &secret
+	err = ucase.userRepo.Update(user)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	return &dto.EnrollTwoFactorRespData{
+		Secret:     secret,
+		OTPAuthURI: totp_util.URI(config.Envs.TOTP_ISSUER, user.Email, secret),
+	}, nil
+}
+
+// ConfirmEnrollment enables 2fa with the first code of the pending secret and
+// returns a fresh set of recovery codes.
+func (ucase *TwoFactorUcase) ConfirmEnrollment(user *model.User, code string) ([]string, error) {
+	if user.IsTOTPEnabled() {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.FailedPrecondition,
+			Message:  "2fa already enabled",
+		}
+	}
+	if user.TOTPSecret == nil {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.FailedPrecondition,
+			Message:  "2fa not enrolled",
+			Detail:   "enroll first to get a totp secret",
+		}
+	}
+
+	err := ucase.verifyTOTPCode(user, code)
+	if err != nil {
+		return nil, err
+	}
+
+	timeNow := helper.TimeNowUTC()
+	user.TOTPEnabledAt = &timeNow
+	err = ucase.userRepo.Update(user)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+	ucase.recordSecurityEvent(user, model.SecurityEventTypeTwoFactorEnabled)
+
+	return ucase.generateRecoveryCodes(user)
+}
+
+// VerifyCode accepts a totp code of the enabled secret or an unused recovery
+// code.
+func (ucase *TwoFactorUcase) VerifyCode(user *model.User, code string) error {
+	if !user.IsTOTPEnabled() {
+		return &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.FailedPrecondition,
+			Message:  "2fa not enabled",
+		}
+	}
+
+	if isTOTPCode(code) {
+		return ucase.verifyTOTPCode(user, code)
+	}
+
+	err := ucase.recoveryCodeRepo.Use(user.UUID.String(), token_util.Hash(normalizeRecoveryCode(code)), helper.TimeNowUTC())
+	if err != nil {
+		if err.Error() == "not found" {
+			return invalidTwoFactorCodeErr()
+		}
+		logger.Errorf("err: %v", err)
+		return &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+	ucase.recordSecurityEvent(user, model.SecurityEventTypeRecoveryCodeUsed)
+
+	return nil
+}
+
+func (ucase *TwoFactorUcase) verifyTOTPCode(user *model.User, code string) error {
+	if user.TOTPSecret == nil {
+		return invalidTwoFactorCodeErr()
+	}
+
+	step, ok := totp_util.Validate(*user.TOTPSecret, code, helper.TimeNowUTC())
+	if !ok {
+		return invalidTwoFactorCodeErr()
+	}
+
+	err := ucase.userRepo.UseTOTPStep(user, step)
+	if err != nil {
+		if err.Error() == "already used" {
+			return invalidTwoFactorCodeErr()
+		}
+		logger.Errorf("err: %v", err)
+		return &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	return nil
+}
+
+func (ucase *TwoFactorUcase) generateRecoveryCodes(user *model.User) ([]string, error) {
+	recoveryCodes := []string{}
+	models := []model.RecoveryCode{}
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 8)
+		_, err := rand.Read(b)
+		if err != nil {
+			logger.Errorf("error generating recovery code: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: codes.Internal,
+				Message:  "internal server error",
+				Detail:   err.Error(),
+			}
+		}
+
+		// 10 base32 chars shown as xxxxx-xxxxx
+		raw := strings.ToLower(base32.StdEncoding.EncodeToString(b))[:10]
+		recoveryCodes = append(recoveryCodes, raw[:5]+"-"+raw[5:])
+		models = append(models, model.RecoveryCode{
+			UserUUID: user.UUID,
+			CodeHash: token_util.Hash(raw),
+		})
+	}
+
+	err := ucase.recoveryCodeRepo.ReplaceManyByUserUUID(user.UUID.String(), models)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	return recoveryCodes, nil
+}
+
+func (ucase *TwoFactorUcase) getUser(userUUID string) (*model.User, error) {
+	user, err := ucase.userRepo.GetByUUID(userUUID)
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, &error_utils.CustomErr{
+				HttpCode: 404,
+				GrpcCode: codes.NotFound,
+				Message:  "user not found",
+			}
+		}
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+	return user, nil
+}
+
+func (ucase *TwoFactorUcase) recordSecurityEvent(user *model.User, eventType string) {
+	err := ucase.securityEventRepo.Create(&model.SecurityEvent{
+		UUID:     uuid.New(),
+		UserUUID: user.UUID,
+		Type:     eventType,
+	})
+	if err != nil {
+		logger.Errorf("error creating security event: %v", err)
+	}
+}
+
+func isTwoFactorMandatory(user *model.User) bool {
+	return config.Envs.TOTP_REQUIRED_FOR_ADMIN && user.Role == model.RoleAdmin
+}
+
+func isTOTPCode(code string) bool {
+	code = strings.ReplaceAll(code, " ", "")
+	if len(code) != 6 {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
+
+func invalidTwoFactorCodeErr() error {
+	return &error_utils.CustomErr{
+		HttpCode: 401,
+		GrpcCode: codes.Unauthenticated,
+		Message:  "Invalid 2FA Code",
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"auth_service/repository"
 	bcrypt_util "auth_service/utils/bcrypt"
 	error_utils "auth_service/utils/error"
+	"auth_service/utils/helper"
+	jwt_util "auth_service/utils/jwt"
 	validator_util "auth_service/utils/validator/user"
 	"context"
 	"fmt"
@@ -16,19 +18,30 @@ import (
 )
 
 type UserUcase struct {
-	userRepo repository.IUserRepo
+	userRepo               repository.IUserRepo
+	roleRepo               repository.IRoleRepo
+	refreshTokenRepo       repository.IRefreshTokenRepo
+	sessionRepo            repository.ISessionRepo
+	securityEventRepo      repository.ISecurityEventRepo
+	emailVerificationUcase IEmailVerificationUcase
 }
 
 type IUserUcase interface {
 	GetByUUID(ctx context.Context, ginCtx *gin.Context, userUUID string) (*dto.GetUserByUUIDResp, error)
+	// CreateUser creates a user with the requested role when currentUser may
+	// manage users, any other caller, or a nil one, creates a plain user.
 	CreateUser(
 		ctx context.Context,
 		ginCtx *gin.Context,
+		currentUser *dto.CurrentUser,
 		payload dto.CreateUserReq,
 	) (*dto.CreateUserRespData, error)
+	// UpdateUser changes the role only for a currentUser that may manage
+	// users. a new password signs out every session of the user.
 	UpdateUser(
 		ctx context.Context,
 		ginCtx *gin.Context,
+		currentUser *dto.CurrentUser,
 		userUUID string,
 		payload dto.UpdateUserReq,
 	) (*dto.UpdateUserRespData, error)
@@ -37,10 +50,29 @@ type IUserUcase interface {
 		ginCtx *gin.Context,
 		userUUID string,
 	) (*dto.DeleteUserRespData, error)
+	// PurgeUser removes the user for good, to compensate a failed creation.
+	PurgeUser(ctx context.Context, userUUID string) (*dto.DeleteUserRespData, error)
+	GetUserList(ctx context.Context, params dto.GetUserListReq) (*dto.GetUserListRespData, error)
+	GetMe(ctx context.Context, currentUser dto.CurrentUser) (*dto.GetMeRespData, error)
+	UpdateMe(ctx context.Context, currentUser dto.CurrentUser, payload dto.UpdateMeReq) (*dto.GetMeRespData, error)
 }
 
-func NewUserUcase(userRepo repository.IUserRepo) IUserUcase {
-	return &UserUcase{userRepo: userRepo}
+func NewUserUcase(
+	userRepo repository.IUserRepo,
+	roleRepo repository.IRoleRepo,
+	refreshTokenRepo repository.IRefreshTokenRepo,
+	sessionRepo repository.ISessionRepo,
+	securityEventRepo repository.ISecurityEventRepo,
+	emailVerificationUcase IEmailVerificationUcase,
+) IUserUcase {
+	return &UserUcase{
+		userRepo:               userRepo,
+		roleRepo:               roleRepo,
+		refreshTokenRepo:       refreshTokenRepo,
+		sessionRepo:            sessionRepo,
+		securityEventRepo:      securityEventRepo,
+		emailVerificationUcase: emailVerificationUcase,
+	}
 }
 
 func (ucase *UserUcase) GetByUUID(ctx context.Context, ginCtx *gin.Context, userUUID string) (*dto.GetUserByUUIDResp, error) {
@@ -57,19 +89,35 @@ func (ucase *UserUcase) GetByUUID(ctx context.Context, ginCtx *gin.Context, user
 		return nil, err
 	}
 
+	permissions := []string{}
+	role, err := ucase.roleRepo.GetByName(user.Role)
+	if err == nil {
+		permissions = role.PermissionNames()
+	} else if err.Error() != "not found" {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
 	return &dto.GetUserByUUIDResp{
-		UUID:      user.UUID.String(),
-		Username:  user.Username,
-		Email:     user.Email,
-		Role:      user.Role,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		UUID:        user.UUID.String(),
+		Username:    user.Username,
+		Email:       user.Email,
+		Role:        user.Role,
+		Permissions: permissions,
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
 	}, nil
 }
 
 func (ucase *UserUcase) CreateUser(
 	ctx context.Context,
 	ginCtx *gin.Context,
+	currentUser *dto.CurrentUser,
 	payload dto.CreateUserReq,
 ) (*dto.CreateUserRespData, error) {
 	// validate input
@@ -103,13 +151,49 @@ func (ucase *UserUcase) CreateUser(
 		}
 	}
 
-	err = validator_util.ValidateRole(payload.Role)
+	// only callers allowed to manage users pick the role
+	canManageUsers, err := ucase.hasPermission(currentUser, model.PermissionUserManage)
 	if err != nil {
-		logger.Errorf("error validating role: %s", err.Error())
-		return nil, &error_utils.CustomErr{
-			HttpCode: 400,
-			GrpcCode: codes.InvalidArgument,
-			Message:  err.Error(),
+		return nil, err
+	}
+	role := model.RoleUser
+	if canManageUsers && payload.Role != "" {
+		role = payload.Role
+	}
+
+	err = ucase.validateRole(role)
+	if err != nil {
+		return nil, err
+	}
+
+	// a retried call returns the user created by the first one
+	if payload.IdempotencyKey != "" {
+		user, err := ucase.userRepo.GetByIdempotencyKey(payload.IdempotencyKey)
+		if err == nil {
+			if user.Username != payload.Username || user.Email != payload.Email {
+				return nil, &error_utils.CustomErr{
+					HttpCode: 409,
+					GrpcCode: codes.FailedPrecondition,
+					Message:  "idempotency key already used for another user",
+				}
+			}
+			return &dto.CreateUserRespData{
+				UUID:      user.UUID.String(),
+				Username:  user.Username,
+				Email:     user.Email,
+				Role:      user.Role,
+				CreatedAt: user.CreatedAt,
+				UpdatedAt: user.UpdatedAt,
+			}, nil
+		}
+		if err.Error() != "not found" {
+			logger.Errorf("err: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: codes.Internal,
+				Message:  "internal server error",
+				Detail:   err.Error(),
+			}
 		}
 	}
 
@@ -142,13 +226,18 @@ func (ucase *UserUcase) CreateUser(
 		return nil, err
 	}
 
-	// create user
+	// create user, accounts created by admins or other services are trusted as verified
+	emailVerifiedAt := helper.TimeNowUTC()
 	user = &model.User{
-		UUID:     uuid.New(),
-		Username: payload.Username,
-		Password: password,
-		Email:    payload.Email,
-		Role:     "user",
+		UUID:            uuid.New(),
+		Username:        payload.Username,
+		Password:        password,
+		Email:           payload.Email,
+		Role:            role,
+		EmailVerifiedAt: &emailVerifiedAt,
+	}
+	if payload.IdempotencyKey != "" {
+		user.IdempotencyKey = &payload.IdempotencyKey
 	}
 	err = user.Validate()
 	if err != nil {
@@ -173,6 +262,7 @@ func (ucase *UserUcase) CreateUser(
 func (ucase *UserUcase) UpdateUser(
 	ctx context.Context,
 	ginCtx *gin.Context,
+	currentUser *dto.CurrentUser,
 	userUUID string,
 	payload dto.UpdateUserReq,
 ) (*dto.UpdateUserRespData, error) {
@@ -214,15 +304,22 @@ func (ucase *UserUcase) UpdateUser(
 	}
 
 	if payload.Role != nil {
-		err := validator_util.ValidateRole(*payload.Role)
+		canManageUsers, err := ucase.hasPermission(currentUser, model.PermissionUserManage)
 		if err != nil {
-			logger.Errorf("error validating role: %s", err.Error())
+			return nil, err
+		}
+		if !canManageUsers {
 			return nil, &error_utils.CustomErr{
-				HttpCode: 400,
-				GrpcCode: codes.InvalidArgument,
-				Message:  err.Error(),
+				HttpCode: 403,
+				GrpcCode: codes.PermissionDenied,
+				Message:  "only user managers can change the role",
 			}
 		}
+
+		err = ucase.validateRole(*payload.Role)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// get existing user
@@ -239,7 +336,32 @@ func (ucase *UserUcase) UpdateUser(
 		return nil, err
 	}
 
+	// check if username or email is taken by another user
+	if payload.Username != nil && *payload.Username != user.Username {
+		existing, _ := ucase.userRepo.GetByUsername(*payload.Username)
+		if existing != nil {
+			logger.Errorf("user with username %s already exists", *payload.Username)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 409,
+				GrpcCode: codes.AlreadyExists,
+				Message:  fmt.Sprintf("user with username %s already exists", *payload.Username),
+			}
+		}
+	}
+	if payload.Email != nil && *payload.Email != user.Email {
+		existing, _ := ucase.userRepo.GetByEmail(*payload.Email)
+		if existing != nil {
+			logger.Errorf("user with email %s already exists", *payload.Email)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 409,
+				GrpcCode: codes.AlreadyExists,
+				Message:  fmt.Sprintf("user with email %s already exists", *payload.Email),
+			}
+		}
+	}
+
 	// update user obj
+	timeNow := helper.TimeNowUTC()
 	if payload.Username != nil {
 		user.Username = *payload.Username
 	}
@@ -252,7 +374,9 @@ func (ucase *UserUcase) UpdateUser(
 			logger.Errorf("error hashing password: %v", err)
 			return nil, err
 		}
+		validAfter := jwt_util.TokensValidAfter(timeNow)
 		user.Password = password
+		user.TokensValidAfter = &validAfter
 	}
 	if payload.Role != nil {
 		user.Role = *payload.Role
@@ -264,6 +388,31 @@ func (ucase *UserUcase) UpdateUser(
 		return nil, err
 	}
 
+	if payload.Password != nil {
+		// invalidate refresh tokens and the sessions they belong to
+		err = ucase.refreshTokenRepo.InvalidateManyByUserUUID(user.UUID.String())
+		if err != nil {
+			logger.Errorf("error invalidating refresh tokens: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: codes.Internal,
+				Message:  "internal server error",
+				Detail:   err.Error(),
+			}
+		}
+		err = ucase.sessionRepo.RevokeManyByUserUUID(user.UUID.String(), model.SessionRevokedReasonPasswordChange, timeNow)
+		if err != nil {
+			logger.Errorf("error revoking sessions: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: codes.Internal,
+				Message:  "internal server error",
+				Detail:   err.Error(),
+			}
+		}
+		ucase.recordSecurityEvent(user, model.SecurityEventTypePasswordChanged)
+	}
+
 	return &dto.UpdateUserRespData{
 		UUID:      user.UUID.String(),
 		Username:  user.Username,
@@ -316,3 +465,355 @@ func (ucase *UserUcase) DeleteUser(
 		UpdatedAt: user.UpdatedAt,
 	}, nil
 }
+
+func (ucase *UserUcase) PurgeUser(ctx context.Context, userUUID string) (*dto.DeleteUserRespData, error) {
+	user, err := ucase.userRepo.GetByUUID(userUUID)
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, &error_utils.CustomErr{
+				HttpCode: 404,
+				GrpcCode: codes.NotFound,
+				Message:  "user not found",
+				Detail:   err.Error(),
+			}
+		}
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	err = ucase.userRepo.Purge(user.UUID.String())
+	if err != nil && err.Error() != "not found" {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	return &dto.DeleteUserRespData{
+		UUID:      user.UUID.String(),
+		Username:  user.Username,
+		Email:     user.Email,
+		Role:      user.Role,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}, nil
+}
+
+func (ucase *UserUcase) GetUserList(ctx context.Context, params dto.GetUserListReq) (*dto.GetUserListRespData, error) {
+	// defaults
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.Limit <= 0 {
+		params.Limit = 10
+	}
+	if params.SortOrder == "" {
+		params.SortOrder = "desc"
+	}
+	if params.SortBy == "" {
+		params.SortBy = "created_at"
+	}
+
+	repoParams := dto.UserRepo_GetListParams{
+		Query:     params.Query,
+		Role:      params.Role,
+		Page:      params.Page,
+		Limit:     params.Limit,
+		SortOrder: params.SortOrder,
+		SortBy:    params.SortBy,
+	}
+
+	// get list
+	users, err := ucase.userRepo.GetList(repoParams)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	// count
+	count, err := ucase.userRepo.CountGetList(repoParams)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	res := &dto.GetUserListRespData{
+		Data: []dto.GetUserListRespDataItem{},
+	}
+	res.Set(params.Page, params.Limit, count)
+	for _, user := range users {
+		res.Data = append(res.Data, dto.GetUserListRespDataItem{
+			UUID:             user.UUID.String(),
+			Username:         user.Username,
+			Email:            user.Email,
+			Role:             user.Role,
+			EmailVerified:    user.IsEmailVerified(),
+			TwoFactorEnabled: user.IsTOTPEnabled(),
+			IsServiceAccount: user.IsServiceAccount,
+			CreatedAt:        user.CreatedAt,
+			UpdatedAt:        user.UpdatedAt,
+		})
+	}
+
+	return res, nil
+}
+
+func (ucase *UserUcase) GetMe(ctx context.Context, currentUser dto.CurrentUser) (*dto.GetMeRespData, error) {
+	user, err := ucase.getCurrentUser(currentUser)
+	if err != nil {
+		return nil, err
+	}
+
+	return meRespData(user), nil
+}
+
+// UpdateMe changes the email or password of the current user after checking
+// the current password. a new email has to be verified again, a new password
+// signs out every session like a password reset does.
+func (ucase *UserUcase) UpdateMe(ctx context.Context, currentUser dto.CurrentUser, payload dto.UpdateMeReq) (*dto.GetMeRespData, error) {
+	// validate input
+	if payload.Email == nil && payload.Password == nil {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.InvalidArgument,
+			Message:  "email or password is required",
+		}
+	}
+
+	if payload.Email != nil {
+		err := validator_util.ValidateEmail(*payload.Email)
+		if err != nil {
+			logger.Errorf("error validating email: %s", err.Error())
+			return nil, &error_utils.CustomErr{
+				HttpCode: 400,
+				GrpcCode: codes.InvalidArgument,
+				Message:  err.Error(),
+			}
+		}
+	}
+
+	if payload.Password != nil {
+		err := validator_util.ValidatePassword(*payload.Password)
+		if err != nil {
+			logger.Errorf("error validating password: %s", err.Error())
+			return nil, &error_utils.CustomErr{
+				HttpCode: 400,
+				GrpcCode: codes.InvalidArgument,
+				Message:  err.Error(),
+			}
+		}
+	}
+
+	user, err := ucase.getCurrentUser(currentUser)
+	if err != nil {
+		return nil, err
+	}
+
+	// check current password
+	if !bcrypt_util.Compare(payload.CurrentPassword, user.Password) {
+		logger.Errorf("invalid current password")
+		return nil, &error_utils.CustomErr{
+			HttpCode: 401,
+			GrpcCode: codes.Unauthenticated,
+			Message:  "Invalid Credentials",
+			Detail:   "current password is wrong",
+		}
+	}
+
+	timeNow := helper.TimeNowUTC()
+	emailChanged := payload.Email != nil && *payload.Email != user.Email
+	if emailChanged {
+		existing, _ := ucase.userRepo.GetByEmail(*payload.Email)
+		if existing != nil {
+			logger.Errorf("user with email %s already exists", *payload.Email)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 400,
+				GrpcCode: codes.AlreadyExists,
+				Message:  fmt.Sprintf("user with email %s already exists", *payload.Email),
+			}
+		}
+
+		user.Email = *payload.Email
+		user.EmailVerifiedAt = nil
+	}
+
+	passwordChanged := payload.Password != nil
+	if passwordChanged {
+		password, err := bcrypt_util.Hash(*payload.Password)
+		if err != nil {
+			logger.Errorf("error hashing password: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: codes.Internal,
+				Message:  "internal server error",
+				Detail:   err.Error(),
+			}
+		}
+		validAfter := jwt_util.TokensValidAfter(timeNow)
+		user.Password = password
+		user.TokensValidAfter = &validAfter
+	}
+
+	err = ucase.userRepo.Update(user)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+
+	if emailChanged {
+		err = ucase.emailVerificationUcase.SendVerificationEmail(ctx, user)
+		if err != nil {
+			logger.Errorf("error sending verification mail: %v", err)
+		}
+		ucase.recordSecurityEvent(user, model.SecurityEventTypeEmailChanged)
+	}
+
+	if passwordChanged {
+		// invalidate refresh tokens and the sessions they belong to
+		err = ucase.refreshTokenRepo.InvalidateManyByUserUUID(user.UUID.String())
+		if err != nil {
+			logger.Errorf("error invalidating refresh tokens: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: codes.Internal,
+				Message:  "internal server error",
+				Detail:   err.Error(),
+			}
+		}
+		err = ucase.sessionRepo.RevokeManyByUserUUID(user.UUID.String(), model.SessionRevokedReasonPasswordChange, timeNow)
+		if err != nil {
+			logger.Errorf("error revoking sessions: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: codes.Internal,
+				Message:  "internal server error",
+				Detail:   err.Error(),
+			}
+		}
+		ucase.recordSecurityEvent(user, model.SecurityEventTypePasswordChanged)
+	}
+
+	return meRespData(user), nil
+}
+
+// validateRole only accepts roles stored in the roles table.
+func (ucase *UserUcase) validateRole(roleName string) error {
+	_, err := ucase.roleRepo.GetByName(roleName)
+	if err != nil {
+		if err.Error() == "not found" {
+			logger.Errorf("error validating role: %s", roleName)
+			return &error_utils.CustomErr{
+				HttpCode: 400,
+				GrpcCode: codes.InvalidArgument,
+				Message:  "invalid role",
+			}
+		}
+		logger.Errorf("err: %v", err)
+		return &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+	return nil
+}
+
+// hasPermission checks the permissions of currentUser, or those of its stored
+// role when the user was propagated by another service without them.
+func (ucase *UserUcase) hasPermission(currentUser *dto.CurrentUser, permission string) (bool, error) {
+	if currentUser == nil {
+		return false, nil
+	}
+	if currentUser.Permissions != nil || currentUser.Role == "" {
+		return currentUser.HasPermission(permission), nil
+	}
+
+	role, err := ucase.roleRepo.GetByName(currentUser.Role)
+	if err != nil {
+		if err.Error() == "not found" {
+			return false, nil
+		}
+		logger.Errorf("err: %v", err)
+		return false, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+	for _, rolePermission := range role.PermissionNames() {
+		if rolePermission == permission {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (ucase *UserUcase) getCurrentUser(currentUser dto.CurrentUser) (*model.User, error) {
+	user, err := ucase.userRepo.GetByUUID(currentUser.UUID)
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, &error_utils.CustomErr{
+				HttpCode: 404,
+				GrpcCode: codes.NotFound,
+				Message:  "user not found",
+			}
+		}
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+	return user, nil
+}
+
+func (ucase *UserUcase) recordSecurityEvent(user *model.User, eventType string) {
+	err := ucase.securityEventRepo.Create(&model.SecurityEvent{
+		UUID:     uuid.New(),
+		UserUUID: user.UUID,
+		Type:     eventType,
+	})
+	if err != nil {
+		logger.Errorf("error creating security event: %v", err)
+	}
+}
+
+func meRespData(user *model.User) *dto.GetMeRespData {
+	return &dto.GetMeRespData{
+		UUID:             user.UUID.String(),
+		Username:         user.Username,
+		Email:            user.Email,
+		Role:             user.Role,
+		EmailVerified:    user.IsEmailVerified(),
+		TwoFactorEnabled: user.IsTOTPEnabled(),
+		CreatedAt:        user.CreatedAt,
+		UpdatedAt:        user.UpdatedAt,
+	}
+}
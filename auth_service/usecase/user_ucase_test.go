@@ -0,0 +1,252 @@
+package ucase
+
+import (
+	"auth_service/domain/dto"
+	"auth_service/domain/model"
+	mocks "auth_service/mocks/repository"
+	"auth_service/repository"
+	error_utils "auth_service/utils/error"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUserUcase_CreateUser_Role(t *testing.T) {
+	librarian := &model.Role{
+		Name:        "librarian",
+		Permissions: []model.RolePermission{{RoleName: "librarian", Permission: model.PermissionAuthorCreate}},
+	}
+	admin := &model.Role{
+		Name:        model.RoleAdmin,
+		Permissions: []model.RolePermission{{RoleName: model.RoleAdmin, Permission: model.PermissionUserManage}},
+	}
+
+	tests := []struct {
+		name        string
+		currentUser *dto.CurrentUser
+		role        string
+		want        string
+	}{
+		{
+			name:        "no_caller_gets_user",
+			currentUser: nil,
+			role:        model.RoleAdmin,
+			want:        model.RoleUser,
+		},
+		{
+			name:        "author_creator_can_not_create_admin",
+			currentUser: &dto.CurrentUser{UUID: "caller", Permissions: []string{model.PermissionAuthorCreate}},
+			role:        model.RoleAdmin,
+			want:        model.RoleUser,
+		},
+		{
+			name:        "propagated_author_creator_can_not_create_admin",
+			currentUser: &dto.CurrentUser{UUID: "caller", Role: librarian.Name},
+			role:        model.RoleAdmin,
+			want:        model.RoleUser,
+		},
+		{
+			name:        "user_manager_picks_role",
+			currentUser: &dto.CurrentUser{UUID: "caller", Permissions: []string{model.PermissionUserManage}},
+			role:        model.RoleAdmin,
+			want:        model.RoleAdmin,
+		},
+		{
+			name:        "propagated_user_manager_picks_role",
+			currentUser: &dto.CurrentUser{UUID: "caller", Role: model.RoleAdmin},
+			role:        model.RoleAdmin,
+			want:        model.RoleAdmin,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := mocks.NewIUserRepo(t)
+			roleRepo := mocks.NewIRoleRepo(t)
+			roleRepo.On("GetByName", librarian.Name).Return(librarian, nil).Maybe()
+			roleRepo.On("GetByName", model.RoleAdmin).Return(admin, nil).Maybe()
+			roleRepo.On("GetByName", model.RoleUser).Return(&model.Role{Name: model.RoleUser}, nil).Maybe()
+			userRepo.On("GetByEmail", "new@example.com").Return(nil, errors.New("not found"))
+			userRepo.On("GetByUsername", "new").Return(nil, errors.New("not found"))
+			userRepo.On("Create", mock.AnythingOfType("*model.User")).Return(nil)
+
+			ucase := NewUserUcase(userRepo, roleRepo, nil, nil, nil, nil)
+			got, err := ucase.CreateUser(context.Background(), nil, tt.currentUser, dto.CreateUserReq{
+				Username: "new",
+				Email:    "new@example.com",
+				Password: "password123",
+				Role:     tt.role,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got.Role)
+		})
+	}
+}
+
+func TestUserUcase_UpdateUser_Role(t *testing.T) {
+	tests := []struct {
+		name        string
+		currentUser *dto.CurrentUser
+		wantErr     bool
+	}{
+		{
+			name:        "no_caller",
+			currentUser: nil,
+			wantErr:     true,
+		},
+		{
+			name:        "author_editor_can_not_change_role",
+			currentUser: &dto.CurrentUser{UUID: "caller", Permissions: []string{model.PermissionAuthorUpdate}},
+			wantErr:     true,
+		},
+		{
+			name:        "user_manager_changes_role",
+			currentUser: &dto.CurrentUser{UUID: "caller", Permissions: []string{model.PermissionUserManage}},
+			wantErr:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &model.User{Username: "existing", Email: "existing@example.com", Role: model.RoleUser}
+			userRepo := mocks.NewIUserRepo(t)
+			roleRepo := mocks.NewIRoleRepo(t)
+			if !tt.wantErr {
+				roleRepo.On("GetByName", model.RoleAdmin).Return(&model.Role{Name: model.RoleAdmin}, nil)
+				userRepo.On("GetByUUID", "target").Return(user, nil)
+				userRepo.On("Update", user).Return(nil)
+			}
+
+			ucase := NewUserUcase(userRepo, roleRepo, nil, nil, nil, nil)
+			role := model.RoleAdmin
+			_, err := ucase.UpdateUser(context.Background(), nil, tt.currentUser, "target", dto.UpdateUserReq{Role: &role})
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, model.RoleUser, user.Role)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, model.RoleAdmin, user.Role)
+		})
+	}
+}
+
+// fakeSessionRepo keeps sessions by uuid and records the users whose sessions
+// were revoked, calling any method not listed here panics.
+type fakeSessionRepo struct {
+	repository.ISessionRepo
+	sessions     map[string]*model.Session
+	revokedUsers []string
+	reasons      []string
+}
+
+func (repo *fakeSessionRepo) GetByUUID(uuid string) (*model.Session, error) {
+	session, ok := repo.sessions[uuid]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return session, nil
+}
+
+func (repo *fakeSessionRepo) Update(session *model.Session) error {
+	repo.sessions[session.UUID.String()] = session
+	return nil
+}
+
+func (repo *fakeSessionRepo) Revoke(uuid string, reason string, now time.Time) error {
+	session, ok := repo.sessions[uuid]
+	if ok && session.RevokedAt == nil {
+		session.RevokedAt = &now
+		session.RevokedReason = &reason
+	}
+	return nil
+}
+
+func (repo *fakeSessionRepo) RevokeManyByUserUUID(userUUID string, reason string, now time.Time) error {
+	repo.revokedUsers = append(repo.revokedUsers, userUUID)
+	repo.reasons = append(repo.reasons, reason)
+	for _, session := range repo.sessions {
+		if session.UserUUID.String() == userUUID && session.RevokedAt == nil {
+			session.RevokedAt = &now
+			session.RevokedReason = &reason
+		}
+	}
+	return nil
+}
+
+// fakeSecurityEventRepo records the type of every created event.
+type fakeSecurityEventRepo struct {
+	types []string
+}
+
+func (repo *fakeSecurityEventRepo) Create(securityEvent *model.SecurityEvent) error {
+	repo.types = append(repo.types, securityEvent.Type)
+	return nil
+}
+
+func TestUserUcase_UpdateUser_Taken(t *testing.T) {
+	manager := &dto.CurrentUser{UUID: "caller", Permissions: []string{model.PermissionUserManage}}
+	other := &model.User{Username: "other", Email: "other@example.com"}
+
+	tests := []struct {
+		name    string
+		payload dto.UpdateUserReq
+		mock    func(userRepo *mocks.IUserRepo)
+	}{
+		{
+			name:    "username_taken",
+			payload: dto.UpdateUserReq{Username: &other.Username},
+			mock: func(userRepo *mocks.IUserRepo) {
+				userRepo.On("GetByUsername", other.Username).Return(other, nil)
+			},
+		},
+		{
+			name:    "email_taken",
+			payload: dto.UpdateUserReq{Email: &other.Email},
+			mock: func(userRepo *mocks.IUserRepo) {
+				userRepo.On("GetByEmail", other.Email).Return(other, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &model.User{Username: "existing", Email: "existing@example.com", Role: model.RoleUser}
+			userRepo := mocks.NewIUserRepo(t)
+			userRepo.On("GetByUUID", "target").Return(user, nil)
+			tt.mock(userRepo)
+
+			ucase := NewUserUcase(userRepo, mocks.NewIRoleRepo(t), nil, nil, nil, nil)
+			_, err := ucase.UpdateUser(context.Background(), nil, manager, "target", tt.payload)
+
+			customErr, ok := err.(*error_utils.CustomErr)
+			if assert.True(t, ok, "expected a custom error, got %v", err) {
+				assert.Equal(t, 409, customErr.HttpCode)
+			}
+			userRepo.AssertNotCalled(t, "Update", mock.Anything)
+		})
+	}
+}
+
+func TestUserUcase_UpdateUser_Password(t *testing.T) {
+	manager := &dto.CurrentUser{UUID: "caller", Permissions: []string{model.PermissionUserManage}}
+	user := &model.User{Username: "existing", Email: "existing@example.com", Role: model.RoleUser}
+	userRepo := mocks.NewIUserRepo(t)
+	userRepo.On("GetByUUID", "target").Return(user, nil)
+	userRepo.On("Update", user).Return(nil)
+	refreshTokenRepo := mocks.NewIRefreshTokenRepo(t)
+	refreshTokenRepo.On("InvalidateManyByUserUUID", user.UUID.String()).Return(nil).Once()
+	sessionRepo := &fakeSessionRepo{}
+	securityEventRepo := &fakeSecurityEventRepo{}
+
+	ucase := NewUserUcase(userRepo, mocks.NewIRoleRepo(t), refreshTokenRepo, sessionRepo, securityEventRepo, nil)
+	password := "newpassword123"
+	_, err := ucase.UpdateUser(context.Background(), nil, manager, "target", dto.UpdateUserReq{Password: &password})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, user.TokensValidAfter)
+	assert.Equal(t, []string{user.UUID.String()}, sessionRepo.revokedUsers)
+	assert.Equal(t, []string{model.SessionRevokedReasonPasswordChange}, sessionRepo.reasons)
+	assert.Equal(t, []string{model.SecurityEventTypePasswordChanged}, securityEventRepo.types)
+}
@@ -0,0 +1,129 @@
+package event_util
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestEvent(eventType string) Event {
+	return Event{
+		UUID:          uuid.New(),
+		Type:          eventType,
+		Source:        "test",
+		AggregateUUID: uuid.New(),
+		Payload:       []byte(`{}`),
+		OccurredAt:    time.Now(),
+	}
+}
+
+// collect subscribes handler and returns the types it handled once n events
+// succeeded or the wait ran out, after the subscription stopped.
+func collect(t *testing.T, broker *MemoryBroker, consumer string, n int, handler Handler) []string {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var mu sync.Mutex
+	handled := []string{}
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		broker.Subscribe(ctx, consumer, func(ctx context.Context, event Event) error {
+			if handler != nil {
+				err := handler(ctx, event)
+				if err != nil {
+					return err
+				}
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			handled = append(handled, event.Type)
+			if len(handled) == n {
+				close(done)
+			}
+			return nil
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 2):
+		t.Errorf("timed out waiting for %d events", n)
+	}
+	cancel()
+	<-stopped
+	mu.Lock()
+	defer mu.Unlock()
+	return append([]string{}, handled...)
+}
+
+func TestMemoryBroker(t *testing.T) {
+	t.Run("delivers in publish order to every consumer", func(t *testing.T) {
+		broker := NewMemoryBroker()
+		for _, eventType := range []string{TypeUserCreated, TypeUserUpdated, TypeUserDeleted} {
+			assert.NoError(t, broker.Publish(context.Background(), newTestEvent(eventType)))
+		}
+
+		expected := []string{TypeUserCreated, TypeUserUpdated, TypeUserDeleted}
+		assert.Equal(t, expected, collect(t, broker, "first", 3, nil))
+		assert.Equal(t, expected, collect(t, broker, "second", 3, nil))
+	})
+
+	t.Run("ignores an event published twice", func(t *testing.T) {
+		broker := NewMemoryBroker()
+		event := newTestEvent(TypeBookCreated)
+		assert.NoError(t, broker.Publish(context.Background(), event))
+		assert.NoError(t, broker.Publish(context.Background(), event))
+		assert.NoError(t, broker.Publish(context.Background(), newTestEvent(TypeBookDeleted)))
+
+		assert.Equal(t, []string{TypeBookCreated, TypeBookDeleted}, collect(t, broker, "consumer", 2, nil))
+	})
+
+	t.Run("delivers events published while subscribed", func(t *testing.T) {
+		broker := NewMemoryBroker()
+		go func() {
+			time.Sleep(time.Millisecond * 50)
+			broker.Publish(context.Background(), newTestEvent(TypeAuthorUpdated))
+		}()
+
+		assert.Equal(t, []string{TypeAuthorUpdated}, collect(t, broker, "consumer", 1, nil))
+	})
+
+	t.Run("redelivers a failed event before the next ones", func(t *testing.T) {
+		broker := NewMemoryBroker()
+		broker.retryInterval = time.Millisecond * 10
+		assert.NoError(t, broker.Publish(context.Background(), newTestEvent(TypeCategoryCreated)))
+		assert.NoError(t, broker.Publish(context.Background(), newTestEvent(TypeCategoryDeleted)))
+
+		failures := 0
+		handled := collect(t, broker, "consumer", 2, func(ctx context.Context, event Event) error {
+			if event.Type == TypeCategoryCreated && failures < 2 {
+				failures++
+				return errors.New("failed")
+			}
+			return nil
+		})
+		assert.Equal(t, 2, failures)
+		assert.Equal(t, []string{TypeCategoryCreated, TypeCategoryDeleted}, handled)
+	})
+
+	t.Run("keeps the position of a consumer", func(t *testing.T) {
+		broker := NewMemoryBroker()
+		assert.NoError(t, broker.Publish(context.Background(), newTestEvent(TypeUserCreated)))
+		assert.Equal(t, []string{TypeUserCreated}, collect(t, broker, "consumer", 1, nil))
+
+		assert.NoError(t, broker.Publish(context.Background(), newTestEvent(TypeUserDeleted)))
+		assert.Equal(t, []string{TypeUserDeleted}, collect(t, broker, "consumer", 1, nil))
+	})
+
+	t.Run("refuses to publish once closed", func(t *testing.T) {
+		broker := NewMemoryBroker()
+		assert.NoError(t, broker.Close())
+		assert.Error(t, broker.Publish(context.Background(), newTestEvent(TypeUserCreated)))
+	})
+}
@@ -0,0 +1,47 @@
+package event_util
+
+// event types, named after the aggregate and the change. they and their
+// payloads are shared by every service, like the protos.
+const (
+	TypeUserCreated = "user.created"
+	TypeUserUpdated = "user.updated"
+	TypeUserDeleted = "user.deleted"
+
+	TypeAuthorCreated = "author.created"
+	TypeAuthorUpdated = "author.updated"
+	TypeAuthorDeleted = "author.deleted"
+
+	TypeBookCreated = "book.created"
+	TypeBookUpdated = "book.updated"
+	TypeBookDeleted = "book.deleted"
+
+	TypeCategoryCreated = "category.created"
+	TypeCategoryUpdated = "category.updated"
+	TypeCategoryDeleted = "category.deleted"
+)
+
+type UserPayload struct {
+	UUID     string `json:"uuid"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+}
+
+type AuthorPayload struct {
+	UUID      string `json:"uuid"`
+	UserUUID  string `json:"user_uuid"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+type BookPayload struct {
+	UUID         string  `json:"uuid"`
+	AuthorUUID   string  `json:"author_uuid"`
+	CategoryUUID *string `json:"category_uuid"`
+	Title        string  `json:"title"`
+}
+
+type CategoryPayload struct {
+	UUID string `json:"uuid"`
+	Name string `json:"name"`
+}
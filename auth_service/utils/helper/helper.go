@@ -1,9 +1,12 @@
 package helper
 
 import (
+	"auth_service/domain/dto"
 	"encoding/json"
 	"fmt"
 	"time"
+
+	"github.com/gin-gonic/gin"
 )
 
 func ArrayContains(arr interface{}, item interface{}) bool {
@@ -31,3 +34,15 @@ func PrettyJson(data interface{}) string {
 func TimeNowUTC() time.Time {
 	return time.Now().UTC()
 }
+
+func GetCurrentUserFromGinCtx(ctx *gin.Context) (*dto.CurrentUser, error) {
+	rawCurrentUser, ok := ctx.Get("currentUser")
+	if !ok {
+		return nil, fmt.Errorf("invalid currentUser: %v", rawCurrentUser)
+	}
+	currentUser, ok := rawCurrentUser.(dto.CurrentUser)
+	if !ok {
+		return nil, fmt.Errorf("invalid currentUser: %v", rawCurrentUser)
+	}
+	return &currentUser, nil
+}
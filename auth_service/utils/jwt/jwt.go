@@ -9,22 +9,54 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 )
 
-func GenerateJwtToken(user *model.User, secretKey string, expHours int, tokenId *string) (string, error) {
-	JWT_SIGNATURE_KEY := []byte(secretKey)
-
-	claims := jwt.MapClaims{
-		"sub":      user.UUID.String(),
-		"username": user.Username,
-		"email":    user.Email,
-		"exp":      time.Now().Add(time.Hour * time.Duration(expHours)).Unix(),
+// IssuedAtPrecision is the precision of the iat of the issued tokens, finer
+// than the second jwt uses by default so revoking every token of a user does
+// not also refuse a login made later in the same second.
+const IssuedAtPrecision = time.Millisecond
+
+func init() {
+	jwt.TimePrecision = IssuedAtPrecision
+}
+
+// TokensValidAfter is the time from which tokens are accepted again once every
+// token issued until now is revoked, tokens issued before it are refused.
+func TokensValidAfter(now time.Time) time.Time {
+	return now.Truncate(IssuedAtPrecision)
+}
+
+func GenerateJwtToken(user *model.User, permissions []string, signingKey *SigningKey, claimsConfig ClaimsConfig, expHours int, tokenId *string, sessionId *string) (string, error) {
+	timeNow := time.Now()
+	claims := Claims{
+		Username: user.Username,
+		Email:    user.Email,
+		Role:     user.Role,
+
+		Permissions: permissions,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user.UUID.String(),
+			Issuer:    claimsConfig.Issuer,
+			Audience:  jwt.ClaimStrings{claimsConfig.Audience},
+			IssuedAt:  jwt.NewNumericDate(timeNow),
+			NotBefore: jwt.NewNumericDate(timeNow),
+			ExpiresAt: jwt.NewNumericDate(timeNow.Add(time.Hour * time.Duration(expHours))),
+		},
 	}
 
 	if tokenId != nil {
-		claims["token_id"] = *tokenId
+		claims.ID = *tokenId
+	}
+	if sessionId != nil {
+		claims.SessionID = *sessionId
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	accessToken, err := token.SignedString(JWT_SIGNATURE_KEY)
+	method, err := signingMethod(signingKey.Algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = signingKey.KID
+	accessToken, err := token.SignedString(signingKey.PrivateKey)
 	if err != nil {
 		return "", err
 	}
@@ -32,41 +64,130 @@ func GenerateJwtToken(user *model.User, secretKey string, expHours int, tokenId
 	return accessToken, nil
 }
 
-func ValidateJWT(tokenString string, secretKey string) (*dto.CurrentUser, error) {
-	var JWT_SIGNATURE_KEY = []byte(secretKey)
+// ValidateJWT verifies the token against the public key matching its kid
+// header, getPublicKey returns an error for unknown kids. the claims are then
+// validated against claimsConfig.
+func ValidateJWT(tokenString string, getPublicKey func(kid string) (*PublicKey, error), claimsConfig ClaimsConfig) (*dto.CurrentUser, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			return nil, fmt.Errorf("missing kid")
+		}
 
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if method, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("signing method invalid")
-		} else if method != jwt.SigningMethodHS256 {
+		publicKey, err := getPublicKey(kid)
+		if err != nil {
+			return nil, err
+		}
+
+		// the key decides the algorithm, never the token
+		if token.Method.Alg() != publicKey.Algorithm {
 			return nil, fmt.Errorf("signing method invalid")
 		}
 
-		return JWT_SIGNATURE_KEY, nil
-	})
+		return publicKey.Key, nil
+	}, jwt.WithoutClaimsValidation())
 
 	if err != nil {
 		return nil, err
 	}
 
 	if !token.Valid {
-		return nil, err
+		return nil, fmt.Errorf("invalid token")
 	}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
+	err = claims.Validate(claimsConfig, time.Now())
+	if err != nil {
 		return nil, err
 	}
 
-	sub, _ := claims["sub"].(string)
-	username, _ := claims["username"].(string)
-	role, _ := claims["role"].(string)
-	email, _ := claims["email"].(string)
-
 	return &dto.CurrentUser{
-		UUID:     sub,
-		Email:    email,
-		Username: username,
-		Role:     role,
+		UUID:      claims.Subject,
+		Email:     claims.Email,
+		Username:  claims.Username,
+		Role:      claims.Role,
+		TokenID:   claims.ID,
+		SessionID: claims.SessionID,
+		IssuedAt:  claims.IssuedAt.Time.UTC(),
+		ExpiredAt: claims.ExpiresAt.Time.UTC(),
 	}, nil
 }
+
+func signingMethod(algorithm string) (jwt.SigningMethod, error) {
+	switch algorithm {
+	case AlgorithmRS256:
+		return jwt.SigningMethodRS256, nil
+	case AlgorithmEdDSA:
+		return jwt.SigningMethodEdDSA, nil
+	}
+	return nil, fmt.Errorf("unsupported signing algorithm: %s", algorithm)
+}
+
+// GenerateServiceToken issues the token a service sends on its grpc calls.
+func GenerateServiceToken(service string, signingKey *SigningKey, issuer string, expMinutes int) (string, time.Time, error) {
+	timeNow := time.Now()
+	expiredAt := timeNow.Add(time.Minute * time.Duration(expMinutes))
+	claims := ServiceClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   service,
+			Issuer:    issuer,
+			Audience:  jwt.ClaimStrings{ServiceAudience},
+			IssuedAt:  jwt.NewNumericDate(timeNow),
+			NotBefore: jwt.NewNumericDate(timeNow),
+			ExpiresAt: jwt.NewNumericDate(expiredAt),
+		},
+	}
+
+	method, err := signingMethod(signingKey.Algorithm)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = signingKey.KID
+	serviceToken, err := token.SignedString(signingKey.PrivateKey)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return serviceToken, expiredAt, nil
+}
+
+// ValidateServiceToken verifies a service token like ValidateJWT and returns
+// the name of the service.
+func ValidateServiceToken(tokenString string, getPublicKey func(kid string) (*PublicKey, error), issuer string) (string, error) {
+	claims := &ServiceClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			return nil, fmt.Errorf("missing kid")
+		}
+
+		publicKey, err := getPublicKey(kid)
+		if err != nil {
+			return nil, err
+		}
+
+		// the key decides the algorithm, never the token
+		if token.Method.Alg() != publicKey.Algorithm {
+			return nil, fmt.Errorf("signing method invalid")
+		}
+
+		return publicKey.Key, nil
+	}, jwt.WithoutClaimsValidation())
+
+	if err != nil {
+		return "", err
+	}
+
+	if !token.Valid {
+		return "", fmt.Errorf("invalid token")
+	}
+
+	err = claims.Validate(issuer, time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	return claims.Subject, nil
+}
@@ -0,0 +1,217 @@
+package jwt_util
+
+import (
+	"auth_service/domain/model"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var testClaimsConfig = ClaimsConfig{Issuer: "auth_service", Audience: "library_app"}
+
+func newTestKeyPair(t *testing.T, kid string, algorithm string) (*SigningKey, *PublicKey) {
+	privatePem, publicPem, err := GenerateKeyPair(algorithm)
+	assert.NoError(t, err)
+
+	signingKey, err := ParseSigningKey(kid, algorithm, privatePem)
+	assert.NoError(t, err)
+	publicKey, err := ParsePublicKey(kid, algorithm, publicPem)
+	assert.NoError(t, err)
+
+	return signingKey, publicKey
+}
+
+func TestValidateJWT(t *testing.T) {
+	user := &model.User{
+		UUID:     uuid.New(),
+		Username: "test",
+		Email:    "test@gmail.com",
+		Role:     "admin",
+	}
+
+	for _, algorithm := range []string{AlgorithmRS256, AlgorithmEdDSA} {
+		t.Run(algorithm, func(t *testing.T) {
+			signingKey, publicKey := newTestKeyPair(t, "key-1", algorithm)
+			_, otherPublicKey := newTestKeyPair(t, "key-1", algorithm)
+
+			tokenId := uuid.New().String()
+			token, err := GenerateJwtToken(user, nil, signingKey, testClaimsConfig, 1, &tokenId, nil)
+			assert.NoError(t, err)
+
+			// valid
+			currentUser, err := ValidateJWT(token, func(kid string) (*PublicKey, error) {
+				return publicKey, nil
+			}, testClaimsConfig)
+			assert.NoError(t, err)
+			assert.Equal(t, user.UUID.String(), currentUser.UUID)
+			assert.Equal(t, "admin", currentUser.Role)
+			assert.Equal(t, tokenId, currentUser.TokenID)
+
+			// unknown kid
+			_, err = ValidateJWT(token, func(kid string) (*PublicKey, error) {
+				return nil, fmt.Errorf("unknown kid: %s", kid)
+			}, testClaimsConfig)
+			assert.Error(t, err)
+
+			// signed by another key with the same kid
+			_, err = ValidateJWT(token, func(kid string) (*PublicKey, error) {
+				return otherPublicKey, nil
+			}, testClaimsConfig)
+			assert.Error(t, err)
+
+			// issued for another audience
+			_, err = ValidateJWT(token, func(kid string) (*PublicKey, error) {
+				return publicKey, nil
+			}, ClaimsConfig{Issuer: testClaimsConfig.Issuer, Audience: "other_app"})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestValidateJWT_AlgorithmMismatch(t *testing.T) {
+	user := &model.User{UUID: uuid.New(), Role: "user"}
+	signingKey, _ := newTestKeyPair(t, "key-1", AlgorithmEdDSA)
+	_, rsaPublicKey := newTestKeyPair(t, "key-1", AlgorithmRS256)
+
+	token, err := GenerateJwtToken(user, nil, signingKey, testClaimsConfig, 1, nil, nil)
+	assert.NoError(t, err)
+
+	_, err = ValidateJWT(token, func(kid string) (*PublicKey, error) {
+		return rsaPublicKey, nil
+	}, testClaimsConfig)
+	assert.Error(t, err)
+}
+
+func TestClaims_Validate(t *testing.T) {
+	now := time.Now()
+	newClaims := func() *Claims {
+		return &Claims{
+			Role: "user",
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   uuid.New().String(),
+				Issuer:    testClaimsConfig.Issuer,
+				Audience:  jwt.ClaimStrings{testClaimsConfig.Audience},
+				IssuedAt:  jwt.NewNumericDate(now),
+				NotBefore: jwt.NewNumericDate(now),
+				ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+			},
+		}
+	}
+
+	testCases := []struct {
+		name    string
+		modify  func(claims *Claims)
+		wantErr bool
+	}{
+		{"valid", func(claims *Claims) {}, false},
+		{"iat within clock skew", func(claims *Claims) { claims.IssuedAt = jwt.NewNumericDate(now.Add(time.Second * 10)) }, false},
+		{"missing sub", func(claims *Claims) { claims.Subject = "" }, true},
+		{"missing role", func(claims *Claims) { claims.Role = "" }, true},
+		{"wrong issuer", func(claims *Claims) { claims.Issuer = "other_service" }, true},
+		{"missing issuer", func(claims *Claims) { claims.Issuer = "" }, true},
+		{"wrong audience", func(claims *Claims) { claims.Audience = jwt.ClaimStrings{"other_app"} }, true},
+		{"missing audience", func(claims *Claims) { claims.Audience = nil }, true},
+		{"expired", func(claims *Claims) { claims.ExpiresAt = jwt.NewNumericDate(now.Add(-time.Minute)) }, true},
+		{"missing exp", func(claims *Claims) { claims.ExpiresAt = nil }, true},
+		{"issued in the future", func(claims *Claims) { claims.IssuedAt = jwt.NewNumericDate(now.Add(time.Minute)) }, true},
+		{"missing iat", func(claims *Claims) { claims.IssuedAt = nil }, true},
+		{"not valid yet", func(claims *Claims) { claims.NotBefore = jwt.NewNumericDate(now.Add(time.Minute)) }, true},
+		{"missing nbf", func(claims *Claims) { claims.NotBefore = nil }, true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			claims := newClaims()
+			testCase.modify(claims)
+
+			err := claims.Validate(testClaimsConfig, now)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPublicKey_JWK(t *testing.T) {
+	_, rsaPublicKey := newTestKeyPair(t, "rsa-key", AlgorithmRS256)
+	jwk := rsaPublicKey.JWK()
+	assert.Equal(t, "RSA", jwk.Kty)
+	assert.Equal(t, "rsa-key", jwk.Kid)
+	assert.Equal(t, "AQAB", jwk.E)
+	assert.NotEmpty(t, jwk.N)
+
+	_, edPublicKey := newTestKeyPair(t, "ed-key", AlgorithmEdDSA)
+	jwk = edPublicKey.JWK()
+	assert.Equal(t, "OKP", jwk.Kty)
+	assert.Equal(t, "Ed25519", jwk.Crv)
+	assert.NotEmpty(t, jwk.X)
+}
+
+func TestValidateServiceToken(t *testing.T) {
+	signingKey, publicKey := newTestKeyPair(t, "key-1", AlgorithmRS256)
+	getPublicKey := func(kid string) (*PublicKey, error) {
+		return publicKey, nil
+	}
+
+	token, expiredAt, err := GenerateServiceToken("book_service", signingKey, "auth_service", 15)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), expiredAt, 5*time.Second)
+
+	// valid
+	service, err := ValidateServiceToken(token, getPublicKey, "auth_service")
+	assert.NoError(t, err)
+	assert.Equal(t, "book_service", service)
+
+	// another issuer
+	_, err = ValidateServiceToken(token, getPublicKey, "other_service")
+	assert.Error(t, err)
+
+	// a service token is not an access token
+	_, err = ValidateJWT(token, getPublicKey, testClaimsConfig)
+	assert.Error(t, err)
+
+	// and an access token is not a service token
+	user := &model.User{UUID: uuid.New(), Username: "test", Role: "admin"}
+	accessToken, err := GenerateJwtToken(user, nil, signingKey, testClaimsConfig, 1, nil, nil)
+	assert.NoError(t, err)
+	_, err = ValidateServiceToken(accessToken, getPublicKey, "auth_service")
+	assert.Error(t, err)
+
+	// expired
+	expiredToken, _, err := GenerateServiceToken("book_service", signingKey, "auth_service", -10)
+	assert.NoError(t, err)
+	_, err = ValidateServiceToken(expiredToken, getPublicKey, "auth_service")
+	assert.Error(t, err)
+}
+
+// TestTokensValidAfter checks revoking every token of a user refuses the
+// tokens issued before, but not a login made right after in the same second.
+func TestTokensValidAfter(t *testing.T) {
+	user := &model.User{UUID: uuid.New(), Role: "user"}
+	signingKey, publicKey := newTestKeyPair(t, "key-1", AlgorithmEdDSA)
+	getPublicKey := func(kid string) (*PublicKey, error) {
+		return publicKey, nil
+	}
+
+	before, err := GenerateJwtToken(user, nil, signingKey, testClaimsConfig, 1, nil, nil)
+	assert.NoError(t, err)
+	time.Sleep(IssuedAtPrecision * 2)
+
+	validAfter := TokensValidAfter(time.Now())
+	after, err := GenerateJwtToken(user, nil, signingKey, testClaimsConfig, 1, nil, nil)
+	assert.NoError(t, err)
+
+	currentUser, err := ValidateJWT(before, getPublicKey, testClaimsConfig)
+	assert.NoError(t, err)
+	assert.True(t, currentUser.IssuedAt.Before(validAfter), "token issued before is revoked")
+
+	currentUser, err = ValidateJWT(after, getPublicKey, testClaimsConfig)
+	assert.NoError(t, err)
+	assert.False(t, currentUser.IssuedAt.Before(validAfter), "token issued after is accepted")
+}
@@ -0,0 +1,142 @@
+package jwt_util
+
+import (
+	"auth_service/domain/dto"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+const rsaKeyBits = 2048
+
+// SigningKey is a private key used to sign access tokens, identified by kid.
+type SigningKey struct {
+	KID        string
+	Algorithm  string
+	PrivateKey crypto.Signer
+}
+
+// PublicKey is the verification half of a SigningKey.
+type PublicKey struct {
+	KID       string
+	Algorithm string
+	Key       crypto.PublicKey
+}
+
+// GenerateKeyPair creates a new key pair for the algorithm, encoded as PKCS8
+// and PKIX pem.
+func GenerateKeyPair(algorithm string) (privatePem string, publicPem string, err error) {
+	var privateKey crypto.Signer
+	switch algorithm {
+	case AlgorithmRS256:
+		privateKey, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	case AlgorithmEdDSA:
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return "", "", fmt.Errorf("unsupported signing algorithm: %s", algorithm)
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	privateDer, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return "", "", err
+	}
+	publicDer, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+	if err != nil {
+		return "", "", err
+	}
+
+	privatePem = string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDer}))
+	publicPem = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDer}))
+	return privatePem, publicPem, nil
+}
+
+func ParseSigningKey(kid string, algorithm string, privatePem string) (*SigningKey, error) {
+	block, _ := pem.Decode([]byte(privatePem))
+	if block == nil {
+		return nil, errors.New("invalid private key pem")
+	}
+	raw, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, ok := raw.(crypto.Signer)
+	if !ok || !matchesAlgorithm(algorithm, privateKey.Public()) {
+		return nil, fmt.Errorf("private key does not match algorithm %s", algorithm)
+	}
+
+	return &SigningKey{
+		KID:        kid,
+		Algorithm:  algorithm,
+		PrivateKey: privateKey,
+	}, nil
+}
+
+func ParsePublicKey(kid string, algorithm string, publicPem string) (*PublicKey, error) {
+	block, _ := pem.Decode([]byte(publicPem))
+	if block == nil {
+		return nil, errors.New("invalid public key pem")
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if !matchesAlgorithm(algorithm, publicKey) {
+		return nil, fmt.Errorf("public key does not match algorithm %s", algorithm)
+	}
+
+	return &PublicKey{
+		KID:       kid,
+		Algorithm: algorithm,
+		Key:       publicKey,
+	}, nil
+}
+
+// JWK encodes the public key as in RFC 7517, RSA keys as kty RSA and ed25519
+// keys as kty OKP (RFC 8037).
+func (k *PublicKey) JWK() dto.JWK {
+	jwk := dto.JWK{
+		Kid: k.KID,
+		Use: "sig",
+		Alg: k.Algorithm,
+	}
+
+	switch key := k.Key.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(key)
+	}
+
+	return jwk
+}
+
+func matchesAlgorithm(algorithm string, publicKey crypto.PublicKey) bool {
+	switch publicKey.(type) {
+	case *rsa.PublicKey:
+		return algorithm == AlgorithmRS256
+	case ed25519.PublicKey:
+		return algorithm == AlgorithmEdDSA
+	}
+	return false
+}
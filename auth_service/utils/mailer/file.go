@@ -0,0 +1,42 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+// NewFileMailer writes every mail as an .eml file into dir instead of sending
+// it, for local setups without a mail server.
+func NewFileMailer(dir string, from string) Mailer {
+	return &FileMailer{
+		dir:  dir,
+		from: from,
+	}
+}
+
+func (mailer *FileMailer) Send(ctx context.Context, message Message) error {
+	err := os.MkdirAll(mailer.dir, 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create mail dir: %w", err)
+	}
+
+	filename := fmt.Sprintf("%s_%s.eml", time.Now().UTC().Format("20060102T150405"), uuid.New().String())
+	path := filepath.Join(mailer.dir, filename)
+	err = os.WriteFile(path, buildMessage(mailer.from, message), 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to write mail: %w", err)
+	}
+
+	logger.Infof("mail %q to %s written to %s", message.Subject, message.To, path)
+	return nil
+}
@@ -0,0 +1,20 @@
+package mailer
+
+import (
+	"context"
+
+	"github.com/op/go-logging"
+)
+
+var logger = logging.MustGetLogger("mailer")
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string // plain text
+}
+
+// Mailer delivers transactional mails such as password reset links.
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
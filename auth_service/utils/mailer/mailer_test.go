@@ -0,0 +1,33 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileMailer_Send(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mails")
+	mailer := NewFileMailer(dir, "no-reply@library.local")
+
+	err := mailer.Send(context.Background(), Message{
+		To:      "user@gmail.com",
+		Subject: "Reset your password",
+		Body:    "line 1\nline 2",
+	})
+	assert.NoError(t, err)
+
+	files, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	content, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "From: no-reply@library.local\r\n")
+	assert.Contains(t, string(content), "To: user@gmail.com\r\n")
+	assert.Contains(t, string(content), "Subject: Reset your password\r\n")
+	assert.Contains(t, string(content), "\r\n\r\nline 1\r\nline 2\r\n")
+}
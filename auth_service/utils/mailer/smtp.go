@@ -0,0 +1,66 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+type SMTPMailer struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+}
+
+// NewSMTPMailer sends mails through an smtp server, authenticating with plain
+// auth when a username is set.
+func NewSMTPMailer(host string, port int, username string, password string, from string) Mailer {
+	return &SMTPMailer{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+func (mailer *SMTPMailer) Send(ctx context.Context, message Message) error {
+	var auth smtp.Auth
+	if mailer.username != "" {
+		auth = smtp.PlainAuth("", mailer.username, mailer.password, mailer.host)
+	}
+
+	addr := fmt.Sprintf("%s:%d", mailer.host, mailer.port)
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, mailer.from, []string{message.To}, buildMessage(mailer.from, message))
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("failed to send mail: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to send mail: %w", ctx.Err())
+	}
+}
+
+// buildMessage renders the message as an rfc 5322 plain text mail.
+func buildMessage(from string, message Message) []byte {
+	headers := []string{
+		"From: " + from,
+		"To: " + message.To,
+		"Subject: " + message.Subject,
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+	}
+	body := strings.ReplaceAll(message.Body, "\n", "\r\n")
+	return []byte(strings.Join(headers, "\r\n") + "\r\n\r\n" + body + "\r\n")
+}
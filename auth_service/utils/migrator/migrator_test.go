@@ -0,0 +1,72 @@
+package migrator
+
+import (
+	"auth_service/migrations"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		want    []int64
+		wantErr bool
+	}{
+		{
+			name: "sorted_by_version",
+			fsys: fstest.MapFS{
+				"0002_add_column.up.sql":   {Data: []byte("ALTER TABLE a ADD COLUMN b text;")},
+				"0002_add_column.down.sql": {Data: []byte("ALTER TABLE a DROP COLUMN b;")},
+				"0001_init.up.sql":         {Data: []byte("CREATE TABLE a (id bigserial);")},
+				"0001_init.down.sql":       {Data: []byte("DROP TABLE a;")},
+				"migrations.go":            {Data: []byte("package migrations")},
+			},
+			want: []int64{1, 2},
+		},
+		{
+			name: "missing_down_file",
+			fsys: fstest.MapFS{
+				"0001_init.up.sql": {Data: []byte("CREATE TABLE a (id bigserial);")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate_version",
+			fsys: fstest.MapFS{
+				"0001_init.up.sql":    {Data: []byte("CREATE TABLE a (id bigserial);")},
+				"0001_init.down.sql":  {Data: []byte("DROP TABLE a;")},
+				"0001_other.up.sql":   {Data: []byte("CREATE TABLE b (id bigserial);")},
+				"0001_other.down.sql": {Data: []byte("DROP TABLE b;")},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(tt.fsys)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Load() got %d migrations, want %d", len(got), len(tt.want))
+			}
+			for i, migration := range got {
+				if migration.Version != tt.want[i] {
+					t.Errorf("Load()[%d].Version = %d, want %d", i, migration.Version, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// every migration shipped with the service must load
+func TestLoad_ServiceMigrations(t *testing.T) {
+	got, err := Load(migrations.FS)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(got) == 0 {
+		t.Fatal("Load() found no migrations")
+	}
+}
@@ -0,0 +1,138 @@
+package saga_util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// saga statuses, recorded so that the reconciliation can finish sagas
+// interrupted by a crash
+const (
+	StatusStarted      = "started"
+	StatusCompleted    = "completed"
+	StatusCompensating = "compensating"
+	StatusCompensated  = "compensated"
+	StatusFailed       = "failed" // a compensation failed, repaired by the reconciliation
+)
+
+// transient grpc errors are retried this many times with the same idempotency
+// key
+const (
+	retryAttempts = 3
+	retryBackoff  = time.Millisecond * 200
+)
+
+type Step struct {
+	Name   string
+	Action func(ctx context.Context) error
+	// Compensate undoes the action once a later step failed, nil when there
+	// is nothing to undo.
+	Compensate func(ctx context.Context) error
+}
+
+// State is the progress of a saga, Step is the last step that completed.
+type State struct {
+	Status string
+	Step   string
+	Err    error
+}
+
+// Error is returned when a step failed, after the completed steps were
+// compensated.
+type Error struct {
+	Step              string
+	Err               error
+	CompensationError error // nil when every compensation succeeded
+}
+
+func (e *Error) Error() string {
+	if e.CompensationError != nil {
+		return fmt.Sprintf("saga step %s failed: %v, compensation failed: %v", e.Step, e.Err, e.CompensationError)
+	}
+	return fmt.Sprintf("saga step %s failed: %v", e.Step, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Run executes the steps in order. when one fails the completed ones are
+// compensated in reverse order. record is called on every change of state,
+// its errors are ignored since the saga itself already went through.
+func Run(ctx context.Context, steps []Step, record func(state State)) error {
+	record(State{Status: StatusStarted})
+
+	completed := []Step{}
+	for _, step := range steps {
+		err := step.Action(ctx)
+		if err != nil {
+			sagaErr := &Error{Step: step.Name, Err: err}
+			lastStep := ""
+			if len(completed) > 0 {
+				lastStep = completed[len(completed)-1].Name
+			}
+			record(State{Status: StatusCompensating, Step: lastStep, Err: err})
+
+			sagaErr.CompensationError = Compensate(ctx, completed)
+			if sagaErr.CompensationError != nil {
+				record(State{Status: StatusFailed, Step: lastStep, Err: sagaErr})
+			} else {
+				record(State{Status: StatusCompensated, Err: err})
+			}
+			return sagaErr
+		}
+
+		completed = append(completed, step)
+		record(State{Status: StatusStarted, Step: step.Name})
+	}
+
+	record(State{Status: StatusCompleted, Step: steps[len(steps)-1].Name})
+	return nil
+}
+
+// Compensate undoes the given completed steps in reverse order, it goes on
+// after a failed compensation and returns the errors joined. compensations
+// still run once the request that started the saga is cancelled.
+func Compensate(ctx context.Context, completed []Step) error {
+	ctx = context.WithoutCancel(ctx)
+	errs := []error{}
+	for i := len(completed) - 1; i >= 0; i-- {
+		step := completed[i]
+		if step.Compensate == nil {
+			continue
+		}
+		err := step.Compensate(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", step.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RetryTransient calls fn again while it fails with an unavailable or
+// deadline exceeded grpc error, fn must be idempotent.
+func RetryTransient(ctx context.Context, fn func(ctx context.Context) error) error {
+	var err error
+	for attempt := 1; attempt <= retryAttempts; attempt++ {
+		err = fn(ctx)
+		code := status.Code(err)
+		if code != codes.Unavailable && code != codes.DeadlineExceeded {
+			return err
+		}
+		if attempt == retryAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(retryBackoff * time.Duration(attempt)):
+		}
+	}
+	return err
+}
@@ -0,0 +1,132 @@
+package saga_util
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// recordedSteps returns steps that log their actions and compensations.
+func recordedSteps(calls *[]string, failAt string, failCompensationAt string) []Step {
+	steps := []Step{}
+	for _, name := range []string{"first", "second", "third"} {
+		name := name
+		steps = append(steps, Step{
+			Name: name,
+			Action: func(ctx context.Context) error {
+				*calls = append(*calls, "do "+name)
+				if name == failAt {
+					return errors.New(name + " failed")
+				}
+				return nil
+			},
+			Compensate: func(ctx context.Context) error {
+				*calls = append(*calls, "undo "+name)
+				if name == failCompensationAt {
+					return errors.New("undo " + name + " failed")
+				}
+				return nil
+			},
+		})
+	}
+	return steps
+}
+
+func TestRun(t *testing.T) {
+	t.Run("completes every step", func(t *testing.T) {
+		calls := []string{}
+		states := []State{}
+		err := Run(context.Background(), recordedSteps(&calls, "", ""), func(state State) {
+			states = append(states, state)
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"do first", "do second", "do third"}, calls)
+		assert.Equal(t, State{Status: StatusCompleted, Step: "third"}, states[len(states)-1])
+	})
+
+	t.Run("compensates the completed steps in reverse order", func(t *testing.T) {
+		calls := []string{}
+		states := []State{}
+		err := Run(context.Background(), recordedSteps(&calls, "third", ""), func(state State) {
+			states = append(states, state)
+		})
+
+		var sagaErr *Error
+		assert.ErrorAs(t, err, &sagaErr)
+		assert.Equal(t, "third", sagaErr.Step)
+		assert.NoError(t, sagaErr.CompensationError)
+		assert.Equal(t, []string{"do first", "do second", "do third", "undo second", "undo first"}, calls)
+		assert.Equal(t, StatusCompensated, states[len(states)-1].Status)
+	})
+
+	t.Run("goes on after a failed compensation", func(t *testing.T) {
+		calls := []string{}
+		states := []State{}
+		err := Run(context.Background(), recordedSteps(&calls, "third", "second"), func(state State) {
+			states = append(states, state)
+		})
+
+		var sagaErr *Error
+		assert.ErrorAs(t, err, &sagaErr)
+		assert.ErrorContains(t, sagaErr.CompensationError, "undo second failed")
+		assert.Equal(t, []string{"do first", "do second", "do third", "undo second", "undo first"}, calls)
+		assert.Equal(t, State{Status: StatusFailed, Step: "second", Err: err}, states[len(states)-1])
+	})
+
+	t.Run("compensates after the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		compensated := false
+		err := Run(ctx, []Step{
+			{
+				Name:   "first",
+				Action: func(ctx context.Context) error { return nil },
+				Compensate: func(ctx context.Context) error {
+					compensated = ctx.Err() == nil
+					return nil
+				},
+			},
+			{
+				Name: "second",
+				Action: func(ctx context.Context) error {
+					cancel()
+					return ctx.Err()
+				},
+			},
+		}, func(state State) {})
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.True(t, compensated)
+	})
+}
+
+func TestRetryTransient(t *testing.T) {
+	t.Run("retries unavailable errors", func(t *testing.T) {
+		attempts := 0
+		err := RetryTransient(context.Background(), func(ctx context.Context) error {
+			attempts++
+			if attempts < 2 {
+				return status.Error(codes.Unavailable, "unavailable")
+			}
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		attempts := 0
+		err := RetryTransient(context.Background(), func(ctx context.Context) error {
+			attempts++
+			return status.Error(codes.AlreadyExists, "already exists")
+		})
+
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+		assert.Equal(t, 1, attempts)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	author_pb "auth_service/interface/grpc/genproto/author"
 	"auth_service/repository"
 	bcrypt_util "auth_service/utils/bcrypt"
+	"auth_service/utils/helper"
 	"context"
 	"fmt"
 
@@ -19,6 +20,7 @@ var logger = logging.MustGetLogger("main")
 
 func SeedUser(userRepo repository.IUserRepo, authorGrpcServiceClient author_pb.AuthorServiceClient) error {
 	users := []model.User{}
+	emailVerifiedAt := helper.TimeNowUTC()
 
 	if config.Envs.INITIAL_ADMIN_USERNAME != "" && config.Envs.INITIAL_ADMIN_PASSWORD != "" {
 		hashedPassword, _ := bcrypt_util.Hash(config.Envs.INITIAL_ADMIN_PASSWORD)
@@ -27,7 +29,9 @@ func SeedUser(userRepo repository.IUserRepo, authorGrpcServiceClient author_pb.A
 			Username: config.Envs.INITIAL_ADMIN_USERNAME,
 			Password: hashedPassword,
 			Email:    fmt.Sprint(config.Envs.INITIAL_ADMIN_USERNAME, "@gmail.com"),
-			Role:     "admin",
+			Role:     model.RoleAdmin,
+
+			EmailVerifiedAt: &emailVerifiedAt,
 		})
 	} else {
 		logger.Warningf("initial admin username and password not set")
@@ -40,7 +44,9 @@ func SeedUser(userRepo repository.IUserRepo, authorGrpcServiceClient author_pb.A
 			Username: config.Envs.INITIAL_USER_USERNAME,
 			Password: hashedPassword,
 			Email:    fmt.Sprint(config.Envs.INITIAL_USER_USERNAME, "@gmail.com"),
-			Role:     "user",
+			Role:     model.RoleUser,
+
+			EmailVerifiedAt: &emailVerifiedAt,
 		})
 	} else {
 		logger.Warningf("initial user username and password not set")
@@ -0,0 +1,68 @@
+package totp_util
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rfcSecret is the sha1 seed of the RFC 6238 test vectors, "12345678901234567890".
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCode_RFC6238(t *testing.T) {
+	// RFC 6238 appendix B lists 8 digit codes, 6 digit codes are their last 6 digits
+	testCases := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, testCase := range testCases {
+		code, err := Code(rfcSecret, Step(time.Unix(testCase.unix, 0)))
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.code, code, "unix %d", testCase.unix)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	code, err := Code(rfcSecret, Step(now))
+	assert.NoError(t, err)
+
+	step, ok := Validate(rfcSecret, code, now)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now), step)
+
+	// previous and next step are accepted, further ones are not
+	_, ok = Validate(rfcSecret, code, now.Add(time.Second*period))
+	assert.True(t, ok)
+	_, ok = Validate(rfcSecret, code, now.Add(-time.Second*period))
+	assert.True(t, ok)
+	_, ok = Validate(rfcSecret, code, now.Add(time.Second*period*2))
+	assert.False(t, ok)
+
+	_, ok = Validate(rfcSecret, "000000", now)
+	assert.False(t, ok)
+	_, ok = Validate(rfcSecret, "12345", now)
+	assert.False(t, ok)
+}
+
+func TestURI(t *testing.T) {
+	secret, err := GenerateSecret()
+	assert.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	uri := URI("Library App", "admin@gmail.com", secret)
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Library%20App:admin@gmail.com?"))
+	assert.Contains(t, uri, "secret="+secret)
+	assert.Contains(t, uri, "issuer=Library+App")
+}
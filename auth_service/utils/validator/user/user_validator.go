@@ -40,11 +40,3 @@ func ValidatePassword(password string) error {
 	}
 	return nil
 }
-
-func ValidateRole(role string) error {
-	if role != "admin" && role != "user" {
-		return errors.New("invalid role")
-	}
-
-	return nil
-}
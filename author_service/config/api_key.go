@@ -0,0 +1,39 @@
+package config
+
+import (
+	"author_service/domain/dto"
+	auth_grpc "author_service/interface/grpc/genproto/auth"
+	api_key_util "author_service/utils/api_key"
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewAPIKeyCache creates the cache of api keys validated by auth_service over
+// grpc.
+func NewAPIKeyCache(authGrpcServiceClient auth_grpc.AuthServiceClient) *api_key_util.Cache {
+	validate := func(ctx context.Context, apiKey string) (*dto.CurrentUser, error) {
+		resp, err := authGrpcServiceClient.ValidateAPIKey(ctx, &auth_grpc.ValidateAPIKeyRequest{ApiKey: apiKey})
+		if err != nil {
+			switch status.Code(err) {
+			case codes.Unauthenticated, codes.InvalidArgument:
+				return nil, fmt.Errorf("%w: %s", api_key_util.ErrInvalidAPIKey, status.Convert(err).Message())
+			}
+			return nil, err
+		}
+
+		return &dto.CurrentUser{
+			UUID:        resp.Uuid,
+			Email:       resp.Email,
+			Username:    resp.Username,
+			Role:        resp.Role,
+			Permissions: resp.Permissions,
+			APIKeyUUID:  resp.ApiKeyUuid,
+		}, nil
+	}
+
+	return api_key_util.NewCache(validate, time.Second*time.Duration(Envs.API_KEY_CACHE_TTL_SECONDS))
+}
@@ -113,6 +113,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.CreateNewAuthorReq"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "retries with the same key return the same author",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -254,6 +260,24 @@ const docTemplate = `{
                     "Authors"
                 ],
                 "summary": "Delete author",
+                "parameters": [
+                    {
+                        "enum": [
+                            "reassign",
+                            "delete"
+                        ],
+                        "type": "string",
+                        "description": "reassign by default",
+                        "name": "books",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "author given the books, PLACEHOLDER_AUTHOR_UUID by default",
+                        "name": "reassign_to",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -339,7 +363,6 @@ const docTemplate = `{
                 "email",
                 "first_name",
                 "password",
-                "role",
                 "username"
             ],
             "properties": {
@@ -362,11 +385,8 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "role": {
-                    "type": "string",
-                    "enum": [
-                        "admin",
-                        "user"
-                    ]
+                    "description": "only honored when the caller may manage users, user by default",
+                    "type": "string"
                 },
                 "user_uuid": {
                     "description": "required for create new author by auth service, optional for client",
@@ -439,6 +459,12 @@ const docTemplate = `{
                 "role": {
                     "type": "string"
                 },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.DeleteAuthorStep"
+                    }
+                },
                 "updated_at": {
                     "type": "string"
                 },
@@ -453,6 +479,20 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.DeleteAuthorStep": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
         "dto.EditAuthorReq": {
             "type": "object",
             "properties": {
@@ -475,11 +515,8 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "role": {
-                    "type": "string",
-                    "enum": [
-                        "admin",
-                        "user"
-                    ]
+                    "description": "checked against the roles of auth_service",
+                    "type": "string"
                 },
                 "username": {
                     "type": "string"
@@ -5,4 +5,26 @@ type CurrentUser struct {
 	Email    string `json:"email"`
 	Username string `json:"username"`
 	Role     string `json:"role"`
+
+	Permissions []string `json:"permissions"` // granted to the role in auth_service
+
+	APIKeyUUID string `json:"-"` // set when authenticated by an api key instead of a token
+
+	AccessToken string `json:"-"` // bearer token of the request, forwarded on grpc calls
 }
+
+func (currentUser CurrentUser) HasPermission(permission string) bool {
+	for _, p := range currentUser.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// permissions checked by this service, roles are granted them in auth_service
+const (
+	PermissionAuthorCreate = "author:create"
+	PermissionAuthorUpdate = "author:update"
+	PermissionAuthorDelete = "author:delete"
+)
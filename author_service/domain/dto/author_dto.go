@@ -49,7 +49,11 @@ type CreateNewAuthorReq struct {
 	LastName  string  `json:"last_name"`
 	BirthDate *string `json:"birth_date"`
 	Bio       *string `json:"bio"`
-	Role      string  `json:"role" binding:"required,oneof=admin user"`
+	Role      string  `json:"role"` // only honored when the caller may manage users, user by default
+
+	// a retry with the same key returns the author of the first call, taken
+	// from the Idempotency-Key header
+	IdempotencyKey string `json:"-"`
 }
 
 type CreateNewAuthorRespData struct {
@@ -74,7 +78,7 @@ type EditAuthorReq struct {
 	LastName  *string `json:"last_name"`
 	BirthDate *string `json:"birth_date"`
 	Bio       *string `json:"bio"`
-	Role      *string `json:"role" binding:"oneof=admin user"`
+	Role      *string `json:"role"` // checked against the roles of auth_service
 }
 
 type EditAuthorRespData struct {
@@ -91,6 +95,37 @@ type EditAuthorRespData struct {
 	Role      string    `json:"role"`
 }
 
+// what happens to the books of a deleted author
+const (
+	DeleteAuthorBooksReassign = "reassign"
+	DeleteAuthorBooksDelete   = "delete"
+)
+
+type DeleteAuthorReq struct {
+	Books      string `form:"books" binding:"omitempty,oneof=reassign delete"` // reassign by default
+	ReassignTo string `form:"reassign_to" binding:"omitempty,uuid"`            // author given the books, PLACEHOLDER_AUTHOR_UUID by default
+}
+
+// statuses of the steps of an author deletion
+const (
+	DeleteAuthorStepPending = "pending"
+	DeleteAuthorStepDone    = "done"
+	DeleteAuthorStepSkipped = "skipped"
+	DeleteAuthorStepFailed  = "failed"
+)
+
+type DeleteAuthorStep struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// DeleteAuthorFailedRespData is returned with the error of a failed deletion,
+// the steps already done are not undone.
+type DeleteAuthorFailedRespData struct {
+	Steps []DeleteAuthorStep `json:"steps"`
+}
+
 type DeleteAuthorRespData struct {
 	UUID      uuid.UUID `json:"uuid"`
 	CreatedAt time.Time `json:"created_at"`
@@ -103,6 +138,8 @@ type DeleteAuthorRespData struct {
 	BirthDate *string   `json:"birth_date"`
 	Bio       *string   `json:"bio"`
 	Role      string    `json:"role"`
+
+	Steps []DeleteAuthorStep `json:"steps"`
 }
 
 type GetAuthorDetailRespData struct {
@@ -130,3 +167,29 @@ type GetAuthorByUserUUIDRespData struct {
 	BirthDate *string   `json:"birth_date"`
 	Bio       *string   `json:"bio"`
 }
+
+type GetAuthorByUUIDRespData struct {
+	UUID      uuid.UUID `json:"uuid"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	UserUUID  uuid.UUID `json:"user_uuid"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	BirthDate *string   `json:"birth_date"`
+	Bio       *string   `json:"bio"`
+}
+
+type PurgeAuthorByUserUUIDRespData struct {
+	UUID uuid.UUID `json:"uuid"`
+}
+
+type ReconcileRespData struct {
+	Items []ReconcileRespDataItem `json:"items"`
+}
+
+type ReconcileRespDataItem struct {
+	Kind   string `json:"kind"` // saga or author
+	UUID   string `json:"uuid"`
+	Action string `json:"action"` // completed, compensated, purged or failed
+	Detail string `json:"detail"`
+}
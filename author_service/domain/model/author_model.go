@@ -1,6 +1,7 @@
 package model
 
 import (
+	event_util "author_service/utils/event"
 	validator_util "author_service/utils/validator/author"
 	"errors"
 
@@ -16,6 +17,18 @@ type Author struct {
 	LastName  string    `gorm:"type:text" json:"last_name"`
 	BirthDate *string   `gorm:"type:text" json:"birth_date"`
 	Bio       *string   `gorm:"type:text" json:"bio"`
+
+	// key of the call that created the author, a retry returns the same one
+	IdempotencyKey *string `gorm:"type:varchar(100);unique" json:"-"`
+}
+
+func (u *Author) EventPayload() event_util.AuthorPayload {
+	return event_util.AuthorPayload{
+		UUID:      u.UUID.String(),
+		UserUUID:  u.UserUUID.String(),
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+	}
 }
 
 func (u *Author) Validate() (err error) {
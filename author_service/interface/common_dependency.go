@@ -2,8 +2,14 @@ package interface_pkg
 
 import (
 	ucase "author_service/usecase"
+	api_key_util "author_service/utils/api_key"
+	jwt_util "author_service/utils/jwt"
 )
 
 type CommonDependency struct {
 	AuthorUcase ucase.IAuthorUcase
+
+	KeySet       *jwt_util.KeySet      // auth_service public keys to verify access tokens
+	ClaimsConfig jwt_util.ClaimsConfig // issuer and audience access tokens must carry
+	APIKeyCache  *api_key_util.Cache   // api keys of service accounts validated by auth_service
 }
@@ -7,7 +7,10 @@
 package category_grpc
 
 import (
+	context "context"
 	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
 )
 
 // This is a compile-time assertion to ensure that this generated file
@@ -15,10 +18,15 @@ import (
 // Requires gRPC-Go v1.64.0 or later.
 const _ = grpc.SupportPackageIsVersion9
 
+const (
+	CategoryService_GetCategoryByUUID_FullMethodName = "/category_service.CategoryService/GetCategoryByUUID"
+)
+
 // CategoryServiceClient is the client API for CategoryService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type CategoryServiceClient interface {
+	GetCategoryByUUID(ctx context.Context, in *GetCategoryByUUIDReq, opts ...grpc.CallOption) (*GetCategoryByUUIDResp, error)
 }
 
 type categoryServiceClient struct {
@@ -29,10 +37,21 @@ func NewCategoryServiceClient(cc grpc.ClientConnInterface) CategoryServiceClient
 	return &categoryServiceClient{cc}
 }
 
+func (c *categoryServiceClient) GetCategoryByUUID(ctx context.Context, in *GetCategoryByUUIDReq, opts ...grpc.CallOption) (*GetCategoryByUUIDResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCategoryByUUIDResp)
+	err := c.cc.Invoke(ctx, CategoryService_GetCategoryByUUID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CategoryServiceServer is the server API for CategoryService service.
 // All implementations must embed UnimplementedCategoryServiceServer
 // for forward compatibility.
 type CategoryServiceServer interface {
+	GetCategoryByUUID(context.Context, *GetCategoryByUUIDReq) (*GetCategoryByUUIDResp, error)
 	mustEmbedUnimplementedCategoryServiceServer()
 }
 
@@ -43,6 +62,9 @@ type CategoryServiceServer interface {
 // pointer dereference when methods are called.
 type UnimplementedCategoryServiceServer struct{}
 
+func (UnimplementedCategoryServiceServer) GetCategoryByUUID(context.Context, *GetCategoryByUUIDReq) (*GetCategoryByUUIDResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCategoryByUUID not implemented")
+}
 func (UnimplementedCategoryServiceServer) mustEmbedUnimplementedCategoryServiceServer() {}
 func (UnimplementedCategoryServiceServer) testEmbeddedByValue()                         {}
 
@@ -64,13 +86,36 @@ func RegisterCategoryServiceServer(s grpc.ServiceRegistrar, srv CategoryServiceS
 	s.RegisterService(&CategoryService_ServiceDesc, srv)
 }
 
+func _CategoryService_GetCategoryByUUID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCategoryByUUIDReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoryServiceServer).GetCategoryByUUID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CategoryService_GetCategoryByUUID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoryServiceServer).GetCategoryByUUID(ctx, req.(*GetCategoryByUUIDReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CategoryService_ServiceDesc is the grpc.ServiceDesc for CategoryService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var CategoryService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "category_service.CategoryService",
 	HandlerType: (*CategoryServiceServer)(nil),
-	Methods:     []grpc.MethodDesc{},
-	Streams:     []grpc.StreamDesc{},
-	Metadata:    "category.proto",
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetCategoryByUUID",
+			Handler:    _CategoryService_GetCategoryByUUID_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "category.proto",
 }
@@ -57,6 +57,7 @@ func (r *AuthorServiceHandler) CreateAuthor(
 	} else {
 		payloadDto.Bio = &in.Bio
 	}
+	payloadDto.IdempotencyKey = in.IdempotencyKey
 
 	logger.Debugf("calling create new author")
 	raw, err := r.authorUcase.CreateNewAuthor(ctx, payloadDto)
@@ -92,7 +93,7 @@ func (r *AuthorServiceHandler) CreateAuthor(
 	return resp, nil
 }
 
-func (r *AuthorServiceHandler) GetUserByUUID(
+func (r *AuthorServiceHandler) GetAuthorByUserUUID(
 	ctx context.Context,
 	in *author_pb.GetAuthorByUserUUIDReq,
 ) (*author_pb.GetAuthorByUserUUIDResp, error) {
@@ -130,3 +131,65 @@ func (r *AuthorServiceHandler) GetUserByUUID(
 
 	return resp, nil
 }
+
+func (r *AuthorServiceHandler) GetAuthorByUUID(
+	ctx context.Context,
+	in *author_pb.GetAuthorByUUIDReq,
+) (*author_pb.GetAuthorByUUIDResp, error) {
+	if in.Uuid == "" {
+		logger.Errorf("invalid request: missing uuid")
+		return nil, status.Error(codes.InvalidArgument, "uuid is required")
+	}
+
+	raw, err := r.authorUcase.GetAuthorByUUID(ctx, in.Uuid)
+	if err != nil {
+		customErr, ok := err.(*error_utils.CustomErr)
+		if ok {
+			return nil, status.Errorf(customErr.GrpcCode, customErr.Error())
+		}
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	resp := &author_pb.GetAuthorByUUIDResp{
+		Uuid:      raw.UUID.String(),
+		UserUuid:  raw.UserUUID.String(),
+		FirstName: raw.FirstName,
+		LastName:  raw.LastName,
+	}
+	if raw.BirthDate != nil {
+		resp.BirthDate = *raw.BirthDate
+	} else {
+		resp.BirthDate = ""
+	}
+
+	if raw.Bio != nil {
+		resp.Bio = *raw.Bio
+	} else {
+		resp.Bio = ""
+	}
+
+	return resp, nil
+}
+
+func (r *AuthorServiceHandler) PurgeAuthorByUserUUID(
+	ctx context.Context,
+	in *author_pb.PurgeAuthorByUserUUIDReq,
+) (*author_pb.PurgeAuthorByUserUUIDResp, error) {
+	if in.UserUuid == "" {
+		logger.Errorf("invalid request: missing user_uuid")
+		return nil, status.Error(codes.InvalidArgument, "user uuid is required")
+	}
+
+	raw, err := r.authorUcase.PurgeAuthorByUserUUID(ctx, in.UserUuid)
+	if err != nil {
+		customErr, ok := err.(*error_utils.CustomErr)
+		if ok {
+			return nil, status.Errorf(customErr.GrpcCode, customErr.Error())
+		}
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	return &author_pb.PurgeAuthorByUserUUIDResp{
+		Uuid: raw.UUID.String(),
+	}, nil
+}
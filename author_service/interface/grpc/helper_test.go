@@ -0,0 +1,105 @@
+package grpc
+
+import (
+	"author_service/domain/dto"
+	interface_pkg "author_service/interface"
+	author_grpc "author_service/interface/grpc/genproto/author"
+	grpc_interceptor "author_service/interface/grpc/interceptor"
+	ucase "author_service/usecase"
+	error_utils "author_service/utils/error"
+	"author_service/utils/jwt/jwttest"
+	"context"
+	"net"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeAuthorUcase serves the authors of byUserUUID, calling any method not
+// listed here panics.
+type fakeAuthorUcase struct {
+	ucase.IAuthorUcase
+	byUserUUID map[string]dto.GetAuthorByUserUUIDRespData
+	created    *dto.CreateNewAuthorReq
+}
+
+func (u *fakeAuthorUcase) CreateNewAuthor(ctx context.Context, payload dto.CreateNewAuthorReq) (*dto.CreateNewAuthorRespData, error) {
+	u.created = &payload
+	return &dto.CreateNewAuthorRespData{
+		UUID:      uuid.New(),
+		UserUUID:  uuid.MustParse(*payload.UserUUID),
+		FirstName: payload.FirstName,
+		LastName:  payload.LastName,
+		Bio:       payload.Bio,
+	}, nil
+}
+
+func (u *fakeAuthorUcase) GetAuthorByUserUUID(ctx context.Context, userUUID string) (*dto.GetAuthorByUserUUIDRespData, error) {
+	author, ok := u.byUserUUID[userUUID]
+	if !ok {
+		return nil, &error_utils.CustomErr{HttpCode: 404, GrpcCode: codes.NotFound, Message: "author not found"}
+	}
+	return &author, nil
+}
+
+func (u *fakeAuthorUcase) GetAuthorByUUID(ctx context.Context, authorUUID string) (*dto.GetAuthorByUUIDRespData, error) {
+	for _, author := range u.byUserUUID {
+		if author.UUID.String() == authorUUID {
+			resp := dto.GetAuthorByUUIDRespData(author)
+			return &resp, nil
+		}
+	}
+	return nil, &error_utils.CustomErr{HttpCode: 404, GrpcCode: codes.NotFound, Message: "author not found"}
+}
+
+func (u *fakeAuthorUcase) PurgeAuthorByUserUUID(ctx context.Context, userUUID string) (*dto.PurgeAuthorByUserUUIDRespData, error) {
+	author, ok := u.byUserUUID[userUUID]
+	if !ok {
+		return nil, &error_utils.CustomErr{HttpCode: 404, GrpcCode: codes.NotFound, Message: "author not found"}
+	}
+	delete(u.byUserUUID, userUUID)
+	return &dto.PurgeAuthorByUserUUIDRespData{UUID: author.UUID}, nil
+}
+
+// newTestConn serves authorUcase on an in-memory listener. calls are signed
+// with a service token of auth_service unless anonymous is set.
+func newTestConn(t *testing.T, authorUcase ucase.IAuthorUcase, anonymous bool) *grpc.ClientConn {
+	signer := jwttest.NewSigner(t)
+
+	lis := bufconn.Listen(1024 * 1024)
+	grpcServer := NewServer(interface_pkg.CommonDependency{
+		AuthorUcase:  authorUcase,
+		KeySet:       signer.KeySet,
+		ClaimsConfig: jwttest.ClaimsConfig,
+	})
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+
+	var tokenSource *grpc_interceptor.ServiceTokenSource
+	if !anonymous {
+		tokenSource = grpc_interceptor.NewServiceTokenSource(signer.ServiceToken("auth_service"))
+	}
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(grpc_interceptor.IdentityUnaryClientInterceptor(tokenSource)),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial bufnet: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func newTestClient(t *testing.T, authorUcase ucase.IAuthorUcase, anonymous bool) author_grpc.AuthorServiceClient {
+	return author_grpc.NewAuthorServiceClient(newTestConn(t, authorUcase, anonymous))
+}
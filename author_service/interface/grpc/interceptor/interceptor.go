@@ -1 +1,32 @@
 package grpc_interceptor
+
+import (
+	"context"
+
+	"github.com/op/go-logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var logger = logging.MustGetLogger("main")
+
+// RecoveryUnaryInterceptor turns a panic in a handler into an internal error
+// instead of taking the whole server down.
+func RecoveryUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Errorf("panic in %s: %v", info.FullMethod, r)
+				err = status.Errorf(codes.Internal, "internal server error")
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
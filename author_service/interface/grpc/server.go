@@ -2,9 +2,13 @@ package grpc
 
 import (
 	"author_service/config"
+	"author_service/domain/dto"
 	interface_pkg "author_service/interface"
 	author_grpc "author_service/interface/grpc/genproto/author"
 	"author_service/interface/grpc/handler"
+	grpc_interceptor "author_service/interface/grpc/interceptor"
+	jwt_util "author_service/utils/jwt"
+	"context"
 	"fmt"
 	"log"
 	"net"
@@ -15,6 +19,40 @@ import (
 
 var logger = logging.MustGetLogger("main")
 
+// NewServer creates the grpc server with the service handler registered.
+// every rpc requires a service credential or a forwarded access token.
+func NewServer(commonDependencies interface_pkg.CommonDependency, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts, grpc.ChainUnaryInterceptor(
+		grpc_interceptor.RecoveryUnaryInterceptor(),
+		grpc_interceptor.AuthUnaryInterceptor(serverAuthConfig(commonDependencies)),
+	))
+	grpcServer := grpc.NewServer(opts...)
+
+	// register service handler
+	authorServiceHandler := handler.NewAuthorServiceHandler(commonDependencies.AuthorUcase)
+	author_grpc.RegisterAuthorServiceServer(grpcServer, authorServiceHandler)
+
+	return grpcServer
+}
+
+// serverAuthConfig verifies service tokens and access tokens locally with the
+// key set of auth_service.
+func serverAuthConfig(commonDependencies interface_pkg.CommonDependency) grpc_interceptor.ServerAuthConfig {
+	return grpc_interceptor.ServerAuthConfig{
+		VerifyServiceToken: func(ctx context.Context, token string) (string, error) {
+			return jwt_util.ValidateServiceToken(token, commonDependencies.KeySet, commonDependencies.ClaimsConfig.Issuer)
+		},
+		VerifyUserToken: func(ctx context.Context, token string) (*dto.CurrentUser, error) {
+			return jwt_util.ValidateJWT(token, commonDependencies.KeySet, commonDependencies.ClaimsConfig)
+		},
+		// called by auth_service only
+		ServiceOnlyMethods: []string{
+			author_grpc.AuthorService_CreateAuthor_FullMethodName,
+			author_grpc.AuthorService_PurgeAuthorByUserUUID_FullMethodName,
+		},
+	}
+}
+
 func SetupServer(commonDependencies interface_pkg.CommonDependency) {
 	// setup listener
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%v", config.Envs.GRPC_PORT))
@@ -23,12 +61,7 @@ func SetupServer(commonDependencies interface_pkg.CommonDependency) {
 	}
 
 	// new grpc server
-	grpcServer := grpc.NewServer()
-
-	// register service handler
-	// logger.Debugf("ucase: %v", commonDependencies.AuthorUcase)
-	authServiceHandler := handler.NewAuthorServiceHandler(commonDependencies.AuthorUcase)
-	author_grpc.RegisterAuthorServiceServer(grpcServer, authServiceHandler)
+	grpcServer := NewServer(commonDependencies, config.NewGrpcServerOptions()...)
 
 	// Start the server
 	fmt.Printf("Starting gRPC server on port :%v...", config.Envs.GRPC_PORT)
@@ -0,0 +1,137 @@
+package grpc
+
+import (
+	"author_service/domain/dto"
+	author_grpc "author_service/interface/grpc/genproto/author"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// TestServer_ServesEveryRPC calls every rpc declared in author.proto with a
+// service token. the fake ucase implements few of them, so calls that reach a
+// handler may fail with Internal, but never with Unimplemented.
+func TestServer_ServesEveryRPC(t *testing.T) {
+	conn := newTestConn(t, &fakeAuthorUcase{}, false)
+
+	services := author_grpc.File_author_proto.Services()
+	if services.Len() == 0 {
+		t.Fatal("no services declared in author.proto")
+	}
+	for i := 0; i < services.Len(); i++ {
+		service := services.Get(i)
+		methods := service.Methods()
+		for j := 0; j < methods.Len(); j++ {
+			method := methods.Get(j)
+			fullMethod := fmt.Sprintf("/%s/%s", service.FullName(), method.Name())
+			t.Run(fullMethod, func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+
+				req := dynamicpb.NewMessage(method.Input())
+				resp := dynamicpb.NewMessage(method.Output())
+				err := conn.Invoke(ctx, fullMethod, req, resp)
+				if status.Code(err) == codes.Unimplemented {
+					t.Errorf("%s is not served: %v", fullMethod, err)
+				}
+				if status.Code(err) == codes.Unauthenticated {
+					t.Errorf("%s rejected the service token: %v", fullMethod, err)
+				}
+			})
+		}
+	}
+}
+
+// TestServer_CreateAuthor checks the request reaches the ucase with the saga
+// idempotency key and the created author is returned.
+func TestServer_CreateAuthor(t *testing.T) {
+	authorUcase := &fakeAuthorUcase{}
+	client := newTestClient(t, authorUcase, false)
+
+	userUUID := uuid.New().String()
+	resp, err := client.CreateAuthor(context.Background(), &author_grpc.CreateAuthorReq{
+		UserUuid:       userUUID,
+		FirstName:      "jane",
+		LastName:       "doe",
+		Bio:            "writes books",
+		IdempotencyKey: "register-" + userUUID,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if resp.UserUuid != userUUID || resp.FirstName != "jane" || resp.LastName != "doe" || resp.Bio != "writes books" || resp.BirthDate != "" {
+		t.Errorf("unexpected response: %v", resp)
+	}
+	if authorUcase.created == nil || authorUcase.created.IdempotencyKey != "register-"+userUUID {
+		t.Errorf("expected the idempotency key to reach the ucase, got %+v", authorUcase.created)
+	}
+
+	_, err = client.CreateAuthor(context.Background(), &author_grpc.CreateAuthorReq{FirstName: "jane"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument without user uuid, got %v", err)
+	}
+}
+
+func TestServer_GetAndPurgeAuthor(t *testing.T) {
+	author := dto.GetAuthorByUserUUIDRespData{
+		UUID:      uuid.New(),
+		UserUUID:  uuid.New(),
+		FirstName: "jane",
+		LastName:  "doe",
+	}
+	authorUcase := &fakeAuthorUcase{byUserUUID: map[string]dto.GetAuthorByUserUUIDRespData{
+		author.UserUUID.String(): author,
+	}}
+	client := newTestClient(t, authorUcase, false)
+	ctx := context.Background()
+
+	resp, err := client.GetAuthorByUserUUID(ctx, &author_grpc.GetAuthorByUserUUIDReq{UserUuid: author.UserUUID.String()})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp.Uuid != author.UUID.String() || resp.FirstName != "jane" || resp.LastName != "doe" {
+		t.Errorf("unexpected response: %v", resp)
+	}
+
+	byUUIDResp, err := client.GetAuthorByUUID(ctx, &author_grpc.GetAuthorByUUIDReq{Uuid: author.UUID.String()})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if byUUIDResp.UserUuid != author.UserUUID.String() || byUUIDResp.FirstName != "jane" || byUUIDResp.Bio != "" {
+		t.Errorf("unexpected response: %v", byUUIDResp)
+	}
+
+	purgeResp, err := client.PurgeAuthorByUserUUID(ctx, &author_grpc.PurgeAuthorByUserUUIDReq{UserUuid: author.UserUUID.String()})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if purgeResp.Uuid != author.UUID.String() {
+		t.Errorf("expected purged author %s, got %s", author.UUID, purgeResp.Uuid)
+	}
+
+	// the ucase error code is kept
+	_, err = client.GetAuthorByUserUUID(ctx, &author_grpc.GetAuthorByUserUUIDReq{UserUuid: author.UserUUID.String()})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound after the purge, got %v", err)
+	}
+	_, err = client.GetAuthorByUUID(ctx, &author_grpc.GetAuthorByUUIDReq{Uuid: author.UUID.String()})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound after the purge, got %v", err)
+	}
+}
+
+func TestServer_RequiresCredentials(t *testing.T) {
+	client := newTestClient(t, &fakeAuthorUcase{}, true)
+
+	_, err := client.GetAuthorByUserUUID(context.Background(), &author_grpc.GetAuthorByUserUUIDReq{UserUuid: uuid.New().String()})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated, got %v", err)
+	}
+}
@@ -37,6 +37,7 @@ func NewAuthorHandler(
 // @Router /authors [post]
 // @Tags Authors
 // @Param payload body dto.CreateNewAuthorReq true "payload"
+// @Param Idempotency-Key header string false "retries with the same key return the same author"
 // @Success 200 {object} dto.BaseJSONResp{data=dto.CreateNewAuthorRespData}
 // @Security BearerAuth
 func (h *AuthorHandler) CreateNewAuthor(ctx *gin.Context) {
@@ -49,6 +50,14 @@ func (h *AuthorHandler) CreateNewAuthor(ctx *gin.Context) {
 		return
 	}
 
+	payload.IdempotencyKey = ctx.GetHeader("Idempotency-Key")
+	if len(payload.IdempotencyKey) > 100 {
+		h.respWriter.HTTPJson(
+			ctx, 400, "invalid request", "Idempotency-Key is longer than 100 characters", nil,
+		)
+		return
+	}
+
 	resp, err := h.authorUcase.CreateNewAuthor(ctx, payload)
 	if err != nil {
 		h.respWriter.HTTPCustomErr(
@@ -125,12 +134,22 @@ func (h *AuthorHandler) EditAuthor(ctx *gin.Context) {
 // @Summary Delete author
 // @Router /authors/{author_uuid} [delete]
 // @Tags Authors
+// @Param query query dto.DeleteAuthorReq false "queries"
 // @Success 200 {object} dto.BaseJSONResp{data=dto.DeleteAuthorRespData}
 // @Security BearerAuth
 func (h *AuthorHandler) DeleteAuthor(ctx *gin.Context) {
 	authorUUID := ctx.Param("author_uuid")
 
-	resp, err := h.authorUcase.DeleteAuthor(ctx, authorUUID)
+	var query dto.DeleteAuthorReq
+	err := ctx.ShouldBindQuery(&query)
+	if err != nil {
+		h.respWriter.HTTPJson(
+			ctx, 400, "invalid request", err.Error(), nil,
+		)
+		return
+	}
+
+	resp, err := h.authorUcase.DeleteAuthor(ctx, authorUUID, query)
 	if err != nil {
 		h.respWriter.HTTPCustomErr(
 			ctx, err,
@@ -20,7 +20,14 @@ import (
 var logger = logging.MustGetLogger("main")
 
 func SetupServer(commonDependencies interface_pkg.CommonDependency) {
+	router := NewRouter(commonDependencies)
+	router.Run(fmt.Sprintf("%s:%d", config.Envs.HOST, config.Envs.PORT))
+}
+
+// NewRouter registers every route on a new gin engine without running it.
+func NewRouter(commonDependencies interface_pkg.CommonDependency) *gin.Engine {
 	router := gin.Default()
+	router.Use(rest_middleware.RequestIDMiddleware())
 
 	respWriter := http_response.NewHttpResponseWriter()
 
@@ -31,8 +38,7 @@ func SetupServer(commonDependencies interface_pkg.CommonDependency) {
 	)
 
 	// middlewares
-	authMiddleware := rest_middleware.AuthMiddleware(respWriter)
-	authMiddlewareAdminOnly := rest_middleware.AuthAdminOnlyMiddleware(respWriter)
+	authMiddleware := rest_middleware.AuthMiddleware(respWriter, commonDependencies.KeySet, commonDependencies.ClaimsConfig, commonDependencies.APIKeyCache)
 
 	// register routes
 	router.GET("/ping", func(c *gin.Context) {
@@ -54,13 +60,9 @@ func SetupServer(commonDependencies interface_pkg.CommonDependency) {
 			authorRouter.GET("/:author_uuid", authorHandler.GetAuthorDetail)
 			authorRouter.GET("", authorHandler.GetList)
 
-			// admin only
-			authorRouterAdminOnly := authorRouter.Group("").Use(authMiddlewareAdminOnly)
-			{
-				authorRouterAdminOnly.POST("", authorHandler.CreateNewAuthor).Use(authMiddlewareAdminOnly)
-				authorRouterAdminOnly.PATCH("/:author_uuid", authorHandler.EditAuthor).Use(authMiddlewareAdminOnly)
-				authorRouterAdminOnly.DELETE("/:author_uuid", authorHandler.DeleteAuthor).Use(authMiddlewareAdminOnly)
-			}
+			authorRouter.POST("", rest_middleware.RequirePermission(respWriter, dto.PermissionAuthorCreate), authorHandler.CreateNewAuthor)
+			authorRouter.PATCH("/:author_uuid", rest_middleware.RequirePermission(respWriter, dto.PermissionAuthorUpdate), authorHandler.EditAuthor)
+			authorRouter.DELETE("/:author_uuid", rest_middleware.RequirePermission(respWriter, dto.PermissionAuthorDelete), authorHandler.DeleteAuthor)
 		}
 	}
 
@@ -70,5 +72,5 @@ func SetupServer(commonDependencies interface_pkg.CommonDependency) {
 		ctx.Redirect(302, "/swagger/index.html")
 	})
 
-	router.Run(fmt.Sprintf("%s:%d", config.Envs.HOST, config.Envs.PORT))
+	return router
 }
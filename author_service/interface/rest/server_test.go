@@ -0,0 +1,127 @@
+package rest
+
+import (
+	"author_service/domain/dto"
+	interface_pkg "author_service/interface"
+	ucase "author_service/usecase"
+	"author_service/utils/jwt/jwttest"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// fakeAuthorUcase records the method reached through the router, calling
+// any method not listed here panics.
+type fakeAuthorUcase struct {
+	ucase.IAuthorUcase
+	called string
+}
+
+func (u *fakeAuthorUcase) CreateNewAuthor(ctx context.Context, payload dto.CreateNewAuthorReq) (*dto.CreateNewAuthorRespData, error) {
+	u.called = "CreateNewAuthor"
+	return &dto.CreateNewAuthorRespData{}, nil
+}
+
+func (u *fakeAuthorUcase) EditAuthor(ctx *gin.Context, authorUUID string, payload dto.EditAuthorReq) (*dto.EditAuthorRespData, error) {
+	u.called = "EditAuthor"
+	return &dto.EditAuthorRespData{}, nil
+}
+
+func (u *fakeAuthorUcase) DeleteAuthor(ctx *gin.Context, authorUUID string, payload dto.DeleteAuthorReq) (*dto.DeleteAuthorRespData, error) {
+	u.called = "DeleteAuthor"
+	return &dto.DeleteAuthorRespData{}, nil
+}
+
+// TestRouter_Permissions checks each admin route of the service asks for its
+// own permission. token validation itself is covered by book_service.
+func TestRouter_Permissions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	signer := jwttest.NewSigner(t)
+	signToken := func(permissions ...string) string {
+		return signer.Sign(t, jwttest.UserClaims("admin", permissions...))
+	}
+
+	authorUUID := uuid.New().String()
+	routes := []struct {
+		method     string
+		path       string
+		body       string
+		permission string
+		other      string // a permission that does not grant the route
+		wantCalled string
+	}{
+		{http.MethodPost, "/authors", `{"email":"author@gmail.com","username":"author","password":"password","first_name":"author"}`, dto.PermissionAuthorCreate, dto.PermissionAuthorUpdate, "CreateNewAuthor"},
+		{http.MethodPatch, "/authors/" + authorUUID, `{"email":"author@gmail.com","first_name":"author"}`, dto.PermissionAuthorUpdate, dto.PermissionAuthorDelete, "EditAuthor"},
+		{http.MethodDelete, "/authors/" + authorUUID, "", dto.PermissionAuthorDelete, dto.PermissionAuthorCreate, "DeleteAuthor"},
+	}
+
+	for _, route := range routes {
+		testCases := []struct {
+			name       string
+			token      string
+			wantStatus int
+		}{
+			{"without permission", signToken(), 403},
+			{"with another permission", signToken(route.other), 403},
+			{"with permission", signToken(route.permission), 200},
+		}
+
+		for _, testCase := range testCases {
+			t.Run(route.method+" "+route.path+" "+testCase.name, func(t *testing.T) {
+				authorUcase := &fakeAuthorUcase{}
+				router := NewRouter(interface_pkg.CommonDependency{
+					AuthorUcase:  authorUcase,
+					KeySet:       signer.KeySet,
+					ClaimsConfig: jwttest.ClaimsConfig,
+				})
+
+				req := httptest.NewRequest(route.method, route.path, bytes.NewReader([]byte(route.body)))
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Authorization", "Bearer "+testCase.token)
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+
+				if rec.Code != testCase.wantStatus {
+					t.Errorf("expected status %d, got %d: %s", testCase.wantStatus, rec.Code, rec.Body.String())
+				}
+				wantCalled := ""
+				if testCase.wantStatus == 200 {
+					wantCalled = route.wantCalled
+				}
+				if authorUcase.called != wantCalled {
+					t.Errorf("expected ucase call %q, got %q", wantCalled, authorUcase.called)
+				}
+			})
+		}
+	}
+}
+
+func TestRouter_DeleteAuthor_InvalidReassignTo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	signer := jwttest.NewSigner(t)
+	authorUcase := &fakeAuthorUcase{}
+	router := NewRouter(interface_pkg.CommonDependency{
+		AuthorUcase:  authorUcase,
+		KeySet:       signer.KeySet,
+		ClaimsConfig: jwttest.ClaimsConfig,
+	})
+
+	req := httptest.NewRequest(http.MethodDelete, "/authors/"+uuid.New().String()+"?reassign_to=not-a-uuid", nil)
+	req.Header.Set("Authorization", "Bearer "+signer.Sign(t, jwttest.UserClaims("admin", dto.PermissionAuthorDelete)))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != 400 {
+		t.Errorf("expected status 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if authorUcase.called != "" {
+		t.Errorf("expected no ucase call, got %q", authorUcase.called)
+	}
+}
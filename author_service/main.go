@@ -2,18 +2,23 @@ package main
 
 import (
 	"author_service/config"
-	"author_service/domain/model"
 	interface_pkg "author_service/interface"
 	"author_service/interface/grpc"
 	"author_service/interface/rest"
+	"author_service/migrations"
 	"author_service/repository"
 	ucase "author_service/usecase"
 	"author_service/utils/helper"
+	jwt_util "author_service/utils/jwt"
+	"author_service/utils/migrator"
+	"context"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/op/go-logging"
+	"gorm.io/gorm"
 )
 
 func init() {
@@ -30,34 +35,52 @@ var logger = logging.MustGetLogger("main")
 // @description JWT Authorization header using the Bearer scheme (add 'Bearer ' prefix).
 func main() {
 	logger.Debugf("Envs: %v", helper.PrettyJson(config.Envs))
-	gormDB := config.NewPostgresqlDB()
-	authGrpcServiceClient := config.NewAuthGrpcServiceClient()
-	bookGrpcServiceClient := config.NewBookGrpcServiceClient()
-
-	// migrations
-	err := gormDB.AutoMigrate(
-		&model.Author{},
-	)
-	if err != nil {
-		logger.Fatalf("failed to migrate database: %v", err)
+	// the database itself is only created when migrating up
+	for _, arg := range os.Args[1:] {
+		if arg == "--migrate=up" {
+			err := config.EnsurePostgresqlDB()
+			if err != nil {
+				logger.Fatalf("failed to ensure database: %v", err)
+			}
+		}
 	}
+	gormDB := config.NewPostgresqlDB()
+	serviceTokenSource := config.NewServiceTokenSource()
+	authGrpcServiceClient := config.NewAuthGrpcServiceClient(serviceTokenSource)
+	bookGrpcServiceClient := config.NewBookGrpcServiceClient(serviceTokenSource)
 
 	// repositories
 	authorRepo := repository.NewAuthorRepo(gormDB)
+	sagaRepo := repository.NewSagaRepo(gormDB)
+	outboxRepo := repository.NewOutboxRepo(gormDB)
+
+	// access token verification
+	keySet := config.NewJWKS(authGrpcServiceClient)
+	apiKeyCache := config.NewAPIKeyCache(authGrpcServiceClient)
+	claimsConfig := jwt_util.ClaimsConfig{
+		Issuer:   config.Envs.JWT_ISSUER,
+		Audience: config.Envs.JWT_AUDIENCE,
+	}
 
 	// ucases
-	authorUcase := ucase.NewAuthorUcase(authorRepo, authGrpcServiceClient, bookGrpcServiceClient)
+	authorUcase := ucase.NewAuthorUcase(authorRepo, sagaRepo, authGrpcServiceClient, bookGrpcServiceClient)
+	reconcileUcase := ucase.NewReconcileUcase(authorRepo, sagaRepo, authGrpcServiceClient)
 	dependencies := interface_pkg.CommonDependency{
 		AuthorUcase: authorUcase,
+
+		KeySet:       keySet,
+		APIKeyCache:  apiKeyCache,
+		ClaimsConfig: claimsConfig,
 	}
 
 	args := os.Args
 	if len(args) == 1 { // run as a rest server
+		startOutboxRelay(outboxRepo)
 		logger.Info("starting rest server...")
 		rest.SetupServer(dependencies)
 	} else if len(args) > 1 {
 		validArgVariables := []string{"server"}
-		validPreRunArgVariables := []string{}
+		validPreRunArgVariables := []string{"migrate", "reconcile"}
 
 		// validate args
 		variables := validArgVariables
@@ -107,15 +130,84 @@ func main() {
 
 				switch value {
 				case "rest":
+					startOutboxRelay(outboxRepo)
 					logger.Info("starting rest server...")
 					rest.SetupServer(dependencies)
 				case "grpc":
+					startOutboxRelay(outboxRepo)
 					logger.Info("starting grpc server...")
 					grpc.SetupServer(dependencies)
 				default:
 					logger.Fatalf("invalid argument: %s", arg)
 				}
+			} else if strings.Contains(arg, fmt.Sprintf("--%s=", "reconcile")) {
+				value := strings.Split(arg, "=")[1]
+
+				switch value {
+				case "authors":
+					data, err := reconcileUcase.ReconcileAuthors(context.Background())
+					if err != nil {
+						logger.Fatalf("failed to reconcile authors: %v", err)
+					}
+					for _, item := range data.Items {
+						logger.Infof("%s %s: %s, %s", item.Kind, item.UUID, item.Action, item.Detail)
+					}
+					logger.Infof("authors reconciled, %d items", len(data.Items))
+				default:
+					logger.Fatalf("invalid argument: %s", arg)
+				}
+			} else if strings.Contains(arg, fmt.Sprintf("--%s=", "migrate")) {
+				value := strings.Split(arg, "=")[1]
+				runMigration(gormDB, value)
+			}
+		}
+	}
+}
+
+// startOutboxRelay publishes the outbox events in the background while the
+// server runs. the relays of several instances take turns through a lock.
+func startOutboxRelay(outboxRepo repository.IOutboxRepo) {
+	go func() {
+		// blocks until the broker is reachable
+		broker := config.NewEventBroker(context.Background())
+		if broker == nil {
+			logger.Warning("no event broker, domain events stay in the outbox")
+			return
+		}
+		outboxUcase := ucase.NewOutboxUcase(outboxRepo, broker)
+		outboxUcase.RunRelay(context.Background())
+	}()
+}
+
+// runMigration applies, rolls back or lists the versioned sql migrations in
+// ./migrations.
+func runMigration(gormDB *gorm.DB, value string) {
+	switch value {
+	case "up":
+		logger.Info("applying migrations...")
+		err := migrator.Up(gormDB, migrations.FS)
+		if err != nil {
+			logger.Fatalf("failed to apply migrations: %v", err)
+		}
+	case "down":
+		logger.Info("rolling back latest migration...")
+		err := migrator.Down(gormDB, migrations.FS)
+		if err != nil {
+			logger.Fatalf("failed to roll back migration: %v", err)
+		}
+	case "status":
+		statuses, err := migrator.Status(gormDB, migrations.FS)
+		if err != nil {
+			logger.Fatalf("failed to get migration status: %v", err)
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
 			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, appliedAt)
 		}
+	default:
+		logger.Fatalf("invalid migrate argument: %s", value)
 	}
 }
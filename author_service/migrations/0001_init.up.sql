@@ -0,0 +1,15 @@
+-- baseline schema, previously created by gorm AutoMigrate. IF NOT EXISTS keeps
+-- it safe to apply on databases that were already auto migrated.
+CREATE TABLE IF NOT EXISTS authors (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    uuid uuid NOT NULL CONSTRAINT uni_authors_uuid UNIQUE,
+    user_uuid uuid NOT NULL,
+    first_name text NOT NULL,
+    last_name text,
+    birth_date text,
+    bio text
+);
+CREATE INDEX IF NOT EXISTS idx_authors_deleted_at ON authors (deleted_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS sagas;
+DROP INDEX IF EXISTS idx_authors_idempotency_key;
+ALTER TABLE authors DROP COLUMN IF EXISTS idempotency_key;
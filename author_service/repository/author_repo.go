@@ -3,9 +3,11 @@ package repository
 import (
 	"author_service/domain/dto"
 	"author_service/domain/model"
+	event_util "author_service/utils/event"
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -18,8 +20,18 @@ type IAuthorRepo interface {
 	Create(author *model.Author) error
 	GetByUUID(uuid string) (*model.Author, error)
 	GetByUserUUID(uuid string) (*model.Author, error)
+	GetByIdempotencyKey(key string) (*model.Author, error)
+	// GetListWithIdempotencyKey returns the authors created by a saga before
+	// createdBefore whose saga is not known to be complete yet.
+	GetListWithIdempotencyKey(createdBefore time.Time) ([]model.Author, error)
+	// ClearIdempotencyKey marks the saga that created the author as complete,
+	// the author is no longer reconciled.
+	ClearIdempotencyKey(uuid string) error
 	Update(author *model.Author) error
-	Delete(id string) error
+	Delete(uuid string) error
+	// PurgeByUserUUID removes the author of the user for good, to undo a
+	// failed registration.
+	PurgeByUserUUID(userUUID string) error
 	GetList(
 		ctx context.Context,
 		params dto.AuthorRepo_GetListParams,
@@ -37,7 +49,13 @@ func NewAuthorRepo(db *gorm.DB) IAuthorRepo {
 }
 
 func (repo *AuthorRepo) Create(author *model.Author) error {
-	err := repo.db.Create(author).Error
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(author).Error
+		if err != nil {
+			return err
+		}
+		return createOutboxEvent(tx, event_util.TypeAuthorCreated, author.UUID, author.EventPayload())
+	})
 	if err != nil {
 		return errors.New("failed to create author")
 	}
@@ -66,13 +84,63 @@ func (repo *AuthorRepo) GetByUserUUID(uuid string) (*model.Author, error) {
 	return &author, nil
 }
 
+func (repo *AuthorRepo) GetByIdempotencyKey(key string) (*model.Author, error) {
+	var author model.Author
+	if err := repo.db.First(&author, "idempotency_key = ?", key).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("not found")
+		}
+		return nil, errors.New("failed to get")
+	}
+	return &author, nil
+}
+
+func (repo *AuthorRepo) GetListWithIdempotencyKey(createdBefore time.Time) ([]model.Author, error) {
+	var authors []model.Author
+	err := repo.db.
+		Where("idempotency_key IS NOT NULL AND created_at < ?", createdBefore).
+		Order("id asc").
+		Find(&authors).Error
+	if err != nil {
+		return nil, errors.New("failed to get")
+	}
+	return authors, nil
+}
+
+func (repo *AuthorRepo) ClearIdempotencyKey(uuid string) error {
+	err := repo.db.Model(&model.Author{}).
+		Where("uuid = ?", uuid).
+		UpdateColumn("idempotency_key", nil).Error
+	if err != nil {
+		return errors.New("failed to update")
+	}
+	return nil
+}
+
 func (repo *AuthorRepo) Update(author *model.Author) error {
-	err := repo.db.Save(author).Error
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Save(author).Error
+		if err != nil {
+			return err
+		}
+		return createOutboxEvent(tx, event_util.TypeAuthorUpdated, author.UUID, author.EventPayload())
+	})
 	return err
 }
 
-func (repo *AuthorRepo) Delete(id string) error {
-	err := repo.db.Delete(&model.Author{}, "id = ?", id).Error
+func (repo *AuthorRepo) Delete(uuid string) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var author model.Author
+		err := tx.First(&author, "uuid = ?", uuid).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Delete(&author).Error
+		if err != nil {
+			return err
+		}
+		return createOutboxEvent(tx, event_util.TypeAuthorDeleted, author.UUID, author.EventPayload())
+	})
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return errors.New("not found")
@@ -82,6 +150,42 @@ func (repo *AuthorRepo) Delete(id string) error {
 	return err
 }
 
+func (repo *AuthorRepo) PurgeByUserUUID(userUUID string) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var authors []model.Author
+		err := tx.Unscoped().Where("user_uuid = ?", userUUID).Find(&authors).Error
+		if err != nil {
+			return err
+		}
+		if len(authors) == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		err = tx.Unscoped().Delete(&model.Author{}, "user_uuid = ?", userUUID).Error
+		if err != nil {
+			return err
+		}
+		for _, author := range authors {
+			// a soft deleted author already had its event
+			if author.DeletedAt.Valid {
+				continue
+			}
+			err = createOutboxEvent(tx, event_util.TypeAuthorDeleted, author.UUID, author.EventPayload())
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.New("not found")
+		}
+		return errors.New("failed to delete")
+	}
+	return nil
+}
+
 func (repo *AuthorRepo) GetList(
 	ctx context.Context,
 	params dto.AuthorRepo_GetListParams,
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"author_service/domain/model"
+	saga_util "author_service/utils/saga"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SagaRepo struct {
+	db *gorm.DB
+}
+
+type ISagaRepo interface {
+	Create(saga *model.Saga) error
+	Update(saga *model.Saga) error
+	// GetStaleList returns the sagas of the name left unfinished since before
+	// updatedBefore, they were interrupted or their compensation failed.
+	GetStaleList(name string, updatedBefore time.Time) ([]model.Saga, error)
+}
+
+func NewSagaRepo(db *gorm.DB) ISagaRepo {
+	return &SagaRepo{db: db}
+}
+
+func (repo *SagaRepo) Create(saga *model.Saga) error {
+	err := repo.db.Create(saga).Error
+	if err != nil {
+		return errors.New("failed to create: " + err.Error())
+	}
+	return nil
+}
+
+func (repo *SagaRepo) Update(saga *model.Saga) error {
+	err := repo.db.Save(saga).Error
+	if err != nil {
+		return errors.New("failed to update: " + err.Error())
+	}
+	return nil
+}
+
+func (repo *SagaRepo) GetStaleList(name string, updatedBefore time.Time) ([]model.Saga, error) {
+	var sagas []model.Saga
+	err := repo.db.
+		Where("name = ? AND status IN ? AND updated_at < ?", name, []string{
+			saga_util.StatusStarted,
+			saga_util.StatusCompensating,
+			saga_util.StatusFailed,
+		}, updatedBefore).
+		Order("id asc").
+		Find(&sagas).Error
+	if err != nil {
+		return nil, errors.New("failed to get: " + err.Error())
+	}
+	return sagas, nil
+}
@@ -1,13 +1,17 @@
 package ucase
 
 import (
+	"author_service/config"
 	"author_service/domain/dto"
 	"author_service/domain/model"
 	auth_pb "author_service/interface/grpc/genproto/auth"
 	book_pb "author_service/interface/grpc/genproto/book"
 	"author_service/repository"
 	error_utils "author_service/utils/error"
+	saga_util "author_service/utils/saga"
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -20,6 +24,7 @@ var logger = logging.MustGetLogger("main")
 
 type AuthorUcase struct {
 	authorRepo            repository.IAuthorRepo
+	sagaRepo              repository.ISagaRepo
 	authGrpcServiceClient auth_pb.AuthServiceClient
 	bookGrpcServiceClient book_pb.BookServiceClient
 }
@@ -34,7 +39,11 @@ type IAuthorUcase interface {
 		authorUUID string,
 		payload dto.EditAuthorReq,
 	) (*dto.EditAuthorRespData, error) // admin only or owner
-	DeleteAuthor(ctx *gin.Context, authorUUID string) (*dto.DeleteAuthorRespData, error) // admin only
+	DeleteAuthor(
+		ctx *gin.Context,
+		authorUUID string,
+		payload dto.DeleteAuthorReq,
+	) (*dto.DeleteAuthorRespData, error) // admin only
 	GetAuthorDetail(ctx *gin.Context, authorUUID string) (*dto.GetAuthorDetailRespData, error)
 	GetList(
 		ctx *gin.Context, query dto.GetAuthorListReq,
@@ -42,15 +51,23 @@ type IAuthorUcase interface {
 	GetAuthorByUserUUID(
 		ctx context.Context, userUUID string,
 	) (*dto.GetAuthorByUserUUIDRespData, error)
+	GetAuthorByUUID(
+		ctx context.Context, authorUUID string,
+	) (*dto.GetAuthorByUUIDRespData, error)
+	PurgeAuthorByUserUUID(
+		ctx context.Context, userUUID string,
+	) (*dto.PurgeAuthorByUserUUIDRespData, error) // undoes a failed registration of auth service
 }
 
 func NewAuthorUcase(
 	authorRepo repository.IAuthorRepo,
+	sagaRepo repository.ISagaRepo,
 	authGrpcServiceClient auth_pb.AuthServiceClient,
 	bookGrpcServiceClient book_pb.BookServiceClient,
 ) IAuthorUcase {
 	return &AuthorUcase{
 		authorRepo:            authorRepo,
+		sagaRepo:              sagaRepo,
 		authGrpcServiceClient: authGrpcServiceClient,
 		bookGrpcServiceClient: bookGrpcServiceClient,
 	}
@@ -61,157 +78,274 @@ func (u *AuthorUcase) CreateNewAuthor(
 	payload dto.CreateNewAuthorReq,
 ) (*dto.CreateNewAuthorRespData, error) {
 	logger.Debugf("CreateNewAuthor in")
-	var parsedUserUUID uuid.UUID
-	var userEmail string
-	var userUsername string
-	var userRole string
-	var err error
 
-	if payload.UserUUID != nil { // user uuid provided for auth service grpc call
-		logger.Debugf("payload.UserUUID: %s", *payload.UserUUID)
-		getUserResp, err := u.authGrpcServiceClient.GetUserByUUID(
-			ctx,
-			&auth_pb.GetUserByUUIDRequest{
-				Uuid: *payload.UserUUID,
-			},
-		)
-		grpcCode := status.Code(err)
-		logger.Debugf("getUserResp: %v, grpcCode: %v, err: %v", getUserResp, grpcCode, err)
-		if grpcCode != codes.OK {
-			switch grpcCode {
-			case codes.NotFound:
-				logger.Errorf("user not found: %s", err.Error())
-				return nil, &error_utils.CustomErr{
-					HttpCode: 400,
-					GrpcCode: grpcCode,
-					Message:  err.Error(),
-					Detail:   err.Error(),
-				}
-			default:
-				logger.Errorf("error getting user: %s", err.Error())
-				return nil, &error_utils.CustomErr{
-					HttpCode: 500,
-					GrpcCode: grpcCode,
-					Message:  err.Error(),
-					Detail:   err.Error(),
-				}
-			}
+	// a retried call gets the author created by the first one
+	if payload.IdempotencyKey != "" {
+		author, err := u.authorRepo.GetByIdempotencyKey(payload.IdempotencyKey)
+		if err == nil {
+			return u.replayCreateNewAuthor(ctx, payload, author)
 		}
+		if err.Error() != "not found" {
+			logger.Errorf("error getting author: %s", err.Error())
+			return nil, err
+		}
+	}
 
-		if getUserResp == nil {
-			return nil, &error_utils.CustomErr{
-				HttpCode: 500,
-				GrpcCode: grpcCode,
-				Message:  "internal server error",
-				Detail:   "get user response is nil",
-			}
+	newAuthor := &model.Author{
+		UUID:      uuid.New(),
+		FirstName: payload.FirstName,
+		LastName:  payload.LastName,
+		BirthDate: payload.BirthDate,
+		Bio:       payload.Bio,
+	}
+	if payload.IdempotencyKey != "" {
+		newAuthor.IdempotencyKey = &payload.IdempotencyKey
+	}
+
+	// validate before creating the user, so an invalid author leaves nothing
+	// behind
+	err := newAuthor.Validate()
+	if err != nil {
+		logger.Errorf("author validation error: %s", err.Error())
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			Message:  err.Error(),
+			Detail:   err.Error(),
 		}
+	}
 
-		parsedUserUUID, err = uuid.Parse(getUserResp.Uuid)
+	var user *userInfo
+	if payload.UserUUID != nil { // user uuid provided for auth service grpc call
+		logger.Debugf("payload.UserUUID: %s", *payload.UserUUID)
+		user, err = u.getUser(ctx, *payload.UserUUID)
 		if err != nil {
-			logger.Errorf("error parsing user uuid: %s", err.Error())
 			return nil, err
 		}
-		userEmail = getUserResp.Email
-		userUsername = getUserResp.Username
-		userRole = getUserResp.Role
+		newAuthor.UserUUID = user.UUID
 
+		// create
+		err = u.authorRepo.Create(newAuthor)
+		if err != nil {
+			logger.Errorf("error creating author: %s", err.Error())
+			return nil, err
+		}
 	} else { // user uuid not provided for client call
-		createUserResp, err := u.authGrpcServiceClient.CreateUser(
-			ctx,
-			&auth_pb.CreateUserReq{
-				Email:    payload.Email,
-				Username: payload.Username,
-				Password: payload.Password,
-				Role:     payload.Role,
-			},
-		)
-		grpcCode := status.Code(err)
-
-		if grpcCode != codes.OK {
-			switch grpcCode {
-			case codes.AlreadyExists:
-				logger.Errorf("user already exists: %s", err.Error())
-				return nil, &error_utils.CustomErr{
-					HttpCode: 400,
-					GrpcCode: grpcCode,
-					Message:  err.Error(),
-					Detail:   err.Error(),
+		user, err = u.createUserAndAuthor(ctx, payload, newAuthor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return newCreateNewAuthorRespData(newAuthor, user), nil
+}
+
+// createUserAndAuthor creates the user in auth_service, then the author. the
+// saga purges the user again when the author cannot be created, so the
+// username can be taken again.
+func (u *AuthorUcase) createUserAndAuthor(
+	ctx context.Context,
+	payload dto.CreateNewAuthorReq,
+	newAuthor *model.Author,
+) (*userInfo, error) {
+	saga := &model.Saga{
+		UUID: uuid.New(),
+		Name: model.SagaNameCreateAuthor,
+	}
+	idempotencyKey := payload.IdempotencyKey
+	if idempotencyKey == "" {
+		idempotencyKey = saga.UUID.String()
+	}
+
+	var user *userInfo
+	err := saga_util.Run(ctx, []saga_util.Step{
+		{
+			Name: "create_user",
+			Action: func(ctx context.Context) error {
+				var createUserResp *auth_pb.CreateUserResp
+				err := saga_util.RetryTransient(ctx, func(ctx context.Context) error {
+					var err error
+					createUserResp, err = u.authGrpcServiceClient.CreateUser(
+						ctx,
+						&auth_pb.CreateUserReq{
+							Email:          payload.Email,
+							Username:       payload.Username,
+							Password:       payload.Password,
+							Role:           payload.Role,
+							IdempotencyKey: idempotencyKey,
+						},
+					)
+					return err
+				})
+				if err != nil {
+					return err
 				}
-			default:
-				logger.Errorf("error creating user: %s", err.Error())
-				return nil, &error_utils.CustomErr{
-					HttpCode: 500,
-					GrpcCode: grpcCode,
-					Message:  err.Error(),
-					Detail:   err.Error(),
+				if createUserResp == nil {
+					return errors.New("create user resp is nil")
 				}
-			}
+
+				parsedUserUUID, err := uuid.Parse(createUserResp.Uuid)
+				if err != nil {
+					return err
+				}
+				newAuthor.UserUUID = parsedUserUUID
+				saga.ResourceUUID = &parsedUserUUID
+				user = &userInfo{
+					UUID:     parsedUserUUID,
+					Email:    createUserResp.Email,
+					Username: createUserResp.Username,
+					Role:     createUserResp.Role,
+				}
+				return nil
+			},
+			Compensate: func(ctx context.Context) error {
+				_, err := u.authGrpcServiceClient.DeleteUser(
+					ctx,
+					&auth_pb.DeleteUserReq{
+						Uuid:  newAuthor.UserUUID.String(),
+						Purge: true,
+					},
+				)
+				if status.Code(err) == codes.NotFound {
+					return nil
+				}
+				return err
+			},
+		},
+		{
+			Name: "create_author",
+			Action: func(ctx context.Context) error {
+				return u.authorRepo.Create(newAuthor)
+			},
+		},
+	}, sagaRecorder(u.sagaRepo, saga))
+	if err != nil {
+		sagaErr, ok := err.(*saga_util.Error)
+		if !ok || sagaErr.Step != "create_user" {
+			logger.Errorf("error creating author: %s", err.Error())
+			return nil, err
 		}
 
-		if createUserResp == nil {
-			logger.Errorf("create user resp is nil")
+		grpcCode := status.Code(sagaErr.Err)
+		switch grpcCode {
+		case codes.AlreadyExists, codes.FailedPrecondition:
+			logger.Errorf("user already exists: %s", sagaErr.Err.Error())
+			return nil, &error_utils.CustomErr{
+				HttpCode: 400,
+				GrpcCode: grpcCode,
+				Message:  sagaErr.Err.Error(),
+				Detail:   sagaErr.Err.Error(),
+			}
+		default:
+			logger.Errorf("error creating user: %s", sagaErr.Err.Error())
 			return nil, &error_utils.CustomErr{
 				HttpCode: 500,
 				GrpcCode: grpcCode,
-				Message:  "internal server error",
-				Detail:   "create user resp is nil",
+				Message:  sagaErr.Err.Error(),
+				Detail:   sagaErr.Err.Error(),
 			}
 		}
+	}
 
-		parsedUserUUID, err = uuid.Parse(createUserResp.Uuid)
-		if err != nil {
-			logger.Errorf("error parsing user uuid: %s", err.Error())
-			return nil, err
+	return user, nil
+}
+
+// replayCreateNewAuthor answers a retry with the author of the first call. the
+// key cannot be reused for another user.
+func (u *AuthorUcase) replayCreateNewAuthor(
+	ctx context.Context,
+	payload dto.CreateNewAuthorReq,
+	author *model.Author,
+) (*dto.CreateNewAuthorRespData, error) {
+	if payload.UserUUID != nil && *payload.UserUUID != author.UserUUID.String() {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 409,
+			GrpcCode: codes.FailedPrecondition,
+			Message:  "idempotency key already used",
+			Detail:   "the key was used to create the author of another user",
 		}
+	}
 
-		userEmail = createUserResp.Email
-		userUsername = createUserResp.Username
-		userRole = createUserResp.Role
+	user, err := u.getUser(ctx, author.UserUUID.String())
+	if err != nil {
+		return nil, err
 	}
+	return newCreateNewAuthorRespData(author, user), nil
+}
 
-	newAuthor := &model.Author{
-		UUID:      uuid.New(),
-		UserUUID:  parsedUserUUID,
-		FirstName: payload.FirstName,
-		LastName:  payload.LastName,
-		BirthDate: payload.BirthDate,
-		Bio:       payload.Bio,
+// userInfo is the part of the auth_service user returned with the author.
+type userInfo struct {
+	UUID     uuid.UUID
+	Email    string
+	Username string
+	Role     string
+}
+
+func (u *AuthorUcase) getUser(ctx context.Context, userUUID string) (*userInfo, error) {
+	getUserResp, err := u.authGrpcServiceClient.GetUserByUUID(
+		ctx,
+		&auth_pb.GetUserByUUIDRequest{
+			Uuid: userUUID,
+		},
+	)
+	grpcCode := status.Code(err)
+	logger.Debugf("getUserResp: %v, grpcCode: %v, err: %v", getUserResp, grpcCode, err)
+	if grpcCode != codes.OK {
+		switch grpcCode {
+		case codes.NotFound:
+			logger.Errorf("user not found: %s", err.Error())
+			return nil, &error_utils.CustomErr{
+				HttpCode: 400,
+				GrpcCode: grpcCode,
+				Message:  err.Error(),
+				Detail:   err.Error(),
+			}
+		default:
+			logger.Errorf("error getting user: %s", err.Error())
+			return nil, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: grpcCode,
+				Message:  err.Error(),
+				Detail:   err.Error(),
+			}
+		}
 	}
 
-	// validate
-	err = newAuthor.Validate()
-	if err != nil {
-		logger.Errorf("author validation error: %s", err.Error())
+	if getUserResp == nil {
 		return nil, &error_utils.CustomErr{
-			HttpCode: 400,
-			Message:  err.Error(),
-			Detail:   err.Error(),
+			HttpCode: 500,
+			GrpcCode: grpcCode,
+			Message:  "internal server error",
+			Detail:   "get user response is nil",
 		}
 	}
 
-	// create
-	err = u.authorRepo.Create(newAuthor)
+	parsedUserUUID, err := uuid.Parse(getUserResp.Uuid)
 	if err != nil {
-		logger.Errorf("error creating author: %s", err.Error())
+		logger.Errorf("error parsing user uuid: %s", err.Error())
 		return nil, err
 	}
+	return &userInfo{
+		UUID:     parsedUserUUID,
+		Email:    getUserResp.Email,
+		Username: getUserResp.Username,
+		Role:     getUserResp.Role,
+	}, nil
+}
 
-	respData := &dto.CreateNewAuthorRespData{
-		UUID:      newAuthor.UUID,
-		CreatedAt: newAuthor.CreatedAt,
-		UpdatedAt: newAuthor.UpdatedAt,
-		UserUUID:  newAuthor.UserUUID,
-		FirstName: newAuthor.FirstName,
-		LastName:  newAuthor.LastName,
-		BirthDate: newAuthor.BirthDate,
-		Bio:       newAuthor.Bio,
-		Email:     userEmail,
-		Username:  userUsername,
-		Role:      userRole,
+func newCreateNewAuthorRespData(author *model.Author, user *userInfo) *dto.CreateNewAuthorRespData {
+	return &dto.CreateNewAuthorRespData{
+		UUID:      author.UUID,
+		CreatedAt: author.CreatedAt,
+		UpdatedAt: author.UpdatedAt,
+		UserUUID:  author.UserUUID,
+		FirstName: author.FirstName,
+		LastName:  author.LastName,
+		BirthDate: author.BirthDate,
+		Bio:       author.Bio,
+		Email:     user.Email,
+		Username:  user.Username,
+		Role:      user.Role,
 	}
-
-	return respData, nil
 }
 
 func (u *AuthorUcase) EditAuthor(
@@ -354,8 +488,15 @@ func (u *AuthorUcase) EditAuthor(
 	return respData, nil
 }
 
-func (u *AuthorUcase) DeleteAuthor(ctx *gin.Context, authorUUID string) (*dto.DeleteAuthorRespData, error) {
-
+// DeleteAuthor removes the author with its user, after reassigning or
+// deleting its books. each step is reported, a failed step stops the deletion
+// and the request can be retried, steps already done are skipped or find
+// nothing left to do.
+func (u *AuthorUcase) DeleteAuthor(
+	ctx *gin.Context,
+	authorUUID string,
+	payload dto.DeleteAuthorReq,
+) (*dto.DeleteAuthorRespData, error) {
 	author, err := u.authorRepo.GetByUUID(authorUUID)
 	if err != nil {
 		if err.Error() == "not found" {
@@ -368,52 +509,217 @@ func (u *AuthorUcase) DeleteAuthor(ctx *gin.Context, authorUUID string) (*dto.De
 		return nil, err
 	}
 
+	booksAction := payload.Books
+	if booksAction == "" {
+		booksAction = dto.DeleteAuthorBooksReassign
+	}
+	var newAuthor *model.Author
+	if booksAction == dto.DeleteAuthorBooksReassign {
+		newAuthor, err = u.getReassignTarget(author, payload.ReassignTo)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	report := newDeleteAuthorReport("check_borrows", booksAction+"_books", "delete_user", "delete_author")
+
+	// check borrows, the books step checks them again while locking the books
+	activeBorrowResp, err := u.bookGrpcServiceClient.GetActiveBorrowTotalByAuthorUUID(
+		ctx,
+		&book_pb.GetActiveBorrowTotalByAuthorUUIDReq{
+			AuthorUuid: author.UUID.String(),
+		},
+	)
+	if err != nil {
+		logger.Errorf("error getting active borrows: %v", err)
+		return nil, report.fail("check_borrows", deleteAuthorStepErr(err))
+	}
+	if activeBorrowResp.ActiveBorrowTotal > 0 {
+		return nil, report.fail("check_borrows", &error_utils.CustomErr{
+			HttpCode: 409,
+			GrpcCode: codes.FailedPrecondition,
+			Message:  "author has active borrows",
+			Detail:   fmt.Sprintf("%d books of the author are borrowed", activeBorrowResp.ActiveBorrowTotal),
+		})
+	}
+	report.set("check_borrows", dto.DeleteAuthorStepDone, "no active borrow")
+
+	// reassign or delete books
+	if booksAction == dto.DeleteAuthorBooksReassign {
+		reassignResp, err := u.bookGrpcServiceClient.ReassignBooksByAuthorUUID(
+			ctx,
+			&book_pb.ReassignBooksByAuthorUUIDReq{
+				AuthorUuid:    author.UUID.String(),
+				NewAuthorUuid: newAuthor.UUID.String(),
+			},
+		)
+		if err != nil {
+			logger.Errorf("error reassigning books: %v", err)
+			return nil, report.fail("reassign_books", deleteAuthorStepErr(err))
+		}
+		report.set("reassign_books", dto.DeleteAuthorStepDone, fmt.Sprintf(
+			"%d books reassigned to author %s", reassignResp.BookTotal, newAuthor.UUID.String(),
+		))
+	} else {
+		deleteBooksResp, err := u.bookGrpcServiceClient.DeleteBooksByAuthorUUID(
+			ctx,
+			&book_pb.DeleteBooksByAuthorUUIDReq{
+				AuthorUuid: author.UUID.String(),
+			},
+		)
+		if err != nil {
+			logger.Errorf("error deleting books: %v", err)
+			return nil, report.fail("delete_books", deleteAuthorStepErr(err))
+		}
+		report.set("delete_books", dto.DeleteAuthorStepDone, fmt.Sprintf(
+			"%d books deleted", deleteBooksResp.BookTotal,
+		))
+	}
+
 	// delete user through auth service
-	deleteUserResp, err := u.authGrpcServiceClient.DeleteUser(ctx, &auth_pb.DeleteUserReq{Uuid: authorUUID})
-	grpcCode := status.Code(err)
-	if grpcCode != codes.OK || err != nil {
-		return nil, &error_utils.CustomErr{
+	respData := &dto.DeleteAuthorRespData{
+		UUID:      author.UUID,
+		CreatedAt: author.CreatedAt,
+		UpdatedAt: author.UpdatedAt,
+		UserUUID:  author.UserUUID,
+		FirstName: author.FirstName,
+		LastName:  author.LastName,
+		BirthDate: author.BirthDate,
+		Bio:       author.Bio,
+	}
+	deleteUserResp, err := u.authGrpcServiceClient.DeleteUser(
+		ctx,
+		&auth_pb.DeleteUserReq{
+			Uuid: author.UserUUID.String(),
+		},
+	)
+	if err != nil {
+		if status.Code(err) != codes.NotFound {
+			logger.Errorf("error deleting user: %v", err)
+			return nil, report.fail("delete_user", deleteAuthorStepErr(err))
+		}
+		report.set("delete_user", dto.DeleteAuthorStepSkipped, "user already deleted")
+	} else {
+		respData.Email = deleteUserResp.Email
+		respData.Username = deleteUserResp.Username
+		respData.Role = deleteUserResp.Role
+		report.set("delete_user", dto.DeleteAuthorStepDone, fmt.Sprintf(
+			"user %s deleted", deleteUserResp.Username,
+		))
+	}
+
+	// delete author
+	err = u.authorRepo.Delete(author.UUID.String())
+	if err != nil {
+		logger.Errorf("error deleting author: %v", err)
+		return nil, report.fail("delete_author", &error_utils.CustomErr{
 			HttpCode: 500,
 			GrpcCode: codes.Internal,
 			Message:  "internal server error",
 			Detail:   err.Error(),
-		}
+		})
 	}
+	report.set("delete_author", dto.DeleteAuthorStepDone, "author deleted")
 
-	if deleteUserResp == nil {
+	respData.Steps = report.steps
+	return respData, nil
+}
+
+// getReassignTarget returns the author given the books of the deleted one.
+func (u *AuthorUcase) getReassignTarget(author *model.Author, reassignTo string) (*model.Author, error) {
+	if reassignTo == "" {
+		reassignTo = config.Envs.PLACEHOLDER_AUTHOR_UUID
+	}
+	if reassignTo == "" {
 		return nil, &error_utils.CustomErr{
-			HttpCode: 500,
-			GrpcCode: codes.Internal,
-			Message:  "internal server error",
-			Detail:   "delete user response is nil",
+			HttpCode: 400,
+			GrpcCode: codes.InvalidArgument,
+			Message:  "reassign_to is required",
+			Detail:   "no placeholder author is configured, set reassign_to or delete the books",
+		}
+	}
+	if _, err := uuid.Parse(reassignTo); err != nil {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.InvalidArgument,
+			Message:  "invalid reassign_to",
+			Detail:   err.Error(),
+		}
+	}
+	if reassignTo == author.UUID.String() {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.InvalidArgument,
+			Message:  "invalid reassign_to",
+			Detail:   "books can not be reassigned to the deleted author",
 		}
 	}
 
-	// delete author
-	err = u.authorRepo.Delete(author.UUID.String())
+	newAuthor, err := u.authorRepo.GetByUUID(reassignTo)
 	if err != nil {
 		if err.Error() == "not found" {
 			return nil, &error_utils.CustomErr{
-				HttpCode: 404,
-				Message:  "user not found",
-				Detail:   err.Error(),
+				HttpCode: 400,
+				GrpcCode: codes.InvalidArgument,
+				Message:  "invalid reassign_to",
+				Detail:   "author to reassign the books to not found",
 			}
 		}
 		return nil, err
 	}
-	return &dto.DeleteAuthorRespData{
-		UUID:      author.UUID,
-		CreatedAt: author.CreatedAt,
-		UpdatedAt: author.UpdatedAt,
-		UserUUID:  author.UserUUID,
-		Email:     deleteUserResp.Email,
-		Username:  deleteUserResp.Username,
-		FirstName: author.FirstName,
-		LastName:  author.LastName,
-		BirthDate: author.BirthDate,
-		Bio:       author.Bio,
-		Role:      deleteUserResp.Role,
-	}, nil
+	return newAuthor, nil
+}
+
+// deleteAuthorReport holds the steps of an author deletion, in order.
+type deleteAuthorReport struct {
+	steps []dto.DeleteAuthorStep
+}
+
+func newDeleteAuthorReport(names ...string) *deleteAuthorReport {
+	report := &deleteAuthorReport{steps: []dto.DeleteAuthorStep{}}
+	for _, name := range names {
+		report.steps = append(report.steps, dto.DeleteAuthorStep{
+			Name:   name,
+			Status: dto.DeleteAuthorStepPending,
+		})
+	}
+	return report
+}
+
+func (r *deleteAuthorReport) set(name string, status string, detail string) {
+	for i := range r.steps {
+		if r.steps[i].Name == name {
+			r.steps[i].Status = status
+			r.steps[i].Detail = detail
+		}
+	}
+}
+
+// fail marks the step failed and returns err with the steps as its data.
+func (r *deleteAuthorReport) fail(name string, err *error_utils.CustomErr) error {
+	r.set(name, dto.DeleteAuthorStepFailed, err.Error())
+	err.Data = dto.DeleteAuthorFailedRespData{Steps: r.steps}
+	return err
+}
+
+// deleteAuthorStepErr maps the error of another service, a book being
+// borrowed meanwhile still blocks the deletion.
+func deleteAuthorStepErr(err error) *error_utils.CustomErr {
+	grpcCode := status.Code(err)
+	if grpcCode == codes.FailedPrecondition {
+		return &error_utils.CustomErr{
+			HttpCode: 409,
+			GrpcCode: grpcCode,
+			Message:  "author has active borrows",
+			Detail:   err.Error(),
+		}
+	}
+	return &error_utils.CustomErr{
+		HttpCode: 500,
+		GrpcCode: codes.Internal,
+		Message:  "internal server error",
+		Detail:   err.Error(),
+	}
 }
 
 func (u *AuthorUcase) GetAuthorDetail(ctx *gin.Context, authorUUID string) (*dto.GetAuthorDetailRespData, error) {
@@ -628,3 +934,63 @@ func (u *AuthorUcase) GetAuthorByUserUUID(
 		Bio:       author.Bio,
 	}, nil
 }
+
+func (u *AuthorUcase) GetAuthorByUUID(
+	ctx context.Context, authorUUID string,
+) (*dto.GetAuthorByUUIDRespData, error) {
+	author, err := u.authorRepo.GetByUUID(authorUUID)
+	if err != nil {
+		if err.Error() == "not found" {
+			logger.Errorf("author not found: %s", authorUUID)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 404,
+				GrpcCode: codes.NotFound,
+				Message:  "author not found",
+				Detail:   err.Error(),
+			}
+		}
+		logger.Errorf("error getting author by uuid: %s", authorUUID)
+		return nil, err
+	}
+
+	return &dto.GetAuthorByUUIDRespData{
+		UUID:      author.UUID,
+		CreatedAt: author.CreatedAt,
+		UpdatedAt: author.UpdatedAt,
+		UserUUID:  author.UserUUID,
+		FirstName: author.FirstName,
+		LastName:  author.LastName,
+		BirthDate: author.BirthDate,
+		Bio:       author.Bio,
+	}, nil
+}
+
+// PurgeAuthorByUserUUID removes the author of the user for good, the books of
+// the author are left untouched.
+func (u *AuthorUcase) PurgeAuthorByUserUUID(
+	ctx context.Context, userUUID string,
+) (*dto.PurgeAuthorByUserUUIDRespData, error) {
+	author, err := u.authorRepo.GetByUserUUID(userUUID)
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, &error_utils.CustomErr{
+				HttpCode: 404,
+				GrpcCode: codes.NotFound,
+				Message:  "author not found",
+				Detail:   err.Error(),
+			}
+		}
+		logger.Errorf("error getting author by user uuid: %s", userUUID)
+		return nil, err
+	}
+
+	err = u.authorRepo.PurgeByUserUUID(userUUID)
+	if err != nil && err.Error() != "not found" {
+		logger.Errorf("error purging author: %s", err.Error())
+		return nil, err
+	}
+
+	return &dto.PurgeAuthorByUserUUIDRespData{
+		UUID: author.UUID,
+	}, nil
+}
@@ -0,0 +1,265 @@
+package ucase
+
+import (
+	"author_service/config"
+	"author_service/domain/dto"
+	"author_service/domain/model"
+	auth_pb "author_service/interface/grpc/genproto/auth"
+	book_pb "author_service/interface/grpc/genproto/book"
+	"author_service/repository"
+	error_utils "author_service/utils/error"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeAuthorRepo keeps authors by uuid, calling any method not listed here
+// panics.
+type fakeAuthorRepo struct {
+	repository.IAuthorRepo
+	authors   map[string]*model.Author
+	deleteErr error
+}
+
+func (repo *fakeAuthorRepo) GetByUUID(uuid string) (*model.Author, error) {
+	author, ok := repo.authors[uuid]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return author, nil
+}
+
+func (repo *fakeAuthorRepo) Delete(uuid string) error {
+	if repo.deleteErr != nil {
+		return repo.deleteErr
+	}
+	delete(repo.authors, uuid)
+	return nil
+}
+
+// fakeBookClient answers with the book totals of the author, or errs on the
+// step named in errs.
+type fakeBookClient struct {
+	book_pb.BookServiceClient
+	activeBorrows int64
+	bookTotal     int64
+	errs          map[string]error
+	calls         []string
+}
+
+func (c *fakeBookClient) GetActiveBorrowTotalByAuthorUUID(ctx context.Context, in *book_pb.GetActiveBorrowTotalByAuthorUUIDReq, opts ...grpc.CallOption) (*book_pb.GetActiveBorrowTotalByAuthorUUIDResp, error) {
+	c.calls = append(c.calls, "check_borrows")
+	if err := c.errs["check_borrows"]; err != nil {
+		return nil, err
+	}
+	return &book_pb.GetActiveBorrowTotalByAuthorUUIDResp{ActiveBorrowTotal: c.activeBorrows}, nil
+}
+
+func (c *fakeBookClient) ReassignBooksByAuthorUUID(ctx context.Context, in *book_pb.ReassignBooksByAuthorUUIDReq, opts ...grpc.CallOption) (*book_pb.ReassignBooksByAuthorUUIDResp, error) {
+	c.calls = append(c.calls, "reassign_books:"+in.NewAuthorUuid)
+	if err := c.errs["reassign_books"]; err != nil {
+		return nil, err
+	}
+	return &book_pb.ReassignBooksByAuthorUUIDResp{BookTotal: c.bookTotal}, nil
+}
+
+func (c *fakeBookClient) DeleteBooksByAuthorUUID(ctx context.Context, in *book_pb.DeleteBooksByAuthorUUIDReq, opts ...grpc.CallOption) (*book_pb.DeleteBooksByAuthorUUIDResp, error) {
+	c.calls = append(c.calls, "delete_books")
+	if err := c.errs["delete_books"]; err != nil {
+		return nil, err
+	}
+	return &book_pb.DeleteBooksByAuthorUUIDResp{BookTotal: c.bookTotal}, nil
+}
+
+// fakeAuthClient deletes the user, or fails with err.
+type fakeAuthClient struct {
+	auth_pb.AuthServiceClient
+	err     error
+	deleted []string
+}
+
+func (c *fakeAuthClient) DeleteUser(ctx context.Context, in *auth_pb.DeleteUserReq, opts ...grpc.CallOption) (*auth_pb.DeleteUserResp, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	c.deleted = append(c.deleted, in.Uuid)
+	return &auth_pb.DeleteUserResp{Uuid: in.Uuid, Username: "jane", Email: "jane@gmail.com", Role: "user"}, nil
+}
+
+func TestAuthorUcase_DeleteAuthor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	placeholder := &model.Author{UUID: uuid.New(), UserUUID: uuid.New(), FirstName: "unknown"}
+	config.Envs = &config.EnvsSchema{PLACEHOLDER_AUTHOR_UUID: placeholder.UUID.String()}
+
+	tests := []struct {
+		name       string
+		payload    dto.DeleteAuthorReq
+		bookClient *fakeBookClient
+		authClient *fakeAuthClient
+		deleteErr  error
+		wantCode   int // http code of the error, 0 when deleted
+		wantSteps  map[string]string
+		wantCalls  []string
+	}{
+		{
+			name:       "blocked_by_active_borrow",
+			bookClient: &fakeBookClient{activeBorrows: 2},
+			authClient: &fakeAuthClient{},
+			wantCode:   409,
+			wantSteps: map[string]string{
+				"check_borrows":  dto.DeleteAuthorStepFailed,
+				"reassign_books": dto.DeleteAuthorStepPending,
+				"delete_user":    dto.DeleteAuthorStepPending,
+				"delete_author":  dto.DeleteAuthorStepPending,
+			},
+			wantCalls: []string{"check_borrows"},
+		},
+		{
+			name:       "borrowed_while_reassigning",
+			bookClient: &fakeBookClient{errs: map[string]error{"reassign_books": status.Error(codes.FailedPrecondition, "book is borrowed")}},
+			authClient: &fakeAuthClient{},
+			wantCode:   409,
+			wantSteps: map[string]string{
+				"check_borrows":  dto.DeleteAuthorStepDone,
+				"reassign_books": dto.DeleteAuthorStepFailed,
+				"delete_user":    dto.DeleteAuthorStepPending,
+				"delete_author":  dto.DeleteAuthorStepPending,
+			},
+			wantCalls: []string{"check_borrows", "reassign_books:" + placeholder.UUID.String()},
+		},
+		{
+			name:       "reassigns_books_to_placeholder",
+			bookClient: &fakeBookClient{bookTotal: 3},
+			authClient: &fakeAuthClient{},
+			wantSteps: map[string]string{
+				"check_borrows":  dto.DeleteAuthorStepDone,
+				"reassign_books": dto.DeleteAuthorStepDone,
+				"delete_user":    dto.DeleteAuthorStepDone,
+				"delete_author":  dto.DeleteAuthorStepDone,
+			},
+			wantCalls: []string{"check_borrows", "reassign_books:" + placeholder.UUID.String()},
+		},
+		{
+			name:       "deletes_books_of_author_without_user",
+			payload:    dto.DeleteAuthorReq{Books: dto.DeleteAuthorBooksDelete},
+			bookClient: &fakeBookClient{bookTotal: 3},
+			authClient: &fakeAuthClient{err: status.Error(codes.NotFound, "user not found")},
+			wantSteps: map[string]string{
+				"check_borrows": dto.DeleteAuthorStepDone,
+				"delete_books":  dto.DeleteAuthorStepDone,
+				"delete_user":   dto.DeleteAuthorStepSkipped,
+				"delete_author": dto.DeleteAuthorStepDone,
+			},
+			wantCalls: []string{"check_borrows", "delete_books"},
+		},
+		{
+			name:       "auth_service_unreachable",
+			bookClient: &fakeBookClient{bookTotal: 3},
+			authClient: &fakeAuthClient{err: status.Error(codes.Unavailable, "connection refused")},
+			wantCode:   500,
+			wantSteps: map[string]string{
+				"check_borrows":  dto.DeleteAuthorStepDone,
+				"reassign_books": dto.DeleteAuthorStepDone,
+				"delete_user":    dto.DeleteAuthorStepFailed,
+				"delete_author":  dto.DeleteAuthorStepPending,
+			},
+			wantCalls: []string{"check_borrows", "reassign_books:" + placeholder.UUID.String()},
+		},
+		{
+			name:       "author_delete_fails",
+			bookClient: &fakeBookClient{bookTotal: 3},
+			authClient: &fakeAuthClient{},
+			deleteErr:  errors.New("failed to delete"),
+			wantCode:   500,
+			wantSteps: map[string]string{
+				"check_borrows":  dto.DeleteAuthorStepDone,
+				"reassign_books": dto.DeleteAuthorStepDone,
+				"delete_user":    dto.DeleteAuthorStepDone,
+				"delete_author":  dto.DeleteAuthorStepFailed,
+			},
+			wantCalls: []string{"check_borrows", "reassign_books:" + placeholder.UUID.String()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			author := &model.Author{UUID: uuid.New(), UserUUID: uuid.New(), FirstName: "jane"}
+			authorRepo := &fakeAuthorRepo{
+				authors: map[string]*model.Author{
+					author.UUID.String():      author,
+					placeholder.UUID.String(): placeholder,
+				},
+				deleteErr: tt.deleteErr,
+			}
+			ucase := NewAuthorUcase(authorRepo, nil, tt.authClient, tt.bookClient)
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+			resp, err := ucase.DeleteAuthor(ctx, author.UUID.String(), tt.payload)
+
+			var steps []dto.DeleteAuthorStep
+			if tt.wantCode == 0 {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				steps = resp.Steps
+				wantUsername := ""
+				if tt.wantSteps["delete_user"] == dto.DeleteAuthorStepDone {
+					wantUsername = "jane"
+				}
+				if resp.Username != wantUsername {
+					t.Errorf("expected username %q, got %q", wantUsername, resp.Username)
+				}
+				if _, ok := authorRepo.authors[author.UUID.String()]; ok {
+					t.Errorf("expected the author to be deleted")
+				}
+			} else {
+				customErr, ok := err.(*error_utils.CustomErr)
+				if !ok {
+					t.Fatalf("expected a custom error, got %v", err)
+				}
+				if customErr.HttpCode != tt.wantCode {
+					t.Errorf("expected http code %d, got %d: %v", tt.wantCode, customErr.HttpCode, customErr)
+				}
+				data, ok := customErr.Data.(dto.DeleteAuthorFailedRespData)
+				if !ok {
+					t.Fatalf("expected the steps with the error, got %v", customErr.Data)
+				}
+				steps = data.Steps
+				if _, ok := authorRepo.authors[author.UUID.String()]; !ok {
+					t.Errorf("expected the author to be kept")
+				}
+			}
+
+			if len(steps) != len(tt.wantSteps) {
+				t.Fatalf("expected %d steps, got %+v", len(tt.wantSteps), steps)
+			}
+			for _, step := range steps {
+				if step.Status != tt.wantSteps[step.Name] {
+					t.Errorf("expected step %s to be %q, got %q (%s)", step.Name, tt.wantSteps[step.Name], step.Status, step.Detail)
+				}
+			}
+
+			if len(tt.bookClient.calls) != len(tt.wantCalls) {
+				t.Fatalf("expected book service calls %v, got %v", tt.wantCalls, tt.bookClient.calls)
+			}
+			for i := range tt.wantCalls {
+				if tt.bookClient.calls[i] != tt.wantCalls[i] {
+					t.Errorf("expected book service calls %v, got %v", tt.wantCalls, tt.bookClient.calls)
+					break
+				}
+			}
+
+			userDeleted := len(tt.authClient.deleted) > 0
+			wantUserDeleted := tt.wantSteps["delete_user"] == dto.DeleteAuthorStepDone
+			if userDeleted != wantUserDeleted {
+				t.Errorf("expected user deleted: %v, got %v", wantUserDeleted, userDeleted)
+			}
+		})
+	}
+}
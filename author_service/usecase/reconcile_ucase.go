@@ -0,0 +1,175 @@
+package ucase
+
+import (
+	"author_service/config"
+	"author_service/domain/dto"
+	"author_service/domain/model"
+	auth_pb "author_service/interface/grpc/genproto/auth"
+	"author_service/repository"
+	error_utils "author_service/utils/error"
+	"author_service/utils/helper"
+	saga_util "author_service/utils/saga"
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type ReconcileUcase struct {
+	authorRepo            repository.IAuthorRepo
+	sagaRepo              repository.ISagaRepo
+	authGrpcServiceClient auth_pb.AuthServiceClient
+}
+
+type IReconcileUcase interface {
+	// ReconcileAuthors finishes the author creations left behind by a crash or
+	// a failed compensation, and removes the authors whose user no longer
+	// exists in auth service.
+	ReconcileAuthors(ctx context.Context) (*dto.ReconcileRespData, error)
+}
+
+func NewReconcileUcase(
+	authorRepo repository.IAuthorRepo,
+	sagaRepo repository.ISagaRepo,
+	authGrpcServiceClient auth_pb.AuthServiceClient,
+) IReconcileUcase {
+	return &ReconcileUcase{
+		authorRepo:            authorRepo,
+		sagaRepo:              sagaRepo,
+		authGrpcServiceClient: authGrpcServiceClient,
+	}
+}
+
+func (u *ReconcileUcase) ReconcileAuthors(ctx context.Context) (*dto.ReconcileRespData, error) {
+	// sagas and authors younger than this may still be in progress
+	staleBefore := helper.TimeNowUTC().Add(-time.Minute * time.Duration(config.Envs.RECONCILE_GRACE_MINUTES))
+	resp := &dto.ReconcileRespData{Items: []dto.ReconcileRespDataItem{}}
+
+	sagas, err := u.sagaRepo.GetStaleList(model.SagaNameCreateAuthor, staleBefore)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+	for i := range sagas {
+		resp.Items = append(resp.Items, u.reconcileCreateAuthorSaga(ctx, &sagas[i]))
+	}
+
+	authors, err := u.authorRepo.GetListWithIdempotencyKey(staleBefore)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err.Error(),
+		}
+	}
+	for _, author := range authors {
+		_, err := u.authGrpcServiceClient.GetUserByUUID(
+			ctx, &auth_pb.GetUserByUUIDRequest{Uuid: author.UserUUID.String()},
+		)
+		if err == nil {
+			// the registration of auth service completed, its retries are long over
+			err = u.authorRepo.ClearIdempotencyKey(author.UUID.String())
+			if err != nil {
+				resp.Items = append(resp.Items, dto.ReconcileRespDataItem{
+					Kind: "author", UUID: author.UUID.String(), Action: "failed", Detail: err.Error(),
+				})
+			}
+			continue
+		}
+		if status.Code(err) != codes.NotFound {
+			resp.Items = append(resp.Items, dto.ReconcileRespDataItem{
+				Kind: "author", UUID: author.UUID.String(), Action: "failed", Detail: err.Error(),
+			})
+			continue
+		}
+
+		// the registration of auth service that created the author was undone
+		err = u.authorRepo.PurgeByUserUUID(author.UserUUID.String())
+		if err != nil && err.Error() != "not found" {
+			resp.Items = append(resp.Items, dto.ReconcileRespDataItem{
+				Kind: "author", UUID: author.UUID.String(), Action: "failed", Detail: err.Error(),
+			})
+			continue
+		}
+		resp.Items = append(resp.Items, dto.ReconcileRespDataItem{
+			Kind: "author", UUID: author.UUID.String(), Action: "purged", Detail: "author without user",
+		})
+	}
+
+	return resp, nil
+}
+
+// reconcileCreateAuthorSaga completes an author creation interrupted once the
+// author exists, otherwise it undoes whatever the saga created.
+func (u *ReconcileUcase) reconcileCreateAuthorSaga(ctx context.Context, saga *model.Saga) dto.ReconcileRespDataItem {
+	item := dto.ReconcileRespDataItem{Kind: "saga", UUID: saga.UUID.String()}
+	if saga.ResourceUUID == nil {
+		// a user created without its uuid being recorded is removed by the
+		// reconciliation of auth service
+		saga.Status = saga_util.StatusCompensated
+		item.Action = saga_util.StatusCompensated
+		item.Detail = "nothing was created"
+		u.updateSaga(saga)
+		return item
+	}
+	userUUID := saga.ResourceUUID.String()
+
+	hasAuthor := true
+	_, err := u.authorRepo.GetByUserUUID(userUUID)
+	if err != nil {
+		if err.Error() != "not found" {
+			item.Action = "failed"
+			item.Detail = err.Error()
+			return item
+		}
+		hasAuthor = false
+	}
+
+	// only an interrupted saga is finished, a failed one was already reported
+	// as failed to the client
+	if saga.Status == saga_util.StatusStarted && hasAuthor {
+		saga.Status = saga_util.StatusCompleted
+		item.Action = saga_util.StatusCompleted
+		item.Detail = "user and author exist"
+		u.updateSaga(saga)
+		return item
+	}
+
+	if hasAuthor {
+		err = u.authorRepo.PurgeByUserUUID(userUUID)
+		if err != nil && err.Error() != "not found" {
+			item.Action = "failed"
+			item.Detail = err.Error()
+			return item
+		}
+	}
+	_, err = u.authGrpcServiceClient.DeleteUser(
+		ctx, &auth_pb.DeleteUserReq{Uuid: userUUID, Purge: true},
+	)
+	if err != nil && status.Code(err) != codes.NotFound {
+		item.Action = "failed"
+		item.Detail = err.Error()
+		return item
+	}
+
+	saga.Status = saga_util.StatusCompensated
+	item.Action = saga_util.StatusCompensated
+	item.Detail = "removed the user and author of the saga"
+	u.updateSaga(saga)
+	return item
+}
+
+func (u *ReconcileUcase) updateSaga(saga *model.Saga) {
+	err := u.sagaRepo.Update(saga)
+	if err != nil {
+		logger.Errorf("error updating saga %s: %v", saga.UUID.String(), err)
+	}
+}
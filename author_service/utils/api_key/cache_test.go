@@ -0,0 +1,111 @@
+package api_key_util
+
+import (
+	"author_service/domain/dto"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// fakeValidator answers like auth_service and counts the calls per key.
+type fakeValidator struct {
+	users map[string]*dto.CurrentUser
+	err   error
+	calls map[string]int
+}
+
+func (v *fakeValidator) validate(ctx context.Context, apiKey string) (*dto.CurrentUser, error) {
+	v.calls[apiKey]++
+	if v.err != nil {
+		return nil, v.err
+	}
+	currentUser, ok := v.users[apiKey]
+	if !ok {
+		return nil, fmt.Errorf("%w: api key not found", ErrInvalidAPIKey)
+	}
+	return currentUser, nil
+}
+
+func newFakeValidator() *fakeValidator {
+	return &fakeValidator{
+		users: map[string]*dto.CurrentUser{"lk_valid": {UUID: "kiosk", Permissions: []string{"copy:manage"}}},
+		calls: map[string]int{},
+	}
+}
+
+func TestCache_Validate(t *testing.T) {
+	validator := newFakeValidator()
+	cache := NewCache(validator.validate, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		currentUser, err := cache.Validate("lk_valid")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if currentUser.UUID != "kiosk" {
+			t.Errorf("expected the kiosk, got %+v", currentUser)
+		}
+		// callers can not change the cached user
+		currentUser.Permissions = nil
+	}
+	currentUser, _ := cache.Validate("lk_valid")
+	if len(currentUser.Permissions) != 1 {
+		t.Errorf("expected the cached permissions untouched, got %v", currentUser.Permissions)
+	}
+
+	for i := 0; i < 3; i++ {
+		_, err := cache.Validate("lk_unknown")
+		if !errors.Is(err, ErrInvalidAPIKey) {
+			t.Errorf("expected an invalid api key, got %v", err)
+		}
+	}
+
+	if validator.calls["lk_valid"] != 1 || validator.calls["lk_unknown"] != 1 {
+		t.Errorf("expected auth_service asked once per key, got %v", validator.calls)
+	}
+}
+
+func TestCache_Validate_Expiry(t *testing.T) {
+	validator := newFakeValidator()
+	cache := NewCache(validator.validate, 10*time.Millisecond)
+
+	if _, err := cache.Validate("lk_valid"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// the key is revoked, the cached answer is kept until it expires
+	delete(validator.users, "lk_valid")
+	if _, err := cache.Validate("lk_valid"); err != nil {
+		t.Errorf("expected the cached answer, got %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if _, err := cache.Validate("lk_valid"); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("expected the revoked key refused once the entry expired, got %v", err)
+	}
+	if validator.calls["lk_valid"] != 2 {
+		t.Errorf("expected auth_service asked twice, got %d", validator.calls["lk_valid"])
+	}
+}
+
+func TestCache_Validate_Unavailable(t *testing.T) {
+	validator := newFakeValidator()
+	validator.err = errors.New("connection refused")
+	cache := NewCache(validator.validate, time.Minute)
+
+	_, err := cache.Validate("lk_valid")
+	if err == nil || errors.Is(err, ErrInvalidAPIKey) {
+		t.Fatalf("expected the auth_service error, got %v", err)
+	}
+
+	// the failure is not cached, the key works once auth_service is back
+	validator.err = nil
+	if _, err := cache.Validate("lk_valid"); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if validator.calls["lk_valid"] != 2 {
+		t.Errorf("expected auth_service asked twice, got %d", validator.calls["lk_valid"])
+	}
+}
@@ -0,0 +1,44 @@
+package event_util
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/op/go-logging"
+)
+
+var logger = logging.MustGetLogger("main")
+
+// a consumer failing on an event gets it again after this long
+const defaultRetryInterval = time.Second * 5
+
+// Event is a domain event as carried by the broker.
+type Event struct {
+	UUID          uuid.UUID       `json:"uuid"` // consumers dedupe on it
+	Type          string          `json:"type"`
+	Source        string          `json:"source"` // service that emitted the event
+	AggregateUUID uuid.UUID       `json:"aggregate_uuid"`
+	Payload       json.RawMessage `json:"payload"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+}
+
+func (e Event) DecodePayload(v interface{}) error {
+	return json.Unmarshal(e.Payload, v)
+}
+
+// Handler processes an event, an error makes the broker deliver it again.
+type Handler func(ctx context.Context, event Event) error
+
+// Broker carries the events from the outbox relays to the consumers. delivery
+// is at least once, consumers dedupe on the event uuid.
+type Broker interface {
+	// Publish ignores an event already published with the same uuid.
+	Publish(ctx context.Context, event Event) error
+	// Subscribe delivers the events in publish order until ctx is done. the
+	// position of each consumer is kept, an event is delivered again until
+	// handler succeeds, before any later one.
+	Subscribe(ctx context.Context, consumer string, handler Handler) error
+	Close() error
+}
@@ -0,0 +1,41 @@
+package jwt_util
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// ServiceAudience is the audience of service tokens. access tokens of users
+// never carry it, so neither token passes for the other.
+const ServiceAudience = "library_services"
+
+// ServiceClaims are the claims of the tokens auth_service issues to the
+// services for their grpc calls, the subject is the service name.
+type ServiceClaims struct {
+	jwt.RegisteredClaims
+}
+
+// Validate checks the claims every service relies on, all of them required.
+func (claims *ServiceClaims) Validate(issuer string, now time.Time) error {
+	if claims.Subject == "" {
+		return fmt.Errorf("missing sub")
+	}
+	if !claims.VerifyIssuer(issuer, true) {
+		return fmt.Errorf("invalid issuer")
+	}
+	if !claims.VerifyAudience(ServiceAudience, true) {
+		return fmt.Errorf("invalid audience")
+	}
+	if !claims.VerifyExpiresAt(now.Add(-clockSkew), true) {
+		return fmt.Errorf("token is expired")
+	}
+	if !claims.VerifyIssuedAt(now.Add(clockSkew), true) {
+		return fmt.Errorf("token used before issued")
+	}
+	if !claims.VerifyNotBefore(now.Add(clockSkew), true) {
+		return fmt.Errorf("token is not valid yet")
+	}
+	return nil
+}
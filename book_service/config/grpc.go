@@ -1,26 +1,77 @@
 package config
 
 import (
+	auth_grpc "book_service/interface/grpc/genproto/auth"
 	author_pb "book_service/interface/grpc/genproto/author"
+	category_pb "book_service/interface/grpc/genproto/category"
+	grpc_interceptor "book_service/interface/grpc/interceptor"
+	"context"
+	"time"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
-// func NewAuthGrpcServiceClient() auth_pb.AuthServiceClient {
-// 	conn, err := grpc.NewClient(Envs.AUTH_GRPC_SERVICE, grpc.WithTransportCredentials(insecure.NewCredentials()))
-// 	if err != nil {
-// 		logger.Fatalf("Failed to connect to auth grpc service: %v", err)
-// 	}
-// 	authServiceClient := auth_pb.NewAuthServiceClient(conn)
-// 	return authServiceClient
-// }
-
-func NewAuthorGrpcServiceClient() author_pb.AuthorServiceClient {
-	conn, err := grpc.NewClient(Envs.AUTHOR_GRPC_SERVICE, grpc.WithTransportCredentials(insecure.NewCredentials()))
+func NewAuthGrpcServiceClient(tokenSource *grpc_interceptor.ServiceTokenSource) auth_grpc.AuthServiceClient {
+	conn, err := grpc.NewClient(
+		Envs.AUTH_GRPC_SERVICE,
+		grpc.WithTransportCredentials(grpcClientCredentials()),
+		grpc.WithUnaryInterceptor(grpc_interceptor.IdentityUnaryClientInterceptor(tokenSource, auth_grpc.AuthService_GetJWKS_FullMethodName)),
+	)
+	if err != nil {
+		logger.Fatalf("Failed to connect to auth grpc service: %v", err)
+	}
+	authServiceClient := auth_grpc.NewAuthServiceClient(conn)
+	return authServiceClient
+}
+
+func NewAuthorGrpcServiceClient(tokenSource *grpc_interceptor.ServiceTokenSource) author_pb.AuthorServiceClient {
+	conn, err := grpc.NewClient(
+		Envs.AUTHOR_GRPC_SERVICE,
+		grpc.WithTransportCredentials(grpcClientCredentials()),
+		grpc.WithUnaryInterceptor(grpc_interceptor.IdentityUnaryClientInterceptor(tokenSource)),
+	)
 	if err != nil {
 		logger.Fatalf("Failed to connect to auth grpc service: %v", err)
 	}
 	authServiceClient := author_pb.NewAuthorServiceClient(conn)
 	return authServiceClient
 }
+
+func NewCategoryGrpcServiceClient(tokenSource *grpc_interceptor.ServiceTokenSource) category_pb.CategoryServiceClient {
+	conn, err := grpc.NewClient(
+		Envs.CATEGORY_GRPC_SERVICE,
+		grpc.WithTransportCredentials(grpcClientCredentials()),
+		grpc.WithUnaryInterceptor(grpc_interceptor.IdentityUnaryClientInterceptor(tokenSource)),
+	)
+	if err != nil {
+		logger.Fatalf("Failed to connect to category grpc service: %v", err)
+	}
+	categoryServiceClient := category_pb.NewCategoryServiceClient(conn)
+	return categoryServiceClient
+}
+
+// NewServiceTokenSource gets the service token of this service from
+// auth_service. it is nil when SERVICE_SECRET is empty, the service is then
+// identified by its mtls certificate.
+func NewServiceTokenSource() *grpc_interceptor.ServiceTokenSource {
+	if Envs.SERVICE_SECRET == "" {
+		return nil
+	}
+
+	conn, err := grpc.NewClient(Envs.AUTH_GRPC_SERVICE, grpc.WithTransportCredentials(grpcClientCredentials()))
+	if err != nil {
+		logger.Fatalf("Failed to connect to auth grpc service: %v", err)
+	}
+	authServiceClient := auth_grpc.NewAuthServiceClient(conn)
+
+	return grpc_interceptor.NewServiceTokenSource(func(ctx context.Context) (string, time.Time, error) {
+		resp, err := authServiceClient.IssueServiceToken(ctx, &auth_grpc.IssueServiceTokenRequest{
+			ServiceName:   Envs.SERVICE_NAME,
+			ServiceSecret: Envs.SERVICE_SECRET,
+		})
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		return resp.Token, time.Unix(resp.ExpiredAt, 0), nil
+	})
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	auth_grpc "book_service/interface/grpc/genproto/auth"
+	jwt_util "book_service/utils/jwt"
+	"context"
+	"time"
+)
+
+// NewJWKS creates the cached key set used to verify access tokens, fetched from
+// auth_service over grpc.
+func NewJWKS(authGrpcServiceClient auth_grpc.AuthServiceClient) *jwt_util.KeySet {
+	fetch := func(ctx context.Context) ([]jwt_util.JWK, error) {
+		resp, err := authGrpcServiceClient.GetJWKS(ctx, &auth_grpc.GetJWKSRequest{})
+		if err != nil {
+			return nil, err
+		}
+
+		keys := []jwt_util.JWK{}
+		for _, key := range resp.Keys {
+			keys = append(keys, jwt_util.JWK{
+				Kty: key.Kty,
+				Kid: key.Kid,
+				Alg: key.Alg,
+				N:   key.N,
+				E:   key.E,
+				Crv: key.Crv,
+				X:   key.X,
+			})
+		}
+		return keys, nil
+	}
+
+	return jwt_util.NewKeySet(fetch, time.Second*time.Duration(Envs.JWKS_CACHE_TTL_SECONDS))
+}
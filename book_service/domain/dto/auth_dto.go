@@ -5,4 +5,31 @@ type CurrentUser struct {
 	Email    string `json:"email"`
 	Username string `json:"username"`
 	Role     string `json:"role"`
+
+	Permissions []string `json:"permissions"` // granted to the role in auth_service
+
+	APIKeyUUID string `json:"-"` // set when authenticated by an api key instead of a token
+	APIKey     string `json:"-"` // api key of the request, to check it with auth_service
+
+	AccessToken string `json:"-"` // bearer token of the request, to check it with auth_service
 }
+
+func (currentUser CurrentUser) HasPermission(permission string) bool {
+	for _, p := range currentUser.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// permissions checked by this service, roles are granted them in auth_service
+const (
+	PermissionCopyManage   = "copy:manage"
+	PermissionBorrowManage = "borrow:manage"
+	PermissionHoldManage   = "hold:manage"
+	PermissionFineManage   = "fine:manage"
+
+	// borrows of users having it use the extended renewal limit
+	PermissionBorrowRenewExtended = "borrow:renew_extended"
+)
@@ -1,11 +1,83 @@
 package dto
 
+import "time"
+
 type BookBorrowRepo_GetListParams struct {
-	AuthorUUID string
-	Query      string
-	QueryBy    string
-	Page       int
-	Limit      int
-	SortOrder  string
-	SortBy     string
+	BookUUID  string
+	UserUUID  string
+	Returned  *bool      // leave nil to query both returned and active borrows
+	DueBefore *time.Time // use with Returned=false to query overdue borrows
+	Page      int
+	Limit     int
+	SortOrder string
+	SortBy    string
+}
+
+type BorrowBookRespData struct {
+	UUID       string     `json:"uuid"`
+	BookUUID   string     `json:"book_uuid"`
+	UserUUID   string     `json:"user_uuid"`
+	CopyUUID   *string    `json:"copy_uuid"`
+	BorrowedAt time.Time  `json:"borrowed_at"`
+	DueAt      time.Time  `json:"due_at"`
+	ReturnedAt *time.Time `json:"returned_at"`
+	RenewCount int        `json:"renew_count"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type ReturnBookRespData struct {
+	UUID       string     `json:"uuid"`
+	BookUUID   string     `json:"book_uuid"`
+	UserUUID   string     `json:"user_uuid"`
+	CopyUUID   *string    `json:"copy_uuid"`
+	BorrowedAt time.Time  `json:"borrowed_at"`
+	DueAt      time.Time  `json:"due_at"`
+	ReturnedAt *time.Time `json:"returned_at"`
+	RenewCount int        `json:"renew_count"`
+	Overdue    bool       `json:"overdue"`     // returned after due date
+	FineAmount int64      `json:"fine_amount"` // fine charged for the late return
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type RenewBookRespData struct {
+	UUID            string    `json:"uuid"`
+	BookUUID        string    `json:"book_uuid"`
+	UserUUID        string    `json:"user_uuid"`
+	BorrowedAt      time.Time `json:"borrowed_at"`
+	DueAt           time.Time `json:"due_at"`
+	RenewCount      int       `json:"renew_count"`
+	RenewsRemaining int       `json:"renews_remaining"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type GetBorrowListReq struct {
+	Status    string `form:"status" default:"any" binding:"omitempty,oneof=any active returned overdue"`
+	BookUUID  string `form:"book_uuid"`
+	UserUUID  string `form:"user_uuid"` // admin only, other users always get their own borrows
+	Page      int    `form:"page" default:"1"`
+	Limit     int    `form:"limit" default:"10"`
+	SortOrder string `form:"sort_order" default:"desc" binding:"required,oneof=asc desc"`
+	SortBy    string `form:"sort_by" default:"created_at" binding:"omitempty,oneof=created_at borrowed_at due_at returned_at"`
+}
+
+type GetBorrowListRespDataItem struct {
+	UUID       string     `json:"uuid"`
+	BookUUID   string     `json:"book_uuid"`
+	UserUUID   string     `json:"user_uuid"`
+	CopyUUID   *string    `json:"copy_uuid"`
+	BorrowedAt time.Time  `json:"borrowed_at"`
+	DueAt      time.Time  `json:"due_at"`
+	ReturnedAt *time.Time `json:"returned_at"`
+	RenewCount int        `json:"renew_count"`
+	Overdue    bool       `json:"overdue"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type GetBorrowListRespData struct {
+	BasePaginatedData
+	Data []GetBorrowListRespDataItem `json:"data"`
 }
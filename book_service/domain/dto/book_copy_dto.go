@@ -0,0 +1,70 @@
+package dto
+
+import "time"
+
+type BookCopyRepo_GetListParams struct {
+	BookUUID  string
+	Status    string
+	Page      int
+	Limit     int
+	SortOrder string
+	SortBy    string
+}
+
+type CreateBookCopyReq struct {
+	Barcode    string     `json:"barcode" binding:"required,max=64"`
+	Condition  string     `json:"condition" default:"good" binding:"omitempty,oneof=new good fair poor damaged"`
+	AcquiredAt *time.Time `json:"acquired_at"` // defaults to now
+}
+
+type CreateBookCopyRespData struct {
+	UUID       string    `json:"uuid"`
+	BookUUID   string    `json:"book_uuid"`
+	Barcode    string    `json:"barcode"`
+	Condition  string    `json:"condition"`
+	Status     string    `json:"status"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// on-loan status is managed by borrows and can not be set by hand
+type PatchBookCopyReq struct {
+	Condition *string `json:"condition" binding:"omitempty,oneof=new good fair poor damaged"`
+	Status    *string `json:"status" binding:"omitempty,oneof=available lost repair"`
+}
+
+type PatchBookCopyRespData struct {
+	UUID       string    `json:"uuid"`
+	BookUUID   string    `json:"book_uuid"`
+	Barcode    string    `json:"barcode"`
+	Condition  string    `json:"condition"`
+	Status     string    `json:"status"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type GetBookCopyListReq struct {
+	Status    string `form:"status" default:"any" binding:"omitempty,oneof=any available on-loan lost repair"`
+	Page      int    `form:"page" default:"1"`
+	Limit     int    `form:"limit" default:"10"`
+	SortOrder string `form:"sort_order" default:"desc" binding:"required,oneof=asc desc"`
+	SortBy    string `form:"sort_by" default:"created_at" binding:"omitempty,oneof=created_at acquired_at barcode"`
+}
+
+type GetBookCopyListRespDataItem struct {
+	UUID       string    `json:"uuid"`
+	BookUUID   string    `json:"book_uuid"`
+	Barcode    string    `json:"barcode"`
+	Condition  string    `json:"condition"`
+	Status     string    `json:"status"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type GetBookCopyListRespData struct {
+	BasePaginatedData
+	Data []GetBookCopyListRespDataItem `json:"data"`
+}
@@ -5,28 +5,57 @@ import (
 )
 
 type GetBookListReq struct {
-	Query     string `form:"query"`
-	QueryBy   string `form:"query_by" default:"any" binding:"oneof=title any"`
-	Page      int    `form:"page" default:"1"`
-	Limit     int    `form:"limit" default:"10"`
-	SortOrder string `form:"sort_order" binding:"required,oneof=asc desc"`
-	SortBy    string `form:"sort_by" default:"created_at"`
+	Query        string `form:"query"`
+	QueryBy      string `form:"query_by" default:"any" binding:"omitempty,oneof=title any"`
+	AuthorUUID   string `form:"author_uuid" binding:"omitempty,uuid"`
+	CategoryUUID string `form:"category_uuid" binding:"omitempty,uuid|eq=null"` // use "null" to get books without category
+	Page         int    `form:"page" default:"1"`
+	Limit        int    `form:"limit" default:"10"`
+	SortOrder    string `form:"sort_order" default:"desc" binding:"required,oneof=asc desc"`
+	SortBy       string `form:"sort_by" default:"created_at" binding:"omitempty,oneof=created_at updated_at title"`
+}
+
+type GetBookListRespDataItem struct {
+	UUID         string    `json:"uuid"`
+	AuthorUUID   string    `json:"author_uuid"`
+	CategoryUUID *string   `json:"category_uuid"`
+	Title        string    `json:"title"`
+	Stock        int64     `json:"stock"` // number of available copies
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type GetBookListRespData struct {
+	BasePaginatedData
+	Data []GetBookListRespDataItem `json:"data"`
+}
+
+type GetBookDetailRespData struct {
+	UUID         string    `json:"uuid"`
+	AuthorUUID   string    `json:"author_uuid"`
+	AuthorName   *string   `json:"author_name"` // nil when author service can not resolve it
+	CategoryUUID *string   `json:"category_uuid"`
+	CategoryName *string   `json:"category_name"` // nil when category service can not resolve it
+	Title        string    `json:"title"`
+	Stock        int64     `json:"stock"` // number of available copies
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
 }
 
 type BookRepo_GetListParams struct {
-	AuthorUUID string // use string "null" to query null field
-	Query      string
-	QueryBy    string
-	Page       int
-	Limit      int
-	SortOrder  string
-	SortBy     string
+	AuthorUUID   string // use string "null" to query null field
+	CategoryUUID string // use string "null" to query null field
+	Query        string
+	QueryBy      string // leave empty to query by any queriable fields
+	Page         int
+	Limit        int
+	SortOrder    string
+	SortBy       string
 }
 
 type CreateBookReq struct {
 	CategoryUUID *string `json:"category_uuid"`
 	Title        string  `json:"title" binding:"required"`
-	Stock        int64   `json:"stock" binding:"required"`
 }
 
 type CreateBookResp struct {
@@ -34,7 +63,7 @@ type CreateBookResp struct {
 	AuthorUUID   string    `json:"author_uuid"`
 	CategoryUUID *string   `json:"category_uuid"`
 	Title        string    `json:"title"`
-	Stock        int64     `json:"stock"`
+	Stock        int64     `json:"stock"` // number of available copies
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
@@ -43,7 +72,6 @@ type CreateBookResp struct {
 type PatchBookReq struct {
 	CategoryUUID *string `json:"category_uuid"`
 	Title        *string `json:"title"`
-	Stock        *int64  `json:"stock"`
 }
 
 type PatchBookRespData struct {
@@ -51,7 +79,7 @@ type PatchBookRespData struct {
 	AuthorUUID   string    `json:"author_uuid"`
 	CategoryUUID *string   `json:"category_uuid"`
 	Title        string    `json:"title"`
-	Stock        int64     `json:"stock"`
+	Stock        int64     `json:"stock"` // number of available copies
 	UpdatedAt    time.Time `json:"updated_at"`
 	CreatedAt    time.Time `json:"created_at"`
 }
@@ -61,7 +89,7 @@ type DeleteBookRespData struct {
 	AuthorUUID   string    `json:"author_uuid"`
 	CategoryUUID *string   `json:"category_uuid"`
 	Title        string    `json:"title"`
-	Stock        int64     `json:"stock"`
+	Stock        int64     `json:"stock"` // number of available copies
 	UpdatedAt    time.Time `json:"updated_at"`
 	CreatedAt    time.Time `json:"created_at"`
 }
@@ -74,3 +102,12 @@ type BulkGetBookTotalByAuthorUUIDsRespDataItem struct {
 	AuthorUUID string `json:"author_uuid"`
 	Total      int64  `json:"total"`
 }
+
+type BulkGetBookTotalByCategoryUUIDsReq struct {
+	CategoryUUIDs []string `json:"category_uuids" binding:"required"`
+}
+
+type BulkGetBookTotalByCategoryUUIDsRespDataItem struct {
+	CategoryUUID string `json:"category_uuid"`
+	Total        int64  `json:"total"`
+}
@@ -0,0 +1,63 @@
+package dto
+
+import "time"
+
+type BookHoldRepo_GetListParams struct {
+	BookUUID  string
+	UserUUID  string
+	Statuses  []string // leave empty to query holds of any status
+	Page      int
+	Limit     int
+	SortOrder string
+	SortBy    string
+}
+
+type PlaceHoldRespData struct {
+	UUID          string     `json:"uuid"`
+	BookUUID      string     `json:"book_uuid"`
+	UserUUID      string     `json:"user_uuid"`
+	Status        string     `json:"status"`
+	QueuePosition int64      `json:"queue_position"` // 1 based, 0 when the hold is not waiting
+	ReadyAt       *time.Time `json:"ready_at"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+type CancelHoldRespData struct {
+	UUID      string     `json:"uuid"`
+	BookUUID  string     `json:"book_uuid"`
+	UserUUID  string     `json:"user_uuid"`
+	Status    string     `json:"status"`
+	ClosedAt  *time.Time `json:"closed_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type GetHoldListReq struct {
+	Status    string `form:"status" default:"any" binding:"omitempty,oneof=any active waiting ready fulfilled cancelled expired"`
+	BookUUID  string `form:"book_uuid"`
+	UserUUID  string `form:"user_uuid"` // admin only, other users always get their own holds
+	Page      int    `form:"page" default:"1"`
+	Limit     int    `form:"limit" default:"10"`
+	SortOrder string `form:"sort_order" default:"desc" binding:"required,oneof=asc desc"`
+	SortBy    string `form:"sort_by" default:"created_at" binding:"omitempty,oneof=created_at ready_at expires_at"`
+}
+
+type GetHoldListRespDataItem struct {
+	UUID          string     `json:"uuid"`
+	BookUUID      string     `json:"book_uuid"`
+	UserUUID      string     `json:"user_uuid"`
+	Status        string     `json:"status"`
+	QueuePosition int64      `json:"queue_position"` // 1 based, 0 when the hold is not waiting
+	ReadyAt       *time.Time `json:"ready_at"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+	ClosedAt      *time.Time `json:"closed_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+type GetHoldListRespData struct {
+	BasePaginatedData
+	Data []GetHoldListRespDataItem `json:"data"`
+}
@@ -0,0 +1,53 @@
+package dto
+
+import "time"
+
+type FineLedgerRepo_GetListParams struct {
+	UserUUID  string
+	Type      string
+	Page      int
+	Limit     int
+	SortOrder string
+	SortBy    string
+}
+
+type GetMyFinesReq struct {
+	Type      string `form:"type" default:"any" binding:"omitempty,oneof=any charge waiver payment"`
+	Page      int    `form:"page" default:"1"`
+	Limit     int    `form:"limit" default:"10"`
+	SortOrder string `form:"sort_order" default:"desc" binding:"required,oneof=asc desc"`
+}
+
+type GetMyFinesRespDataItem struct {
+	UUID       string    `json:"uuid"`
+	BorrowUUID *string   `json:"borrow_uuid"`
+	Type       string    `json:"type"`
+	Amount     int64     `json:"amount"`
+	Note       *string   `json:"note"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type GetMyFinesRespData struct {
+	BasePaginatedData
+	Balance int64                    `json:"balance"`
+	Data    []GetMyFinesRespDataItem `json:"data"`
+}
+
+type FineAdjustmentReq struct {
+	UserUUID   string  `json:"user_uuid" binding:"required"`
+	BorrowUUID *string `json:"borrow_uuid"`
+	Amount     int64   `json:"amount" binding:"required,gt=0"`
+	Note       *string `json:"note"`
+}
+
+type FineAdjustmentRespData struct {
+	UUID       string    `json:"uuid"`
+	UserUUID   string    `json:"user_uuid"`
+	BorrowUUID *string   `json:"borrow_uuid"`
+	Type       string    `json:"type"`
+	Amount     int64     `json:"amount"`
+	Note       *string   `json:"note"`
+	CreatedBy  string    `json:"created_by"`
+	Balance    int64     `json:"balance"` // outstanding balance after this entry
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -1,16 +1,26 @@
 package model
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type BookBorrow struct {
 	gorm.Model
-	UUID       uuid.UUID `gorm:"type:uuid;unique;not null" json:"uuid"`
-	BookUUID   uuid.UUID `gorm:"type:uuid;not null" json:"book_uuid"`
-	UserUUID   uuid.UUID `gorm:"type:uuid;not null" json:"user_uuid"`
-	Book       Book      `gorm:"foreignKey:BookUUID;references:UUID;constraint:OnDelete:CASCADE;" json:"-"`
-	BorrowDate *string   `json:"borrow_date"`
-	ReturnDate *string   `json:"return_date"`
+	UUID       uuid.UUID  `gorm:"type:uuid;unique;not null" json:"uuid"`
+	BookUUID   uuid.UUID  `gorm:"type:uuid;not null" json:"book_uuid"`
+	UserUUID   uuid.UUID  `gorm:"type:uuid;not null" json:"user_uuid"`
+	CopyUUID   *uuid.UUID `gorm:"type:uuid;index" json:"copy_uuid"` // nil for borrows made before per copy inventory
+	Book       Book       `gorm:"foreignKey:BookUUID;references:UUID;constraint:OnDelete:CASCADE;" json:"-"`
+	Copy       *BookCopy  `gorm:"foreignKey:CopyUUID;references:UUID;constraint:OnDelete:SET NULL;" json:"-"`
+	BorrowedAt time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"borrowed_at"`
+	DueAt      time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP;index" json:"due_at"`
+	ReturnedAt *time.Time `json:"returned_at"`
+	RenewCount int        `gorm:"not null;default:0" json:"renew_count"`
+}
+
+func (b *BookBorrow) IsOverdue(now time.Time) bool {
+	return b.ReturnedAt == nil && b.DueAt.Before(now)
 }
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	BookCopyStatusAvailable = "available"
+	BookCopyStatusOnLoan    = "on-loan"
+	BookCopyStatusLost      = "lost"
+	BookCopyStatusRepair    = "repair"
+)
+
+const (
+	BookCopyConditionNew     = "new"
+	BookCopyConditionGood    = "good"
+	BookCopyConditionFair    = "fair"
+	BookCopyConditionPoor    = "poor"
+	BookCopyConditionDamaged = "damaged"
+)
+
+// BookCopy is a single physical copy of a book. the available stock of a book
+// is the number of its copies with the available status.
+type BookCopy struct {
+	gorm.Model
+	UUID       uuid.UUID `gorm:"type:uuid;unique;not null" json:"uuid"`
+	BookUUID   uuid.UUID `gorm:"type:uuid;not null;index" json:"book_uuid"`
+	Barcode    string    `gorm:"type:varchar(64);unique;not null" json:"barcode"`
+	Condition  string    `gorm:"type:varchar(20);not null;default:good" json:"condition"`
+	Status     string    `gorm:"type:varchar(20);not null;default:available;index" json:"status"`
+	AcquiredAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"acquired_at"`
+
+	Book Book `gorm:"foreignKey:BookUUID;references:UUID;constraint:OnDelete:CASCADE;" json:"-"`
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	BookHoldStatusWaiting   = "waiting"
+	BookHoldStatusReady     = "ready"
+	BookHoldStatusFulfilled = "fulfilled"
+	BookHoldStatusCancelled = "cancelled"
+	BookHoldStatusExpired   = "expired"
+)
+
+// BookHold is a place in the FIFO hold queue of an out of stock book. once a
+// copy comes back the oldest waiting hold becomes ready and reserves that copy
+// until ExpiresAt.
+type BookHold struct {
+	gorm.Model
+	UUID      uuid.UUID  `gorm:"type:uuid;unique;not null" json:"uuid"`
+	BookUUID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"book_uuid"`
+	UserUUID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_uuid"`
+	Status    string     `gorm:"type:varchar(20);not null;default:waiting" json:"status"`
+	ReadyAt   *time.Time `json:"ready_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	ClosedAt  *time.Time `json:"closed_at"` // set once fulfilled, cancelled or expired
+
+	Book Book `gorm:"foreignKey:BookUUID;references:UUID;constraint:OnDelete:CASCADE;" json:"-"`
+}
+
+func (h *BookHold) IsActive() bool {
+	return h.Status == BookHoldStatusWaiting || h.Status == BookHoldStatusReady
+}
@@ -1,6 +1,8 @@
 package model
 
 import (
+	event_util "book_service/utils/event"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -11,7 +13,21 @@ type Book struct {
 	AuthorUUID   uuid.UUID  `gorm:"type:uuid;not null" json:"author_uuid"`
 	CategoryUUID *uuid.UUID `gorm:"type:uuid" json:"category_uuid"`
 	Title        string     `gorm:"type:text;not null" json:"title"`
-	Stock        int64      `json:"stock"`
 
+	BookCopies  []BookCopy   `gorm:"foreignKey:BookUUID;references:UUID;constraint:OnDelete:CASCADE;" json:"-"`
 	BookBorrows []BookBorrow `gorm:"foreignKey:BookUUID;references:UUID;constraint:OnDelete:CASCADE;" json:"-"`
+	BookHolds   []BookHold   `gorm:"foreignKey:BookUUID;references:UUID;constraint:OnDelete:CASCADE;" json:"-"`
+}
+
+func (b *Book) EventPayload() event_util.BookPayload {
+	payload := event_util.BookPayload{
+		UUID:       b.UUID.String(),
+		AuthorUUID: b.AuthorUUID.String(),
+		Title:      b.Title,
+	}
+	if b.CategoryUUID != nil {
+		categoryUUID := b.CategoryUUID.String()
+		payload.CategoryUUID = &categoryUUID
+	}
+	return payload
 }
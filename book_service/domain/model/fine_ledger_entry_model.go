@@ -0,0 +1,25 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	FineEntryTypeCharge  = "charge"
+	FineEntryTypeWaiver  = "waiver"
+	FineEntryTypePayment = "payment"
+)
+
+// FineLedgerEntry is an append only ledger of fines. a user's outstanding
+// balance is the sum of charges minus the sum of waivers and payments.
+type FineLedgerEntry struct {
+	gorm.Model
+	UUID       uuid.UUID  `gorm:"type:uuid;unique;not null" json:"uuid"`
+	UserUUID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_uuid"`
+	BorrowUUID *uuid.UUID `gorm:"type:uuid" json:"borrow_uuid"`
+	Type       string     `gorm:"type:varchar(20);not null" json:"type"`
+	Amount     int64      `gorm:"not null" json:"amount"`
+	Note       *string    `gorm:"type:text" json:"note"`
+	CreatedBy  *uuid.UUID `gorm:"type:uuid" json:"created_by"` // nil for charges accrued by the system
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	event_util "book_service/utils/event"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ProcessedEvent records a broker event a consumer already applied, written in
+// the transaction of its changes. the broker delivers at least once, a
+// redelivered event is skipped.
+type ProcessedEvent struct {
+	gorm.Model
+	EventUUID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_processed_events_consumer_event_uuid,priority:2" json:"event_uuid"`
+	Consumer  string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_processed_events_consumer_event_uuid,priority:1" json:"consumer"`
+	EventType string    `gorm:"type:varchar(100);not null" json:"event_type"`
+}
+
+func NewProcessedEvent(consumer string, event event_util.Event) *ProcessedEvent {
+	return &ProcessedEvent{
+		EventUUID: event.UUID,
+		Consumer:  consumer,
+		EventType: event.Type,
+	}
+}
@@ -2,8 +2,18 @@ package interface_pkg
 
 import (
 	ucase "book_service/usecase"
+	api_key_util "book_service/utils/api_key"
+	jwt_util "book_service/utils/jwt"
 )
 
 type CommonDependency struct {
-	BookUcase ucase.IBookUcase
+	BookUcase       ucase.IBookUcase
+	BookBorrowUcase ucase.IBookBorrowUcase
+	FineUcase       ucase.IFineUcase
+	BookHoldUcase   ucase.IBookHoldUcase
+	BookCopyUcase   ucase.IBookCopyUcase
+
+	KeySet       *jwt_util.KeySet      // auth_service public keys to verify access tokens
+	ClaimsConfig jwt_util.ClaimsConfig // issuer and audience access tokens must carry
+	APIKeyCache  *api_key_util.Cache   // api keys of service accounts validated by auth_service
 }
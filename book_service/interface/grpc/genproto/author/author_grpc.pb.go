@@ -19,8 +19,10 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	AuthorService_CreateAuthor_FullMethodName        = "/author_service.AuthorService/CreateAuthor"
-	AuthorService_GetAuthorByUserUUID_FullMethodName = "/author_service.AuthorService/GetAuthorByUserUUID"
+	AuthorService_CreateAuthor_FullMethodName          = "/author_service.AuthorService/CreateAuthor"
+	AuthorService_GetAuthorByUserUUID_FullMethodName   = "/author_service.AuthorService/GetAuthorByUserUUID"
+	AuthorService_GetAuthorByUUID_FullMethodName       = "/author_service.AuthorService/GetAuthorByUUID"
+	AuthorService_PurgeAuthorByUserUUID_FullMethodName = "/author_service.AuthorService/PurgeAuthorByUserUUID"
 )
 
 // AuthorServiceClient is the client API for AuthorService service.
@@ -29,6 +31,8 @@ const (
 type AuthorServiceClient interface {
 	CreateAuthor(ctx context.Context, in *CreateAuthorReq, opts ...grpc.CallOption) (*CreateAuthorResp, error)
 	GetAuthorByUserUUID(ctx context.Context, in *GetAuthorByUserUUIDReq, opts ...grpc.CallOption) (*GetAuthorByUserUUIDResp, error)
+	GetAuthorByUUID(ctx context.Context, in *GetAuthorByUUIDReq, opts ...grpc.CallOption) (*GetAuthorByUUIDResp, error)
+	PurgeAuthorByUserUUID(ctx context.Context, in *PurgeAuthorByUserUUIDReq, opts ...grpc.CallOption) (*PurgeAuthorByUserUUIDResp, error)
 }
 
 type authorServiceClient struct {
@@ -59,12 +63,34 @@ func (c *authorServiceClient) GetAuthorByUserUUID(ctx context.Context, in *GetAu
 	return out, nil
 }
 
+func (c *authorServiceClient) GetAuthorByUUID(ctx context.Context, in *GetAuthorByUUIDReq, opts ...grpc.CallOption) (*GetAuthorByUUIDResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAuthorByUUIDResp)
+	err := c.cc.Invoke(ctx, AuthorService_GetAuthorByUUID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorServiceClient) PurgeAuthorByUserUUID(ctx context.Context, in *PurgeAuthorByUserUUIDReq, opts ...grpc.CallOption) (*PurgeAuthorByUserUUIDResp, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PurgeAuthorByUserUUIDResp)
+	err := c.cc.Invoke(ctx, AuthorService_PurgeAuthorByUserUUID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AuthorServiceServer is the server API for AuthorService service.
 // All implementations must embed UnimplementedAuthorServiceServer
 // for forward compatibility.
 type AuthorServiceServer interface {
 	CreateAuthor(context.Context, *CreateAuthorReq) (*CreateAuthorResp, error)
 	GetAuthorByUserUUID(context.Context, *GetAuthorByUserUUIDReq) (*GetAuthorByUserUUIDResp, error)
+	GetAuthorByUUID(context.Context, *GetAuthorByUUIDReq) (*GetAuthorByUUIDResp, error)
+	PurgeAuthorByUserUUID(context.Context, *PurgeAuthorByUserUUIDReq) (*PurgeAuthorByUserUUIDResp, error)
 	mustEmbedUnimplementedAuthorServiceServer()
 }
 
@@ -81,6 +107,12 @@ func (UnimplementedAuthorServiceServer) CreateAuthor(context.Context, *CreateAut
 func (UnimplementedAuthorServiceServer) GetAuthorByUserUUID(context.Context, *GetAuthorByUserUUIDReq) (*GetAuthorByUserUUIDResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAuthorByUserUUID not implemented")
 }
+func (UnimplementedAuthorServiceServer) GetAuthorByUUID(context.Context, *GetAuthorByUUIDReq) (*GetAuthorByUUIDResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAuthorByUUID not implemented")
+}
+func (UnimplementedAuthorServiceServer) PurgeAuthorByUserUUID(context.Context, *PurgeAuthorByUserUUIDReq) (*PurgeAuthorByUserUUIDResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeAuthorByUserUUID not implemented")
+}
 func (UnimplementedAuthorServiceServer) mustEmbedUnimplementedAuthorServiceServer() {}
 func (UnimplementedAuthorServiceServer) testEmbeddedByValue()                       {}
 
@@ -138,6 +170,42 @@ func _AuthorService_GetAuthorByUserUUID_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AuthorService_GetAuthorByUUID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAuthorByUUIDReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorServiceServer).GetAuthorByUUID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorService_GetAuthorByUUID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorServiceServer).GetAuthorByUUID(ctx, req.(*GetAuthorByUUIDReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorService_PurgeAuthorByUserUUID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeAuthorByUserUUIDReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorServiceServer).PurgeAuthorByUserUUID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorService_PurgeAuthorByUserUUID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorServiceServer).PurgeAuthorByUserUUID(ctx, req.(*PurgeAuthorByUserUUIDReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AuthorService_ServiceDesc is the grpc.ServiceDesc for AuthorService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -153,6 +221,14 @@ var AuthorService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetAuthorByUserUUID",
 			Handler:    _AuthorService_GetAuthorByUserUUID_Handler,
 		},
+		{
+			MethodName: "GetAuthorByUUID",
+			Handler:    _AuthorService_GetAuthorByUUID_Handler,
+		},
+		{
+			MethodName: "PurgeAuthorByUserUUID",
+			Handler:    _AuthorService_PurgeAuthorByUserUUID_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "author.proto",
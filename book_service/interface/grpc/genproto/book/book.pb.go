@@ -253,6 +253,517 @@ func (x *BulkGetBookTotalByAuthorUUIDsResp) GetData() []*BulkGetBookTotalByAutho
 	return nil
 }
 
+type GetBookTotalByCategoryUUIDReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CategoryUuid string `protobuf:"bytes,1,opt,name=category_uuid,json=categoryUuid,proto3" json:"category_uuid,omitempty"`
+}
+
+func (x *GetBookTotalByCategoryUUIDReq) Reset() {
+	*x = GetBookTotalByCategoryUUIDReq{}
+	mi := &file_book_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBookTotalByCategoryUUIDReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBookTotalByCategoryUUIDReq) ProtoMessage() {}
+
+func (x *GetBookTotalByCategoryUUIDReq) ProtoReflect() protoreflect.Message {
+	mi := &file_book_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBookTotalByCategoryUUIDReq.ProtoReflect.Descriptor instead.
+func (*GetBookTotalByCategoryUUIDReq) Descriptor() ([]byte, []int) {
+	return file_book_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetBookTotalByCategoryUUIDReq) GetCategoryUuid() string {
+	if x != nil {
+		return x.CategoryUuid
+	}
+	return ""
+}
+
+type GetBookTotalByCategoryUUIDResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BookTotal int64 `protobuf:"varint,1,opt,name=book_total,json=bookTotal,proto3" json:"book_total,omitempty"`
+}
+
+func (x *GetBookTotalByCategoryUUIDResp) Reset() {
+	*x = GetBookTotalByCategoryUUIDResp{}
+	mi := &file_book_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBookTotalByCategoryUUIDResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBookTotalByCategoryUUIDResp) ProtoMessage() {}
+
+func (x *GetBookTotalByCategoryUUIDResp) ProtoReflect() protoreflect.Message {
+	mi := &file_book_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBookTotalByCategoryUUIDResp.ProtoReflect.Descriptor instead.
+func (*GetBookTotalByCategoryUUIDResp) Descriptor() ([]byte, []int) {
+	return file_book_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetBookTotalByCategoryUUIDResp) GetBookTotal() int64 {
+	if x != nil {
+		return x.BookTotal
+	}
+	return 0
+}
+
+type BulkGetBookTotalByCategoryUUIDsReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CategoryUuids []string `protobuf:"bytes,1,rep,name=category_uuids,json=categoryUuids,proto3" json:"category_uuids,omitempty"`
+}
+
+func (x *BulkGetBookTotalByCategoryUUIDsReq) Reset() {
+	*x = BulkGetBookTotalByCategoryUUIDsReq{}
+	mi := &file_book_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkGetBookTotalByCategoryUUIDsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkGetBookTotalByCategoryUUIDsReq) ProtoMessage() {}
+
+func (x *BulkGetBookTotalByCategoryUUIDsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_book_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkGetBookTotalByCategoryUUIDsReq.ProtoReflect.Descriptor instead.
+func (*BulkGetBookTotalByCategoryUUIDsReq) Descriptor() ([]byte, []int) {
+	return file_book_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *BulkGetBookTotalByCategoryUUIDsReq) GetCategoryUuids() []string {
+	if x != nil {
+		return x.CategoryUuids
+	}
+	return nil
+}
+
+type BulkGetBookTotalByCategoryUUIDsResp_Data struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CategoryUuid string `protobuf:"bytes,1,opt,name=category_uuid,json=categoryUuid,proto3" json:"category_uuid,omitempty"`
+	BookTotal    int64  `protobuf:"varint,2,opt,name=book_total,json=bookTotal,proto3" json:"book_total,omitempty"`
+}
+
+func (x *BulkGetBookTotalByCategoryUUIDsResp_Data) Reset() {
+	*x = BulkGetBookTotalByCategoryUUIDsResp_Data{}
+	mi := &file_book_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkGetBookTotalByCategoryUUIDsResp_Data) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkGetBookTotalByCategoryUUIDsResp_Data) ProtoMessage() {}
+
+func (x *BulkGetBookTotalByCategoryUUIDsResp_Data) ProtoReflect() protoreflect.Message {
+	mi := &file_book_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkGetBookTotalByCategoryUUIDsResp_Data.ProtoReflect.Descriptor instead.
+func (*BulkGetBookTotalByCategoryUUIDsResp_Data) Descriptor() ([]byte, []int) {
+	return file_book_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *BulkGetBookTotalByCategoryUUIDsResp_Data) GetCategoryUuid() string {
+	if x != nil {
+		return x.CategoryUuid
+	}
+	return ""
+}
+
+func (x *BulkGetBookTotalByCategoryUUIDsResp_Data) GetBookTotal() int64 {
+	if x != nil {
+		return x.BookTotal
+	}
+	return 0
+}
+
+type BulkGetBookTotalByCategoryUUIDsResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data []*BulkGetBookTotalByCategoryUUIDsResp_Data `protobuf:"bytes,1,rep,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *BulkGetBookTotalByCategoryUUIDsResp) Reset() {
+	*x = BulkGetBookTotalByCategoryUUIDsResp{}
+	mi := &file_book_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkGetBookTotalByCategoryUUIDsResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkGetBookTotalByCategoryUUIDsResp) ProtoMessage() {}
+
+func (x *BulkGetBookTotalByCategoryUUIDsResp) ProtoReflect() protoreflect.Message {
+	mi := &file_book_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkGetBookTotalByCategoryUUIDsResp.ProtoReflect.Descriptor instead.
+func (*BulkGetBookTotalByCategoryUUIDsResp) Descriptor() ([]byte, []int) {
+	return file_book_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BulkGetBookTotalByCategoryUUIDsResp) GetData() []*BulkGetBookTotalByCategoryUUIDsResp_Data {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type GetActiveBorrowTotalByAuthorUUIDReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AuthorUuid string `protobuf:"bytes,1,opt,name=author_uuid,json=authorUuid,proto3" json:"author_uuid,omitempty"`
+}
+
+func (x *GetActiveBorrowTotalByAuthorUUIDReq) Reset() {
+	*x = GetActiveBorrowTotalByAuthorUUIDReq{}
+	mi := &file_book_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetActiveBorrowTotalByAuthorUUIDReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetActiveBorrowTotalByAuthorUUIDReq) ProtoMessage() {}
+
+func (x *GetActiveBorrowTotalByAuthorUUIDReq) ProtoReflect() protoreflect.Message {
+	mi := &file_book_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetActiveBorrowTotalByAuthorUUIDReq.ProtoReflect.Descriptor instead.
+func (*GetActiveBorrowTotalByAuthorUUIDReq) Descriptor() ([]byte, []int) {
+	return file_book_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetActiveBorrowTotalByAuthorUUIDReq) GetAuthorUuid() string {
+	if x != nil {
+		return x.AuthorUuid
+	}
+	return ""
+}
+
+type GetActiveBorrowTotalByAuthorUUIDResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ActiveBorrowTotal int64 `protobuf:"varint,1,opt,name=active_borrow_total,json=activeBorrowTotal,proto3" json:"active_borrow_total,omitempty"`
+}
+
+func (x *GetActiveBorrowTotalByAuthorUUIDResp) Reset() {
+	*x = GetActiveBorrowTotalByAuthorUUIDResp{}
+	mi := &file_book_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetActiveBorrowTotalByAuthorUUIDResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetActiveBorrowTotalByAuthorUUIDResp) ProtoMessage() {}
+
+func (x *GetActiveBorrowTotalByAuthorUUIDResp) ProtoReflect() protoreflect.Message {
+	mi := &file_book_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetActiveBorrowTotalByAuthorUUIDResp.ProtoReflect.Descriptor instead.
+func (*GetActiveBorrowTotalByAuthorUUIDResp) Descriptor() ([]byte, []int) {
+	return file_book_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetActiveBorrowTotalByAuthorUUIDResp) GetActiveBorrowTotal() int64 {
+	if x != nil {
+		return x.ActiveBorrowTotal
+	}
+	return 0
+}
+
+type ReassignBooksByAuthorUUIDReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AuthorUuid    string `protobuf:"bytes,1,opt,name=author_uuid,json=authorUuid,proto3" json:"author_uuid,omitempty"`
+	NewAuthorUuid string `protobuf:"bytes,2,opt,name=new_author_uuid,json=newAuthorUuid,proto3" json:"new_author_uuid,omitempty"`
+}
+
+func (x *ReassignBooksByAuthorUUIDReq) Reset() {
+	*x = ReassignBooksByAuthorUUIDReq{}
+	mi := &file_book_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReassignBooksByAuthorUUIDReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReassignBooksByAuthorUUIDReq) ProtoMessage() {}
+
+func (x *ReassignBooksByAuthorUUIDReq) ProtoReflect() protoreflect.Message {
+	mi := &file_book_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReassignBooksByAuthorUUIDReq.ProtoReflect.Descriptor instead.
+func (*ReassignBooksByAuthorUUIDReq) Descriptor() ([]byte, []int) {
+	return file_book_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ReassignBooksByAuthorUUIDReq) GetAuthorUuid() string {
+	if x != nil {
+		return x.AuthorUuid
+	}
+	return ""
+}
+
+func (x *ReassignBooksByAuthorUUIDReq) GetNewAuthorUuid() string {
+	if x != nil {
+		return x.NewAuthorUuid
+	}
+	return ""
+}
+
+type ReassignBooksByAuthorUUIDResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BookTotal int64 `protobuf:"varint,1,opt,name=book_total,json=bookTotal,proto3" json:"book_total,omitempty"`
+}
+
+func (x *ReassignBooksByAuthorUUIDResp) Reset() {
+	*x = ReassignBooksByAuthorUUIDResp{}
+	mi := &file_book_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReassignBooksByAuthorUUIDResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReassignBooksByAuthorUUIDResp) ProtoMessage() {}
+
+func (x *ReassignBooksByAuthorUUIDResp) ProtoReflect() protoreflect.Message {
+	mi := &file_book_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReassignBooksByAuthorUUIDResp.ProtoReflect.Descriptor instead.
+func (*ReassignBooksByAuthorUUIDResp) Descriptor() ([]byte, []int) {
+	return file_book_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ReassignBooksByAuthorUUIDResp) GetBookTotal() int64 {
+	if x != nil {
+		return x.BookTotal
+	}
+	return 0
+}
+
+type DeleteBooksByAuthorUUIDReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AuthorUuid string `protobuf:"bytes,1,opt,name=author_uuid,json=authorUuid,proto3" json:"author_uuid,omitempty"`
+}
+
+func (x *DeleteBooksByAuthorUUIDReq) Reset() {
+	*x = DeleteBooksByAuthorUUIDReq{}
+	mi := &file_book_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteBooksByAuthorUUIDReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteBooksByAuthorUUIDReq) ProtoMessage() {}
+
+func (x *DeleteBooksByAuthorUUIDReq) ProtoReflect() protoreflect.Message {
+	mi := &file_book_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteBooksByAuthorUUIDReq.ProtoReflect.Descriptor instead.
+func (*DeleteBooksByAuthorUUIDReq) Descriptor() ([]byte, []int) {
+	return file_book_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DeleteBooksByAuthorUUIDReq) GetAuthorUuid() string {
+	if x != nil {
+		return x.AuthorUuid
+	}
+	return ""
+}
+
+type DeleteBooksByAuthorUUIDResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BookTotal int64 `protobuf:"varint,1,opt,name=book_total,json=bookTotal,proto3" json:"book_total,omitempty"`
+}
+
+func (x *DeleteBooksByAuthorUUIDResp) Reset() {
+	*x = DeleteBooksByAuthorUUIDResp{}
+	mi := &file_book_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteBooksByAuthorUUIDResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteBooksByAuthorUUIDResp) ProtoMessage() {}
+
+func (x *DeleteBooksByAuthorUUIDResp) ProtoReflect() protoreflect.Message {
+	mi := &file_book_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteBooksByAuthorUUIDResp.ProtoReflect.Descriptor instead.
+func (*DeleteBooksByAuthorUUIDResp) Descriptor() ([]byte, []int) {
+	return file_book_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DeleteBooksByAuthorUUIDResp) GetBookTotal() int64 {
+	if x != nil {
+		return x.BookTotal
+	}
+	return 0
+}
+
 var File_book_proto protoreflect.FileDescriptor
 
 var file_book_proto_rawDesc = []byte{
@@ -283,25 +794,120 @@ var file_book_proto_rawDesc = []byte{
 	0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x42, 0x75, 0x6c,
 	0x6b, 0x47, 0x65, 0x74, 0x42, 0x6f, 0x6f, 0x6b, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x41,
 	0x75, 0x74, 0x68, 0x6f, 0x72, 0x55, 0x55, 0x49, 0x44, 0x73, 0x52, 0x65, 0x73, 0x70, 0x5f, 0x44,
-	0x61, 0x74, 0x61, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x32, 0x83, 0x02, 0x0a, 0x0b, 0x42, 0x6f,
-	0x6f, 0x6b, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x71, 0x0a, 0x18, 0x47, 0x65, 0x74,
+	0x61, 0x74, 0x61, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x44, 0x0a, 0x1d, 0x47, 0x65, 0x74,
+	0x42, 0x6f, 0x6f, 0x6b, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x43, 0x61, 0x74, 0x65, 0x67,
+	0x6f, 0x72, 0x79, 0x55, 0x55, 0x49, 0x44, 0x52, 0x65, 0x71, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x61,
+	0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x5f, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0c, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x55, 0x75, 0x69, 0x64, 0x22,
+	0x3f, 0x0a, 0x1e, 0x47, 0x65, 0x74, 0x42, 0x6f, 0x6f, 0x6b, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x42,
+	0x79, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x55, 0x55, 0x49, 0x44, 0x52, 0x65, 0x73,
+	0x70, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x62, 0x6f, 0x6f, 0x6b, 0x54, 0x6f, 0x74, 0x61, 0x6c,
+	0x22, 0x4b, 0x0a, 0x22, 0x42, 0x75, 0x6c, 0x6b, 0x47, 0x65, 0x74, 0x42, 0x6f, 0x6f, 0x6b, 0x54,
+	0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x55, 0x55,
+	0x49, 0x44, 0x73, 0x52, 0x65, 0x71, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f,
+	0x72, 0x79, 0x5f, 0x75, 0x75, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d,
+	0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x55, 0x75, 0x69, 0x64, 0x73, 0x22, 0x6e, 0x0a,
+	0x28, 0x42, 0x75, 0x6c, 0x6b, 0x47, 0x65, 0x74, 0x42, 0x6f, 0x6f, 0x6b, 0x54, 0x6f, 0x74, 0x61,
+	0x6c, 0x42, 0x79, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x55, 0x55, 0x49, 0x44, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x5f, 0x44, 0x61, 0x74, 0x61, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x61, 0x74,
+	0x65, 0x67, 0x6f, 0x72, 0x79, 0x5f, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0c, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x55, 0x75, 0x69, 0x64, 0x12, 0x1d,
+	0x0a, 0x0a, 0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x09, 0x62, 0x6f, 0x6f, 0x6b, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x22, 0x71, 0x0a,
+	0x23, 0x42, 0x75, 0x6c, 0x6b, 0x47, 0x65, 0x74, 0x42, 0x6f, 0x6f, 0x6b, 0x54, 0x6f, 0x74, 0x61,
+	0x6c, 0x42, 0x79, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x55, 0x55, 0x49, 0x44, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x12, 0x4a, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x36, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x42, 0x75, 0x6c, 0x6b, 0x47, 0x65, 0x74, 0x42, 0x6f, 0x6f, 0x6b, 0x54, 0x6f, 0x74,
+	0x61, 0x6c, 0x42, 0x79, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x55, 0x55, 0x49, 0x44,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x5f, 0x44, 0x61, 0x74, 0x61, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61,
+	0x22, 0x46, 0x0a, 0x23, 0x47, 0x65, 0x74, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x42, 0x6f, 0x72,
+	0x72, 0x6f, 0x77, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72,
+	0x55, 0x55, 0x49, 0x44, 0x52, 0x65, 0x71, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x75, 0x74, 0x68, 0x6f,
+	0x72, 0x5f, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x75,
+	0x74, 0x68, 0x6f, 0x72, 0x55, 0x75, 0x69, 0x64, 0x22, 0x56, 0x0a, 0x24, 0x47, 0x65, 0x74, 0x41,
+	0x63, 0x74, 0x69, 0x76, 0x65, 0x42, 0x6f, 0x72, 0x72, 0x6f, 0x77, 0x54, 0x6f, 0x74, 0x61, 0x6c,
+	0x42, 0x79, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x55, 0x55, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70,
+	0x12, 0x2e, 0x0a, 0x13, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x62, 0x6f, 0x72, 0x72, 0x6f,
+	0x77, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x61,
+	0x63, 0x74, 0x69, 0x76, 0x65, 0x42, 0x6f, 0x72, 0x72, 0x6f, 0x77, 0x54, 0x6f, 0x74, 0x61, 0x6c,
+	0x22, 0x67, 0x0a, 0x1c, 0x52, 0x65, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x42, 0x6f, 0x6f, 0x6b,
+	0x73, 0x42, 0x79, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x55, 0x55, 0x49, 0x44, 0x52, 0x65, 0x71,
+	0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x5f, 0x75, 0x75, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x55, 0x75, 0x69,
+	0x64, 0x12, 0x26, 0x0a, 0x0f, 0x6e, 0x65, 0x77, 0x5f, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x5f,
+	0x75, 0x75, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6e, 0x65, 0x77, 0x41,
+	0x75, 0x74, 0x68, 0x6f, 0x72, 0x55, 0x75, 0x69, 0x64, 0x22, 0x3e, 0x0a, 0x1d, 0x52, 0x65, 0x61,
+	0x73, 0x73, 0x69, 0x67, 0x6e, 0x42, 0x6f, 0x6f, 0x6b, 0x73, 0x42, 0x79, 0x41, 0x75, 0x74, 0x68,
+	0x6f, 0x72, 0x55, 0x55, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x6f,
+	0x6f, 0x6b, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09,
+	0x62, 0x6f, 0x6f, 0x6b, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x22, 0x3d, 0x0a, 0x1a, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x42, 0x6f, 0x6f, 0x6b, 0x73, 0x42, 0x79, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72,
+	0x55, 0x55, 0x49, 0x44, 0x52, 0x65, 0x71, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x75, 0x74, 0x68, 0x6f,
+	0x72, 0x5f, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x75,
+	0x74, 0x68, 0x6f, 0x72, 0x55, 0x75, 0x69, 0x64, 0x22, 0x3c, 0x0a, 0x1b, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x42, 0x6f, 0x6f, 0x6b, 0x73, 0x42, 0x79, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x55,
+	0x55, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x6f, 0x6f, 0x6b, 0x5f,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x62, 0x6f, 0x6f,
+	0x6b, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x32, 0xf7, 0x06, 0x0a, 0x0b, 0x42, 0x6f, 0x6f, 0x6b, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x71, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x42, 0x6f, 0x6f,
+	0x6b, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x55, 0x55,
+	0x49, 0x44, 0x12, 0x29, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x6f, 0x6f, 0x6b, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79,
+	0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x55, 0x55, 0x49, 0x44, 0x52, 0x65, 0x71, 0x1a, 0x2a, 0x2e,
+	0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x47, 0x65, 0x74,
 	0x42, 0x6f, 0x6f, 0x6b, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x41, 0x75, 0x74, 0x68, 0x6f,
-	0x72, 0x55, 0x55, 0x49, 0x44, 0x12, 0x29, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x73, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x6f, 0x6f, 0x6b, 0x54, 0x6f, 0x74, 0x61,
-	0x6c, 0x42, 0x79, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x55, 0x55, 0x49, 0x44, 0x52, 0x65, 0x71,
-	0x1a, 0x2a, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
-	0x47, 0x65, 0x74, 0x42, 0x6f, 0x6f, 0x6b, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x41, 0x75,
-	0x74, 0x68, 0x6f, 0x72, 0x55, 0x55, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x12, 0x80, 0x01, 0x0a,
-	0x1d, 0x42, 0x75, 0x6c, 0x6b, 0x47, 0x65, 0x74, 0x42, 0x6f, 0x6f, 0x6b, 0x54, 0x6f, 0x74, 0x61,
-	0x6c, 0x42, 0x79, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x55, 0x55, 0x49, 0x44, 0x73, 0x12, 0x2e,
-	0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x42, 0x75,
-	0x6c, 0x6b, 0x47, 0x65, 0x74, 0x42, 0x6f, 0x6f, 0x6b, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79,
-	0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x55, 0x55, 0x49, 0x44, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x2f,
-	0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x42, 0x75,
+	0x72, 0x55, 0x55, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x12, 0x80, 0x01, 0x0a, 0x1d, 0x42, 0x75,
 	0x6c, 0x6b, 0x47, 0x65, 0x74, 0x42, 0x6f, 0x6f, 0x6b, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79,
-	0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x55, 0x55, 0x49, 0x44, 0x73, 0x52, 0x65, 0x73, 0x70, 0x42,
-	0x0c, 0x5a, 0x0a, 0x2f, 0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x55, 0x55, 0x49, 0x44, 0x73, 0x12, 0x2e, 0x2e, 0x62, 0x6f,
+	0x6f, 0x6b, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x42, 0x75, 0x6c, 0x6b, 0x47,
+	0x65, 0x74, 0x42, 0x6f, 0x6f, 0x6b, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x41, 0x75, 0x74,
+	0x68, 0x6f, 0x72, 0x55, 0x55, 0x49, 0x44, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x2f, 0x2e, 0x62, 0x6f,
+	0x6f, 0x6b, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x42, 0x75, 0x6c, 0x6b, 0x47,
+	0x65, 0x74, 0x42, 0x6f, 0x6f, 0x6b, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x41, 0x75, 0x74,
+	0x68, 0x6f, 0x72, 0x55, 0x55, 0x49, 0x44, 0x73, 0x52, 0x65, 0x73, 0x70, 0x12, 0x77, 0x0a, 0x1a,
+	0x47, 0x65, 0x74, 0x42, 0x6f, 0x6f, 0x6b, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x43, 0x61,
+	0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x55, 0x55, 0x49, 0x44, 0x12, 0x2b, 0x2e, 0x62, 0x6f, 0x6f,
+	0x6b, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x6f, 0x6f,
+	0x6b, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79,
+	0x55, 0x55, 0x49, 0x44, 0x52, 0x65, 0x71, 0x1a, 0x2c, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x6f, 0x6f, 0x6b, 0x54, 0x6f,
+	0x74, 0x61, 0x6c, 0x42, 0x79, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x55, 0x55, 0x49,
+	0x44, 0x52, 0x65, 0x73, 0x70, 0x12, 0x86, 0x01, 0x0a, 0x1f, 0x42, 0x75, 0x6c, 0x6b, 0x47, 0x65,
+	0x74, 0x42, 0x6f, 0x6f, 0x6b, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x43, 0x61, 0x74, 0x65,
+	0x67, 0x6f, 0x72, 0x79, 0x55, 0x55, 0x49, 0x44, 0x73, 0x12, 0x30, 0x2e, 0x62, 0x6f, 0x6f, 0x6b,
+	0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x42, 0x75, 0x6c, 0x6b, 0x47, 0x65, 0x74,
+	0x42, 0x6f, 0x6f, 0x6b, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x43, 0x61, 0x74, 0x65, 0x67,
+	0x6f, 0x72, 0x79, 0x55, 0x55, 0x49, 0x44, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x31, 0x2e, 0x62, 0x6f,
+	0x6f, 0x6b, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x42, 0x75, 0x6c, 0x6b, 0x47,
+	0x65, 0x74, 0x42, 0x6f, 0x6f, 0x6b, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x43, 0x61, 0x74,
+	0x65, 0x67, 0x6f, 0x72, 0x79, 0x55, 0x55, 0x49, 0x44, 0x73, 0x52, 0x65, 0x73, 0x70, 0x12, 0x89,
+	0x01, 0x0a, 0x20, 0x47, 0x65, 0x74, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x42, 0x6f, 0x72, 0x72,
+	0x6f, 0x77, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x55,
+	0x55, 0x49, 0x44, 0x12, 0x31, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x42, 0x6f, 0x72, 0x72,
+	0x6f, 0x77, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x55,
+	0x55, 0x49, 0x44, 0x52, 0x65, 0x71, 0x1a, 0x32, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x42,
+	0x6f, 0x72, 0x72, 0x6f, 0x77, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x41, 0x75, 0x74, 0x68,
+	0x6f, 0x72, 0x55, 0x55, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x12, 0x74, 0x0a, 0x19, 0x52, 0x65,
+	0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x42, 0x6f, 0x6f, 0x6b, 0x73, 0x42, 0x79, 0x41, 0x75, 0x74,
+	0x68, 0x6f, 0x72, 0x55, 0x55, 0x49, 0x44, 0x12, 0x2a, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x52, 0x65, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x42,
+	0x6f, 0x6f, 0x6b, 0x73, 0x42, 0x79, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x55, 0x55, 0x49, 0x44,
+	0x52, 0x65, 0x71, 0x1a, 0x2b, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x52, 0x65, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x42, 0x6f, 0x6f, 0x6b, 0x73,
+	0x42, 0x79, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x55, 0x55, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70,
+	0x12, 0x6e, 0x0a, 0x17, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x6f, 0x6f, 0x6b, 0x73, 0x42,
+	0x79, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x55, 0x55, 0x49, 0x44, 0x12, 0x28, 0x2e, 0x62, 0x6f,
+	0x6f, 0x6b, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x42, 0x6f, 0x6f, 0x6b, 0x73, 0x42, 0x79, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x55, 0x55,
+	0x49, 0x44, 0x52, 0x65, 0x71, 0x1a, 0x29, 0x2e, 0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x6f, 0x6f, 0x6b, 0x73,
+	0x42, 0x79, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x55, 0x55, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70,
+	0x42, 0x0c, 0x5a, 0x0a, 0x2f, 0x62, 0x6f, 0x6f, 0x6b, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -316,25 +922,47 @@ func file_book_proto_rawDescGZIP() []byte {
 	return file_book_proto_rawDescData
 }
 
-var file_book_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_book_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_book_proto_goTypes = []any{
-	(*GetBookTotalByAuthorUUIDReq)(nil),            // 0: book_service.GetBookTotalByAuthorUUIDReq
-	(*GetBookTotalByAuthorUUIDResp)(nil),           // 1: book_service.GetBookTotalByAuthorUUIDResp
-	(*BulkGetBookTotalByAuthorUUIDsReq)(nil),       // 2: book_service.BulkGetBookTotalByAuthorUUIDsReq
-	(*BulkGetBookTotalByAuthorUUIDsResp_Data)(nil), // 3: book_service.BulkGetBookTotalByAuthorUUIDsResp_Data
-	(*BulkGetBookTotalByAuthorUUIDsResp)(nil),      // 4: book_service.BulkGetBookTotalByAuthorUUIDsResp
+	(*GetBookTotalByAuthorUUIDReq)(nil),              // 0: book_service.GetBookTotalByAuthorUUIDReq
+	(*GetBookTotalByAuthorUUIDResp)(nil),             // 1: book_service.GetBookTotalByAuthorUUIDResp
+	(*BulkGetBookTotalByAuthorUUIDsReq)(nil),         // 2: book_service.BulkGetBookTotalByAuthorUUIDsReq
+	(*BulkGetBookTotalByAuthorUUIDsResp_Data)(nil),   // 3: book_service.BulkGetBookTotalByAuthorUUIDsResp_Data
+	(*BulkGetBookTotalByAuthorUUIDsResp)(nil),        // 4: book_service.BulkGetBookTotalByAuthorUUIDsResp
+	(*GetBookTotalByCategoryUUIDReq)(nil),            // 5: book_service.GetBookTotalByCategoryUUIDReq
+	(*GetBookTotalByCategoryUUIDResp)(nil),           // 6: book_service.GetBookTotalByCategoryUUIDResp
+	(*BulkGetBookTotalByCategoryUUIDsReq)(nil),       // 7: book_service.BulkGetBookTotalByCategoryUUIDsReq
+	(*BulkGetBookTotalByCategoryUUIDsResp_Data)(nil), // 8: book_service.BulkGetBookTotalByCategoryUUIDsResp_Data
+	(*BulkGetBookTotalByCategoryUUIDsResp)(nil),      // 9: book_service.BulkGetBookTotalByCategoryUUIDsResp
+	(*GetActiveBorrowTotalByAuthorUUIDReq)(nil),      // 10: book_service.GetActiveBorrowTotalByAuthorUUIDReq
+	(*GetActiveBorrowTotalByAuthorUUIDResp)(nil),     // 11: book_service.GetActiveBorrowTotalByAuthorUUIDResp
+	(*ReassignBooksByAuthorUUIDReq)(nil),             // 12: book_service.ReassignBooksByAuthorUUIDReq
+	(*ReassignBooksByAuthorUUIDResp)(nil),            // 13: book_service.ReassignBooksByAuthorUUIDResp
+	(*DeleteBooksByAuthorUUIDReq)(nil),               // 14: book_service.DeleteBooksByAuthorUUIDReq
+	(*DeleteBooksByAuthorUUIDResp)(nil),              // 15: book_service.DeleteBooksByAuthorUUIDResp
 }
 var file_book_proto_depIdxs = []int32{
-	3, // 0: book_service.BulkGetBookTotalByAuthorUUIDsResp.data:type_name -> book_service.BulkGetBookTotalByAuthorUUIDsResp_Data
-	0, // 1: book_service.BookService.GetBookTotalByAuthorUUID:input_type -> book_service.GetBookTotalByAuthorUUIDReq
-	2, // 2: book_service.BookService.BulkGetBookTotalByAuthorUUIDs:input_type -> book_service.BulkGetBookTotalByAuthorUUIDsReq
-	1, // 3: book_service.BookService.GetBookTotalByAuthorUUID:output_type -> book_service.GetBookTotalByAuthorUUIDResp
-	4, // 4: book_service.BookService.BulkGetBookTotalByAuthorUUIDs:output_type -> book_service.BulkGetBookTotalByAuthorUUIDsResp
-	3, // [3:5] is the sub-list for method output_type
-	1, // [1:3] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	3,  // 0: book_service.BulkGetBookTotalByAuthorUUIDsResp.data:type_name -> book_service.BulkGetBookTotalByAuthorUUIDsResp_Data
+	8,  // 1: book_service.BulkGetBookTotalByCategoryUUIDsResp.data:type_name -> book_service.BulkGetBookTotalByCategoryUUIDsResp_Data
+	0,  // 2: book_service.BookService.GetBookTotalByAuthorUUID:input_type -> book_service.GetBookTotalByAuthorUUIDReq
+	2,  // 3: book_service.BookService.BulkGetBookTotalByAuthorUUIDs:input_type -> book_service.BulkGetBookTotalByAuthorUUIDsReq
+	5,  // 4: book_service.BookService.GetBookTotalByCategoryUUID:input_type -> book_service.GetBookTotalByCategoryUUIDReq
+	7,  // 5: book_service.BookService.BulkGetBookTotalByCategoryUUIDs:input_type -> book_service.BulkGetBookTotalByCategoryUUIDsReq
+	10, // 6: book_service.BookService.GetActiveBorrowTotalByAuthorUUID:input_type -> book_service.GetActiveBorrowTotalByAuthorUUIDReq
+	12, // 7: book_service.BookService.ReassignBooksByAuthorUUID:input_type -> book_service.ReassignBooksByAuthorUUIDReq
+	14, // 8: book_service.BookService.DeleteBooksByAuthorUUID:input_type -> book_service.DeleteBooksByAuthorUUIDReq
+	1,  // 9: book_service.BookService.GetBookTotalByAuthorUUID:output_type -> book_service.GetBookTotalByAuthorUUIDResp
+	4,  // 10: book_service.BookService.BulkGetBookTotalByAuthorUUIDs:output_type -> book_service.BulkGetBookTotalByAuthorUUIDsResp
+	6,  // 11: book_service.BookService.GetBookTotalByCategoryUUID:output_type -> book_service.GetBookTotalByCategoryUUIDResp
+	9,  // 12: book_service.BookService.BulkGetBookTotalByCategoryUUIDs:output_type -> book_service.BulkGetBookTotalByCategoryUUIDsResp
+	11, // 13: book_service.BookService.GetActiveBorrowTotalByAuthorUUID:output_type -> book_service.GetActiveBorrowTotalByAuthorUUIDResp
+	13, // 14: book_service.BookService.ReassignBooksByAuthorUUID:output_type -> book_service.ReassignBooksByAuthorUUIDResp
+	15, // 15: book_service.BookService.DeleteBooksByAuthorUUID:output_type -> book_service.DeleteBooksByAuthorUUIDResp
+	9,  // [9:16] is the sub-list for method output_type
+	2,  // [2:9] is the sub-list for method input_type
+	2,  // [2:2] is the sub-list for extension type_name
+	2,  // [2:2] is the sub-list for extension extendee
+	0,  // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_book_proto_init() }
@@ -348,7 +976,7 @@ func file_book_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_book_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -80,3 +80,123 @@ func (r *BookServiceHandler) BulkGetBookTotalByAuthorUUIDs(
 	}
 	return resp, nil
 }
+
+func (r *BookServiceHandler) GetBookTotalByCategoryUUID(
+	ctx context.Context,
+	in *book_grpc.GetBookTotalByCategoryUUIDReq,
+) (*book_grpc.GetBookTotalByCategoryUUIDResp, error) {
+	logger.Debugf("incoming request: %v", in)
+
+	raw, err := r.bookUcase.GetBookTotalByCategoryUUID(ctx, in.CategoryUuid)
+	if err != nil {
+		customErr, ok := err.(*error_utils.CustomErr)
+		if ok {
+			return nil, status.Errorf(customErr.GrpcCode, customErr.Error())
+		}
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	resp := &book_grpc.GetBookTotalByCategoryUUIDResp{
+		BookTotal: raw,
+	}
+	return resp, nil
+}
+
+func (r *BookServiceHandler) BulkGetBookTotalByCategoryUUIDs(
+	ctx context.Context,
+	in *book_grpc.BulkGetBookTotalByCategoryUUIDsReq,
+) (*book_grpc.BulkGetBookTotalByCategoryUUIDsResp, error) {
+	logger.Debugf("incoming request: %v", in)
+
+	if in == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	if len(in.CategoryUuids) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "category uuids are required")
+	}
+
+	payloadDto := dto.BulkGetBookTotalByCategoryUUIDsReq{
+		CategoryUUIDs: in.CategoryUuids,
+	}
+	raw, err := r.bookUcase.BulkGetBookTotalByCategoryUUIDs(ctx, payloadDto)
+	if err != nil {
+		customErr, ok := err.(*error_utils.CustomErr)
+		if ok {
+			return nil, status.Errorf(customErr.GrpcCode, customErr.Error())
+		}
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	resp := &book_grpc.BulkGetBookTotalByCategoryUUIDsResp{}
+	for _, item := range raw {
+		resp.Data = append(resp.Data, &book_grpc.BulkGetBookTotalByCategoryUUIDsResp_Data{
+			CategoryUuid: item.CategoryUUID,
+			BookTotal:    item.Total,
+		})
+	}
+	return resp, nil
+}
+
+func (r *BookServiceHandler) GetActiveBorrowTotalByAuthorUUID(
+	ctx context.Context,
+	in *book_grpc.GetActiveBorrowTotalByAuthorUUIDReq,
+) (*book_grpc.GetActiveBorrowTotalByAuthorUUIDResp, error) {
+	logger.Debugf("incoming request: %v", in)
+
+	raw, err := r.bookUcase.GetActiveBorrowTotalByAuthorUUID(ctx, in.AuthorUuid)
+	if err != nil {
+		customErr, ok := err.(*error_utils.CustomErr)
+		if ok {
+			return nil, status.Errorf(customErr.GrpcCode, customErr.Error())
+		}
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	resp := &book_grpc.GetActiveBorrowTotalByAuthorUUIDResp{
+		ActiveBorrowTotal: raw,
+	}
+	return resp, nil
+}
+
+func (r *BookServiceHandler) ReassignBooksByAuthorUUID(
+	ctx context.Context,
+	in *book_grpc.ReassignBooksByAuthorUUIDReq,
+) (*book_grpc.ReassignBooksByAuthorUUIDResp, error) {
+	logger.Debugf("incoming request: %v", in)
+
+	raw, err := r.bookUcase.ReassignBooksByAuthorUUID(ctx, in.AuthorUuid, in.NewAuthorUuid)
+	if err != nil {
+		customErr, ok := err.(*error_utils.CustomErr)
+		if ok {
+			return nil, status.Errorf(customErr.GrpcCode, customErr.Error())
+		}
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	resp := &book_grpc.ReassignBooksByAuthorUUIDResp{
+		BookTotal: raw,
+	}
+	return resp, nil
+}
+
+func (r *BookServiceHandler) DeleteBooksByAuthorUUID(
+	ctx context.Context,
+	in *book_grpc.DeleteBooksByAuthorUUIDReq,
+) (*book_grpc.DeleteBooksByAuthorUUIDResp, error) {
+	logger.Debugf("incoming request: %v", in)
+
+	raw, err := r.bookUcase.DeleteBooksByAuthorUUID(ctx, in.AuthorUuid)
+	if err != nil {
+		customErr, ok := err.(*error_utils.CustomErr)
+		if ok {
+			return nil, status.Errorf(customErr.GrpcCode, customErr.Error())
+		}
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	resp := &book_grpc.DeleteBooksByAuthorUUIDResp{
+		BookTotal: raw,
+	}
+	return resp, nil
+}
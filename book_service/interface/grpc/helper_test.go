@@ -0,0 +1,137 @@
+package grpc
+
+import (
+	"book_service/domain/dto"
+	interface_pkg "book_service/interface"
+	book_grpc "book_service/interface/grpc/genproto/book"
+	grpc_interceptor "book_service/interface/grpc/interceptor"
+	ucase "book_service/usecase"
+	error_utils "book_service/utils/error"
+	"book_service/utils/jwt/jwttest"
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeBookUcase keeps the author and category of each book in books, keyed
+// by book uuid, calling any method not listed here panics.
+type fakeBookUcase struct {
+	ucase.IBookUcase
+	books         map[string]fakeBook
+	activeBorrows map[string]int64 // by author uuid
+}
+
+type fakeBook struct {
+	authorUUID   string
+	categoryUUID string
+}
+
+func (u *fakeBookUcase) countBooks(match func(book fakeBook) bool) int64 {
+	var total int64
+	for _, book := range u.books {
+		if match(book) {
+			total++
+		}
+	}
+	return total
+}
+
+func (u *fakeBookUcase) GetBookTotalByAuthorUUID(ctx context.Context, authorUUID string) (int64, error) {
+	return u.countBooks(func(book fakeBook) bool { return book.authorUUID == authorUUID }), nil
+}
+
+func (u *fakeBookUcase) BulkGetBookTotalByAuthorUUIDs(ctx context.Context, payload dto.BulkGetBookTotalByAuthorUUIDsReq) ([]dto.BulkGetBookTotalByAuthorUUIDsRespDataItem, error) {
+	items := []dto.BulkGetBookTotalByAuthorUUIDsRespDataItem{}
+	for _, authorUUID := range payload.AuthorUUIDs {
+		total, _ := u.GetBookTotalByAuthorUUID(ctx, authorUUID)
+		items = append(items, dto.BulkGetBookTotalByAuthorUUIDsRespDataItem{AuthorUUID: authorUUID, Total: total})
+	}
+	return items, nil
+}
+
+func (u *fakeBookUcase) GetBookTotalByCategoryUUID(ctx context.Context, categoryUUID string) (int64, error) {
+	return u.countBooks(func(book fakeBook) bool { return book.categoryUUID == categoryUUID }), nil
+}
+
+func (u *fakeBookUcase) BulkGetBookTotalByCategoryUUIDs(ctx context.Context, payload dto.BulkGetBookTotalByCategoryUUIDsReq) ([]dto.BulkGetBookTotalByCategoryUUIDsRespDataItem, error) {
+	items := []dto.BulkGetBookTotalByCategoryUUIDsRespDataItem{}
+	for _, categoryUUID := range payload.CategoryUUIDs {
+		total, _ := u.GetBookTotalByCategoryUUID(ctx, categoryUUID)
+		items = append(items, dto.BulkGetBookTotalByCategoryUUIDsRespDataItem{CategoryUUID: categoryUUID, Total: total})
+	}
+	return items, nil
+}
+
+func (u *fakeBookUcase) GetActiveBorrowTotalByAuthorUUID(ctx context.Context, authorUUID string) (int64, error) {
+	return u.activeBorrows[authorUUID], nil
+}
+
+func (u *fakeBookUcase) ReassignBooksByAuthorUUID(ctx context.Context, authorUUID string, newAuthorUUID string) (int64, error) {
+	if u.activeBorrows[authorUUID] > 0 {
+		return 0, &error_utils.CustomErr{HttpCode: 409, GrpcCode: codes.FailedPrecondition, Message: "author has active borrows"}
+	}
+	var total int64
+	for bookUUID, book := range u.books {
+		if book.authorUUID == authorUUID {
+			book.authorUUID = newAuthorUUID
+			u.books[bookUUID] = book
+			total++
+		}
+	}
+	return total, nil
+}
+
+func (u *fakeBookUcase) DeleteBooksByAuthorUUID(ctx context.Context, authorUUID string) (int64, error) {
+	var total int64
+	for bookUUID, book := range u.books {
+		if book.authorUUID == authorUUID {
+			delete(u.books, bookUUID)
+			total++
+		}
+	}
+	return total, nil
+}
+
+// newTestConn serves bookUcase on an in-memory listener. calls are signed with
+// a service token of author_service unless anonymous is set.
+func newTestConn(t *testing.T, bookUcase ucase.IBookUcase, anonymous bool) *grpc.ClientConn {
+	signer := jwttest.NewSigner(t)
+
+	lis := bufconn.Listen(1024 * 1024)
+	grpcServer := NewServer(interface_pkg.CommonDependency{
+		BookUcase:    bookUcase,
+		KeySet:       signer.KeySet,
+		ClaimsConfig: jwttest.ClaimsConfig,
+	})
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+
+	var tokenSource *grpc_interceptor.ServiceTokenSource
+	if !anonymous {
+		tokenSource = grpc_interceptor.NewServiceTokenSource(signer.ServiceToken("author_service"))
+	}
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(grpc_interceptor.IdentityUnaryClientInterceptor(tokenSource)),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial bufnet: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func newTestClient(t *testing.T, bookUcase ucase.IBookUcase, anonymous bool) book_grpc.BookServiceClient {
+	return book_grpc.NewBookServiceClient(newTestConn(t, bookUcase, anonymous))
+}
@@ -2,7 +2,13 @@ package grpc
 
 import (
 	"book_service/config"
+	"book_service/domain/dto"
 	interface_pkg "book_service/interface"
+	book_grpc "book_service/interface/grpc/genproto/book"
+	"book_service/interface/grpc/handler"
+	grpc_interceptor "book_service/interface/grpc/interceptor"
+	jwt_util "book_service/utils/jwt"
+	"context"
 	"fmt"
 	"log"
 	"net"
@@ -13,6 +19,39 @@ import (
 
 var logger = logging.MustGetLogger("main")
 
+// NewServer creates the grpc server with the service handler registered.
+// every rpc requires a service credential or a forwarded access token.
+func NewServer(commonDependencies interface_pkg.CommonDependency, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts, grpc.ChainUnaryInterceptor(
+		grpc_interceptor.RecoveryUnaryInterceptor(),
+		grpc_interceptor.AuthUnaryInterceptor(serverAuthConfig(commonDependencies)),
+	))
+	grpcServer := grpc.NewServer(opts...)
+
+	// register service handler
+	bookServiceHandler := handler.NewBookServiceHandler(commonDependencies.BookUcase)
+	book_grpc.RegisterBookServiceServer(grpcServer, bookServiceHandler)
+
+	return grpcServer
+}
+
+// serverAuthConfig verifies service tokens and access tokens locally with the
+// key set of auth_service.
+func serverAuthConfig(commonDependencies interface_pkg.CommonDependency) grpc_interceptor.ServerAuthConfig {
+	return grpc_interceptor.ServerAuthConfig{
+		VerifyServiceToken: func(ctx context.Context, token string) (string, error) {
+			return jwt_util.ValidateServiceToken(token, commonDependencies.KeySet, commonDependencies.ClaimsConfig.Issuer)
+		},
+		VerifyUserToken: func(ctx context.Context, token string) (*dto.CurrentUser, error) {
+			return jwt_util.ValidateJWT(token, commonDependencies.KeySet, commonDependencies.ClaimsConfig)
+		},
+		ServiceOnlyMethods: []string{
+			book_grpc.BookService_ReassignBooksByAuthorUUID_FullMethodName,
+			book_grpc.BookService_DeleteBooksByAuthorUUID_FullMethodName,
+		},
+	}
+}
+
 func SetupServer(commonDependencies interface_pkg.CommonDependency) {
 	// setup listener
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%v", config.Envs.GRPC_PORT))
@@ -21,9 +60,7 @@ func SetupServer(commonDependencies interface_pkg.CommonDependency) {
 	}
 
 	// new grpc server
-	grpcServer := grpc.NewServer()
-
-	// register service handler
+	grpcServer := NewServer(commonDependencies, config.NewGrpcServerOptions()...)
 
 	// Start the server
 	fmt.Printf("Starting gRPC server on port :%v...", config.Envs.GRPC_PORT)
@@ -0,0 +1,158 @@
+package grpc
+
+import (
+	book_grpc "book_service/interface/grpc/genproto/book"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// TestServer_ServesEveryRPC calls every rpc declared in book.proto with a
+// service token. the fake ucase implements none of them, so calls that reach
+// a handler fail with Internal, but never with Unimplemented.
+func TestServer_ServesEveryRPC(t *testing.T) {
+	conn := newTestConn(t, &fakeBookUcase{}, false)
+
+	services := book_grpc.File_book_proto.Services()
+	if services.Len() == 0 {
+		t.Fatal("no services declared in book.proto")
+	}
+	for i := 0; i < services.Len(); i++ {
+		service := services.Get(i)
+		methods := service.Methods()
+		for j := 0; j < methods.Len(); j++ {
+			method := methods.Get(j)
+			fullMethod := fmt.Sprintf("/%s/%s", service.FullName(), method.Name())
+			t.Run(fullMethod, func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+
+				req := dynamicpb.NewMessage(method.Input())
+				resp := dynamicpb.NewMessage(method.Output())
+				err := conn.Invoke(ctx, fullMethod, req, resp)
+				if status.Code(err) == codes.Unimplemented {
+					t.Errorf("%s is not served: %v", fullMethod, err)
+				}
+				if status.Code(err) == codes.Unauthenticated {
+					t.Errorf("%s rejected the service token: %v", fullMethod, err)
+				}
+			})
+		}
+	}
+}
+
+func TestServer_BookTotals(t *testing.T) {
+	authorUUID, otherAuthorUUID := uuid.New().String(), uuid.New().String()
+	categoryUUID := uuid.New().String()
+	client := newTestClient(t, &fakeBookUcase{books: map[string]fakeBook{
+		uuid.New().String(): {authorUUID: authorUUID, categoryUUID: categoryUUID},
+		uuid.New().String(): {authorUUID: authorUUID},
+		uuid.New().String(): {authorUUID: otherAuthorUUID, categoryUUID: categoryUUID},
+	}}, false)
+	ctx := context.Background()
+
+	authorResp, err := client.GetBookTotalByAuthorUUID(ctx, &book_grpc.GetBookTotalByAuthorUUIDReq{AuthorUuid: authorUUID})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if authorResp.BookTotal != 2 {
+		t.Errorf("expected 2 books of the author, got %d", authorResp.BookTotal)
+	}
+
+	categoryResp, err := client.GetBookTotalByCategoryUUID(ctx, &book_grpc.GetBookTotalByCategoryUUIDReq{CategoryUuid: categoryUUID})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if categoryResp.BookTotal != 2 {
+		t.Errorf("expected 2 books in the category, got %d", categoryResp.BookTotal)
+	}
+
+	bulkAuthorResp, err := client.BulkGetBookTotalByAuthorUUIDs(ctx, &book_grpc.BulkGetBookTotalByAuthorUUIDsReq{
+		AuthorUuids: []string{authorUUID, otherAuthorUUID},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(bulkAuthorResp.Data) != 2 ||
+		bulkAuthorResp.Data[0].AuthorUuid != authorUUID || bulkAuthorResp.Data[0].BookTotal != 2 ||
+		bulkAuthorResp.Data[1].AuthorUuid != otherAuthorUUID || bulkAuthorResp.Data[1].BookTotal != 1 {
+		t.Errorf("unexpected response: %v", bulkAuthorResp)
+	}
+
+	bulkCategoryResp, err := client.BulkGetBookTotalByCategoryUUIDs(ctx, &book_grpc.BulkGetBookTotalByCategoryUUIDsReq{
+		CategoryUuids: []string{categoryUUID},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(bulkCategoryResp.Data) != 1 || bulkCategoryResp.Data[0].CategoryUuid != categoryUUID || bulkCategoryResp.Data[0].BookTotal != 2 {
+		t.Errorf("unexpected response: %v", bulkCategoryResp)
+	}
+
+	_, err = client.BulkGetBookTotalByAuthorUUIDs(ctx, &book_grpc.BulkGetBookTotalByAuthorUUIDsReq{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument without author uuids, got %v", err)
+	}
+}
+
+// TestServer_AuthorDeletionRPCs walks the calls author_service makes when it
+// deletes an author.
+func TestServer_AuthorDeletionRPCs(t *testing.T) {
+	borrowedAuthorUUID, authorUUID := uuid.New().String(), uuid.New().String()
+	placeholderUUID := uuid.New().String()
+	bookUcase := &fakeBookUcase{
+		books: map[string]fakeBook{
+			uuid.New().String(): {authorUUID: borrowedAuthorUUID},
+			uuid.New().String(): {authorUUID: authorUUID},
+			uuid.New().String(): {authorUUID: authorUUID},
+		},
+		activeBorrows: map[string]int64{borrowedAuthorUUID: 1},
+	}
+	client := newTestClient(t, bookUcase, false)
+	ctx := context.Background()
+
+	borrowResp, err := client.GetActiveBorrowTotalByAuthorUUID(ctx, &book_grpc.GetActiveBorrowTotalByAuthorUUIDReq{AuthorUuid: borrowedAuthorUUID})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if borrowResp.ActiveBorrowTotal != 1 {
+		t.Errorf("expected 1 active borrow, got %d", borrowResp.ActiveBorrowTotal)
+	}
+
+	// the ucase error code is kept
+	_, err = client.ReassignBooksByAuthorUUID(ctx, &book_grpc.ReassignBooksByAuthorUUIDReq{AuthorUuid: borrowedAuthorUUID, NewAuthorUuid: placeholderUUID})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected FailedPrecondition with active borrows, got %v", err)
+	}
+
+	reassignResp, err := client.ReassignBooksByAuthorUUID(ctx, &book_grpc.ReassignBooksByAuthorUUIDReq{AuthorUuid: authorUUID, NewAuthorUuid: placeholderUUID})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if reassignResp.BookTotal != 2 {
+		t.Errorf("expected 2 books reassigned, got %d", reassignResp.BookTotal)
+	}
+
+	deleteResp, err := client.DeleteBooksByAuthorUUID(ctx, &book_grpc.DeleteBooksByAuthorUUIDReq{AuthorUuid: placeholderUUID})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if deleteResp.BookTotal != 2 || len(bookUcase.books) != 1 {
+		t.Errorf("expected 2 books deleted and 1 left, got %d deleted and %d left", deleteResp.BookTotal, len(bookUcase.books))
+	}
+}
+
+func TestServer_RequiresCredentials(t *testing.T) {
+	client := newTestClient(t, &fakeBookUcase{}, true)
+
+	_, err := client.GetBookTotalByAuthorUUID(context.Background(), &book_grpc.GetBookTotalByAuthorUUIDReq{AuthorUuid: uuid.New().String()})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated, got %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package rest_handler
+
+import (
+	"book_service/domain/dto"
+	ucase "book_service/usecase"
+	"book_service/utils/helper"
+	"book_service/utils/http_response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BookBorrowHandler struct {
+	bookBorrowUcase ucase.IBookBorrowUcase
+	respWriter      http_response.IHttpResponseWriter
+}
+
+type IBookBorrowHandler interface {
+	BorrowBook(ctx *gin.Context)
+	ReturnBook(ctx *gin.Context)
+	RenewBook(ctx *gin.Context)
+	GetBorrowList(ctx *gin.Context)
+}
+
+func NewBookBorrowHandler(
+	bookBorrowUcase ucase.IBookBorrowUcase,
+	respWriter http_response.IHttpResponseWriter,
+) IBookBorrowHandler {
+	return &BookBorrowHandler{
+		bookBorrowUcase: bookBorrowUcase,
+		respWriter:      respWriter,
+	}
+}
+
+// @Summary Borrow book
+// @Router /books/{book_uuid}/borrow [post]
+// @Tags Borrows
+// @Success 200 {object} dto.BaseJSONResp{data=dto.BorrowBookRespData}
+// @Security BearerAuth
+func (handler *BookBorrowHandler) BorrowBook(ctx *gin.Context) {
+	bookUUID := ctx.Param("book_uuid")
+
+	currentUser, err := helper.GetCurrentUserFromGinCtx(ctx)
+	if err != nil {
+		handler.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	data, err := handler.bookBorrowUcase.BorrowBook(ctx, *currentUser, bookUUID)
+	if err != nil {
+		handler.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	handler.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// @Summary Return borrowed book
+// @Router /borrows/{borrow_uuid}/return [post]
+// @Tags Borrows
+// @Success 200 {object} dto.BaseJSONResp{data=dto.ReturnBookRespData}
+// @Security BearerAuth
+func (handler *BookBorrowHandler) ReturnBook(ctx *gin.Context) {
+	borrowUUID := ctx.Param("borrow_uuid")
+
+	currentUser, err := helper.GetCurrentUserFromGinCtx(ctx)
+	if err != nil {
+		handler.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	data, err := handler.bookBorrowUcase.ReturnBook(ctx, *currentUser, borrowUUID)
+	if err != nil {
+		handler.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	handler.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// @Summary Renew borrowed book
+// @Router /borrows/{borrow_uuid}/renew [post]
+// @Tags Borrows
+// @Success 200 {object} dto.BaseJSONResp{data=dto.RenewBookRespData}
+// @Security BearerAuth
+func (handler *BookBorrowHandler) RenewBook(ctx *gin.Context) {
+	borrowUUID := ctx.Param("borrow_uuid")
+
+	currentUser, err := helper.GetCurrentUserFromGinCtx(ctx)
+	if err != nil {
+		handler.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	data, err := handler.bookBorrowUcase.RenewBook(ctx, *currentUser, borrowUUID)
+	if err != nil {
+		handler.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	handler.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// @Summary Get borrow list
+// @Description non admin users only get their own borrows
+// @Router /borrows [get]
+// @Tags Borrows
+// @Param query query dto.GetBorrowListReq true "query"
+// @Success 200 {object} dto.BaseJSONResp{data=dto.GetBorrowListRespData}
+// @Security BearerAuth
+func (handler *BookBorrowHandler) GetBorrowList(ctx *gin.Context) {
+	var queries dto.GetBorrowListReq
+	if err := ctx.ShouldBindQuery(&queries); err != nil {
+		logger.Errorf("invalid query: %v", err)
+		handler.respWriter.HTTPJson(ctx, 400, "invalid query", err.Error(), nil)
+		return
+	}
+
+	currentUser, err := helper.GetCurrentUserFromGinCtx(ctx)
+	if err != nil {
+		handler.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	data, err := handler.bookBorrowUcase.GetBorrowList(ctx, *currentUser, queries)
+	if err != nil {
+		handler.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	handler.respWriter.HTTPJsonOK(ctx, data)
+}
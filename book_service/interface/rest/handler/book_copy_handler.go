@@ -0,0 +1,124 @@
+package rest_handler
+
+import (
+	"book_service/domain/dto"
+	ucase "book_service/usecase"
+	"book_service/utils/helper"
+	"book_service/utils/http_response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BookCopyHandler struct {
+	bookCopyUcase ucase.IBookCopyUcase
+	respWriter    http_response.IHttpResponseWriter
+}
+
+type IBookCopyHandler interface {
+	CreateBookCopy(ctx *gin.Context)
+	PatchBookCopy(ctx *gin.Context)
+	GetBookCopyList(ctx *gin.Context)
+}
+
+func NewBookCopyHandler(
+	bookCopyUcase ucase.IBookCopyUcase,
+	respWriter http_response.IHttpResponseWriter,
+) IBookCopyHandler {
+	return &BookCopyHandler{
+		bookCopyUcase: bookCopyUcase,
+		respWriter:    respWriter,
+	}
+}
+
+// @Summary Add book copy (admin only)
+// @Router /books/{book_uuid}/copies [post]
+// @Tags Copies
+// @Param payload body dto.CreateBookCopyReq true "payload"
+// @Success 200 {object} dto.BaseJSONResp{data=dto.CreateBookCopyRespData}
+// @Security BearerAuth
+func (handler *BookCopyHandler) CreateBookCopy(ctx *gin.Context) {
+	bookUUID := ctx.Param("book_uuid")
+
+	var payload dto.CreateBookCopyReq
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		logger.Errorf("invalid payload: %v", err)
+		handler.respWriter.HTTPJson(ctx, 400, "invalid payload", err.Error(), nil)
+		return
+	}
+
+	currentUser, err := helper.GetCurrentUserFromGinCtx(ctx)
+	if err != nil {
+		handler.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	data, err := handler.bookCopyUcase.CreateBookCopy(ctx, *currentUser, bookUUID, payload)
+	if err != nil {
+		handler.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	handler.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// @Summary Patch book copy condition or status (admin only)
+// @Router /copies/{copy_uuid} [patch]
+// @Tags Copies
+// @Param payload body dto.PatchBookCopyReq true "payload"
+// @Success 200 {object} dto.BaseJSONResp{data=dto.PatchBookCopyRespData}
+// @Security BearerAuth
+func (handler *BookCopyHandler) PatchBookCopy(ctx *gin.Context) {
+	copyUUID := ctx.Param("copy_uuid")
+
+	var payload dto.PatchBookCopyReq
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		logger.Errorf("invalid payload: %v", err)
+		handler.respWriter.HTTPJson(ctx, 400, "invalid payload", err.Error(), nil)
+		return
+	}
+
+	currentUser, err := helper.GetCurrentUserFromGinCtx(ctx)
+	if err != nil {
+		handler.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	data, err := handler.bookCopyUcase.PatchBookCopy(ctx, *currentUser, copyUUID, payload)
+	if err != nil {
+		handler.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	handler.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// @Summary Get book copy list (admin only)
+// @Router /books/{book_uuid}/copies [get]
+// @Tags Copies
+// @Param query query dto.GetBookCopyListReq true "query"
+// @Success 200 {object} dto.BaseJSONResp{data=dto.GetBookCopyListRespData}
+// @Security BearerAuth
+func (handler *BookCopyHandler) GetBookCopyList(ctx *gin.Context) {
+	bookUUID := ctx.Param("book_uuid")
+
+	var queries dto.GetBookCopyListReq
+	if err := ctx.ShouldBindQuery(&queries); err != nil {
+		logger.Errorf("invalid query: %v", err)
+		handler.respWriter.HTTPJson(ctx, 400, "invalid query", err.Error(), nil)
+		return
+	}
+
+	currentUser, err := helper.GetCurrentUserFromGinCtx(ctx)
+	if err != nil {
+		handler.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	data, err := handler.bookCopyUcase.GetBookCopyList(ctx, *currentUser, bookUUID, queries)
+	if err != nil {
+		handler.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	handler.respWriter.HTTPJsonOK(ctx, data)
+}
@@ -21,6 +21,8 @@ type IBookHandler interface {
 	Create(ctx *gin.Context)
 	PatchBook(ctx *gin.Context)
 	DeleteBook(ctx *gin.Context)
+	GetBookList(ctx *gin.Context)
+	GetBookDetail(ctx *gin.Context)
 }
 
 func NewBookHandler(
@@ -121,3 +123,41 @@ func (handler *BookHandler) DeleteBook(
 
 	handler.respWriter.HTTPJsonOK(ctx, data)
 }
+
+// @Summary Get book list
+// @Router /books [get]
+// @Tags Books
+// @Param query query dto.GetBookListReq true "query"
+// @Success 200 {object} dto.BaseJSONResp{data=dto.GetBookListRespData}
+func (handler *BookHandler) GetBookList(ctx *gin.Context) {
+	var queries dto.GetBookListReq
+	if err := ctx.ShouldBindQuery(&queries); err != nil {
+		logger.Errorf("invalid query: %v", err)
+		handler.respWriter.HTTPJson(ctx, 400, "invalid query", err.Error(), nil)
+		return
+	}
+
+	data, err := handler.bookUcase.GetBookList(ctx, queries)
+	if err != nil {
+		handler.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	handler.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// @Summary Get book detail
+// @Router /books/{book_uuid} [get]
+// @Tags Books
+// @Success 200 {object} dto.BaseJSONResp{data=dto.GetBookDetailRespData}
+func (handler *BookHandler) GetBookDetail(ctx *gin.Context) {
+	bookUUID := ctx.Param("book_uuid")
+
+	data, err := handler.bookUcase.GetBookDetail(ctx, bookUUID)
+	if err != nil {
+		handler.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	handler.respWriter.HTTPJsonOK(ctx, data)
+}
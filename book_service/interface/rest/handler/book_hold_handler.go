@@ -0,0 +1,107 @@
+package rest_handler
+
+import (
+	"book_service/domain/dto"
+	ucase "book_service/usecase"
+	"book_service/utils/helper"
+	"book_service/utils/http_response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BookHoldHandler struct {
+	bookHoldUcase ucase.IBookHoldUcase
+	respWriter    http_response.IHttpResponseWriter
+}
+
+type IBookHoldHandler interface {
+	PlaceHold(ctx *gin.Context)
+	CancelHold(ctx *gin.Context)
+	GetHoldList(ctx *gin.Context)
+}
+
+func NewBookHoldHandler(
+	bookHoldUcase ucase.IBookHoldUcase,
+	respWriter http_response.IHttpResponseWriter,
+) IBookHoldHandler {
+	return &BookHoldHandler{
+		bookHoldUcase: bookHoldUcase,
+		respWriter:    respWriter,
+	}
+}
+
+// @Summary Place hold on out of stock book
+// @Router /books/{book_uuid}/holds [post]
+// @Tags Holds
+// @Success 200 {object} dto.BaseJSONResp{data=dto.PlaceHoldRespData}
+// @Security BearerAuth
+func (handler *BookHoldHandler) PlaceHold(ctx *gin.Context) {
+	bookUUID := ctx.Param("book_uuid")
+
+	currentUser, err := helper.GetCurrentUserFromGinCtx(ctx)
+	if err != nil {
+		handler.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	data, err := handler.bookHoldUcase.PlaceHold(ctx, *currentUser, bookUUID)
+	if err != nil {
+		handler.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	handler.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// @Summary Cancel hold
+// @Router /holds/{hold_uuid}/cancel [post]
+// @Tags Holds
+// @Success 200 {object} dto.BaseJSONResp{data=dto.CancelHoldRespData}
+// @Security BearerAuth
+func (handler *BookHoldHandler) CancelHold(ctx *gin.Context) {
+	holdUUID := ctx.Param("hold_uuid")
+
+	currentUser, err := helper.GetCurrentUserFromGinCtx(ctx)
+	if err != nil {
+		handler.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	data, err := handler.bookHoldUcase.CancelHold(ctx, *currentUser, holdUUID)
+	if err != nil {
+		handler.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	handler.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// @Summary Get hold list
+// @Description non admin users only get their own holds
+// @Router /holds [get]
+// @Tags Holds
+// @Param query query dto.GetHoldListReq true "query"
+// @Success 200 {object} dto.BaseJSONResp{data=dto.GetHoldListRespData}
+// @Security BearerAuth
+func (handler *BookHoldHandler) GetHoldList(ctx *gin.Context) {
+	var queries dto.GetHoldListReq
+	if err := ctx.ShouldBindQuery(&queries); err != nil {
+		logger.Errorf("invalid query: %v", err)
+		handler.respWriter.HTTPJson(ctx, 400, "invalid query", err.Error(), nil)
+		return
+	}
+
+	currentUser, err := helper.GetCurrentUserFromGinCtx(ctx)
+	if err != nil {
+		handler.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	data, err := handler.bookHoldUcase.GetHoldList(ctx, *currentUser, queries)
+	if err != nil {
+		handler.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	handler.respWriter.HTTPJsonOK(ctx, data)
+}
@@ -0,0 +1,118 @@
+package rest_handler
+
+import (
+	"book_service/domain/dto"
+	ucase "book_service/usecase"
+	"book_service/utils/helper"
+	"book_service/utils/http_response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FineHandler struct {
+	fineUcase  ucase.IFineUcase
+	respWriter http_response.IHttpResponseWriter
+}
+
+type IFineHandler interface {
+	GetMyFines(ctx *gin.Context)
+	WaiveFine(ctx *gin.Context)
+	RecordFinePayment(ctx *gin.Context)
+}
+
+func NewFineHandler(
+	fineUcase ucase.IFineUcase,
+	respWriter http_response.IHttpResponseWriter,
+) IFineHandler {
+	return &FineHandler{
+		fineUcase:  fineUcase,
+		respWriter: respWriter,
+	}
+}
+
+// @Summary Get my fines ledger and outstanding balance
+// @Router /me/fines [get]
+// @Tags Fines
+// @Param query query dto.GetMyFinesReq true "query"
+// @Success 200 {object} dto.BaseJSONResp{data=dto.GetMyFinesRespData}
+// @Security BearerAuth
+func (handler *FineHandler) GetMyFines(ctx *gin.Context) {
+	var queries dto.GetMyFinesReq
+	if err := ctx.ShouldBindQuery(&queries); err != nil {
+		logger.Errorf("invalid query: %v", err)
+		handler.respWriter.HTTPJson(ctx, 400, "invalid query", err.Error(), nil)
+		return
+	}
+
+	currentUser, err := helper.GetCurrentUserFromGinCtx(ctx)
+	if err != nil {
+		handler.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	data, err := handler.fineUcase.GetMyFines(ctx, *currentUser, queries)
+	if err != nil {
+		handler.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	handler.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// @Summary Waive user fine (admin only)
+// @Router /fines/waivers [post]
+// @Tags Fines
+// @Param payload body dto.FineAdjustmentReq true "payload"
+// @Success 200 {object} dto.BaseJSONResp{data=dto.FineAdjustmentRespData}
+// @Security BearerAuth
+func (handler *FineHandler) WaiveFine(ctx *gin.Context) {
+	var payload dto.FineAdjustmentReq
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		logger.Errorf("invalid payload: %v", err)
+		handler.respWriter.HTTPJson(ctx, 400, "invalid payload", err.Error(), nil)
+		return
+	}
+
+	currentUser, err := helper.GetCurrentUserFromGinCtx(ctx)
+	if err != nil {
+		handler.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	data, err := handler.fineUcase.WaiveFine(ctx, *currentUser, payload)
+	if err != nil {
+		handler.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	handler.respWriter.HTTPJsonOK(ctx, data)
+}
+
+// @Summary Record user fine payment (admin only)
+// @Router /fines/payments [post]
+// @Tags Fines
+// @Param payload body dto.FineAdjustmentReq true "payload"
+// @Success 200 {object} dto.BaseJSONResp{data=dto.FineAdjustmentRespData}
+// @Security BearerAuth
+func (handler *FineHandler) RecordFinePayment(ctx *gin.Context) {
+	var payload dto.FineAdjustmentReq
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		logger.Errorf("invalid payload: %v", err)
+		handler.respWriter.HTTPJson(ctx, 400, "invalid payload", err.Error(), nil)
+		return
+	}
+
+	currentUser, err := helper.GetCurrentUserFromGinCtx(ctx)
+	if err != nil {
+		handler.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	data, err := handler.fineUcase.RecordFinePayment(ctx, *currentUser, payload)
+	if err != nil {
+		handler.respWriter.HTTPCustomErr(ctx, err)
+		return
+	}
+
+	handler.respWriter.HTTPJsonOK(ctx, data)
+}
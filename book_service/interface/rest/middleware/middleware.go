@@ -1,17 +1,52 @@
 package rest_middleware
 
 import (
-	"book_service/config"
 	"book_service/domain/dto"
+	api_key_util "book_service/utils/api_key"
 	"book_service/utils/http_response"
 	jwt_util "book_service/utils/jwt"
+	"errors"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
-func AuthMiddleware(respWriter http_response.IHttpResponseWriter) gin.HandlerFunc {
+// AuthMiddleware verifies the bearer token locally. service accounts send an
+// X-API-Key header instead, checked with auth_service through apiKeyCache.
+func AuthMiddleware(respWriter http_response.IHttpResponseWriter, keySet *jwt_util.KeySet, claimsConfig jwt_util.ClaimsConfig, apiKeyCache *api_key_util.Cache) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		apiKey := c.GetHeader("X-API-Key")
+		if apiKey != "" {
+			if apiKeyCache == nil {
+				respWriter.HTTPJson(
+					c, 401, "unauthorized", "api keys are not accepted", nil,
+				)
+				c.Abort()
+				return
+			}
+
+			currentUser, err := apiKeyCache.Validate(apiKey)
+			if err != nil {
+				if errors.Is(err, api_key_util.ErrInvalidAPIKey) {
+					respWriter.HTTPJson(
+						c, 401, "unauthorized", err.Error(), nil,
+					)
+				} else {
+					respWriter.HTTPJson(
+						c, 500, "internal server error", err.Error(), nil,
+					)
+				}
+				c.Abort()
+				return
+			}
+
+			currentUser.APIKey = apiKey
+			c.Set("currentUser", *currentUser)
+			c.Next()
+			return
+		}
+
 		token := c.GetHeader("Authorization")
 		if token == "" || !strings.HasPrefix(token, "Bearer ") {
 			respWriter.HTTPJson(
@@ -23,7 +58,7 @@ func AuthMiddleware(respWriter http_response.IHttpResponseWriter) gin.HandlerFun
 
 		token = strings.TrimPrefix(token, "Bearer ")
 
-		currentUser, err := jwt_util.ValidateJWT(token, config.Envs.JWT_SECRET_KEY)
+		currentUser, err := jwt_util.ValidateJWT(token, keySet, claimsConfig)
 		if err != nil {
 			respWriter.HTTPJson(
 				c, 401, "unauthorized", err.Error(), nil,
@@ -32,18 +67,22 @@ func AuthMiddleware(respWriter http_response.IHttpResponseWriter) gin.HandlerFun
 			return
 		}
 
-		c.Set("currentUser", currentUser)
+		currentUser.AccessToken = token
+		c.Set("currentUser", *currentUser)
 		c.Next()
 	}
 }
 
-func AuthAdminOnlyMiddleware(respWriter http_response.IHttpResponseWriter) gin.HandlerFunc {
+// RequirePermission must run after AuthMiddleware, the current user needs
+// every given permission.
+func RequirePermission(respWriter http_response.IHttpResponseWriter, permissions ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		currentUserRaw, ok := c.Get("currentUser")
 		if !ok {
 			respWriter.HTTPJson(
 				c, 500, "internal service error", "current user not found", nil,
 			)
+			c.Abort()
 			return
 		}
 
@@ -52,16 +91,39 @@ func AuthAdminOnlyMiddleware(respWriter http_response.IHttpResponseWriter) gin.H
 			respWriter.HTTPJson(
 				c, 500, "internal service error", "current user missmatched", nil,
 			)
+			c.Abort()
 			return
 		}
 
-		if currentUser.Role != "admin" {
-			respWriter.HTTPJson(
-				c, 403, "forbidden", "admin only", nil,
-			)
-			return
+		for _, permission := range permissions {
+			if !currentUser.HasPermission(permission) {
+				respWriter.HTTPJson(
+					c, 403, "forbidden", "missing permission: "+permission, nil,
+				)
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// requestIDMaxLength bounds request ids sent by clients, they end up in every
+// log line and grpc call.
+const requestIDMaxLength = 64
+
+// RequestIDMiddleware keeps the X-Request-ID of the client or generates one.
+// it is returned in the response and forwarded on grpc calls.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader("X-Request-ID")
+		if requestID == "" || len(requestID) > requestIDMaxLength {
+			requestID = uuid.New().String()
 		}
 
+		c.Set("requestID", requestID)
+		c.Header("X-Request-ID", requestID)
 		c.Next()
 	}
 }
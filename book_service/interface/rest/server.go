@@ -20,7 +20,14 @@ import (
 var logger = logging.MustGetLogger("main")
 
 func SetupServer(commonDependencies interface_pkg.CommonDependency) {
+	router := NewRouter(commonDependencies)
+	router.Run(fmt.Sprintf("%s:%d", config.Envs.HOST, config.Envs.PORT))
+}
+
+// NewRouter registers every route on a new gin engine without running it.
+func NewRouter(commonDependencies interface_pkg.CommonDependency) *gin.Engine {
 	router := gin.Default()
+	router.Use(rest_middleware.RequestIDMiddleware())
 
 	respWriter := http_response.NewHttpResponseWriter()
 
@@ -29,10 +36,27 @@ func SetupServer(commonDependencies interface_pkg.CommonDependency) {
 		commonDependencies.BookUcase,
 		respWriter,
 	)
+	bookBorrowHandler := rest_handler.NewBookBorrowHandler(
+		commonDependencies.BookBorrowUcase,
+		respWriter,
+	)
+	fineHandler := rest_handler.NewFineHandler(
+		commonDependencies.FineUcase,
+		respWriter,
+	)
+	bookHoldHandler := rest_handler.NewBookHoldHandler(
+		commonDependencies.BookHoldUcase,
+		respWriter,
+	)
+	bookCopyHandler := rest_handler.NewBookCopyHandler(
+		commonDependencies.BookCopyUcase,
+		respWriter,
+	)
 
 	// middlewares
-	authMiddleware := rest_middleware.AuthMiddleware(respWriter)
-	// authMiddlewareAdminOnly := rest_middleware.AuthAdminOnlyMiddleware(respWriter)
+	authMiddleware := rest_middleware.AuthMiddleware(respWriter, commonDependencies.KeySet, commonDependencies.ClaimsConfig, commonDependencies.APIKeyCache)
+	requireCopyManage := rest_middleware.RequirePermission(respWriter, dto.PermissionCopyManage)
+	requireFineManage := rest_middleware.RequirePermission(respWriter, dto.PermissionFineManage)
 
 	// register routes
 	router.GET("/ping", func(c *gin.Context) {
@@ -42,6 +66,16 @@ func SetupServer(commonDependencies interface_pkg.CommonDependency) {
 		})
 	})
 
+	// public
+	{
+		// /books
+		publicBookRouter := router.Group("/books")
+		{
+			publicBookRouter.GET("", bookHandler.GetBookList)
+			publicBookRouter.GET("/:book_uuid", bookHandler.GetBookDetail)
+		}
+	}
+
 	secureRouter := router.Group("")
 	secureRouter.Use(authMiddleware)
 	// secured
@@ -52,6 +86,44 @@ func SetupServer(commonDependencies interface_pkg.CommonDependency) {
 			bookRouter.POST("", bookHandler.Create)
 			bookRouter.PATCH("/:book_uuid", bookHandler.PatchBook)
 			bookRouter.DELETE("/:book_uuid", bookHandler.DeleteBook)
+			bookRouter.POST("/:book_uuid/borrow", bookBorrowHandler.BorrowBook)
+			bookRouter.POST("/:book_uuid/holds", bookHoldHandler.PlaceHold)
+			bookRouter.GET("/:book_uuid/copies", requireCopyManage, bookCopyHandler.GetBookCopyList)
+			bookRouter.POST("/:book_uuid/copies", requireCopyManage, bookCopyHandler.CreateBookCopy)
+		}
+
+		// /copies
+		copyRouter := secureRouter.Group("/copies", requireCopyManage)
+		{
+			copyRouter.PATCH("/:copy_uuid", bookCopyHandler.PatchBookCopy)
+		}
+
+		// /borrows
+		borrowRouter := secureRouter.Group("/borrows")
+		{
+			borrowRouter.GET("", bookBorrowHandler.GetBorrowList)
+			borrowRouter.POST("/:borrow_uuid/return", bookBorrowHandler.ReturnBook)
+			borrowRouter.POST("/:borrow_uuid/renew", bookBorrowHandler.RenewBook)
+		}
+
+		// /holds
+		holdRouter := secureRouter.Group("/holds")
+		{
+			holdRouter.GET("", bookHoldHandler.GetHoldList)
+			holdRouter.POST("/:hold_uuid/cancel", bookHoldHandler.CancelHold)
+		}
+
+		// /me
+		meRouter := secureRouter.Group("/me")
+		{
+			meRouter.GET("/fines", fineHandler.GetMyFines)
+		}
+
+		// /fines
+		fineRouter := secureRouter.Group("/fines", requireFineManage)
+		{
+			fineRouter.POST("/waivers", fineHandler.WaiveFine)
+			fineRouter.POST("/payments", fineHandler.RecordFinePayment)
 		}
 	}
 
@@ -61,5 +133,5 @@ func SetupServer(commonDependencies interface_pkg.CommonDependency) {
 		ctx.Redirect(302, "/swagger/index.html")
 	})
 
-	router.Run(fmt.Sprintf("%s:%d", config.Envs.HOST, config.Envs.PORT))
+	return router
 }
@@ -0,0 +1,301 @@
+package rest
+
+import (
+	"book_service/domain/dto"
+	interface_pkg "book_service/interface"
+	ucase "book_service/usecase"
+	api_key_util "book_service/utils/api_key"
+	"book_service/utils/jwt/jwttest"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+)
+
+// fakeFineUcase records the method reached through the router, calling
+// any method not listed here panics.
+type fakeFineUcase struct {
+	ucase.IFineUcase
+	called string
+}
+
+func (u *fakeFineUcase) WaiveFine(ctx context.Context, currentUser dto.CurrentUser, payload dto.FineAdjustmentReq) (*dto.FineAdjustmentRespData, error) {
+	u.called = "WaiveFine"
+	return &dto.FineAdjustmentRespData{}, nil
+}
+
+func (u *fakeFineUcase) RecordFinePayment(ctx context.Context, currentUser dto.CurrentUser, payload dto.FineAdjustmentReq) (*dto.FineAdjustmentRespData, error) {
+	u.called = "RecordFinePayment"
+	return &dto.FineAdjustmentRespData{}, nil
+}
+
+// fakeBookCopyUcase records the method reached through the router, calling
+// any method not listed here panics.
+type fakeBookCopyUcase struct {
+	ucase.IBookCopyUcase
+	called string
+}
+
+func (u *fakeBookCopyUcase) CreateBookCopy(ctx context.Context, currentUser dto.CurrentUser, bookUUID string, payload dto.CreateBookCopyReq) (*dto.CreateBookCopyRespData, error) {
+	u.called = "CreateBookCopy"
+	return &dto.CreateBookCopyRespData{}, nil
+}
+
+func (u *fakeBookCopyUcase) PatchBookCopy(ctx context.Context, currentUser dto.CurrentUser, copyUUID string, payload dto.PatchBookCopyReq) (*dto.PatchBookCopyRespData, error) {
+	u.called = "PatchBookCopy"
+	return &dto.PatchBookCopyRespData{}, nil
+}
+
+func (u *fakeBookCopyUcase) GetBookCopyList(ctx context.Context, currentUser dto.CurrentUser, bookUUID string, params dto.GetBookCopyListReq) (*dto.GetBookCopyListRespData, error) {
+	u.called = "GetBookCopyList"
+	return &dto.GetBookCopyListRespData{}, nil
+}
+
+// fakeBookUcase records the method reached through the router, calling any
+// method not listed here panics.
+type fakeBookUcase struct {
+	ucase.IBookUcase
+	called string
+}
+
+func (u *fakeBookUcase) GetBookList(ctx context.Context, params dto.GetBookListReq) (*dto.GetBookListRespData, error) {
+	u.called = "GetBookList"
+	return &dto.GetBookListRespData{}, nil
+}
+
+// TestRouter_Tokens sends signed tokens through the real router to
+// POST /fines/waivers, which requires a permission rather than a role.
+func TestRouter_Tokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	signer := jwttest.NewSigner(t)
+
+	wrongAudienceClaims := jwttest.UserClaims("admin", dto.PermissionFineManage)
+	wrongAudienceClaims.Audience = jwt.ClaimStrings{"other_app"}
+	expiredClaims := jwttest.UserClaims("admin", dto.PermissionFineManage)
+	expiredClaims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+
+	testCases := []struct {
+		name       string
+		token      string
+		wantStatus int
+	}{
+		{"no token", "", 401},
+		{"without role claim", signer.Sign(t, jwt.MapClaims{
+			"sub":      uuid.New().String(),
+			"username": "test",
+			"email":    "test@gmail.com",
+			"exp":      time.Now().Add(time.Hour).Unix(),
+		}), 401},
+		{"wrong audience", signer.Sign(t, wrongAudienceClaims), 401},
+		{"expired", signer.Sign(t, expiredClaims), 401},
+		{"user", signer.Sign(t, jwttest.UserClaims("user")), 403},
+		{"admin without permission", signer.Sign(t, jwttest.UserClaims("admin")), 403},
+		{"with permission", signer.Sign(t, jwttest.UserClaims("user", dto.PermissionFineManage)), 200},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			fakeUcase := &fakeFineUcase{}
+			router := NewRouter(interface_pkg.CommonDependency{
+				FineUcase:    fakeUcase,
+				KeySet:       signer.KeySet,
+				ClaimsConfig: jwttest.ClaimsConfig,
+			})
+
+			body := []byte(`{"user_uuid":"00000000-0000-0000-0000-000000000001","amount":1000}`)
+			req := httptest.NewRequest(http.MethodPost, "/fines/waivers", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			if testCase.token != "" {
+				req.Header.Set("Authorization", "Bearer "+testCase.token)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != testCase.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", testCase.wantStatus, rec.Code, rec.Body.String())
+			}
+			if (fakeUcase.called != "") != (testCase.wantStatus == 200) {
+				t.Errorf("expected ucase called: %v, got %q", testCase.wantStatus == 200, fakeUcase.called)
+			}
+		})
+	}
+}
+
+// TestRouter_APIKey sends X-API-Key headers to /fines/waivers, auth_service is
+// faked by the validate func of the cache.
+func TestRouter_APIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	signer := jwttest.NewSigner(t)
+
+	validateCalls := 0
+	apiKeyCache := api_key_util.NewCache(func(ctx context.Context, apiKey string) (*dto.CurrentUser, error) {
+		validateCalls++
+		switch apiKey {
+		case "lk_scoped":
+			return &dto.CurrentUser{UUID: uuid.New().String(), Role: "kiosk", Permissions: []string{dto.PermissionFineManage}}, nil
+		case "lk_unscoped":
+			return &dto.CurrentUser{UUID: uuid.New().String(), Role: "kiosk"}, nil
+		}
+		return nil, fmt.Errorf("%w: api key not found", api_key_util.ErrInvalidAPIKey)
+	}, time.Minute)
+
+	testCases := []struct {
+		name       string
+		apiKey     string
+		wantStatus int
+	}{
+		{"unknown key", "lk_unknown", 401},
+		{"unknown key again", "lk_unknown", 401},
+		{"key without scope", "lk_unscoped", 403},
+		{"key with scope", "lk_scoped", 200},
+		{"key with scope again", "lk_scoped", 200},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			fakeUcase := &fakeFineUcase{}
+			router := NewRouter(interface_pkg.CommonDependency{
+				FineUcase:    fakeUcase,
+				KeySet:       signer.KeySet,
+				ClaimsConfig: jwttest.ClaimsConfig,
+				APIKeyCache:  apiKeyCache,
+			})
+
+			body := []byte(`{"user_uuid":"00000000-0000-0000-0000-000000000001","amount":1000}`)
+			req := httptest.NewRequest(http.MethodPost, "/fines/waivers", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-API-Key", testCase.apiKey)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != testCase.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", testCase.wantStatus, rec.Code, rec.Body.String())
+			}
+			if (fakeUcase.called != "") != (testCase.wantStatus == 200) {
+				t.Errorf("expected ucase called: %v, got %q", testCase.wantStatus == 200, fakeUcase.called)
+			}
+		})
+	}
+
+	// repeated keys are answered from the cache
+	if validateCalls != 3 {
+		t.Errorf("expected 3 validate calls, got %d", validateCalls)
+	}
+}
+
+// TestRouter_Permissions checks each route guarded by the router asks for its
+// own permission.
+func TestRouter_Permissions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	signer := jwttest.NewSigner(t)
+
+	bookUUID := uuid.New().String()
+	routes := []struct {
+		method     string
+		path       string
+		body       string
+		permission string
+		wantCalled string
+	}{
+		{http.MethodGet, "/books/" + bookUUID + "/copies?sort_order=desc", "", dto.PermissionCopyManage, "GetBookCopyList"},
+		{http.MethodPost, "/books/" + bookUUID + "/copies", `{"barcode":"B-0001"}`, dto.PermissionCopyManage, "CreateBookCopy"},
+		{http.MethodPatch, "/copies/" + uuid.New().String(), `{"status":"repair"}`, dto.PermissionCopyManage, "PatchBookCopy"},
+		{http.MethodPost, "/fines/waivers", `{"user_uuid":"00000000-0000-0000-0000-000000000001","amount":1000}`, dto.PermissionFineManage, "WaiveFine"},
+		{http.MethodPost, "/fines/payments", `{"user_uuid":"00000000-0000-0000-0000-000000000001","amount":1000}`, dto.PermissionFineManage, "RecordFinePayment"},
+	}
+
+	for _, route := range routes {
+		// the other manage permissions of the service do not grant the route
+		otherPermissions := []string{}
+		for _, permission := range []string{dto.PermissionCopyManage, dto.PermissionBorrowManage, dto.PermissionHoldManage, dto.PermissionFineManage} {
+			if permission != route.permission {
+				otherPermissions = append(otherPermissions, permission)
+			}
+		}
+
+		testCases := []struct {
+			name       string
+			token      string
+			wantStatus int
+		}{
+			{"with other permissions", signer.Sign(t, jwttest.UserClaims("admin", otherPermissions...)), 403},
+			{"with permission", signer.Sign(t, jwttest.UserClaims("user", route.permission)), 200},
+		}
+
+		for _, testCase := range testCases {
+			t.Run(route.method+" "+route.path+" "+testCase.name, func(t *testing.T) {
+				fineUcase := &fakeFineUcase{}
+				bookCopyUcase := &fakeBookCopyUcase{}
+				router := NewRouter(interface_pkg.CommonDependency{
+					FineUcase:     fineUcase,
+					BookCopyUcase: bookCopyUcase,
+					KeySet:        signer.KeySet,
+					ClaimsConfig:  jwttest.ClaimsConfig,
+				})
+
+				req := httptest.NewRequest(route.method, route.path, bytes.NewReader([]byte(route.body)))
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Authorization", "Bearer "+testCase.token)
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+
+				if rec.Code != testCase.wantStatus {
+					t.Errorf("expected status %d, got %d: %s", testCase.wantStatus, rec.Code, rec.Body.String())
+				}
+				wantCalled := ""
+				if testCase.wantStatus == 200 {
+					wantCalled = route.wantCalled
+				}
+				if called := fineUcase.called + bookCopyUcase.called; called != wantCalled {
+					t.Errorf("expected ucase call %q, got %q", wantCalled, called)
+				}
+			})
+		}
+	}
+}
+
+func TestRouter_GetBookList_Filters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{"no filter", "", 200},
+		{"author", "&author_uuid=" + uuid.New().String(), 200},
+		{"category", "&category_uuid=" + uuid.New().String(), 200},
+		{"without category", "&category_uuid=null", 200},
+		{"malformed author", "&author_uuid=jane", 400},
+		{"malformed category", "&category_uuid=fiction", 400},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			bookUcase := &fakeBookUcase{}
+			router := NewRouter(interface_pkg.CommonDependency{BookUcase: bookUcase})
+
+			req := httptest.NewRequest(http.MethodGet, "/books?sort_order=desc"+testCase.query, nil)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != testCase.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", testCase.wantStatus, rec.Code, rec.Body.String())
+			}
+			wantCalled := ""
+			if testCase.wantStatus == 200 {
+				wantCalled = "GetBookList"
+			}
+			if bookUcase.called != wantCalled {
+				t.Errorf("expected ucase call %q, got %q", wantCalled, bookUcase.called)
+			}
+		})
+	}
+}
@@ -2,18 +2,23 @@ package main
 
 import (
 	"book_service/config"
-	"book_service/domain/model"
 	interface_pkg "book_service/interface"
 	"book_service/interface/grpc"
 	"book_service/interface/rest"
+	"book_service/migrations"
 	"book_service/repository"
 	ucase "book_service/usecase"
 	"book_service/utils/helper"
+	jwt_util "book_service/utils/jwt"
+	"book_service/utils/migrator"
+	"context"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/op/go-logging"
+	"gorm.io/gorm"
 )
 
 func init() {
@@ -30,36 +35,66 @@ var logger = logging.MustGetLogger("main")
 // @description JWT Authorization header using the Bearer scheme (add 'Bearer ' prefix).
 func main() {
 	logger.Debugf("Envs: %v", helper.PrettyJson(config.Envs))
-	gormDB := config.NewPostgresqlDB()
-	// authGrpcServiceClient := config.NewAuthGrpcServiceClient()
-	authorGrpcServiceClient := config.NewAuthorGrpcServiceClient()
-
-	// migrations
-	err := gormDB.AutoMigrate(
-		&model.Book{},
-		&model.BookBorrow{},
-	)
-	if err != nil {
-		logger.Fatalf("failed to migrate database: %v", err)
+	// the database itself is only created when migrating up
+	for _, arg := range os.Args[1:] {
+		if arg == "--migrate=up" {
+			err := config.EnsurePostgresqlDB()
+			if err != nil {
+				logger.Fatalf("failed to ensure database: %v", err)
+			}
+		}
 	}
+	gormDB := config.NewPostgresqlDB()
+	serviceTokenSource := config.NewServiceTokenSource()
+	authGrpcServiceClient := config.NewAuthGrpcServiceClient(serviceTokenSource)
+	authorGrpcServiceClient := config.NewAuthorGrpcServiceClient(serviceTokenSource)
+	categoryGrpcServiceClient := config.NewCategoryGrpcServiceClient(serviceTokenSource)
 
 	// repositories
 	// authRepo := repository.NewAuthRepo(authGrpcServiceClient)
 	bookRepo := repository.NewBookRepo(gormDB)
+	bookBorrowRepo := repository.NewBookBorrowRepo(gormDB)
+	fineLedgerRepo := repository.NewFineLedgerRepo(gormDB)
+	bookHoldRepo := repository.NewBookHoldRepo(gormDB)
+	bookCopyRepo := repository.NewBookCopyRepo(gormDB)
+	outboxRepo := repository.NewOutboxRepo(gormDB)
+
+	// access token verification
+	keySet := config.NewJWKS(authGrpcServiceClient)
+	apiKeyCache := config.NewAPIKeyCache(authGrpcServiceClient)
+	claimsConfig := jwt_util.ClaimsConfig{
+		Issuer:   config.Envs.JWT_ISSUER,
+		Audience: config.Envs.JWT_AUDIENCE,
+	}
 
 	// ucases
-	bookUcase := ucase.NewBookUcase(bookRepo, authorGrpcServiceClient)
+	bookUcase := ucase.NewBookUcase(bookRepo, bookCopyRepo, authorGrpcServiceClient, categoryGrpcServiceClient)
+	bookBorrowUcase := ucase.NewBookBorrowUcase(bookRepo, bookBorrowRepo, fineLedgerRepo, bookHoldRepo, authGrpcServiceClient)
+	fineUcase := ucase.NewFineUcase(fineLedgerRepo, bookBorrowRepo)
+	bookHoldUcase := ucase.NewBookHoldUcase(bookRepo, bookBorrowRepo, bookHoldRepo, bookCopyRepo)
+	bookCopyUcase := ucase.NewBookCopyUcase(bookRepo, bookCopyRepo, bookHoldRepo)
+	eventUcase := ucase.NewEventUcase(bookRepo, bookHoldRepo)
 	dependencies := interface_pkg.CommonDependency{
-		BookUcase: bookUcase,
+		BookUcase:       bookUcase,
+		BookBorrowUcase: bookBorrowUcase,
+		FineUcase:       fineUcase,
+		BookHoldUcase:   bookHoldUcase,
+		BookCopyUcase:   bookCopyUcase,
+
+		KeySet:       keySet,
+		APIKeyCache:  apiKeyCache,
+		ClaimsConfig: claimsConfig,
 	}
 
 	args := os.Args
 	if len(args) == 1 { // run as a rest server
+		startEvents(outboxRepo, eventUcase)
 		logger.Info("starting rest server...")
+		go runHoldQueueWorker(bookHoldUcase)
 		rest.SetupServer(dependencies)
 	} else if len(args) > 1 {
 		validArgVariables := []string{"server"}
-		validPreRunArgVariables := []string{}
+		validPreRunArgVariables := []string{"migrate"}
 
 		// validate args
 		variables := validArgVariables
@@ -110,15 +145,85 @@ func main() {
 
 				switch value {
 				case "rest":
+					startEvents(outboxRepo, eventUcase)
 					logger.Info("starting rest server...")
+					go runHoldQueueWorker(bookHoldUcase)
 					rest.SetupServer(dependencies)
 				case "grpc":
+					startEvents(outboxRepo, eventUcase)
 					logger.Info("starting grpc server...")
 					grpc.SetupServer(dependencies)
 				default:
 					logger.Fatalf("invalid argument: %s", arg)
 				}
+			} else if strings.Contains(arg, fmt.Sprintf("--%s=", "migrate")) {
+				value := strings.Split(arg, "=")[1]
+				runMigration(gormDB, value)
+			}
+		}
+	}
+}
+
+// startEvents publishes the outbox events and consumes those of the other
+// services in the background while the server runs. the relays of several
+// instances take turns through a lock, consumers skip redelivered events.
+func startEvents(outboxRepo repository.IOutboxRepo, eventUcase ucase.IEventUcase) {
+	go func() {
+		// blocks until the broker is reachable
+		broker := config.NewEventBroker(context.Background())
+		if broker == nil {
+			logger.Warning("no event broker, domain events stay in the outbox")
+			return
+		}
+		outboxUcase := ucase.NewOutboxUcase(outboxRepo, broker)
+		go outboxUcase.RunRelay(context.Background())
+		eventUcase.RunConsumer(context.Background(), broker)
+	}()
+}
+
+// runHoldQueueWorker periodically expires ready holds that were not picked up
+// and passes their copies to the next in queue.
+func runHoldQueueWorker(bookHoldUcase ucase.IBookHoldUcase) {
+	ticker := time.NewTicker(time.Second * time.Duration(config.Envs.HOLD_SWEEP_INTERVAL_SECONDS))
+	defer ticker.Stop()
+
+	for range ticker.C {
+		err := bookHoldUcase.ProcessHoldQueues(context.Background())
+		if err != nil {
+			logger.Errorf("failed to process hold queues: %v", err)
+		}
+	}
+}
+
+// runMigration applies, rolls back or lists the versioned sql migrations in
+// ./migrations.
+func runMigration(gormDB *gorm.DB, value string) {
+	switch value {
+	case "up":
+		logger.Info("applying migrations...")
+		err := migrator.Up(gormDB, migrations.FS)
+		if err != nil {
+			logger.Fatalf("failed to apply migrations: %v", err)
+		}
+	case "down":
+		logger.Info("rolling back latest migration...")
+		err := migrator.Down(gormDB, migrations.FS)
+		if err != nil {
+			logger.Fatalf("failed to roll back migration: %v", err)
+		}
+	case "status":
+		statuses, err := migrator.Status(gormDB, migrations.FS)
+		if err != nil {
+			logger.Fatalf("failed to get migration status: %v", err)
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
 			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, appliedAt)
 		}
+	default:
+		logger.Fatalf("invalid migrate argument: %s", value)
 	}
 }
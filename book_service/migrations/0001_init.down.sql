@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS fine_ledger_entries;
+DROP TABLE IF EXISTS book_holds;
+DROP TABLE IF EXISTS book_borrows;
+DROP TABLE IF EXISTS book_copies;
+DROP TABLE IF EXISTS books;
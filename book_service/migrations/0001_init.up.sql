@@ -0,0 +1,115 @@
+-- baseline schema, previously created by gorm AutoMigrate. tables that were
+-- already auto migrated are kept and brought up to date below.
+CREATE TABLE IF NOT EXISTS books (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    uuid uuid NOT NULL CONSTRAINT uni_books_uuid UNIQUE,
+    author_uuid uuid NOT NULL,
+    category_uuid uuid,
+    title text NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_books_deleted_at ON books (deleted_at);
+
+CREATE TABLE IF NOT EXISTS book_copies (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    uuid uuid NOT NULL CONSTRAINT uni_book_copies_uuid UNIQUE,
+    book_uuid uuid NOT NULL CONSTRAINT fk_books_book_copies REFERENCES books (uuid) ON DELETE CASCADE,
+    barcode varchar(64) NOT NULL CONSTRAINT uni_book_copies_barcode UNIQUE,
+    condition varchar(20) NOT NULL DEFAULT 'good',
+    status varchar(20) NOT NULL DEFAULT 'available',
+    acquired_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_book_copies_deleted_at ON book_copies (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_book_copies_book_uuid ON book_copies (book_uuid);
+CREATE INDEX IF NOT EXISTS idx_book_copies_status ON book_copies (status);
+
+CREATE TABLE IF NOT EXISTS book_borrows (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    uuid uuid NOT NULL CONSTRAINT uni_book_borrows_uuid UNIQUE,
+    book_uuid uuid NOT NULL CONSTRAINT fk_books_book_borrows REFERENCES books (uuid) ON DELETE CASCADE,
+    user_uuid uuid NOT NULL,
+    copy_uuid uuid CONSTRAINT fk_book_borrows_copy REFERENCES book_copies (uuid) ON DELETE SET NULL,
+    borrowed_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    due_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    returned_at timestamptz,
+    renew_count bigint NOT NULL DEFAULT 0
+);
+
+-- auto migrated book_borrows only has the borrow_date and return_date strings
+ALTER TABLE book_borrows ADD COLUMN IF NOT EXISTS copy_uuid uuid CONSTRAINT fk_book_borrows_copy REFERENCES book_copies (uuid) ON DELETE SET NULL;
+ALTER TABLE book_borrows ADD COLUMN IF NOT EXISTS borrowed_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE book_borrows ADD COLUMN IF NOT EXISTS due_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE book_borrows ADD COLUMN IF NOT EXISTS returned_at timestamptz;
+ALTER TABLE book_borrows ADD COLUMN IF NOT EXISTS renew_count bigint NOT NULL DEFAULT 0;
+
+-- borrows made before per copy inventory have no copy. they are borrowed at
+-- borrow_date, or when the row was created when it is not a date, and due
+-- after the default LOAN_PERIOD_DAYS. a return_date that is not a date still
+-- means the book was returned.
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = 'book_borrows' AND column_name = 'borrow_date'
+    ) THEN
+        UPDATE book_borrows SET borrowed_at = CASE
+            WHEN borrow_date ~ '^[0-9]{4}-[0-9]{2}-[0-9]{2}' THEN borrow_date::timestamptz
+            ELSE COALESCE(created_at, borrowed_at)
+        END
+        WHERE copy_uuid IS NULL;
+        UPDATE book_borrows SET due_at = borrowed_at + interval '14 days'
+        WHERE copy_uuid IS NULL;
+        UPDATE book_borrows SET returned_at = CASE
+            WHEN return_date ~ '^[0-9]{4}-[0-9]{2}-[0-9]{2}' THEN return_date::timestamptz
+            ELSE COALESCE(updated_at, now())
+        END
+        WHERE copy_uuid IS NULL AND returned_at IS NULL AND return_date <> '';
+    END IF;
+END $$;
+
+ALTER TABLE book_borrows DROP COLUMN IF EXISTS borrow_date;
+ALTER TABLE book_borrows DROP COLUMN IF EXISTS return_date;
+CREATE INDEX IF NOT EXISTS idx_book_borrows_deleted_at ON book_borrows (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_book_borrows_copy_uuid ON book_borrows (copy_uuid);
+CREATE INDEX IF NOT EXISTS idx_book_borrows_due_at ON book_borrows (due_at);
+
+CREATE TABLE IF NOT EXISTS book_holds (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    uuid uuid NOT NULL CONSTRAINT uni_book_holds_uuid UNIQUE,
+    book_uuid uuid NOT NULL CONSTRAINT fk_books_book_holds REFERENCES books (uuid) ON DELETE CASCADE,
+    user_uuid uuid NOT NULL,
+    status varchar(20) NOT NULL DEFAULT 'waiting',
+    ready_at timestamptz,
+    expires_at timestamptz,
+    closed_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_book_holds_deleted_at ON book_holds (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_book_holds_book_uuid ON book_holds (book_uuid);
+CREATE INDEX IF NOT EXISTS idx_book_holds_user_uuid ON book_holds (user_uuid);
+
+CREATE TABLE IF NOT EXISTS fine_ledger_entries (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    uuid uuid NOT NULL CONSTRAINT uni_fine_ledger_entries_uuid UNIQUE,
+    user_uuid uuid NOT NULL,
+    borrow_uuid uuid,
+    type varchar(20) NOT NULL,
+    amount bigint NOT NULL,
+    note text,
+    created_by uuid
+);
+CREATE INDEX IF NOT EXISTS idx_fine_ledger_entries_deleted_at ON fine_ledger_entries (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_fine_ledger_entries_user_uuid ON fine_ledger_entries (user_uuid);
@@ -0,0 +1,7 @@
+ALTER TABLE books ADD COLUMN IF NOT EXISTS stock bigint;
+UPDATE books SET stock = (
+    SELECT COUNT(*) FROM book_copies
+    WHERE book_copies.book_uuid = books.uuid
+        AND book_copies.status = 'available'
+        AND book_copies.deleted_at IS NULL
+);
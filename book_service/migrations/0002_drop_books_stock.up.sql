@@ -0,0 +1,23 @@
+-- stock is derived from available book copies since per copy inventory,
+-- auto migrate could never drop the old column. books created before copies
+-- existed only have their stock, so turn it into available copies first.
+-- books that already have copies are skipped, the down migration rebuilds
+-- stock from them and leaves the copies in place.
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = 'books' AND column_name = 'stock'
+    ) THEN
+        INSERT INTO book_copies (created_at, updated_at, uuid, book_uuid, barcode, condition, status, acquired_at)
+        SELECT now(), now(), gen_random_uuid(), books.uuid,
+            'LEGACY-' || books.uuid || '-' || copy_number, 'good', 'available', now()
+        FROM books, generate_series(1, books.stock) AS copy_number
+        WHERE books.stock > 0
+            AND NOT EXISTS (
+                SELECT 1 FROM book_copies WHERE book_copies.book_uuid = books.uuid
+            );
+    END IF;
+END $$;
+
+ALTER TABLE books DROP COLUMN IF EXISTS stock;
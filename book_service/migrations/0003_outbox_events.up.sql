@@ -0,0 +1,31 @@
+-- domain events written with the change they describe, published to the
+-- broker by the relay
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    uuid uuid NOT NULL CONSTRAINT uni_outbox_events_uuid UNIQUE,
+    type varchar(100) NOT NULL,
+    aggregate_uuid uuid NOT NULL,
+    payload jsonb NOT NULL,
+    published_at timestamptz,
+    attempts bigint NOT NULL DEFAULT 0,
+    last_error text
+);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_deleted_at ON outbox_events (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_unpublished ON outbox_events (id) WHERE published_at IS NULL;
+
+-- events of the other services already applied by a consumer, the broker
+-- delivers at least once
+CREATE TABLE IF NOT EXISTS processed_events (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    event_uuid uuid NOT NULL,
+    consumer varchar(100) NOT NULL,
+    event_type varchar(100) NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_processed_events_deleted_at ON processed_events (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_processed_events_consumer_event_uuid ON processed_events (consumer, event_uuid);
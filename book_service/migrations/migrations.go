@@ -0,0 +1,9 @@
+package migrations
+
+import "embed"
+
+// FS holds the numbered up/down sql files of this service, applied with
+// --migrate=up|down|status.
+//
+//go:embed *.sql
+var FS embed.FS
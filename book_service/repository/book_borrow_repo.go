@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type BookBorrowRepo struct {
@@ -27,6 +28,9 @@ type IBookBorrowRepo interface {
 		ctx context.Context,
 		params dto.BookBorrowRepo_GetListParams,
 	) (int64, error)
+	Borrow(bookBorrow *model.BookBorrow) error
+	Return(bookBorrow *model.BookBorrow, fine *model.FineLedgerEntry) error
+	Renew(bookBorrow *model.BookBorrow, maxRenewals int) error
 }
 
 func NewBookBorrowRepo(db *gorm.DB) IBookBorrowRepo {
@@ -83,23 +87,26 @@ func (repo *BookBorrowRepo) GetList(
 
 	tx := repo.db.Model(&models)
 
-	if params.AuthorUUID != "" {
-		tx = tx.Where("author_uuid = ?", params.AuthorUUID)
+	if params.BookUUID != "" {
+		tx = tx.Where("book_uuid = ?", params.BookUUID)
 	}
 
-	if params.Query != "" {
-		if params.QueryBy != "" {
-			tx = tx.Where("? LIKE ?", params.QueryBy, "%"+params.Query+"%")
+	if params.UserUUID != "" {
+		tx = tx.Where("user_uuid = ?", params.UserUUID)
+	}
+
+	if params.Returned != nil {
+		if *params.Returned {
+			tx = tx.Where("returned_at IS NOT NULL")
 		} else {
-			tx = tx.Where(
-				`
-					Title LIKE ?
-				`,
-				"%"+params.Query+"%",
-			)
+			tx = tx.Where("returned_at IS NULL")
 		}
 	}
 
+	if params.DueBefore != nil {
+		tx = tx.Where("due_at < ?", *params.DueBefore)
+	}
+
 	if params.Page > 0 && params.Limit > 0 {
 		offset := (params.Page - 1) * params.Limit
 		tx = tx.Offset(offset).Limit(params.Limit)
@@ -123,23 +130,26 @@ func (repo *BookBorrowRepo) CountGetList(
 ) (int64, error) {
 	tx := repo.db.Model(&model.BookBorrow{})
 
-	if params.AuthorUUID != "" {
-		tx = tx.Where("author_uuid = ?", params.AuthorUUID)
+	if params.BookUUID != "" {
+		tx = tx.Where("book_uuid = ?", params.BookUUID)
+	}
+
+	if params.UserUUID != "" {
+		tx = tx.Where("user_uuid = ?", params.UserUUID)
 	}
 
-	if params.Query != "" {
-		if params.QueryBy != "" {
-			tx = tx.Where("? LIKE ?", params.QueryBy, "%"+params.Query+"%")
+	if params.Returned != nil {
+		if *params.Returned {
+			tx = tx.Where("returned_at IS NOT NULL")
 		} else {
-			tx = tx.Where(
-				`
-					Title LIKE ?
-				`,
-				"%"+params.Query+"%",
-			)
+			tx = tx.Where("returned_at IS NULL")
 		}
 	}
 
+	if params.DueBefore != nil {
+		tx = tx.Where("due_at < ?", *params.DueBefore)
+	}
+
 	var count int64
 	err := tx.Count(&count).Error
 	if err != nil {
@@ -148,3 +158,134 @@ func (repo *BookBorrowRepo) CountGetList(
 
 	return count, nil
 }
+
+// Borrow takes an available copy of the book and creates the borrow record
+// for it in a single transaction. the book row is locked so concurrent borrows
+// are serialized, a user holds at most one active borrow of the book, and a
+// copy is only handed out while the available copies outnumber the ones
+// reserved by other users' holds. the borrower's own active hold on the book,
+// if any, is fulfilled.
+func (repo *BookBorrowRepo) Borrow(bookBorrow *model.BookBorrow) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&model.Book{}, "uuid = ?", bookBorrow.BookUUID).Error
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.New("not found")
+			}
+			return errors.New("failed to get book: " + err.Error())
+		}
+
+		var active int64
+		err = tx.Model(&model.BookBorrow{}).
+			Where("book_uuid = ? AND user_uuid = ? AND returned_at IS NULL", bookBorrow.BookUUID, bookBorrow.UserUUID).
+			Count(&active).Error
+		if err != nil {
+			return errors.New("failed to count borrows: " + err.Error())
+		}
+		if active > 0 {
+			return errors.New("already borrowed")
+		}
+
+		var available int64
+		err = availableCopiesQuery(tx, bookBorrow.BookUUID).Count(&available).Error
+		if err != nil {
+			return errors.New("failed to count copies: " + err.Error())
+		}
+
+		reserved, err := countReservedHolds(tx, bookBorrow.BookUUID, bookBorrow.UserUUID.String(), bookBorrow.BorrowedAt)
+		if err != nil {
+			return errors.New("failed to count holds: " + err.Error())
+		}
+
+		if available <= reserved {
+			return errors.New("out of stock")
+		}
+
+		var bookCopy model.BookCopy
+		err = availableCopiesQuery(tx, bookBorrow.BookUUID).Order("id asc").First(&bookCopy).Error
+		if err != nil {
+			return errors.New("failed to get copy: " + err.Error())
+		}
+
+		res := tx.Model(&model.BookCopy{}).
+			Where("id = ?", bookCopy.ID).
+			Update("status", model.BookCopyStatusOnLoan)
+		if res.Error != nil {
+			return errors.New("failed to update copy: " + res.Error.Error())
+		}
+		bookBorrow.CopyUUID = &bookCopy.UUID
+
+		if err := tx.Create(bookBorrow).Error; err != nil {
+			return errors.New("failed to create: " + err.Error())
+		}
+
+		res = tx.Model(&model.BookHold{}).
+			Where(
+				"book_uuid = ? AND user_uuid = ? AND status IN ?",
+				bookBorrow.BookUUID, bookBorrow.UserUUID,
+				[]string{model.BookHoldStatusWaiting, model.BookHoldStatusReady},
+			).
+			Updates(map[string]interface{}{
+				"status":    model.BookHoldStatusFulfilled,
+				"closed_at": bookBorrow.BorrowedAt,
+			})
+		if res.Error != nil {
+			return errors.New("failed to fulfill hold: " + res.Error.Error())
+		}
+		return nil
+	})
+}
+
+// Return marks the borrow as returned and makes its copy available again in a
+// single transaction. a borrow can only be returned once. fine is optional and
+// is written to the ledger in the same transaction.
+func (repo *BookBorrowRepo) Return(bookBorrow *model.BookBorrow, fine *model.FineLedgerEntry) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.BookBorrow{}).
+			Where("uuid = ? AND returned_at IS NULL", bookBorrow.UUID).
+			Update("returned_at", bookBorrow.ReturnedAt)
+		if res.Error != nil {
+			return errors.New("failed to update: " + res.Error.Error())
+		}
+		if res.RowsAffected == 0 {
+			return errors.New("already returned")
+		}
+
+		if bookBorrow.CopyUUID != nil {
+			res = tx.Model(&model.BookCopy{}).
+				Where("uuid = ? AND status = ?", *bookBorrow.CopyUUID, model.BookCopyStatusOnLoan).
+				Update("status", model.BookCopyStatusAvailable)
+			if res.Error != nil {
+				return errors.New("failed to update copy: " + res.Error.Error())
+			}
+		}
+
+		if fine != nil {
+			if err := tx.Create(fine).Error; err != nil {
+				return errors.New("failed to create fine: " + err.Error())
+			}
+		}
+		return nil
+	})
+}
+
+// Renew moves the due date of an active borrow to bookBorrow.DueAt and bumps
+// its renew count, as long as the count is still below maxRenewals.
+func (repo *BookBorrowRepo) Renew(bookBorrow *model.BookBorrow, maxRenewals int) error {
+	res := repo.db.Model(&model.BookBorrow{}).
+		Where("uuid = ? AND returned_at IS NULL AND renew_count < ?", bookBorrow.UUID, maxRenewals).
+		Updates(map[string]interface{}{
+			"due_at":      bookBorrow.DueAt,
+			"renew_count": gorm.Expr("renew_count + 1"),
+		})
+	if res.Error != nil {
+		return errors.New("failed to update: " + res.Error.Error())
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("renew limit reached")
+	}
+
+	bookBorrow.RenewCount++
+	return nil
+}
@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"book_service/domain/dto"
+	"book_service/domain/model"
+	"book_service/migrations"
+	"book_service/utils/migrator"
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// newTestDB migrates a schema of its own in the postgres database of
+// TEST_POSTGRESQL_DSN and drops it after the test.
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_POSTGRESQL_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRESQL_DSN is not set")
+	}
+
+	schema := fmt.Sprintf("repository_test_%d", time.Now().UnixNano())
+	adminDB, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	if err := adminDB.Exec(fmt.Sprintf("CREATE SCHEMA %q", schema)).Error; err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		adminDB.Exec(fmt.Sprintf("DROP SCHEMA %q CASCADE", schema))
+	})
+
+	db, err := gorm.Open(postgres.Open(dsn+" search_path="+schema), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	if err := migrator.Up(db, migrations.FS); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	return db
+}
+
+func createTestBook(t *testing.T, db *gorm.DB, copies int) *model.Book {
+	t.Helper()
+	book := &model.Book{UUID: uuid.New(), AuthorUUID: uuid.New(), Title: "Dune"}
+	if err := db.Create(book).Error; err != nil {
+		t.Fatalf("failed to create book: %v", err)
+	}
+	for i := 0; i < copies; i++ {
+		bookCopy := &model.BookCopy{
+			UUID:     uuid.New(),
+			BookUUID: book.UUID,
+			Barcode:  fmt.Sprintf("%s-%d", book.UUID, i),
+			Status:   model.BookCopyStatusAvailable,
+		}
+		if err := db.Create(bookCopy).Error; err != nil {
+			t.Fatalf("failed to create copy: %v", err)
+		}
+	}
+	return book
+}
+
+func newTestBorrow(book *model.Book, userUUID uuid.UUID) *model.BookBorrow {
+	now := time.Now().UTC()
+	return &model.BookBorrow{
+		UUID:       uuid.New(),
+		BookUUID:   book.UUID,
+		UserUUID:   userUUID,
+		BorrowedAt: now,
+		DueAt:      now.Add(14 * 24 * time.Hour),
+	}
+}
+
+func countAvailableCopies(t *testing.T, db *gorm.DB, book *model.Book) int64 {
+	t.Helper()
+	var count int64
+	err := db.Model(&model.BookCopy{}).
+		Where("book_uuid = ? AND status = ?", book.UUID, model.BookCopyStatusAvailable).
+		Count(&count).Error
+	if err != nil {
+		t.Fatalf("failed to count copies: %v", err)
+	}
+	return count
+}
+
+func TestBookBorrowRepo_Borrow(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewBookBorrowRepo(db)
+
+	t.Run("out_of_stock", func(t *testing.T) {
+		book := createTestBook(t, db, 1)
+		if err := repo.Borrow(newTestBorrow(book, uuid.New())); err != nil {
+			t.Fatalf("expected the only copy to be borrowed, got %v", err)
+		}
+
+		err := repo.Borrow(newTestBorrow(book, uuid.New()))
+		if err == nil || err.Error() != "out of stock" {
+			t.Fatalf("expected out of stock, got %v", err)
+		}
+		if got := countAvailableCopies(t, db, book); got != 0 {
+			t.Errorf("expected no available copy, got %d", got)
+		}
+	})
+
+	t.Run("concurrent_duplicate_borrow", func(t *testing.T) {
+		book := createTestBook(t, db, 3)
+		userUUID := uuid.New()
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 5)
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs <- repo.Borrow(newTestBorrow(book, userUUID))
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		borrowed := 0
+		for err := range errs {
+			if err == nil {
+				borrowed++
+			} else if err.Error() != "already borrowed" {
+				t.Errorf("expected already borrowed, got %v", err)
+			}
+		}
+		if borrowed != 1 {
+			t.Errorf("expected one borrow, got %d", borrowed)
+		}
+		if got := countAvailableCopies(t, db, book); got != 2 {
+			t.Errorf("expected 2 available copies, got %d", got)
+		}
+	})
+}
+
+func TestBookBorrowRepo_Return(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewBookBorrowRepo(db)
+	book := createTestBook(t, db, 1)
+
+	bookBorrow := newTestBorrow(book, uuid.New())
+	if err := repo.Borrow(bookBorrow); err != nil {
+		t.Fatalf("failed to borrow: %v", err)
+	}
+
+	returnedAt := time.Now().UTC()
+	bookBorrow.ReturnedAt = &returnedAt
+	fine := &model.FineLedgerEntry{UUID: uuid.New(), UserUUID: bookBorrow.UserUUID, BorrowUUID: &bookBorrow.UUID, Type: model.FineEntryTypeCharge, Amount: 10}
+	if err := repo.Return(bookBorrow, fine); err != nil {
+		t.Fatalf("failed to return: %v", err)
+	}
+	if got := countAvailableCopies(t, db, book); got != 1 {
+		t.Errorf("expected the copy available again, got %d", got)
+	}
+
+	secondFine := &model.FineLedgerEntry{UUID: uuid.New(), UserUUID: bookBorrow.UserUUID, BorrowUUID: &bookBorrow.UUID, Type: model.FineEntryTypeCharge, Amount: 10}
+	err := repo.Return(bookBorrow, secondFine)
+	if err == nil || err.Error() != "already returned" {
+		t.Fatalf("expected already returned, got %v", err)
+	}
+
+	var fines int64
+	db.Model(&model.FineLedgerEntry{}).Where("borrow_uuid = ?", bookBorrow.UUID).Count(&fines)
+	if fines != 1 {
+		t.Errorf("expected the fine charged once, got %d", fines)
+	}
+}
+
+func TestBookBorrowRepo_GetList_Overdue(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewBookBorrowRepo(db)
+	book := createTestBook(t, db, 3)
+	now := time.Now().UTC()
+
+	borrowDueIn := func(dueIn time.Duration) *model.BookBorrow {
+		bookBorrow := newTestBorrow(book, uuid.New())
+		bookBorrow.DueAt = now.Add(dueIn)
+		if err := repo.Borrow(bookBorrow); err != nil {
+			t.Fatalf("failed to borrow: %v", err)
+		}
+		return bookBorrow
+	}
+	borrowDueIn(time.Hour)
+	overdue := borrowDueIn(-time.Hour)
+	returnedLate := borrowDueIn(-2 * time.Hour)
+	returnedAt := now
+	returnedLate.ReturnedAt = &returnedAt
+	if err := repo.Return(returnedLate, nil); err != nil {
+		t.Fatalf("failed to return: %v", err)
+	}
+
+	returned := false
+	bookBorrows, err := repo.GetList(context.Background(), dto.BookBorrowRepo_GetListParams{
+		BookUUID:  book.UUID.String(),
+		Returned:  &returned,
+		DueBefore: &now,
+		SortOrder: "desc",
+	})
+	if err != nil {
+		t.Fatalf("failed to get list: %v", err)
+	}
+	if len(bookBorrows) != 1 || bookBorrows[0].UUID != overdue.UUID {
+		t.Errorf("expected only the overdue borrow, got %+v", bookBorrows)
+	}
+}
+
+func TestBookBorrowRepo_CopyAssignment(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewBookBorrowRepo(db)
+	book := createTestBook(t, db, 2)
+
+	// the oldest copy is in repair, so the other one is lent
+	var copies []model.BookCopy
+	db.Where("book_uuid = ?", book.UUID).Order("id asc").Find(&copies)
+	db.Model(&copies[0]).Update("status", model.BookCopyStatusRepair)
+
+	copyStatus := func(copyUUID uuid.UUID) string {
+		var bookCopy model.BookCopy
+		if err := db.First(&bookCopy, "uuid = ?", copyUUID).Error; err != nil {
+			t.Fatalf("failed to get copy: %v", err)
+		}
+		return bookCopy.Status
+	}
+
+	bookBorrow := newTestBorrow(book, uuid.New())
+	if err := repo.Borrow(bookBorrow); err != nil {
+		t.Fatalf("failed to borrow: %v", err)
+	}
+	if bookBorrow.CopyUUID == nil || *bookBorrow.CopyUUID != copies[1].UUID {
+		t.Fatalf("expected copy %s assigned, got %v", copies[1].UUID, bookBorrow.CopyUUID)
+	}
+	if got := copyStatus(copies[1].UUID); got != model.BookCopyStatusOnLoan {
+		t.Errorf("expected the assigned copy on loan, got %s", got)
+	}
+	if got := copyStatus(copies[0].UUID); got != model.BookCopyStatusRepair {
+		t.Errorf("expected the copy in repair untouched, got %s", got)
+	}
+
+	err := repo.Borrow(newTestBorrow(book, uuid.New()))
+	if err == nil || err.Error() != "out of stock" {
+		t.Fatalf("expected out of stock while the other copy is in repair, got %v", err)
+	}
+
+	returnedAt := time.Now().UTC()
+	bookBorrow.ReturnedAt = &returnedAt
+	if err := repo.Return(bookBorrow, nil); err != nil {
+		t.Fatalf("failed to return: %v", err)
+	}
+	if got := copyStatus(copies[1].UUID); got != model.BookCopyStatusAvailable {
+		t.Errorf("expected the returned copy available, got %s", got)
+	}
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"book_service/domain/dto"
+	"book_service/domain/model"
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+type BookCopyRepo struct {
+	db *gorm.DB
+}
+
+type IBookCopyRepo interface {
+	Create(bookCopy *model.BookCopy) error
+	GetByUUID(uuid string) (*model.BookCopy, error)
+	GetByBarcode(barcode string) (*model.BookCopy, error)
+	Update(bookCopy *model.BookCopy, currentStatus string) error
+	GetList(
+		ctx context.Context,
+		params dto.BookCopyRepo_GetListParams,
+	) ([]model.BookCopy, error)
+	CountGetList(
+		ctx context.Context,
+		params dto.BookCopyRepo_GetListParams,
+	) (int64, error)
+	CountAvailableByBookUUID(bookUUID string) (int64, error)
+	CountAvailableByBookUUIDs(bookUUIDs []string) (map[string]int64, error)
+}
+
+func NewBookCopyRepo(db *gorm.DB) IBookCopyRepo {
+	return &BookCopyRepo{
+		db: db,
+	}
+}
+
+func (repo *BookCopyRepo) Create(bookCopy *model.BookCopy) error {
+	err := repo.db.Create(bookCopy).Error
+	if err != nil {
+		return errors.New("failed to create: " + err.Error())
+	}
+	return err
+}
+
+func (repo *BookCopyRepo) GetByUUID(uuid string) (*model.BookCopy, error) {
+	var bookCopy model.BookCopy
+	if err := repo.db.First(&bookCopy, "uuid = ?", uuid).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("not found")
+		}
+		return nil, errors.New("failed to get: " + err.Error())
+	}
+	return &bookCopy, nil
+}
+
+func (repo *BookCopyRepo) GetByBarcode(barcode string) (*model.BookCopy, error) {
+	var bookCopy model.BookCopy
+	if err := repo.db.First(&bookCopy, "barcode = ?", barcode).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("not found")
+		}
+		return nil, errors.New("failed to get: " + err.Error())
+	}
+	return &bookCopy, nil
+}
+
+// Update saves the copy only while its status in the database is still
+// currentStatus, so a copy can not be changed under a borrow that just took it.
+func (repo *BookCopyRepo) Update(bookCopy *model.BookCopy, currentStatus string) error {
+	res := repo.db.Model(&model.BookCopy{}).
+		Where("uuid = ? AND status = ?", bookCopy.UUID, currentStatus).
+		Updates(map[string]interface{}{
+			"condition": bookCopy.Condition,
+			"status":    bookCopy.Status,
+		})
+	if res.Error != nil {
+		return errors.New("failed to update: " + res.Error.Error())
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("status changed")
+	}
+	return nil
+}
+
+func (repo *BookCopyRepo) GetList(
+	ctx context.Context,
+	params dto.BookCopyRepo_GetListParams,
+) ([]model.BookCopy, error) {
+	// validate param
+	if params.SortOrder != "asc" && params.SortOrder != "desc" {
+		return nil, fmt.Errorf("invalid sort order")
+	}
+
+	var models []model.BookCopy
+
+	tx := repo.db.Model(&models)
+
+	if params.BookUUID != "" {
+		tx = tx.Where("book_uuid = ?", params.BookUUID)
+	}
+
+	if params.Status != "" {
+		tx = tx.Where("status = ?", params.Status)
+	}
+
+	if params.Page > 0 && params.Limit > 0 {
+		offset := (params.Page - 1) * params.Limit
+		tx = tx.Offset(offset).Limit(params.Limit)
+	}
+
+	if params.SortOrder != "" && params.SortBy != "" {
+		tx = tx.Order(fmt.Sprintf("%s %s", params.SortBy, params.SortOrder))
+	}
+
+	err := tx.Find(&models).Error
+	if err != nil {
+		return nil, errors.New("failed to get: " + err.Error())
+	}
+
+	return models, nil
+}
+
+func (repo *BookCopyRepo) CountGetList(
+	ctx context.Context,
+	params dto.BookCopyRepo_GetListParams,
+) (int64, error) {
+	tx := repo.db.Model(&model.BookCopy{})
+
+	if params.BookUUID != "" {
+		tx = tx.Where("book_uuid = ?", params.BookUUID)
+	}
+
+	if params.Status != "" {
+		tx = tx.Where("status = ?", params.Status)
+	}
+
+	var count int64
+	err := tx.Count(&count).Error
+	if err != nil {
+		return 0, errors.New("failed to count: " + err.Error())
+	}
+
+	return count, nil
+}
+
+// CountAvailableByBookUUID returns the available stock of a book.
+func (repo *BookCopyRepo) CountAvailableByBookUUID(bookUUID string) (int64, error) {
+	var count int64
+	err := availableCopiesQuery(repo.db, bookUUID).Count(&count).Error
+	if err != nil {
+		return 0, errors.New("failed to count: " + err.Error())
+	}
+
+	return count, nil
+}
+
+// CountAvailableByBookUUIDs returns the available stock of many books at once,
+// keyed by book uuid. books without available copies are left out.
+func (repo *BookCopyRepo) CountAvailableByBookUUIDs(bookUUIDs []string) (map[string]int64, error) {
+	var rows []struct {
+		BookUUID string
+		Total    int64
+	}
+	err := repo.db.Model(&model.BookCopy{}).
+		Select("book_uuid, COUNT(*) AS total").
+		Where("book_uuid IN ? AND status = ?", bookUUIDs, model.BookCopyStatusAvailable).
+		Group("book_uuid").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, errors.New("failed to count: " + err.Error())
+	}
+
+	result := map[string]int64{}
+	for _, row := range rows {
+		result[row.BookUUID] = row.Total
+	}
+	return result, nil
+}
+
+func availableCopiesQuery(db *gorm.DB, bookUUID interface{}) *gorm.DB {
+	return db.Model(&model.BookCopy{}).
+		Where("book_uuid = ? AND status = ?", bookUUID, model.BookCopyStatusAvailable)
+}
@@ -0,0 +1,348 @@
+package repository
+
+import (
+	"book_service/domain/dto"
+	"book_service/domain/model"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BookHoldRepo struct {
+	db *gorm.DB
+}
+
+type IBookHoldRepo interface {
+	Create(bookHold *model.BookHold) error
+	GetByUUID(uuid string) (*model.BookHold, error)
+	GetList(
+		ctx context.Context,
+		params dto.BookHoldRepo_GetListParams,
+	) ([]model.BookHold, error)
+	CountGetList(
+		ctx context.Context,
+		params dto.BookHoldRepo_GetListParams,
+	) (int64, error)
+	GetQueuePosition(bookHold *model.BookHold) (int64, error)
+	CountReserved(bookUUID string, excludeUserUUID string, now time.Time) (int64, error)
+	Cancel(bookHold *model.BookHold) error
+	// CancelByUserUUID cancels the active holds of the user once, for the
+	// given processed event, and returns the number of holds cancelled.
+	CancelByUserUUID(userUUID string, now time.Time, processed *model.ProcessedEvent) (int64, error)
+	SyncQueue(bookUUID string, now time.Time, readyWindow time.Duration) ([]model.BookHold, error)
+	GetBookUUIDsWithActiveHolds() ([]string, error)
+}
+
+func NewBookHoldRepo(db *gorm.DB) IBookHoldRepo {
+	return &BookHoldRepo{
+		db: db,
+	}
+}
+
+func (repo *BookHoldRepo) Create(bookHold *model.BookHold) error {
+	err := repo.db.Create(bookHold).Error
+	if err != nil {
+		return errors.New("failed to create: " + err.Error())
+	}
+	return err
+}
+
+func (repo *BookHoldRepo) GetByUUID(uuid string) (*model.BookHold, error) {
+	var bookHold model.BookHold
+	if err := repo.db.First(&bookHold, "uuid = ?", uuid).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("not found")
+		}
+		return nil, errors.New("failed to get: " + err.Error())
+	}
+	return &bookHold, nil
+}
+
+func (repo *BookHoldRepo) GetList(
+	ctx context.Context,
+	params dto.BookHoldRepo_GetListParams,
+) ([]model.BookHold, error) {
+	// validate param
+	if params.SortOrder != "asc" && params.SortOrder != "desc" {
+		return nil, fmt.Errorf("invalid sort order")
+	}
+
+	var models []model.BookHold
+
+	tx := repo.db.Model(&models)
+
+	if params.BookUUID != "" {
+		tx = tx.Where("book_uuid = ?", params.BookUUID)
+	}
+
+	if params.UserUUID != "" {
+		tx = tx.Where("user_uuid = ?", params.UserUUID)
+	}
+
+	if len(params.Statuses) > 0 {
+		tx = tx.Where("status IN ?", params.Statuses)
+	}
+
+	if params.Page > 0 && params.Limit > 0 {
+		offset := (params.Page - 1) * params.Limit
+		tx = tx.Offset(offset).Limit(params.Limit)
+	}
+
+	if params.SortOrder != "" && params.SortBy != "" {
+		tx = tx.Order(fmt.Sprintf("%s %s", params.SortBy, params.SortOrder))
+	}
+
+	err := tx.Find(&models).Error
+	if err != nil {
+		return nil, errors.New("failed to get: " + err.Error())
+	}
+
+	return models, nil
+}
+
+func (repo *BookHoldRepo) CountGetList(
+	ctx context.Context,
+	params dto.BookHoldRepo_GetListParams,
+) (int64, error) {
+	tx := repo.db.Model(&model.BookHold{})
+
+	if params.BookUUID != "" {
+		tx = tx.Where("book_uuid = ?", params.BookUUID)
+	}
+
+	if params.UserUUID != "" {
+		tx = tx.Where("user_uuid = ?", params.UserUUID)
+	}
+
+	if len(params.Statuses) > 0 {
+		tx = tx.Where("status IN ?", params.Statuses)
+	}
+
+	var count int64
+	err := tx.Count(&count).Error
+	if err != nil {
+		return 0, errors.New("failed to count: " + err.Error())
+	}
+
+	return count, nil
+}
+
+// GetQueuePosition returns the 1 based position of a waiting hold in its book
+// queue. holds that are not waiting have no position and get 0.
+func (repo *BookHoldRepo) GetQueuePosition(bookHold *model.BookHold) (int64, error) {
+	if bookHold.Status != model.BookHoldStatusWaiting {
+		return 0, nil
+	}
+
+	var position int64
+	err := repo.db.Model(&model.BookHold{}).
+		Where("book_uuid = ? AND status = ? AND id <= ?", bookHold.BookUUID, model.BookHoldStatusWaiting, bookHold.ID).
+		Count(&position).Error
+	if err != nil {
+		return 0, errors.New("failed to count: " + err.Error())
+	}
+
+	return position, nil
+}
+
+// CountReserved counts the holds on a book that are still claiming a copy,
+// leaving out the ones of excludeUserUUID.
+func (repo *BookHoldRepo) CountReserved(bookUUID string, excludeUserUUID string, now time.Time) (int64, error) {
+	count, err := countReservedHolds(repo.db, bookUUID, excludeUserUUID, now)
+	if err != nil {
+		return 0, errors.New("failed to count: " + err.Error())
+	}
+
+	return count, nil
+}
+
+func (repo *BookHoldRepo) Cancel(bookHold *model.BookHold) error {
+	res := repo.db.Model(&model.BookHold{}).
+		Where("uuid = ? AND status IN ?", bookHold.UUID, []string{model.BookHoldStatusWaiting, model.BookHoldStatusReady}).
+		Updates(map[string]interface{}{
+			"status":    model.BookHoldStatusCancelled,
+			"closed_at": bookHold.ClosedAt,
+		})
+	if res.Error != nil {
+		return errors.New("failed to update: " + res.Error.Error())
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("not active")
+	}
+
+	bookHold.Status = model.BookHoldStatusCancelled
+	return nil
+}
+
+func (repo *BookHoldRepo) CancelByUserUUID(userUUID string, now time.Time, processed *model.ProcessedEvent) (int64, error) {
+	var total int64
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		err := markProcessed(tx, processed)
+		if err != nil {
+			return err
+		}
+
+		res := tx.Model(&model.BookHold{}).
+			Where("user_uuid = ? AND status IN ?", userUUID, []string{model.BookHoldStatusWaiting, model.BookHoldStatusReady}).
+			Updates(map[string]interface{}{
+				"status":    model.BookHoldStatusCancelled,
+				"closed_at": now,
+			})
+		if res.Error != nil {
+			return errors.New("failed to update: " + res.Error.Error())
+		}
+		total = res.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// SyncQueue moves the hold queue of a book forward in a single transaction.
+// ready holds past their pickup window are expired, then the oldest waiting
+// holds become ready for as long as there are available copies that are not
+// already reserved. the holds that just became ready are returned.
+func (repo *BookHoldRepo) SyncQueue(bookUUID string, now time.Time, readyWindow time.Duration) ([]model.BookHold, error) {
+	var promoted []model.BookHold
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		// lock the book so concurrent syncs do not promote the same copy twice
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&model.Book{}, "uuid = ?", bookUUID).Error
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.New("not found")
+			}
+			return errors.New("failed to get book: " + err.Error())
+		}
+
+		res := tx.Model(&model.BookHold{}).
+			Where("book_uuid = ? AND status = ? AND expires_at <= ?", bookUUID, model.BookHoldStatusReady, now).
+			Updates(map[string]interface{}{
+				"status":    model.BookHoldStatusExpired,
+				"closed_at": now,
+			})
+		if res.Error != nil {
+			return errors.New("failed to expire holds: " + res.Error.Error())
+		}
+
+		var readyCount int64
+		err = tx.Model(&model.BookHold{}).
+			Where("book_uuid = ? AND status = ?", bookUUID, model.BookHoldStatusReady).
+			Count(&readyCount).Error
+		if err != nil {
+			return errors.New("failed to count: " + err.Error())
+		}
+
+		var available int64
+		err = availableCopiesQuery(tx, bookUUID).Count(&available).Error
+		if err != nil {
+			return errors.New("failed to count copies: " + err.Error())
+		}
+
+		free := available - readyCount
+		if free <= 0 {
+			return nil
+		}
+
+		err = tx.Where("book_uuid = ? AND status = ?", bookUUID, model.BookHoldStatusWaiting).
+			Order("id asc").
+			Limit(int(free)).
+			Find(&promoted).Error
+		if err != nil {
+			return errors.New("failed to get: " + err.Error())
+		}
+		if len(promoted) == 0 {
+			return nil
+		}
+
+		expiresAt := now.Add(readyWindow)
+		ids := []uint{}
+		for i := range promoted {
+			ids = append(ids, promoted[i].ID)
+			promoted[i].Status = model.BookHoldStatusReady
+			promoted[i].ReadyAt = &now
+			promoted[i].ExpiresAt = &expiresAt
+		}
+
+		res = tx.Model(&model.BookHold{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"status":     model.BookHoldStatusReady,
+				"ready_at":   now,
+				"expires_at": expiresAt,
+			})
+		if res.Error != nil {
+			return errors.New("failed to update: " + res.Error.Error())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return promoted, nil
+}
+
+func (repo *BookHoldRepo) GetBookUUIDsWithActiveHolds() ([]string, error) {
+	var bookUUIDs []string
+	err := repo.db.Model(&model.BookHold{}).
+		Distinct("book_uuid").
+		Where("status IN ?", []string{model.BookHoldStatusWaiting, model.BookHoldStatusReady}).
+		Pluck("book_uuid", &bookUUIDs).Error
+	if err != nil {
+		return nil, errors.New("failed to get: " + err.Error())
+	}
+
+	return bookUUIDs, nil
+}
+
+// countReservedHolds counts the active holds of other users on the book that
+// claim a copy against userUUID.
+func countReservedHolds(db *gorm.DB, bookUUID interface{}, userUUID string, now time.Time) (int64, error) {
+	var holds []model.BookHold
+	err := db.Model(&model.BookHold{}).
+		Where("book_uuid = ?", bookUUID).
+		Where("status IN ?", []string{model.BookHoldStatusWaiting, model.BookHoldStatusReady}).
+		Find(&holds).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return reservedCopies(holds, userUUID, now), nil
+}
+
+// reservedCopies counts the holds that claim a copy against userUUID: other
+// users' ready holds still inside their pickup window, and their waiting holds
+// unless userUUID has a ready hold of its own. a ready hold already owns one of
+// the available copies, so the waiting queue behind it must not block it.
+func reservedCopies(holds []model.BookHold, userUUID string, now time.Time) int64 {
+	isReady := func(hold model.BookHold) bool {
+		return hold.Status == model.BookHoldStatusReady && hold.ExpiresAt != nil && hold.ExpiresAt.After(now)
+	}
+
+	hasReady := false
+	for _, hold := range holds {
+		if hold.UserUUID.String() == userUUID && isReady(hold) {
+			hasReady = true
+			break
+		}
+	}
+
+	var reserved int64
+	for _, hold := range holds {
+		if hold.UserUUID.String() == userUUID {
+			continue
+		}
+		if isReady(hold) || (hold.Status == model.BookHoldStatusWaiting && !hasReady) {
+			reserved++
+		}
+	}
+
+	return reserved
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"book_service/domain/model"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestReservedCopies(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	later := now.Add(time.Hour)
+	earlier := now.Add(-time.Hour)
+
+	userA := uuid.New()
+	userB := uuid.New()
+	userC := uuid.New()
+
+	readyA := model.BookHold{UserUUID: userA, Status: model.BookHoldStatusReady, ExpiresAt: &later}
+	waitingB := model.BookHold{UserUUID: userB, Status: model.BookHoldStatusWaiting}
+	expiredA := model.BookHold{UserUUID: userA, Status: model.BookHoldStatusReady, ExpiresAt: &earlier}
+
+	tests := []struct {
+		name  string
+		holds []model.BookHold
+		user  uuid.UUID
+		want  int64
+	}{
+		{
+			name:  "ready_holder_not_blocked_by_waiting",
+			holds: []model.BookHold{readyA, waitingB},
+			user:  userA,
+			want:  0,
+		},
+		{
+			name:  "waiting_holder_blocked_by_ready",
+			holds: []model.BookHold{readyA, waitingB},
+			user:  userB,
+			want:  1,
+		},
+		{
+			name:  "walk_in_blocked_by_ready_and_waiting",
+			holds: []model.BookHold{readyA, waitingB},
+			user:  userC,
+			want:  2,
+		},
+		{
+			name:  "expired_ready_hold_not_reserved",
+			holds: []model.BookHold{expiredA, waitingB},
+			user:  userC,
+			want:  1,
+		},
+		{
+			name:  "expired_ready_hold_does_not_skip_queue",
+			holds: []model.BookHold{expiredA, waitingB},
+			user:  userA,
+			want:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := reservedCopies(tt.holds, tt.user.String(), now)
+			if got != tt.want {
+				t.Errorf("reservedCopies() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
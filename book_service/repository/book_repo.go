@@ -3,10 +3,14 @@ package repository
 import (
 	"book_service/domain/dto"
 	"book_service/domain/model"
+	event_util "book_service/utils/event"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type BookRepo struct {
@@ -17,13 +21,24 @@ type IBookRepo interface {
 	Create(book *model.Book) error
 	GetByUUID(uuid string) (*model.Book, error)
 	Update(book *model.Book) error
-	Delete(id string) error
+	Delete(uuid string) error
 	GetList(
 		params dto.BookRepo_GetListParams,
 	) ([]model.Book, error)
 	CountGetList(
 		params dto.BookRepo_GetListParams,
 	) (int64, error)
+	CountByCategoryUUIDs(categoryUUIDs []string) (map[string]int64, error)
+	CountActiveBorrowsByAuthorUUID(authorUUID string) (int64, error)
+	// ReassignByAuthorUUID and DeleteByAuthorUUID lock the books of the author
+	// against new borrows and fail with "active borrows" while one is borrowed.
+	// they return the number of books changed.
+	ReassignByAuthorUUID(authorUUID string, newAuthorUUID string) (int64, error)
+	// DeleteByAuthorUUID also cancels the active holds of the books.
+	DeleteByAuthorUUID(authorUUID string, now time.Time) (int64, error)
+	// ClearCategoryUUID removes the category from its books once, for the
+	// given processed event, and returns the number of books changed.
+	ClearCategoryUUID(categoryUUID string, processed *model.ProcessedEvent) (int64, error)
 }
 
 func NewBookRepo(db *gorm.DB) IBookRepo {
@@ -33,7 +48,13 @@ func NewBookRepo(db *gorm.DB) IBookRepo {
 }
 
 func (repo *BookRepo) Create(book *model.Book) error {
-	err := repo.db.Create(book).Error
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(book).Error
+		if err != nil {
+			return err
+		}
+		return createOutboxEvent(tx, event_util.TypeBookCreated, book.UUID, book.EventPayload())
+	})
 	if err != nil {
 		return errors.New("failed to create: " + err.Error())
 	}
@@ -52,12 +73,29 @@ func (repo *BookRepo) GetByUUID(uuid string) (*model.Book, error) {
 }
 
 func (repo *BookRepo) Update(book *model.Book) error {
-	err := repo.db.Save(book).Error
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Save(book).Error
+		if err != nil {
+			return err
+		}
+		return createOutboxEvent(tx, event_util.TypeBookUpdated, book.UUID, book.EventPayload())
+	})
 	return err
 }
 
-func (repo *BookRepo) Delete(id string) error {
-	err := repo.db.Delete(&model.Book{}, "id = ?", id).Error
+func (repo *BookRepo) Delete(uuid string) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var book model.Book
+		err := tx.First(&book, "uuid = ?", uuid).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Delete(&book).Error
+		if err != nil {
+			return err
+		}
+		return createOutboxEvent(tx, event_util.TypeBookDeleted, book.UUID, book.EventPayload())
+	})
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return errors.New("not found")
@@ -87,9 +125,17 @@ func (repo *BookRepo) GetList(
 		}
 	}
 
+	if params.CategoryUUID != "" {
+		if params.CategoryUUID == "null" {
+			tx = tx.Where("category_uuid IS NULL")
+		} else {
+			tx = tx.Where("category_uuid = ?", params.CategoryUUID)
+		}
+	}
+
 	if params.Query != "" {
 		if params.QueryBy != "" {
-			tx = tx.Where("? LIKE ?", params.QueryBy, "%"+params.Query+"%")
+			tx = tx.Where(fmt.Sprintf("%s LIKE ?", params.QueryBy), "%"+params.Query+"%")
 		} else {
 			tx = tx.Where(
 				`
@@ -130,9 +176,17 @@ func (repo *BookRepo) CountGetList(
 		}
 	}
 
+	if params.CategoryUUID != "" {
+		if params.CategoryUUID == "null" {
+			tx = tx.Where("category_uuid IS NULL")
+		} else {
+			tx = tx.Where("category_uuid = ?", params.CategoryUUID)
+		}
+	}
+
 	if params.Query != "" {
 		if params.QueryBy != "" {
-			tx = tx.Where("? LIKE ?", params.QueryBy, "%"+params.Query+"%")
+			tx = tx.Where(fmt.Sprintf("%s LIKE ?", params.QueryBy), "%"+params.Query+"%")
 		} else {
 			tx = tx.Where(
 				`
@@ -151,3 +205,175 @@ func (repo *BookRepo) CountGetList(
 
 	return count, nil
 }
+
+// CountByCategoryUUIDs returns the book total of many categories at once,
+// keyed by category uuid. categories without books are left out.
+func (repo *BookRepo) CountByCategoryUUIDs(categoryUUIDs []string) (map[string]int64, error) {
+	var rows []struct {
+		CategoryUUID string
+		Total        int64
+	}
+	err := repo.db.Model(&model.Book{}).
+		Select("category_uuid, COUNT(*) AS total").
+		Where("category_uuid IN ?", categoryUUIDs).
+		Group("category_uuid").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, errors.New("failed to count: " + err.Error())
+	}
+
+	result := map[string]int64{}
+	for _, row := range rows {
+		result[row.CategoryUUID] = row.Total
+	}
+	return result, nil
+}
+
+func (repo *BookRepo) CountActiveBorrowsByAuthorUUID(authorUUID string) (int64, error) {
+	var count int64
+	err := activeBorrowsByAuthorQuery(repo.db, authorUUID).Count(&count).Error
+	if err != nil {
+		return 0, errors.New("failed to count: " + err.Error())
+	}
+	return count, nil
+}
+
+func (repo *BookRepo) ReassignByAuthorUUID(authorUUID string, newAuthorUUID string) (int64, error) {
+	parsedNewAuthorUUID, err := uuid.Parse(newAuthorUUID)
+	if err != nil {
+		return 0, errors.New("invalid new author uuid")
+	}
+
+	var total int64
+	err = repo.db.Transaction(func(tx *gorm.DB) error {
+		books, err := lockAuthorBooks(tx, authorUUID)
+		if err != nil {
+			return err
+		}
+
+		res := tx.Model(&model.Book{}).
+			Where("author_uuid = ?", authorUUID).
+			Update("author_uuid", newAuthorUUID)
+		if res.Error != nil {
+			return errors.New("failed to update: " + res.Error.Error())
+		}
+		total = res.RowsAffected
+
+		for _, book := range books {
+			book.AuthorUUID = parsedNewAuthorUUID
+			err = createOutboxEvent(tx, event_util.TypeBookUpdated, book.UUID, book.EventPayload())
+			if err != nil {
+				return errors.New("failed to create event: " + err.Error())
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (repo *BookRepo) DeleteByAuthorUUID(authorUUID string, now time.Time) (int64, error) {
+	var total int64
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		books, err := lockAuthorBooks(tx, authorUUID)
+		if err != nil {
+			return err
+		}
+
+		res := tx.Model(&model.BookHold{}).
+			Where("book_uuid IN (?)", tx.Model(&model.Book{}).Select("uuid").Where("author_uuid = ?", authorUUID)).
+			Where("status IN ?", []string{model.BookHoldStatusWaiting, model.BookHoldStatusReady}).
+			Updates(map[string]interface{}{
+				"status":    model.BookHoldStatusCancelled,
+				"closed_at": now,
+			})
+		if res.Error != nil {
+			return errors.New("failed to cancel holds: " + res.Error.Error())
+		}
+
+		res = tx.Delete(&model.Book{}, "author_uuid = ?", authorUUID)
+		if res.Error != nil {
+			return errors.New("failed to delete: " + res.Error.Error())
+		}
+		total = res.RowsAffected
+
+		for _, book := range books {
+			err = createOutboxEvent(tx, event_util.TypeBookDeleted, book.UUID, book.EventPayload())
+			if err != nil {
+				return errors.New("failed to create event: " + err.Error())
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (repo *BookRepo) ClearCategoryUUID(categoryUUID string, processed *model.ProcessedEvent) (int64, error) {
+	var total int64
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		err := markProcessed(tx, processed)
+		if err != nil {
+			return err
+		}
+
+		var books []model.Book
+		err = tx.Where("category_uuid = ?", categoryUUID).Find(&books).Error
+		if err != nil {
+			return errors.New("failed to get books: " + err.Error())
+		}
+
+		res := tx.Model(&model.Book{}).
+			Where("category_uuid = ?", categoryUUID).
+			Update("category_uuid", nil)
+		if res.Error != nil {
+			return errors.New("failed to update: " + res.Error.Error())
+		}
+		total = res.RowsAffected
+
+		for _, book := range books {
+			book.CategoryUUID = nil
+			err = createOutboxEvent(tx, event_util.TypeBookUpdated, book.UUID, book.EventPayload())
+			if err != nil {
+				return errors.New("failed to create event: " + err.Error())
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// lockAuthorBooks locks the books of the author the way borrowing does, then
+// checks that none of them is borrowed. the locked books are returned.
+func lockAuthorBooks(tx *gorm.DB, authorUUID string) ([]model.Book, error) {
+	var books []model.Book
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("author_uuid = ?", authorUUID).
+		Find(&books).Error
+	if err != nil {
+		return nil, errors.New("failed to get books: " + err.Error())
+	}
+
+	var activeBorrows int64
+	err = activeBorrowsByAuthorQuery(tx, authorUUID).Count(&activeBorrows).Error
+	if err != nil {
+		return nil, errors.New("failed to count borrows: " + err.Error())
+	}
+	if activeBorrows > 0 {
+		return nil, errors.New("active borrows")
+	}
+	return books, nil
+}
+
+func activeBorrowsByAuthorQuery(db *gorm.DB, authorUUID string) *gorm.DB {
+	return db.Model(&model.BookBorrow{}).
+		Joins("JOIN books ON books.uuid = book_borrows.book_uuid AND books.deleted_at IS NULL").
+		Where("books.author_uuid = ? AND book_borrows.returned_at IS NULL", authorUUID)
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"book_service/domain/dto"
+	"book_service/domain/model"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+type FineLedgerRepo struct {
+	db *gorm.DB
+}
+
+type IFineLedgerRepo interface {
+	Create(entry *model.FineLedgerEntry) error
+	GetList(
+		params dto.FineLedgerRepo_GetListParams,
+	) ([]model.FineLedgerEntry, error)
+	CountGetList(
+		params dto.FineLedgerRepo_GetListParams,
+	) (int64, error)
+	GetBalanceByUserUUID(userUUID string) (int64, error)
+}
+
+func NewFineLedgerRepo(db *gorm.DB) IFineLedgerRepo {
+	return &FineLedgerRepo{
+		db: db,
+	}
+}
+
+func (repo *FineLedgerRepo) Create(entry *model.FineLedgerEntry) error {
+	err := repo.db.Create(entry).Error
+	if err != nil {
+		return errors.New("failed to create: " + err.Error())
+	}
+	return err
+}
+
+func (repo *FineLedgerRepo) GetList(
+	params dto.FineLedgerRepo_GetListParams,
+) ([]model.FineLedgerEntry, error) {
+	// validate param
+	if params.SortOrder != "asc" && params.SortOrder != "desc" {
+		return nil, fmt.Errorf("invalid sort order")
+	}
+
+	var models []model.FineLedgerEntry
+
+	tx := repo.db.Model(&models)
+
+	if params.UserUUID != "" {
+		tx = tx.Where("user_uuid = ?", params.UserUUID)
+	}
+
+	if params.Type != "" {
+		tx = tx.Where("type = ?", params.Type)
+	}
+
+	if params.Page > 0 && params.Limit > 0 {
+		offset := (params.Page - 1) * params.Limit
+		tx = tx.Offset(offset).Limit(params.Limit)
+	}
+
+	if params.SortOrder != "" && params.SortBy != "" {
+		tx = tx.Order(fmt.Sprintf("%s %s", params.SortBy, params.SortOrder))
+	}
+
+	err := tx.Find(&models).Error
+	if err != nil {
+		return nil, errors.New("failed to get: " + err.Error())
+	}
+
+	return models, nil
+}
+
+func (repo *FineLedgerRepo) CountGetList(
+	params dto.FineLedgerRepo_GetListParams,
+) (int64, error) {
+	tx := repo.db.Model(&model.FineLedgerEntry{})
+
+	if params.UserUUID != "" {
+		tx = tx.Where("user_uuid = ?", params.UserUUID)
+	}
+
+	if params.Type != "" {
+		tx = tx.Where("type = ?", params.Type)
+	}
+
+	var count int64
+	err := tx.Count(&count).Error
+	if err != nil {
+		return 0, errors.New("failed to count: " + err.Error())
+	}
+
+	return count, nil
+}
+
+func (repo *FineLedgerRepo) GetBalanceByUserUUID(userUUID string) (int64, error) {
+	var balance int64
+	err := repo.db.Model(&model.FineLedgerEntry{}).
+		Select(
+			"COALESCE(SUM(CASE WHEN type = ? THEN amount ELSE -amount END), 0)",
+			model.FineEntryTypeCharge,
+		).
+		Where("user_uuid = ?", userUUID).
+		Scan(&balance).Error
+	if err != nil {
+		return 0, errors.New("failed to get balance: " + err.Error())
+	}
+
+	return balance, nil
+}
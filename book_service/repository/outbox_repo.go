@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"book_service/domain/model"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// advisory lock held by the relay publishing, so events are published in the
+// order they were written even with several instances running
+const outboxRelayLockKey = 72616302
+
+type OutboxRepo struct {
+	db *gorm.DB
+}
+
+type IOutboxRepo interface {
+	// RelayBatch hands the oldest unpublished events to publish in order and
+	// marks them published. it stops at the first failure, recorded on the
+	// event, and returns 0 while another relay holds the lock.
+	RelayBatch(limit int, now time.Time, publish func(event model.OutboxEvent) error) (int, error)
+}
+
+func NewOutboxRepo(db *gorm.DB) IOutboxRepo {
+	return &OutboxRepo{db: db}
+}
+
+func (repo *OutboxRepo) RelayBatch(limit int, now time.Time, publish func(event model.OutboxEvent) error) (int, error) {
+	published := 0
+	var publishErr error
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var locked bool
+		err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", outboxRelayLockKey).Scan(&locked).Error
+		if err != nil {
+			return err
+		}
+		if !locked {
+			return nil
+		}
+
+		var events []model.OutboxEvent
+		err = tx.Where("published_at IS NULL").Order("id asc").Limit(limit).Find(&events).Error
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			publishErr = publish(event)
+			if publishErr != nil {
+				return tx.Model(&model.OutboxEvent{}).
+					Where("id = ?", event.ID).
+					Updates(map[string]interface{}{
+						"attempts":   gorm.Expr("attempts + 1"),
+						"last_error": publishErr.Error(),
+					}).Error
+			}
+
+			err = tx.Model(&model.OutboxEvent{}).
+				Where("id = ?", event.ID).
+				Updates(map[string]interface{}{
+					"published_at": now,
+					"attempts":     gorm.Expr("attempts + 1"),
+					"last_error":   nil,
+				}).Error
+			if err != nil {
+				return err
+			}
+			published++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, errors.New("failed to relay: " + err.Error())
+	}
+	return published, publishErr
+}
+
+// createOutboxEvent writes the event in the transaction of the change it
+// describes.
+func createOutboxEvent(tx *gorm.DB, eventType string, aggregateUUID uuid.UUID, payload interface{}) error {
+	event, err := model.NewOutboxEvent(eventType, aggregateUUID, payload)
+	if err != nil {
+		return err
+	}
+	return tx.Create(event).Error
+}
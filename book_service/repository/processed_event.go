@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"book_service/domain/model"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// markProcessed records the event in the transaction applying it, it fails
+// with "already processed" when the consumer applied it before.
+func markProcessed(tx *gorm.DB, processed *model.ProcessedEvent) error {
+	res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(processed)
+	if res.Error != nil {
+		return errors.New("failed to mark processed: " + res.Error.Error())
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("already processed")
+	}
+	return nil
+}
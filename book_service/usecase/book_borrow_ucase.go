@@ -0,0 +1,612 @@
+package ucase
+
+import (
+	"book_service/config"
+	"book_service/domain/dto"
+	"book_service/domain/model"
+	auth_grpc "book_service/interface/grpc/genproto/auth"
+	"book_service/repository"
+	error_utils "book_service/utils/error"
+	"book_service/utils/helper"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type BookBorrowUcase struct {
+	bookRepo       repository.IBookRepo
+	bookBorrowRepo repository.IBookBorrowRepo
+	fineLedgerRepo repository.IFineLedgerRepo
+	bookHoldRepo   repository.IBookHoldRepo
+
+	authGrpcServiceClient auth_grpc.AuthServiceClient
+}
+
+type IBookBorrowUcase interface {
+	BorrowBook(
+		ctx context.Context,
+		currentUser dto.CurrentUser,
+		bookUUID string,
+	) (*dto.BorrowBookRespData, error)
+	ReturnBook(
+		ctx context.Context,
+		currentUser dto.CurrentUser,
+		borrowUUID string,
+	) (*dto.ReturnBookRespData, error)
+	RenewBook(
+		ctx context.Context,
+		currentUser dto.CurrentUser,
+		borrowUUID string,
+	) (*dto.RenewBookRespData, error)
+	GetBorrowList(
+		ctx context.Context,
+		currentUser dto.CurrentUser,
+		params dto.GetBorrowListReq,
+	) (*dto.GetBorrowListRespData, error)
+}
+
+func NewBookBorrowUcase(
+	bookRepo repository.IBookRepo,
+	bookBorrowRepo repository.IBookBorrowRepo,
+	fineLedgerRepo repository.IFineLedgerRepo,
+	bookHoldRepo repository.IBookHoldRepo,
+	authGrpcServiceClient auth_grpc.AuthServiceClient,
+) IBookBorrowUcase {
+	return &BookBorrowUcase{
+		bookRepo:       bookRepo,
+		bookBorrowRepo: bookBorrowRepo,
+		fineLedgerRepo: fineLedgerRepo,
+		bookHoldRepo:   bookHoldRepo,
+
+		authGrpcServiceClient: authGrpcServiceClient,
+	}
+}
+
+func (ucase *BookBorrowUcase) BorrowBook(
+	ctx context.Context,
+	currentUser dto.CurrentUser,
+	bookUUID string,
+) (*dto.BorrowBookRespData, error) {
+	parsedUserUUID, err := uuid.Parse(currentUser.UUID)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 401,
+			GrpcCode: codes.Unauthenticated,
+			Message:  "unauthorized",
+			Detail:   "invalid current user uuid",
+		}
+	}
+
+	// check verified email
+	if config.Envs.BORROW_REQUIRE_VERIFIED_EMAIL {
+		emailVerified, err := ucase.isEmailVerified(ctx, currentUser)
+		if err != nil {
+			return nil, err
+		}
+		if !emailVerified {
+			return nil, &error_utils.CustomErr{
+				HttpCode: 403,
+				GrpcCode: codes.PermissionDenied,
+				Message:  "email not verified",
+				Detail:   "verify your email before borrowing books",
+			}
+		}
+	}
+
+	// check outstanding fines
+	fineBalance, err := ucase.fineLedgerRepo.GetBalanceByUserUUID(currentUser.UUID)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+	if fineBalance > config.Envs.FINE_BLOCK_THRESHOLD {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 403,
+			GrpcCode: codes.FailedPrecondition,
+			Message:  "outstanding fines",
+			Detail:   fmt.Sprintf("outstanding fine balance %d is above the allowed %d", fineBalance, config.Envs.FINE_BLOCK_THRESHOLD),
+		}
+	}
+
+	// find book
+	book, err := ucase.bookRepo.GetByUUID(bookUUID)
+	if err != nil {
+		if err.Error() == "not found" {
+			logger.Errorf("err: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 404,
+				GrpcCode: codes.NotFound,
+				Message:  "not found",
+				Detail:   err,
+			}
+		} else {
+			logger.Errorf("err: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: codes.Internal,
+				Message:  "internal server error",
+				Detail:   err,
+			}
+		}
+	}
+
+	// borrow book
+	borrowedAt := helper.TimeNowUTC()
+	newBookBorrow := &model.BookBorrow{
+		UUID:       uuid.New(),
+		BookUUID:   book.UUID,
+		UserUUID:   parsedUserUUID,
+		BorrowedAt: borrowedAt,
+		DueAt:      borrowedAt.Add(loanPeriod()),
+	}
+
+	// a user still holding a copy of this book is refused by the repo, under
+	// the same lock that hands out the copy
+	err = ucase.bookBorrowRepo.Borrow(newBookBorrow)
+	if err != nil {
+		if err.Error() == "already borrowed" {
+			return nil, &error_utils.CustomErr{
+				HttpCode: 400,
+				GrpcCode: codes.AlreadyExists,
+				Message:  "conflict",
+				Detail:   "book already borrowed",
+			}
+		}
+		if err.Error() == "out of stock" {
+			return nil, &error_utils.CustomErr{
+				HttpCode: 400,
+				GrpcCode: codes.FailedPrecondition,
+				Message:  "out of stock",
+				Detail:   "no copy available, place a hold instead",
+			}
+		}
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
+	return &dto.BorrowBookRespData{
+		UUID:     newBookBorrow.UUID.String(),
+		BookUUID: newBookBorrow.BookUUID.String(),
+		UserUUID: newBookBorrow.UserUUID.String(),
+		CopyUUID: func() *string {
+			if newBookBorrow.CopyUUID == nil {
+				return nil
+			}
+			tmp := newBookBorrow.CopyUUID.String()
+			return &tmp
+		}(),
+		BorrowedAt: newBookBorrow.BorrowedAt,
+		DueAt:      newBookBorrow.DueAt,
+		ReturnedAt: newBookBorrow.ReturnedAt,
+		RenewCount: newBookBorrow.RenewCount,
+		CreatedAt:  newBookBorrow.CreatedAt,
+		UpdatedAt:  newBookBorrow.UpdatedAt,
+	}, nil
+}
+
+func (ucase *BookBorrowUcase) ReturnBook(
+	ctx context.Context,
+	currentUser dto.CurrentUser,
+	borrowUUID string,
+) (*dto.ReturnBookRespData, error) {
+	// find borrow
+	bookBorrow, err := ucase.bookBorrowRepo.GetByUUID(borrowUUID)
+	if err != nil {
+		if err.Error() == "not found" {
+			logger.Errorf("err: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 404,
+				GrpcCode: codes.NotFound,
+				Message:  "not found",
+				Detail:   err,
+			}
+		} else {
+			logger.Errorf("err: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: codes.Internal,
+				Message:  "internal server error",
+				Detail:   err,
+			}
+		}
+	}
+
+	// validate user
+	if bookBorrow.UserUUID.String() != currentUser.UUID && !currentUser.HasPermission(dto.PermissionBorrowManage) {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 403,
+			GrpcCode: codes.PermissionDenied,
+			Message:  "forbidden",
+			Detail:   "forbidden",
+		}
+	}
+
+	if bookBorrow.ReturnedAt != nil {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.FailedPrecondition,
+			Message:  "already returned",
+			Detail:   "book already returned",
+		}
+	}
+
+	// return book
+	returnedAt := helper.TimeNowUTC()
+	bookBorrow.ReturnedAt = &returnedAt
+
+	// accrue late fine
+	var fine *model.FineLedgerEntry
+	if fineAmount := calculateLateFine(bookBorrow.DueAt, returnedAt); fineAmount > 0 {
+		note := fmt.Sprintf("late return, due at %s", bookBorrow.DueAt.Format(time.RFC3339))
+		fine = &model.FineLedgerEntry{
+			UUID:       uuid.New(),
+			UserUUID:   bookBorrow.UserUUID,
+			BorrowUUID: &bookBorrow.UUID,
+			Type:       model.FineEntryTypeCharge,
+			Amount:     fineAmount,
+			Note:       &note,
+		}
+	}
+
+	err = ucase.bookBorrowRepo.Return(bookBorrow, fine)
+	if err != nil {
+		if err.Error() == "already returned" {
+			return nil, &error_utils.CustomErr{
+				HttpCode: 400,
+				GrpcCode: codes.FailedPrecondition,
+				Message:  "already returned",
+				Detail:   "book already returned",
+			}
+		}
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
+	// hand the returned copy to the next hold in queue
+	syncHoldQueue(ucase.bookHoldRepo, bookBorrow.BookUUID.String(), returnedAt)
+
+	return &dto.ReturnBookRespData{
+		UUID:     bookBorrow.UUID.String(),
+		BookUUID: bookBorrow.BookUUID.String(),
+		UserUUID: bookBorrow.UserUUID.String(),
+		CopyUUID: func() *string {
+			if bookBorrow.CopyUUID == nil {
+				return nil
+			}
+			tmp := bookBorrow.CopyUUID.String()
+			return &tmp
+		}(),
+		BorrowedAt: bookBorrow.BorrowedAt,
+		DueAt:      bookBorrow.DueAt,
+		ReturnedAt: bookBorrow.ReturnedAt,
+		RenewCount: bookBorrow.RenewCount,
+		Overdue:    returnedAt.After(bookBorrow.DueAt),
+		FineAmount: func() int64 {
+			if fine == nil {
+				return 0
+			}
+			return fine.Amount
+		}(),
+		CreatedAt: bookBorrow.CreatedAt,
+		UpdatedAt: bookBorrow.UpdatedAt,
+	}, nil
+}
+
+func (ucase *BookBorrowUcase) RenewBook(
+	ctx context.Context,
+	currentUser dto.CurrentUser,
+	borrowUUID string,
+) (*dto.RenewBookRespData, error) {
+	// find borrow
+	bookBorrow, err := ucase.bookBorrowRepo.GetByUUID(borrowUUID)
+	if err != nil {
+		if err.Error() == "not found" {
+			logger.Errorf("err: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 404,
+				GrpcCode: codes.NotFound,
+				Message:  "not found",
+				Detail:   err,
+			}
+		} else {
+			logger.Errorf("err: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: codes.Internal,
+				Message:  "internal server error",
+				Detail:   err,
+			}
+		}
+	}
+
+	// validate user
+	if bookBorrow.UserUUID.String() != currentUser.UUID && !currentUser.HasPermission(dto.PermissionBorrowManage) {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 403,
+			GrpcCode: codes.PermissionDenied,
+			Message:  "forbidden",
+			Detail:   "forbidden",
+		}
+	}
+
+	if bookBorrow.ReturnedAt != nil {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.FailedPrecondition,
+			Message:  "already returned",
+			Detail:   "book already returned",
+		}
+	}
+
+	timeNow := helper.TimeNowUTC()
+	if bookBorrow.IsOverdue(timeNow) {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.FailedPrecondition,
+			Message:  "overdue",
+			Detail:   "overdue borrow cannot be renewed",
+		}
+	}
+
+	maxRenewals, err := ucase.getMaxRenewals(ctx, currentUser, bookBorrow.UserUUID.String())
+	if err != nil {
+		return nil, err
+	}
+	if bookBorrow.RenewCount >= maxRenewals {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.ResourceExhausted,
+			Message:  "renew limit reached",
+			Detail:   fmt.Sprintf("borrow can only be renewed %d times", maxRenewals),
+		}
+	}
+
+	// a fresh loan period starts from now, but never shortens the current one
+	newDueAt := timeNow.Add(loanPeriod())
+	if newDueAt.Before(bookBorrow.DueAt) {
+		newDueAt = bookBorrow.DueAt
+	}
+	bookBorrow.DueAt = newDueAt
+
+	err = ucase.bookBorrowRepo.Renew(bookBorrow, maxRenewals)
+	if err != nil {
+		if err.Error() == "renew limit reached" {
+			return nil, &error_utils.CustomErr{
+				HttpCode: 400,
+				GrpcCode: codes.ResourceExhausted,
+				Message:  "renew limit reached",
+				Detail:   fmt.Sprintf("borrow can only be renewed %d times", maxRenewals),
+			}
+		}
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
+	return &dto.RenewBookRespData{
+		UUID:            bookBorrow.UUID.String(),
+		BookUUID:        bookBorrow.BookUUID.String(),
+		UserUUID:        bookBorrow.UserUUID.String(),
+		BorrowedAt:      bookBorrow.BorrowedAt,
+		DueAt:           bookBorrow.DueAt,
+		RenewCount:      bookBorrow.RenewCount,
+		RenewsRemaining: maxRenewals - bookBorrow.RenewCount,
+		CreatedAt:       bookBorrow.CreatedAt,
+		UpdatedAt:       bookBorrow.UpdatedAt,
+	}, nil
+}
+
+func (ucase *BookBorrowUcase) GetBorrowList(
+	ctx context.Context,
+	currentUser dto.CurrentUser,
+	params dto.GetBorrowListReq,
+) (*dto.GetBorrowListRespData, error) {
+	// users without borrow:manage can only see their own borrows
+	userUUID := params.UserUUID
+	if !currentUser.HasPermission(dto.PermissionBorrowManage) {
+		userUUID = currentUser.UUID
+	}
+
+	repoParams := dto.BookBorrowRepo_GetListParams{
+		BookUUID:  params.BookUUID,
+		UserUUID:  userUUID,
+		Page:      params.Page,
+		Limit:     params.Limit,
+		SortOrder: params.SortOrder,
+		SortBy:    params.SortBy,
+	}
+
+	// prepare status filter
+	timeNow := helper.TimeNowUTC()
+	switch params.Status {
+	case "active":
+		returned := false
+		repoParams.Returned = &returned
+	case "returned":
+		returned := true
+		repoParams.Returned = &returned
+	case "overdue":
+		returned := false
+		repoParams.Returned = &returned
+		repoParams.DueBefore = &timeNow
+	}
+
+	bookBorrows, err := ucase.bookBorrowRepo.GetList(ctx, repoParams)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
+	count, err := ucase.bookBorrowRepo.CountGetList(ctx, repoParams)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
+	res := &dto.GetBorrowListRespData{
+		Data: []dto.GetBorrowListRespDataItem{},
+	}
+	res.Set(params.Page, params.Limit, count)
+	for _, bookBorrow := range bookBorrows {
+		res.Data = append(res.Data, dto.GetBorrowListRespDataItem{
+			UUID:     bookBorrow.UUID.String(),
+			BookUUID: bookBorrow.BookUUID.String(),
+			UserUUID: bookBorrow.UserUUID.String(),
+			CopyUUID: func() *string {
+				if bookBorrow.CopyUUID == nil {
+					return nil
+				}
+				tmp := bookBorrow.CopyUUID.String()
+				return &tmp
+			}(),
+			BorrowedAt: bookBorrow.BorrowedAt,
+			DueAt:      bookBorrow.DueAt,
+			ReturnedAt: bookBorrow.ReturnedAt,
+			RenewCount: bookBorrow.RenewCount,
+			Overdue:    bookBorrow.IsOverdue(timeNow),
+			CreatedAt:  bookBorrow.CreatedAt,
+			UpdatedAt:  bookBorrow.UpdatedAt,
+		})
+	}
+
+	return res, nil
+}
+
+func loanPeriod() time.Duration {
+	return time.Hour * 24 * time.Duration(config.Envs.LOAN_PERIOD_DAYS)
+}
+
+// calculateLateFine charges the daily rate for every started day past the due
+// date, capped at the configured max amount.
+func calculateLateFine(dueAt time.Time, returnedAt time.Time) int64 {
+	if !returnedAt.After(dueAt) {
+		return 0
+	}
+
+	lateDuration := returnedAt.Sub(dueAt)
+	lateDays := int64(lateDuration / (time.Hour * 24))
+	if lateDuration%(time.Hour*24) != 0 {
+		lateDays++
+	}
+
+	amount := lateDays * config.Envs.FINE_DAILY_RATE
+	if config.Envs.FINE_MAX_AMOUNT > 0 && amount > config.Envs.FINE_MAX_AMOUNT {
+		amount = config.Envs.FINE_MAX_AMOUNT
+	}
+	return amount
+}
+
+// isEmailVerified asks auth_service through the token or the api key of the
+// request, so a freshly verified email counts right away.
+func (ucase *BookBorrowUcase) isEmailVerified(ctx context.Context, currentUser dto.CurrentUser) (bool, error) {
+	var emailVerified bool
+	var err error
+	if currentUser.APIKey != "" {
+		var resp *auth_grpc.ValidateAPIKeyResponse
+		resp, err = ucase.authGrpcServiceClient.ValidateAPIKey(
+			ctx, &auth_grpc.ValidateAPIKeyRequest{
+				ApiKey: currentUser.APIKey,
+			},
+		)
+		if err == nil {
+			emailVerified = resp.EmailVerified
+		}
+	} else {
+		var resp *auth_grpc.CheckTokenResponse
+		resp, err = ucase.authGrpcServiceClient.CheckToken(
+			ctx, &auth_grpc.CheckTokenRequest{
+				AccessToken: currentUser.AccessToken,
+			},
+		)
+		if err == nil {
+			emailVerified = resp.EmailVerified
+		}
+	}
+
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		if status.Code(err) == codes.Unauthenticated {
+			return false, &error_utils.CustomErr{
+				HttpCode: 401,
+				GrpcCode: codes.Unauthenticated,
+				Message:  "unauthorized",
+				Detail:   status.Convert(err).Message(),
+			}
+		}
+		return false, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+	return emailVerified, nil
+}
+
+// getMaxRenewals is the renewal limit of the borrower, whoever renews the
+// borrow. the permissions of another borrower are asked to auth_service.
+func (ucase *BookBorrowUcase) getMaxRenewals(
+	ctx context.Context,
+	currentUser dto.CurrentUser,
+	borrowerUUID string,
+) (int, error) {
+	permissions := currentUser.Permissions
+	if borrowerUUID != currentUser.UUID {
+		resp, err := ucase.authGrpcServiceClient.GetUserByUUID(
+			ctx, &auth_grpc.GetUserByUUIDRequest{
+				Uuid: borrowerUUID,
+			},
+		)
+		if err != nil && status.Code(err) != codes.NotFound {
+			logger.Errorf("err: %v", err)
+			return 0, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: codes.Internal,
+				Message:  "internal server error",
+				Detail:   err,
+			}
+		}
+		// a deleted borrower gets the default limit
+		permissions = resp.GetPermissions()
+	}
+
+	borrower := dto.CurrentUser{Permissions: permissions}
+	if borrower.HasPermission(dto.PermissionBorrowRenewExtended) {
+		return config.Envs.LOAN_MAX_RENEWALS_EXTENDED, nil
+	}
+	return config.Envs.LOAN_MAX_RENEWALS, nil
+}
@@ -0,0 +1,694 @@
+package ucase
+
+import (
+	"book_service/config"
+	"book_service/domain/dto"
+	"book_service/domain/model"
+	auth_grpc "book_service/interface/grpc/genproto/auth"
+	"book_service/repository"
+	error_utils "book_service/utils/error"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeAuthClient answers GetUserByUUID from users, keyed by uuid, and
+// CheckToken and ValidateAPIKey from verified, keyed by access token or api
+// key, unless checkErr is set.
+type fakeAuthClient struct {
+	auth_grpc.AuthServiceClient
+	users    map[string]*auth_grpc.GetUserByUUIDResponse
+	verified map[string]bool
+	checkErr error
+	checked  []string
+}
+
+func (c *fakeAuthClient) GetUserByUUID(
+	ctx context.Context,
+	in *auth_grpc.GetUserByUUIDRequest,
+	opts ...grpc.CallOption,
+) (*auth_grpc.GetUserByUUIDResponse, error) {
+	user, ok := c.users[in.Uuid]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	return user, nil
+}
+
+func (c *fakeAuthClient) CheckToken(
+	ctx context.Context,
+	in *auth_grpc.CheckTokenRequest,
+	opts ...grpc.CallOption,
+) (*auth_grpc.CheckTokenResponse, error) {
+	c.checked = append(c.checked, in.AccessToken)
+	if c.checkErr != nil {
+		return nil, c.checkErr
+	}
+	return &auth_grpc.CheckTokenResponse{EmailVerified: c.verified[in.AccessToken]}, nil
+}
+
+func (c *fakeAuthClient) ValidateAPIKey(
+	ctx context.Context,
+	in *auth_grpc.ValidateAPIKeyRequest,
+	opts ...grpc.CallOption,
+) (*auth_grpc.ValidateAPIKeyResponse, error) {
+	c.checked = append(c.checked, in.ApiKey)
+	if c.checkErr != nil {
+		return nil, c.checkErr
+	}
+	return &auth_grpc.ValidateAPIKeyResponse{EmailVerified: c.verified[in.ApiKey]}, nil
+}
+
+// fakeBookRepo keeps books by uuid, calling any method not listed here panics.
+type fakeBookRepo struct {
+	repository.IBookRepo
+	books map[string]*model.Book
+}
+
+func (repo *fakeBookRepo) GetByUUID(uuid string) (*model.Book, error) {
+	book, ok := repo.books[uuid]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return book, nil
+}
+
+// fakeBookBorrowRepo keeps borrows by uuid. Borrow and Return fail with
+// borrowErr and returnErr, the way the repo reports a conflict found under
+// its lock.
+type fakeBookBorrowRepo struct {
+	repository.IBookBorrowRepo
+	borrows   map[string]*model.BookBorrow
+	borrowErr error
+	returnErr error
+	fines     []*model.FineLedgerEntry
+}
+
+func (repo *fakeBookBorrowRepo) GetByUUID(uuid string) (*model.BookBorrow, error) {
+	bookBorrow, ok := repo.borrows[uuid]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return bookBorrow, nil
+}
+
+func (repo *fakeBookBorrowRepo) Borrow(bookBorrow *model.BookBorrow) error {
+	if repo.borrowErr != nil {
+		return repo.borrowErr
+	}
+	copyUUID := uuid.New()
+	bookBorrow.CopyUUID = &copyUUID
+	repo.borrows[bookBorrow.UUID.String()] = bookBorrow
+	return nil
+}
+
+func (repo *fakeBookBorrowRepo) Return(bookBorrow *model.BookBorrow, fine *model.FineLedgerEntry) error {
+	if repo.returnErr != nil {
+		return repo.returnErr
+	}
+	if fine != nil {
+		repo.fines = append(repo.fines, fine)
+	}
+	return nil
+}
+
+func (repo *fakeBookBorrowRepo) Renew(bookBorrow *model.BookBorrow, maxRenewals int) error {
+	if bookBorrow.RenewCount >= maxRenewals {
+		return errors.New("renew limit reached")
+	}
+	bookBorrow.RenewCount++
+	return nil
+}
+
+// GetList filters the borrows the way the repo query does, without paging.
+func (repo *fakeBookBorrowRepo) GetList(ctx context.Context, params dto.BookBorrowRepo_GetListParams) ([]model.BookBorrow, error) {
+	bookBorrows := []model.BookBorrow{}
+	for _, bookBorrow := range repo.borrows {
+		if params.UserUUID != "" && bookBorrow.UserUUID.String() != params.UserUUID {
+			continue
+		}
+		if params.Returned != nil && *params.Returned != (bookBorrow.ReturnedAt != nil) {
+			continue
+		}
+		if params.DueBefore != nil && !bookBorrow.DueAt.Before(*params.DueBefore) {
+			continue
+		}
+		bookBorrows = append(bookBorrows, *bookBorrow)
+	}
+	return bookBorrows, nil
+}
+
+func (repo *fakeBookBorrowRepo) CountGetList(ctx context.Context, params dto.BookBorrowRepo_GetListParams) (int64, error) {
+	bookBorrows, err := repo.GetList(ctx, params)
+	return int64(len(bookBorrows)), err
+}
+
+// fakeFineLedgerRepo answers every balance with balance.
+type fakeFineLedgerRepo struct {
+	repository.IFineLedgerRepo
+	balance int64
+}
+
+func (repo *fakeFineLedgerRepo) GetBalanceByUserUUID(userUUID string) (int64, error) {
+	return repo.balance, nil
+}
+
+// fakeBookHoldRepo has no hold to promote.
+type fakeBookHoldRepo struct {
+	repository.IBookHoldRepo
+	synced []string
+}
+
+func (repo *fakeBookHoldRepo) SyncQueue(bookUUID string, now time.Time, readyWindow time.Duration) ([]model.BookHold, error) {
+	repo.synced = append(repo.synced, bookUUID)
+	return nil, nil
+}
+
+func assertCustomErr(t *testing.T, err error, wantHttpCode int, wantGrpcCode codes.Code) {
+	t.Helper()
+	customErr, ok := err.(*error_utils.CustomErr)
+	if !ok {
+		t.Fatalf("expected a custom error, got %v", err)
+	}
+	if customErr.HttpCode != wantHttpCode || customErr.GrpcCode != wantGrpcCode {
+		t.Errorf("expected %d %s, got %d %s: %v", wantHttpCode, wantGrpcCode, customErr.HttpCode, customErr.GrpcCode, customErr)
+	}
+}
+
+func TestBookBorrowUcase_BorrowBook(t *testing.T) {
+	config.Envs = &config.EnvsSchema{LOAN_PERIOD_DAYS: 14, FINE_BLOCK_THRESHOLD: 100}
+	book := &model.Book{UUID: uuid.New(), Title: "Dune"}
+	currentUser := dto.CurrentUser{UUID: uuid.New().String()}
+
+	tests := []struct {
+		name         string
+		borrowErr    error
+		wantHttpCode int // 0 when borrowed
+		wantGrpcCode codes.Code
+	}{
+		{
+			name: "borrowed",
+		},
+		{
+			name:         "out_of_stock",
+			borrowErr:    errors.New("out of stock"),
+			wantHttpCode: 400,
+			wantGrpcCode: codes.FailedPrecondition,
+		},
+		{
+			name:         "already_borrowed",
+			borrowErr:    errors.New("already borrowed"),
+			wantHttpCode: 400,
+			wantGrpcCode: codes.AlreadyExists,
+		},
+		{
+			name:         "repo_error",
+			borrowErr:    errors.New("failed to create: connection refused"),
+			wantHttpCode: 500,
+			wantGrpcCode: codes.Internal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bookBorrowRepo := &fakeBookBorrowRepo{borrows: map[string]*model.BookBorrow{}, borrowErr: tt.borrowErr}
+			ucase := NewBookBorrowUcase(
+				&fakeBookRepo{books: map[string]*model.Book{book.UUID.String(): book}},
+				bookBorrowRepo,
+				&fakeFineLedgerRepo{},
+				&fakeBookHoldRepo{},
+				nil,
+			)
+
+			resp, err := ucase.BorrowBook(context.Background(), currentUser, book.UUID.String())
+			if tt.wantHttpCode != 0 {
+				assertCustomErr(t, err, tt.wantHttpCode, tt.wantGrpcCode)
+				if len(bookBorrowRepo.borrows) != 0 {
+					t.Errorf("expected no borrow, got %d", len(bookBorrowRepo.borrows))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if resp.CopyUUID == nil {
+				t.Errorf("expected the borrowed copy")
+			}
+			if got := resp.DueAt.Sub(resp.BorrowedAt); got != 14*24*time.Hour {
+				t.Errorf("expected the borrow due in 14 days, got %v", got)
+			}
+		})
+	}
+}
+
+func TestBookBorrowUcase_BorrowBook_VerifiedEmail(t *testing.T) {
+	book := &model.Book{UUID: uuid.New(), Title: "Dune"}
+	userUUID := uuid.New().String()
+
+	tests := []struct {
+		name         string
+		required     bool
+		currentUser  dto.CurrentUser
+		checkErr     error
+		wantHttpCode int // 0 when borrowed
+		wantGrpcCode codes.Code
+		wantChecked  []string
+	}{
+		{
+			name:        "not_required",
+			currentUser: dto.CurrentUser{UUID: userUUID, AccessToken: "unverified-token"},
+		},
+		{
+			name:        "verified_token",
+			required:    true,
+			currentUser: dto.CurrentUser{UUID: userUUID, AccessToken: "verified-token"},
+			wantChecked: []string{"verified-token"},
+		},
+		{
+			name:         "unverified_token",
+			required:     true,
+			currentUser:  dto.CurrentUser{UUID: userUUID, AccessToken: "unverified-token"},
+			wantHttpCode: 403,
+			wantGrpcCode: codes.PermissionDenied,
+			wantChecked:  []string{"unverified-token"},
+		},
+		{
+			name:        "verified_api_key",
+			required:    true,
+			currentUser: dto.CurrentUser{UUID: userUUID, APIKey: "verified-key"},
+			wantChecked: []string{"verified-key"},
+		},
+		{
+			name:         "unverified_api_key",
+			required:     true,
+			currentUser:  dto.CurrentUser{UUID: userUUID, APIKey: "unverified-key"},
+			wantHttpCode: 403,
+			wantGrpcCode: codes.PermissionDenied,
+			wantChecked:  []string{"unverified-key"},
+		},
+		{
+			name:         "token_revoked_meanwhile",
+			required:     true,
+			currentUser:  dto.CurrentUser{UUID: userUUID, AccessToken: "verified-token"},
+			checkErr:     status.Error(codes.Unauthenticated, "token revoked"),
+			wantHttpCode: 401,
+			wantGrpcCode: codes.Unauthenticated,
+			wantChecked:  []string{"verified-token"},
+		},
+		{
+			name:         "auth_service_unreachable",
+			required:     true,
+			currentUser:  dto.CurrentUser{UUID: userUUID, AccessToken: "verified-token"},
+			checkErr:     status.Error(codes.Unavailable, "connection refused"),
+			wantHttpCode: 500,
+			wantGrpcCode: codes.Internal,
+			wantChecked:  []string{"verified-token"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.Envs = &config.EnvsSchema{LOAN_PERIOD_DAYS: 14, FINE_BLOCK_THRESHOLD: 100, BORROW_REQUIRE_VERIFIED_EMAIL: tt.required}
+			authClient := &fakeAuthClient{
+				verified: map[string]bool{"verified-token": true, "verified-key": true},
+				checkErr: tt.checkErr,
+			}
+			bookBorrowRepo := &fakeBookBorrowRepo{borrows: map[string]*model.BookBorrow{}}
+			ucase := NewBookBorrowUcase(
+				&fakeBookRepo{books: map[string]*model.Book{book.UUID.String(): book}},
+				bookBorrowRepo,
+				&fakeFineLedgerRepo{},
+				&fakeBookHoldRepo{},
+				authClient,
+			)
+
+			_, err := ucase.BorrowBook(context.Background(), tt.currentUser, book.UUID.String())
+			if tt.wantHttpCode != 0 {
+				assertCustomErr(t, err, tt.wantHttpCode, tt.wantGrpcCode)
+				if len(bookBorrowRepo.borrows) != 0 {
+					t.Errorf("expected no borrow, got %d", len(bookBorrowRepo.borrows))
+				}
+			} else if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if fmt.Sprint(authClient.checked) != fmt.Sprint(tt.wantChecked) {
+				t.Errorf("expected auth_service asked about %v, got %v", tt.wantChecked, authClient.checked)
+			}
+		})
+	}
+}
+
+func TestBookBorrowUcase_ReturnBook(t *testing.T) {
+	config.Envs = &config.EnvsSchema{FINE_DAILY_RATE: 10, FINE_MAX_AMOUNT: 100}
+	returnedAt := time.Now().Add(-time.Hour)
+	copyUUID := uuid.New()
+
+	tests := []struct {
+		name         string
+		returnedAt   *time.Time
+		dueIn        time.Duration
+		returnErr    error
+		wantHttpCode int // 0 when returned
+		wantFine     int64
+	}{
+		{
+			name:  "returned_on_time",
+			dueIn: time.Hour,
+		},
+		{
+			name:     "returned_late",
+			dueIn:    -25 * time.Hour,
+			wantFine: 20,
+		},
+		{
+			name:         "already_returned",
+			returnedAt:   &returnedAt,
+			dueIn:        time.Hour,
+			wantHttpCode: 400,
+		},
+		{
+			name:         "returned_concurrently",
+			dueIn:        time.Hour,
+			returnErr:    errors.New("already returned"),
+			wantHttpCode: 400,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bookBorrow := &model.BookBorrow{
+				UUID:       uuid.New(),
+				BookUUID:   uuid.New(),
+				UserUUID:   uuid.New(),
+				CopyUUID:   &copyUUID,
+				BorrowedAt: time.Now().Add(-14 * 24 * time.Hour),
+				DueAt:      time.Now().Add(tt.dueIn),
+				ReturnedAt: tt.returnedAt,
+			}
+			bookBorrowRepo := &fakeBookBorrowRepo{
+				borrows:   map[string]*model.BookBorrow{bookBorrow.UUID.String(): bookBorrow},
+				returnErr: tt.returnErr,
+			}
+			bookHoldRepo := &fakeBookHoldRepo{}
+			ucase := NewBookBorrowUcase(nil, bookBorrowRepo, nil, bookHoldRepo, nil)
+
+			_, err := ucase.ReturnBook(context.Background(), dto.CurrentUser{UUID: bookBorrow.UserUUID.String()}, bookBorrow.UUID.String())
+			if tt.wantHttpCode != 0 {
+				assertCustomErr(t, err, tt.wantHttpCode, codes.FailedPrecondition)
+				if len(bookHoldRepo.synced) != 0 {
+					t.Errorf("expected the hold queue untouched, got %v", bookHoldRepo.synced)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			var fine int64
+			for _, entry := range bookBorrowRepo.fines {
+				fine += entry.Amount
+			}
+			if fine != tt.wantFine {
+				t.Errorf("expected fine %d, got %d", tt.wantFine, fine)
+			}
+			if len(bookHoldRepo.synced) != 1 {
+				t.Errorf("expected the hold queue synced once, got %v", bookHoldRepo.synced)
+			}
+		})
+	}
+}
+
+func TestBookBorrowUcase_RenewBook(t *testing.T) {
+	config.Envs = &config.EnvsSchema{LOAN_PERIOD_DAYS: 14, LOAN_MAX_RENEWALS: 2}
+	day := 24 * time.Hour
+	returnedAt := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name         string
+		dueIn        time.Duration
+		renewCount   int
+		returnedAt   *time.Time
+		wantHttpCode int // 0 when renewed
+		wantGrpcCode codes.Code
+		wantDueIn    time.Duration // from now, when renewed
+	}{
+		{
+			name:      "due_soon_gets_a_fresh_period",
+			dueIn:     2 * day,
+			wantDueIn: 14 * day,
+		},
+		{
+			name:       "last_renewal",
+			dueIn:      day,
+			renewCount: 1,
+			wantDueIn:  14 * day,
+		},
+		{
+			name:      "due_later_than_a_fresh_period_keeps_its_due_date",
+			dueIn:     20 * day,
+			wantDueIn: 20 * day,
+		},
+		{
+			name:         "overdue",
+			dueIn:        -time.Hour,
+			wantHttpCode: 400,
+			wantGrpcCode: codes.FailedPrecondition,
+		},
+		{
+			name:         "renew_limit_reached",
+			dueIn:        day,
+			renewCount:   2,
+			wantHttpCode: 400,
+			wantGrpcCode: codes.ResourceExhausted,
+		},
+		{
+			name:         "already_returned",
+			dueIn:        day,
+			returnedAt:   &returnedAt,
+			wantHttpCode: 400,
+			wantGrpcCode: codes.FailedPrecondition,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dueAt := time.Now().UTC().Add(tt.dueIn)
+			bookBorrow := &model.BookBorrow{
+				UUID:       uuid.New(),
+				BookUUID:   uuid.New(),
+				UserUUID:   uuid.New(),
+				BorrowedAt: time.Now().UTC().Add(-7 * day),
+				DueAt:      dueAt,
+				ReturnedAt: tt.returnedAt,
+				RenewCount: tt.renewCount,
+			}
+			bookBorrowRepo := &fakeBookBorrowRepo{borrows: map[string]*model.BookBorrow{bookBorrow.UUID.String(): bookBorrow}}
+			ucase := NewBookBorrowUcase(nil, bookBorrowRepo, nil, nil, nil)
+
+			resp, err := ucase.RenewBook(context.Background(), dto.CurrentUser{UUID: bookBorrow.UserUUID.String()}, bookBorrow.UUID.String())
+			if tt.wantHttpCode != 0 {
+				assertCustomErr(t, err, tt.wantHttpCode, tt.wantGrpcCode)
+				if !bookBorrow.DueAt.Equal(dueAt) || bookBorrow.RenewCount != tt.renewCount {
+					t.Errorf("expected the borrow untouched, got due %v and %d renewals", bookBorrow.DueAt, bookBorrow.RenewCount)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if got := time.Until(resp.DueAt); got < tt.wantDueIn-time.Minute || got > tt.wantDueIn {
+				t.Errorf("expected the borrow due in %v, got %v", tt.wantDueIn, got)
+			}
+			if resp.RenewCount != tt.renewCount+1 {
+				t.Errorf("expected %d renewals, got %d", tt.renewCount+1, resp.RenewCount)
+			}
+			if resp.RenewsRemaining != 2-resp.RenewCount {
+				t.Errorf("expected %d renewals remaining, got %d", 2-resp.RenewCount, resp.RenewsRemaining)
+			}
+		})
+	}
+}
+
+func TestBookBorrowUcase_GetBorrowList_Status(t *testing.T) {
+	userUUID := uuid.New()
+	now := time.Now().UTC()
+	returnedAt := now.Add(-time.Hour)
+	newBorrow := func(dueIn time.Duration, returnedAt *time.Time) *model.BookBorrow {
+		return &model.BookBorrow{
+			UUID:       uuid.New(),
+			BookUUID:   uuid.New(),
+			UserUUID:   userUUID,
+			BorrowedAt: now.Add(-14 * 24 * time.Hour),
+			DueAt:      now.Add(dueIn),
+			ReturnedAt: returnedAt,
+		}
+	}
+	active := newBorrow(time.Hour, nil)
+	overdue := newBorrow(-time.Hour, nil)
+	returnedLate := newBorrow(-2*time.Hour, &returnedAt)
+	bookBorrowRepo := &fakeBookBorrowRepo{borrows: map[string]*model.BookBorrow{}}
+	for _, bookBorrow := range []*model.BookBorrow{active, overdue, returnedLate} {
+		bookBorrowRepo.borrows[bookBorrow.UUID.String()] = bookBorrow
+	}
+	ucase := NewBookBorrowUcase(nil, bookBorrowRepo, nil, nil, nil)
+
+	tests := []struct {
+		status string
+		want   map[string]bool // uuid of the borrows listed, to their overdue flag
+	}{
+		{"", map[string]bool{active.UUID.String(): false, overdue.UUID.String(): true, returnedLate.UUID.String(): false}},
+		{"active", map[string]bool{active.UUID.String(): false, overdue.UUID.String(): true}},
+		{"returned", map[string]bool{returnedLate.UUID.String(): false}},
+		{"overdue", map[string]bool{overdue.UUID.String(): true}},
+	}
+	for _, tt := range tests {
+		t.Run("status_"+tt.status, func(t *testing.T) {
+			resp, err := ucase.GetBorrowList(context.Background(), dto.CurrentUser{UUID: userUUID.String()}, dto.GetBorrowListReq{Status: tt.status})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(resp.Data) != len(tt.want) {
+				t.Fatalf("expected %d borrows, got %+v", len(tt.want), resp.Data)
+			}
+			for _, item := range resp.Data {
+				wantOverdue, ok := tt.want[item.UUID]
+				if !ok {
+					t.Errorf("unexpected borrow %s", item.UUID)
+				} else if item.Overdue != wantOverdue {
+					t.Errorf("expected borrow %s overdue %v, got %v", item.UUID, wantOverdue, item.Overdue)
+				}
+			}
+		})
+	}
+}
+
+func TestBookBorrowUcase_getMaxRenewals(t *testing.T) {
+	config.Envs = &config.EnvsSchema{LOAN_MAX_RENEWALS: 2, LOAN_MAX_RENEWALS_EXTENDED: 5}
+
+	extended := []string{dto.PermissionBorrowRenewExtended}
+	authClient := &fakeAuthClient{users: map[string]*auth_grpc.GetUserByUUIDResponse{
+		"plain-borrower":    {Uuid: "plain-borrower", Role: "user"},
+		"extended-borrower": {Uuid: "extended-borrower", Role: "admin", Permissions: extended},
+	}}
+	ucase := &BookBorrowUcase{authGrpcServiceClient: authClient}
+
+	tests := []struct {
+		name         string
+		currentUser  dto.CurrentUser
+		borrowerUUID string
+		want         int
+	}{
+		{
+			name:         "own_borrow",
+			currentUser:  dto.CurrentUser{UUID: "plain-borrower"},
+			borrowerUUID: "plain-borrower",
+			want:         2,
+		},
+		{
+			name:         "own_borrow_extended",
+			currentUser:  dto.CurrentUser{UUID: "extended-borrower", Permissions: extended},
+			borrowerUUID: "extended-borrower",
+			want:         5,
+		},
+		{
+			name:         "extended_renews_for_plain_borrower",
+			currentUser:  dto.CurrentUser{UUID: "extended-borrower", Permissions: extended},
+			borrowerUUID: "plain-borrower",
+			want:         2,
+		},
+		{
+			name:         "staff_renews_for_extended_borrower",
+			currentUser:  dto.CurrentUser{UUID: "librarian", Permissions: []string{dto.PermissionBorrowManage}},
+			borrowerUUID: "extended-borrower",
+			want:         5,
+		},
+		{
+			name:         "deleted_borrower",
+			currentUser:  dto.CurrentUser{UUID: "librarian", Permissions: extended},
+			borrowerUUID: "deleted-borrower",
+			want:         2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ucase.getMaxRenewals(context.Background(), tt.currentUser, tt.borrowerUUID)
+			if err != nil {
+				t.Fatalf("getMaxRenewals() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("getMaxRenewals() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateLateFine(t *testing.T) {
+	dueAt := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name       string
+		returnedAt time.Time
+		maxAmount  int64
+		want       int64
+	}{
+		{
+			name:       "before_due",
+			returnedAt: dueAt.Add(-day),
+			maxAmount:  100,
+			want:       0,
+		},
+		{
+			name:       "at_due",
+			returnedAt: dueAt,
+			maxAmount:  100,
+			want:       0,
+		},
+		{
+			name:       "one_second_late",
+			returnedAt: dueAt.Add(time.Second),
+			maxAmount:  100,
+			want:       10,
+		},
+		{
+			name:       "exactly_one_day_late",
+			returnedAt: dueAt.Add(day),
+			maxAmount:  100,
+			want:       10,
+		},
+		{
+			name:       "one_day_and_a_second_late",
+			returnedAt: dueAt.Add(day + time.Second),
+			maxAmount:  100,
+			want:       20,
+		},
+		{
+			name:       "at_cap",
+			returnedAt: dueAt.Add(10 * day),
+			maxAmount:  100,
+			want:       100,
+		},
+		{
+			name:       "above_cap",
+			returnedAt: dueAt.Add(30 * day),
+			maxAmount:  100,
+			want:       100,
+		},
+		{
+			name:       "no_cap",
+			returnedAt: dueAt.Add(30 * day),
+			maxAmount:  0,
+			want:       300,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.Envs = &config.EnvsSchema{FINE_DAILY_RATE: 10, FINE_MAX_AMOUNT: tt.maxAmount}
+			if got := calculateLateFine(dueAt, tt.returnedAt); got != tt.want {
+				t.Errorf("calculateLateFine() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
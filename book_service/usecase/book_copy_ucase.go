@@ -0,0 +1,307 @@
+package ucase
+
+import (
+	"book_service/domain/dto"
+	"book_service/domain/model"
+	"book_service/repository"
+	error_utils "book_service/utils/error"
+	"book_service/utils/helper"
+	"context"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+)
+
+type BookCopyUcase struct {
+	bookRepo     repository.IBookRepo
+	bookCopyRepo repository.IBookCopyRepo
+	bookHoldRepo repository.IBookHoldRepo
+}
+
+type IBookCopyUcase interface {
+	CreateBookCopy(
+		ctx context.Context,
+		currentUser dto.CurrentUser,
+		bookUUID string,
+		payload dto.CreateBookCopyReq,
+	) (*dto.CreateBookCopyRespData, error) // copy:manage only
+	PatchBookCopy(
+		ctx context.Context,
+		currentUser dto.CurrentUser,
+		copyUUID string,
+		payload dto.PatchBookCopyReq,
+	) (*dto.PatchBookCopyRespData, error) // copy:manage only
+	GetBookCopyList(
+		ctx context.Context,
+		currentUser dto.CurrentUser,
+		bookUUID string,
+		params dto.GetBookCopyListReq,
+	) (*dto.GetBookCopyListRespData, error) // copy:manage only
+}
+
+func NewBookCopyUcase(
+	bookRepo repository.IBookRepo,
+	bookCopyRepo repository.IBookCopyRepo,
+	bookHoldRepo repository.IBookHoldRepo,
+) IBookCopyUcase {
+	return &BookCopyUcase{
+		bookRepo:     bookRepo,
+		bookCopyRepo: bookCopyRepo,
+		bookHoldRepo: bookHoldRepo,
+	}
+}
+
+func (ucase *BookCopyUcase) CreateBookCopy(
+	ctx context.Context,
+	currentUser dto.CurrentUser,
+	bookUUID string,
+	payload dto.CreateBookCopyReq,
+) (*dto.CreateBookCopyRespData, error) {
+	// find book
+	book, err := ucase.bookRepo.GetByUUID(bookUUID)
+	if err != nil {
+		if err.Error() == "not found" {
+			logger.Errorf("err: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 404,
+				GrpcCode: codes.NotFound,
+				Message:  "not found",
+				Detail:   err,
+			}
+		} else {
+			logger.Errorf("err: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: codes.Internal,
+				Message:  "internal server error",
+				Detail:   err,
+			}
+		}
+	}
+
+	// check barcode
+	_, err = ucase.bookCopyRepo.GetByBarcode(payload.Barcode)
+	if err == nil {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.AlreadyExists,
+			Message:  "conflict",
+			Detail:   "barcode already exists",
+		}
+	} else if err.Error() != "not found" {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
+	// create copy
+	condition := payload.Condition
+	if condition == "" {
+		condition = model.BookCopyConditionGood
+	}
+	acquiredAt := helper.TimeNowUTC()
+	if payload.AcquiredAt != nil {
+		acquiredAt = payload.AcquiredAt.UTC()
+	}
+	newBookCopy := &model.BookCopy{
+		UUID:       uuid.New(),
+		BookUUID:   book.UUID,
+		Barcode:    payload.Barcode,
+		Condition:  condition,
+		Status:     model.BookCopyStatusAvailable,
+		AcquiredAt: acquiredAt,
+	}
+
+	err = ucase.bookCopyRepo.Create(newBookCopy)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
+	// a new copy may be what the hold queue is waiting for
+	syncHoldQueue(ucase.bookHoldRepo, book.UUID.String(), helper.TimeNowUTC())
+
+	return &dto.CreateBookCopyRespData{
+		UUID:       newBookCopy.UUID.String(),
+		BookUUID:   newBookCopy.BookUUID.String(),
+		Barcode:    newBookCopy.Barcode,
+		Condition:  newBookCopy.Condition,
+		Status:     newBookCopy.Status,
+		AcquiredAt: newBookCopy.AcquiredAt,
+		CreatedAt:  newBookCopy.CreatedAt,
+		UpdatedAt:  newBookCopy.UpdatedAt,
+	}, nil
+}
+
+func (ucase *BookCopyUcase) PatchBookCopy(
+	ctx context.Context,
+	currentUser dto.CurrentUser,
+	copyUUID string,
+	payload dto.PatchBookCopyReq,
+) (*dto.PatchBookCopyRespData, error) {
+	// find copy
+	bookCopy, err := ucase.bookCopyRepo.GetByUUID(copyUUID)
+	if err != nil {
+		if err.Error() == "not found" {
+			logger.Errorf("err: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 404,
+				GrpcCode: codes.NotFound,
+				Message:  "not found",
+				Detail:   err,
+			}
+		} else {
+			logger.Errorf("err: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: codes.Internal,
+				Message:  "internal server error",
+				Detail:   err,
+			}
+		}
+	}
+
+	currentStatus := bookCopy.Status
+
+	if payload.Condition != nil {
+		bookCopy.Condition = *payload.Condition
+	}
+
+	if payload.Status != nil && *payload.Status != currentStatus {
+		if currentStatus == model.BookCopyStatusOnLoan {
+			return nil, &error_utils.CustomErr{
+				HttpCode: 400,
+				GrpcCode: codes.FailedPrecondition,
+				Message:  "copy on loan",
+				Detail:   "copy is on loan, return it first",
+			}
+		}
+		bookCopy.Status = *payload.Status
+	}
+
+	// update copy
+	err = ucase.bookCopyRepo.Update(bookCopy, currentStatus)
+	if err != nil {
+		if err.Error() == "status changed" {
+			return nil, &error_utils.CustomErr{
+				HttpCode: 409,
+				GrpcCode: codes.Aborted,
+				Message:  "conflict",
+				Detail:   "copy status changed, try again",
+			}
+		}
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
+	// a copy back from repair may be what the hold queue is waiting for
+	if bookCopy.Status == model.BookCopyStatusAvailable && currentStatus != model.BookCopyStatusAvailable {
+		syncHoldQueue(ucase.bookHoldRepo, bookCopy.BookUUID.String(), helper.TimeNowUTC())
+	}
+
+	return &dto.PatchBookCopyRespData{
+		UUID:       bookCopy.UUID.String(),
+		BookUUID:   bookCopy.BookUUID.String(),
+		Barcode:    bookCopy.Barcode,
+		Condition:  bookCopy.Condition,
+		Status:     bookCopy.Status,
+		AcquiredAt: bookCopy.AcquiredAt,
+		CreatedAt:  bookCopy.CreatedAt,
+		UpdatedAt:  bookCopy.UpdatedAt,
+	}, nil
+}
+
+func (ucase *BookCopyUcase) GetBookCopyList(
+	ctx context.Context,
+	currentUser dto.CurrentUser,
+	bookUUID string,
+	params dto.GetBookCopyListReq,
+) (*dto.GetBookCopyListRespData, error) {
+	// find book
+	book, err := ucase.bookRepo.GetByUUID(bookUUID)
+	if err != nil {
+		if err.Error() == "not found" {
+			logger.Errorf("err: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 404,
+				GrpcCode: codes.NotFound,
+				Message:  "not found",
+				Detail:   err,
+			}
+		} else {
+			logger.Errorf("err: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: codes.Internal,
+				Message:  "internal server error",
+				Detail:   err,
+			}
+		}
+	}
+
+	repoParams := dto.BookCopyRepo_GetListParams{
+		BookUUID:  book.UUID.String(),
+		Page:      params.Page,
+		Limit:     params.Limit,
+		SortOrder: params.SortOrder,
+		SortBy:    params.SortBy,
+	}
+	if params.Status != "any" {
+		repoParams.Status = params.Status
+	}
+
+	bookCopies, err := ucase.bookCopyRepo.GetList(ctx, repoParams)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
+	count, err := ucase.bookCopyRepo.CountGetList(ctx, repoParams)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
+	res := &dto.GetBookCopyListRespData{
+		Data: []dto.GetBookCopyListRespDataItem{},
+	}
+	res.Set(params.Page, params.Limit, count)
+	for _, bookCopy := range bookCopies {
+		res.Data = append(res.Data, dto.GetBookCopyListRespDataItem{
+			UUID:       bookCopy.UUID.String(),
+			BookUUID:   bookCopy.BookUUID.String(),
+			Barcode:    bookCopy.Barcode,
+			Condition:  bookCopy.Condition,
+			Status:     bookCopy.Status,
+			AcquiredAt: bookCopy.AcquiredAt,
+			CreatedAt:  bookCopy.CreatedAt,
+			UpdatedAt:  bookCopy.UpdatedAt,
+		})
+	}
+
+	return res, nil
+}
@@ -0,0 +1,125 @@
+package ucase
+
+import (
+	"book_service/config"
+	"book_service/domain/dto"
+	"book_service/domain/model"
+	"book_service/repository"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+)
+
+// fakeBookCopyRepo keeps copies by uuid and, like the repo, saves a copy only
+// while its stored status is still the one it was read with. a non empty
+// statusAfterGet moves the stored copy to that status once it is read, the
+// way a concurrent borrow would.
+type fakeBookCopyRepo struct {
+	repository.IBookCopyRepo
+	copies         map[string]*model.BookCopy
+	statusAfterGet string
+}
+
+func (repo *fakeBookCopyRepo) GetByUUID(uuid string) (*model.BookCopy, error) {
+	bookCopy, ok := repo.copies[uuid]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	copied := *bookCopy
+	if repo.statusAfterGet != "" {
+		bookCopy.Status = repo.statusAfterGet
+	}
+	return &copied, nil
+}
+
+func (repo *fakeBookCopyRepo) Update(bookCopy *model.BookCopy, currentStatus string) error {
+	stored := repo.copies[bookCopy.UUID.String()]
+	if stored.Status != currentStatus {
+		return errors.New("status changed")
+	}
+	stored.Condition = bookCopy.Condition
+	stored.Status = bookCopy.Status
+	return nil
+}
+
+func TestBookCopyUcase_PatchBookCopy(t *testing.T) {
+	config.Envs = &config.EnvsSchema{HOLD_READY_WINDOW_HOURS: 48}
+	available := model.BookCopyStatusAvailable
+	repair := model.BookCopyStatusRepair
+	damaged := "damaged"
+
+	tests := []struct {
+		name           string
+		status         string
+		statusAfterGet string
+		payload        dto.PatchBookCopyReq
+		wantHttpCode   int // 0 when updated
+		wantGrpcCode   codes.Code
+		wantStatus     string
+		wantHoldSynced bool
+	}{
+		{
+			name:       "sent_to_repair",
+			status:     model.BookCopyStatusAvailable,
+			payload:    dto.PatchBookCopyReq{Status: &repair, Condition: &damaged},
+			wantStatus: model.BookCopyStatusRepair,
+		},
+		{
+			name:           "back_from_repair_syncs_the_hold_queue",
+			status:         model.BookCopyStatusRepair,
+			payload:        dto.PatchBookCopyReq{Status: &available},
+			wantStatus:     model.BookCopyStatusAvailable,
+			wantHoldSynced: true,
+		},
+		{
+			name:       "condition_of_a_copy_on_loan",
+			status:     model.BookCopyStatusOnLoan,
+			payload:    dto.PatchBookCopyReq{Condition: &damaged},
+			wantStatus: model.BookCopyStatusOnLoan,
+		},
+		{
+			name:         "status_of_a_copy_on_loan",
+			status:       model.BookCopyStatusOnLoan,
+			payload:      dto.PatchBookCopyReq{Status: &repair},
+			wantHttpCode: 400,
+			wantGrpcCode: codes.FailedPrecondition,
+			wantStatus:   model.BookCopyStatusOnLoan,
+		},
+		{
+			name:           "borrowed_while_patching",
+			status:         model.BookCopyStatusAvailable,
+			statusAfterGet: model.BookCopyStatusOnLoan,
+			payload:        dto.PatchBookCopyReq{Status: &repair},
+			wantHttpCode:   409,
+			wantGrpcCode:   codes.Aborted,
+			wantStatus:     model.BookCopyStatusOnLoan,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bookCopy := &model.BookCopy{UUID: uuid.New(), BookUUID: uuid.New(), Barcode: "B-0001", Condition: "good", Status: tt.status}
+			bookCopyRepo := &fakeBookCopyRepo{
+				copies:         map[string]*model.BookCopy{bookCopy.UUID.String(): bookCopy},
+				statusAfterGet: tt.statusAfterGet,
+			}
+			bookHoldRepo := &fakeBookHoldRepo{}
+			ucase := NewBookCopyUcase(nil, bookCopyRepo, bookHoldRepo)
+
+			_, err := ucase.PatchBookCopy(context.Background(), dto.CurrentUser{}, bookCopy.UUID.String(), tt.payload)
+			if tt.wantHttpCode != 0 {
+				assertCustomErr(t, err, tt.wantHttpCode, tt.wantGrpcCode)
+			} else if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if got := bookCopyRepo.copies[bookCopy.UUID.String()].Status; got != tt.wantStatus {
+				t.Errorf("expected status %s, got %s", tt.wantStatus, got)
+			}
+			if synced := len(bookHoldRepo.synced) == 1; synced != tt.wantHoldSynced {
+				t.Errorf("expected the hold queue synced %v, got %v", tt.wantHoldSynced, bookHoldRepo.synced)
+			}
+		})
+	}
+}
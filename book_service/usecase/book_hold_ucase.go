@@ -0,0 +1,428 @@
+package ucase
+
+import (
+	"book_service/config"
+	"book_service/domain/dto"
+	"book_service/domain/model"
+	"book_service/repository"
+	error_utils "book_service/utils/error"
+	"book_service/utils/helper"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+)
+
+type BookHoldUcase struct {
+	bookRepo       repository.IBookRepo
+	bookBorrowRepo repository.IBookBorrowRepo
+	bookHoldRepo   repository.IBookHoldRepo
+	bookCopyRepo   repository.IBookCopyRepo
+}
+
+type IBookHoldUcase interface {
+	PlaceHold(
+		ctx context.Context,
+		currentUser dto.CurrentUser,
+		bookUUID string,
+	) (*dto.PlaceHoldRespData, error)
+	CancelHold(
+		ctx context.Context,
+		currentUser dto.CurrentUser,
+		holdUUID string,
+	) (*dto.CancelHoldRespData, error)
+	GetHoldList(
+		ctx context.Context,
+		currentUser dto.CurrentUser,
+		params dto.GetHoldListReq,
+	) (*dto.GetHoldListRespData, error)
+	ProcessHoldQueues(ctx context.Context) error
+}
+
+func NewBookHoldUcase(
+	bookRepo repository.IBookRepo,
+	bookBorrowRepo repository.IBookBorrowRepo,
+	bookHoldRepo repository.IBookHoldRepo,
+	bookCopyRepo repository.IBookCopyRepo,
+) IBookHoldUcase {
+	return &BookHoldUcase{
+		bookRepo:       bookRepo,
+		bookBorrowRepo: bookBorrowRepo,
+		bookHoldRepo:   bookHoldRepo,
+		bookCopyRepo:   bookCopyRepo,
+	}
+}
+
+func (ucase *BookHoldUcase) PlaceHold(
+	ctx context.Context,
+	currentUser dto.CurrentUser,
+	bookUUID string,
+) (*dto.PlaceHoldRespData, error) {
+	parsedUserUUID, err := uuid.Parse(currentUser.UUID)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 401,
+			GrpcCode: codes.Unauthenticated,
+			Message:  "unauthorized",
+			Detail:   "invalid current user uuid",
+		}
+	}
+
+	// find book
+	book, err := ucase.bookRepo.GetByUUID(bookUUID)
+	if err != nil {
+		if err.Error() == "not found" {
+			logger.Errorf("err: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 404,
+				GrpcCode: codes.NotFound,
+				Message:  "not found",
+				Detail:   err,
+			}
+		} else {
+			logger.Errorf("err: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: codes.Internal,
+				Message:  "internal server error",
+				Detail:   err,
+			}
+		}
+	}
+
+	// check if user already holds or borrows this book
+	activeHoldCount, err := ucase.bookHoldRepo.CountGetList(
+		ctx,
+		dto.BookHoldRepo_GetListParams{
+			BookUUID: book.UUID.String(),
+			UserUUID: currentUser.UUID,
+			Statuses: []string{model.BookHoldStatusWaiting, model.BookHoldStatusReady},
+		},
+	)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+	if activeHoldCount > 0 {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.AlreadyExists,
+			Message:  "conflict",
+			Detail:   "book already on hold",
+		}
+	}
+
+	notReturned := false
+	activeBorrowCount, err := ucase.bookBorrowRepo.CountGetList(
+		ctx,
+		dto.BookBorrowRepo_GetListParams{
+			BookUUID: book.UUID.String(),
+			UserUUID: currentUser.UUID,
+			Returned: &notReturned,
+		},
+	)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+	if activeBorrowCount > 0 {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.AlreadyExists,
+			Message:  "conflict",
+			Detail:   "book already borrowed",
+		}
+	}
+
+	// holds are only for books without a free copy
+	available, err := ucase.bookCopyRepo.CountAvailableByBookUUID(book.UUID.String())
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
+	timeNow := helper.TimeNowUTC()
+	reserved, err := ucase.bookHoldRepo.CountReserved(book.UUID.String(), currentUser.UUID, timeNow)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+	if available > reserved {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.FailedPrecondition,
+			Message:  "in stock",
+			Detail:   "book is in stock, borrow it instead",
+		}
+	}
+
+	// place hold
+	newBookHold := &model.BookHold{
+		UUID:     uuid.New(),
+		BookUUID: book.UUID,
+		UserUUID: parsedUserUUID,
+		Status:   model.BookHoldStatusWaiting,
+	}
+
+	err = ucase.bookHoldRepo.Create(newBookHold)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
+	queuePosition, err := ucase.bookHoldRepo.GetQueuePosition(newBookHold)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
+	return &dto.PlaceHoldRespData{
+		UUID:          newBookHold.UUID.String(),
+		BookUUID:      newBookHold.BookUUID.String(),
+		UserUUID:      newBookHold.UserUUID.String(),
+		Status:        newBookHold.Status,
+		QueuePosition: queuePosition,
+		ReadyAt:       newBookHold.ReadyAt,
+		ExpiresAt:     newBookHold.ExpiresAt,
+		CreatedAt:     newBookHold.CreatedAt,
+		UpdatedAt:     newBookHold.UpdatedAt,
+	}, nil
+}
+
+func (ucase *BookHoldUcase) CancelHold(
+	ctx context.Context,
+	currentUser dto.CurrentUser,
+	holdUUID string,
+) (*dto.CancelHoldRespData, error) {
+	// find hold
+	bookHold, err := ucase.bookHoldRepo.GetByUUID(holdUUID)
+	if err != nil {
+		if err.Error() == "not found" {
+			logger.Errorf("err: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 404,
+				GrpcCode: codes.NotFound,
+				Message:  "not found",
+				Detail:   err,
+			}
+		} else {
+			logger.Errorf("err: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: codes.Internal,
+				Message:  "internal server error",
+				Detail:   err,
+			}
+		}
+	}
+
+	// validate user
+	if bookHold.UserUUID.String() != currentUser.UUID && !currentUser.HasPermission(dto.PermissionHoldManage) {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 403,
+			GrpcCode: codes.PermissionDenied,
+			Message:  "forbidden",
+			Detail:   "forbidden",
+		}
+	}
+
+	if !bookHold.IsActive() {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.FailedPrecondition,
+			Message:  "hold not active",
+			Detail:   "hold is already " + bookHold.Status,
+		}
+	}
+
+	// cancel hold
+	timeNow := helper.TimeNowUTC()
+	bookHold.ClosedAt = &timeNow
+
+	err = ucase.bookHoldRepo.Cancel(bookHold)
+	if err != nil {
+		if err.Error() == "not active" {
+			return nil, &error_utils.CustomErr{
+				HttpCode: 400,
+				GrpcCode: codes.FailedPrecondition,
+				Message:  "hold not active",
+				Detail:   "hold is no longer active",
+			}
+		}
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
+	// a cancelled ready hold frees its copy for the next in queue
+	syncHoldQueue(ucase.bookHoldRepo, bookHold.BookUUID.String(), timeNow)
+
+	return &dto.CancelHoldRespData{
+		UUID:      bookHold.UUID.String(),
+		BookUUID:  bookHold.BookUUID.String(),
+		UserUUID:  bookHold.UserUUID.String(),
+		Status:    bookHold.Status,
+		ClosedAt:  bookHold.ClosedAt,
+		CreatedAt: bookHold.CreatedAt,
+		UpdatedAt: bookHold.UpdatedAt,
+	}, nil
+}
+
+func (ucase *BookHoldUcase) GetHoldList(
+	ctx context.Context,
+	currentUser dto.CurrentUser,
+	params dto.GetHoldListReq,
+) (*dto.GetHoldListRespData, error) {
+	// users without hold:manage can only see their own holds
+	userUUID := params.UserUUID
+	if !currentUser.HasPermission(dto.PermissionHoldManage) {
+		userUUID = currentUser.UUID
+	}
+
+	repoParams := dto.BookHoldRepo_GetListParams{
+		BookUUID:  params.BookUUID,
+		UserUUID:  userUUID,
+		Page:      params.Page,
+		Limit:     params.Limit,
+		SortOrder: params.SortOrder,
+		SortBy:    params.SortBy,
+	}
+
+	// prepare status filter
+	switch params.Status {
+	case "", "any":
+	case "active":
+		repoParams.Statuses = []string{model.BookHoldStatusWaiting, model.BookHoldStatusReady}
+	default:
+		repoParams.Statuses = []string{params.Status}
+	}
+
+	bookHolds, err := ucase.bookHoldRepo.GetList(ctx, repoParams)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
+	count, err := ucase.bookHoldRepo.CountGetList(ctx, repoParams)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
+	res := &dto.GetHoldListRespData{
+		Data: []dto.GetHoldListRespDataItem{},
+	}
+	res.Set(params.Page, params.Limit, count)
+	for _, bookHold := range bookHolds {
+		queuePosition, err := ucase.bookHoldRepo.GetQueuePosition(&bookHold)
+		if err != nil {
+			logger.Errorf("err: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: codes.Internal,
+				Message:  "internal server error",
+				Detail:   err,
+			}
+		}
+
+		res.Data = append(res.Data, dto.GetHoldListRespDataItem{
+			UUID:          bookHold.UUID.String(),
+			BookUUID:      bookHold.BookUUID.String(),
+			UserUUID:      bookHold.UserUUID.String(),
+			Status:        bookHold.Status,
+			QueuePosition: queuePosition,
+			ReadyAt:       bookHold.ReadyAt,
+			ExpiresAt:     bookHold.ExpiresAt,
+			ClosedAt:      bookHold.ClosedAt,
+			CreatedAt:     bookHold.CreatedAt,
+			UpdatedAt:     bookHold.UpdatedAt,
+		})
+	}
+
+	return res, nil
+}
+
+// ProcessHoldQueues expires ready holds whose pickup window has passed and
+// hands their copies to the next waiting holds. it is meant to run
+// periodically.
+func (ucase *BookHoldUcase) ProcessHoldQueues(ctx context.Context) error {
+	bookUUIDs, err := ucase.bookHoldRepo.GetBookUUIDsWithActiveHolds()
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return err
+	}
+
+	timeNow := helper.TimeNowUTC()
+	for _, bookUUID := range bookUUIDs {
+		syncHoldQueue(ucase.bookHoldRepo, bookUUID, timeNow)
+	}
+
+	return nil
+}
+
+// syncHoldQueue moves the hold queue of a book forward. failures are only
+// logged, the queue is synced again on the next run of ProcessHoldQueues.
+func syncHoldQueue(bookHoldRepo repository.IBookHoldRepo, bookUUID string, now time.Time) {
+	promoted, err := bookHoldRepo.SyncQueue(bookUUID, now, holdReadyWindow())
+	if err != nil {
+		logger.Errorf("failed to sync hold queue of book %s: %v", bookUUID, err)
+		return
+	}
+
+	for _, bookHold := range promoted {
+		logger.Infof(
+			"hold %s of user %s is ready for pickup until %s",
+			bookHold.UUID, bookHold.UserUUID, bookHold.ExpiresAt.Format(time.RFC3339),
+		)
+	}
+}
+
+func holdReadyWindow() time.Duration {
+	return time.Hour * time.Duration(config.Envs.HOLD_READY_WINDOW_HOURS)
+}
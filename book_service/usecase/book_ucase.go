@@ -4,9 +4,12 @@ import (
 	"book_service/domain/dto"
 	"book_service/domain/model"
 	author_grpc "book_service/interface/grpc/genproto/author"
+	category_grpc "book_service/interface/grpc/genproto/category"
 	"book_service/repository"
 	error_utils "book_service/utils/error"
+	"book_service/utils/helper"
 	"context"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/op/go-logging"
@@ -17,8 +20,10 @@ import (
 var logger = logging.MustGetLogger("main")
 
 type BookUcase struct {
-	bookRepo                repository.IBookRepo
-	authorGrpcServiceClient author_grpc.AuthorServiceClient
+	bookRepo                  repository.IBookRepo
+	bookCopyRepo              repository.IBookCopyRepo
+	authorGrpcServiceClient   author_grpc.AuthorServiceClient
+	categoryGrpcServiceClient category_grpc.CategoryServiceClient
 }
 
 type IBookUcase interface {
@@ -34,20 +39,42 @@ type IBookUcase interface {
 		currentUser dto.CurrentUser,
 		bookUUID string,
 	) (*dto.DeleteBookRespData, error)
+	GetBookList(
+		ctx context.Context,
+		params dto.GetBookListReq,
+	) (*dto.GetBookListRespData, error)
+	GetBookDetail(
+		ctx context.Context,
+		bookUUID string,
+	) (*dto.GetBookDetailRespData, error)
 	GetBookTotalByAuthorUUID(ctx context.Context, authorUUID string) (int64, error)
 	BulkGetBookTotalByAuthorUUIDs(
 		ctx context.Context,
 		payload dto.BulkGetBookTotalByAuthorUUIDsReq,
 	) ([]dto.BulkGetBookTotalByAuthorUUIDsRespDataItem, error)
+	GetBookTotalByCategoryUUID(ctx context.Context, categoryUUID string) (int64, error)
+	BulkGetBookTotalByCategoryUUIDs(
+		ctx context.Context,
+		payload dto.BulkGetBookTotalByCategoryUUIDsReq,
+	) ([]dto.BulkGetBookTotalByCategoryUUIDsRespDataItem, error)
+
+	// used by author service when deleting an author
+	GetActiveBorrowTotalByAuthorUUID(ctx context.Context, authorUUID string) (int64, error)
+	ReassignBooksByAuthorUUID(ctx context.Context, authorUUID string, newAuthorUUID string) (int64, error)
+	DeleteBooksByAuthorUUID(ctx context.Context, authorUUID string) (int64, error)
 }
 
 func NewBookUcase(
 	bookRepo repository.IBookRepo,
+	bookCopyRepo repository.IBookCopyRepo,
 	authorGrpcServiceClient author_grpc.AuthorServiceClient,
+	categoryGrpcServiceClient category_grpc.CategoryServiceClient,
 ) IBookUcase {
 	return &BookUcase{
-		bookRepo:                bookRepo,
-		authorGrpcServiceClient: authorGrpcServiceClient,
+		bookRepo:                  bookRepo,
+		bookCopyRepo:              bookCopyRepo,
+		authorGrpcServiceClient:   authorGrpcServiceClient,
+		categoryGrpcServiceClient: categoryGrpcServiceClient,
 	}
 }
 
@@ -98,19 +125,20 @@ func (ucase *BookUcase) Create(ctx context.Context, currentUser dto.CurrentUser,
 		}
 	}
 
-	// TODO: validate category through category service
-
-	// create book
+	// validate category through category service
 	var parsedCategoryUUID *uuid.UUID = nil
 	if payload.CategoryUUID != nil {
-		tmp, _ := uuid.Parse(*payload.CategoryUUID)
-		parsedCategoryUUID = &tmp
-
+		parsedCategoryUUID, err = ucase.validateCategory(ctx, *payload.CategoryUUID)
+		if err != nil {
+			return nil, err
+		}
 	}
+
+	// create book
 	newBook := &model.Book{
+		UUID:         uuid.New(),
 		AuthorUUID:   uuid.MustParse(getAuthorResp.Uuid),
 		Title:        payload.Title,
-		Stock:        payload.Stock,
 		CategoryUUID: parsedCategoryUUID,
 	}
 
@@ -136,7 +164,7 @@ func (ucase *BookUcase) Create(ctx context.Context, currentUser dto.CurrentUser,
 			return &tmp
 		}(),
 		Title:     newBook.Title,
-		Stock:     newBook.Stock,
+		Stock:     0, // copies are added separately
 		CreatedAt: newBook.CreatedAt,
 		UpdatedAt: newBook.UpdatedAt,
 	}, nil
@@ -210,14 +238,15 @@ func (ucase *BookUcase) PatchBook(
 	}
 
 	if payload.CategoryUUID != nil {
-		// TODO: validate category through category service
-
-		noValue := "no value"
-		if payload.CategoryUUID == &noValue {
+		if *payload.CategoryUUID == "no value" {
 			book.CategoryUUID = nil
 		} else {
-			tmp, _ := uuid.Parse(*payload.CategoryUUID)
-			book.CategoryUUID = &tmp
+			// validate category through category service
+			parsedCategoryUUID, err := ucase.validateCategory(ctx, *payload.CategoryUUID)
+			if err != nil {
+				return nil, err
+			}
+			book.CategoryUUID = parsedCategoryUUID
 		}
 	}
 
@@ -225,10 +254,6 @@ func (ucase *BookUcase) PatchBook(
 		book.Title = *payload.Title
 	}
 
-	if payload.Stock != nil {
-		book.Stock = *payload.Stock
-	}
-
 	// update book
 	err = ucase.bookRepo.Update(book)
 	if err != nil {
@@ -241,6 +266,17 @@ func (ucase *BookUcase) PatchBook(
 		}
 	}
 
+	stock, err := ucase.bookCopyRepo.CountAvailableByBookUUID(book.UUID.String())
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
 	return &dto.PatchBookRespData{
 		UUID:       book.UUID.String(),
 		AuthorUUID: book.AuthorUUID.String(),
@@ -252,7 +288,7 @@ func (ucase *BookUcase) PatchBook(
 			return &tmp
 		}(),
 		Title:     book.Title,
-		Stock:     book.Stock,
+		Stock:     stock,
 		CreatedAt: book.CreatedAt,
 		UpdatedAt: book.UpdatedAt,
 	}, nil
@@ -324,6 +360,17 @@ func (ucase *BookUcase) DeleteBook(
 		}
 	}
 
+	stock, err := ucase.bookCopyRepo.CountAvailableByBookUUID(book.UUID.String())
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
 	// delete book
 	err = ucase.bookRepo.Delete(bookUUID)
 	if err != nil {
@@ -347,12 +394,177 @@ func (ucase *BookUcase) DeleteBook(
 			return &tmp
 		}(),
 		Title:     book.Title,
-		Stock:     book.Stock,
+		Stock:     stock,
 		CreatedAt: book.CreatedAt,
 		UpdatedAt: book.UpdatedAt,
 	}, nil
 }
 
+func (ucase *BookUcase) GetBookList(
+	ctx context.Context,
+	params dto.GetBookListReq,
+) (*dto.GetBookListRespData, error) {
+	// prepare queryBy
+	queryBy := params.QueryBy
+	if queryBy == "any" {
+		queryBy = ""
+	}
+
+	repoParams := dto.BookRepo_GetListParams{
+		AuthorUUID:   params.AuthorUUID,
+		CategoryUUID: params.CategoryUUID,
+		Query:        params.Query,
+		QueryBy:      queryBy,
+		Page:         params.Page,
+		Limit:        params.Limit,
+		SortOrder:    params.SortOrder,
+		SortBy:       params.SortBy,
+	}
+
+	// get list
+	books, err := ucase.bookRepo.GetList(repoParams)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
+	// count
+	count, err := ucase.bookRepo.CountGetList(repoParams)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
+	// get stock of listed books
+	bookUUIDs := []string{}
+	for _, book := range books {
+		bookUUIDs = append(bookUUIDs, book.UUID.String())
+	}
+	stocks, err := ucase.bookCopyRepo.CountAvailableByBookUUIDs(bookUUIDs)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
+	res := &dto.GetBookListRespData{
+		Data: []dto.GetBookListRespDataItem{},
+	}
+	res.Set(params.Page, params.Limit, count)
+	for _, book := range books {
+		res.Data = append(res.Data, dto.GetBookListRespDataItem{
+			UUID:       book.UUID.String(),
+			AuthorUUID: book.AuthorUUID.String(),
+			CategoryUUID: func() *string {
+				if book.CategoryUUID == nil {
+					return nil
+				}
+				tmp := book.CategoryUUID.String()
+				return &tmp
+			}(),
+			Title:     book.Title,
+			Stock:     stocks[book.UUID.String()],
+			CreatedAt: book.CreatedAt,
+			UpdatedAt: book.UpdatedAt,
+		})
+	}
+
+	return res, nil
+}
+
+func (ucase *BookUcase) GetBookDetail(
+	ctx context.Context,
+	bookUUID string,
+) (*dto.GetBookDetailRespData, error) {
+	// find book
+	book, err := ucase.bookRepo.GetByUUID(bookUUID)
+	if err != nil {
+		if err.Error() == "not found" {
+			logger.Errorf("err: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 404,
+				GrpcCode: codes.NotFound,
+				Message:  "not found",
+				Detail:   err,
+			}
+		} else {
+			logger.Errorf("err: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: codes.Internal,
+				Message:  "internal server error",
+				Detail:   err,
+			}
+		}
+	}
+
+	stock, err := ucase.bookCopyRepo.CountAvailableByBookUUID(book.UUID.String())
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
+	res := &dto.GetBookDetailRespData{
+		UUID:       book.UUID.String(),
+		AuthorUUID: book.AuthorUUID.String(),
+		Title:      book.Title,
+		Stock:      stock,
+		CreatedAt:  book.CreatedAt,
+		UpdatedAt:  book.UpdatedAt,
+	}
+
+	// get author name through author service
+	getAuthorResp, err := ucase.authorGrpcServiceClient.GetAuthorByUUID(
+		ctx, &author_grpc.GetAuthorByUUIDReq{
+			Uuid: book.AuthorUUID.String(),
+		},
+	)
+	if err != nil {
+		logger.Warningf("failed to get author %s: %v", book.AuthorUUID, err)
+	} else {
+		authorName := strings.TrimSpace(getAuthorResp.FirstName + " " + getAuthorResp.LastName)
+		res.AuthorName = &authorName
+	}
+
+	// get category name through category service
+	if book.CategoryUUID != nil {
+		categoryUUID := book.CategoryUUID.String()
+		res.CategoryUUID = &categoryUUID
+
+		getCategoryResp, err := ucase.categoryGrpcServiceClient.GetCategoryByUUID(
+			ctx, &category_grpc.GetCategoryByUUIDReq{
+				Uuid: categoryUUID,
+			},
+		)
+		if err != nil {
+			logger.Warningf("failed to get category %s: %v", categoryUUID, err)
+		} else {
+			res.CategoryName = &getCategoryResp.Name
+		}
+	}
+
+	return res, nil
+}
+
 func (ucase *BookUcase) GetBookTotalByAuthorUUID(
 	ctx context.Context,
 	authorUUID string,
@@ -407,3 +619,229 @@ func (ucase *BookUcase) BulkGetBookTotalByAuthorUUIDs(
 
 	return results, nil
 }
+
+func (ucase *BookUcase) GetBookTotalByCategoryUUID(
+	ctx context.Context,
+	categoryUUID string,
+) (int64, error) {
+	if categoryUUID == "" {
+		return 0, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.InvalidArgument,
+			Message:  "invalid argument",
+			Detail:   "categoryUUID is empty",
+		}
+	}
+
+	if _, err := uuid.Parse(categoryUUID); err != nil {
+		return 0, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.InvalidArgument,
+			Message:  "invalid argument",
+			Detail:   "invalid category uuid",
+		}
+	}
+
+	count, err := ucase.bookRepo.CountGetList(
+		dto.BookRepo_GetListParams{
+			CategoryUUID: categoryUUID,
+		},
+	)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return 0, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
+	return count, nil
+}
+
+func (ucase *BookUcase) BulkGetBookTotalByCategoryUUIDs(
+	ctx context.Context,
+	payload dto.BulkGetBookTotalByCategoryUUIDsReq,
+) ([]dto.BulkGetBookTotalByCategoryUUIDsRespDataItem, error) {
+	// invalid uuids can not match any book, keep them out of the query
+	validCategoryUUIDs := []string{}
+	for _, categoryUUID := range payload.CategoryUUIDs {
+		if _, err := uuid.Parse(categoryUUID); err != nil {
+			logger.Warningf("invalid category uuid: %s; skip", categoryUUID)
+			continue
+		}
+		validCategoryUUIDs = append(validCategoryUUIDs, categoryUUID)
+	}
+
+	countByCategoryUUID, err := ucase.bookRepo.CountByCategoryUUIDs(validCategoryUUIDs)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
+	var results []dto.BulkGetBookTotalByCategoryUUIDsRespDataItem
+	for _, categoryUUID := range payload.CategoryUUIDs {
+		results = append(results, dto.BulkGetBookTotalByCategoryUUIDsRespDataItem{
+			CategoryUUID: categoryUUID,
+			Total:        countByCategoryUUID[categoryUUID],
+		})
+	}
+
+	return results, nil
+}
+
+// validateCategory makes sure categoryUUID is a valid uuid of an existing
+// category in category service.
+func (ucase *BookUcase) validateCategory(
+	ctx context.Context,
+	categoryUUID string,
+) (*uuid.UUID, error) {
+	parsedCategoryUUID, err := uuid.Parse(categoryUUID)
+	if err != nil {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.InvalidArgument,
+			Message:  "invalid input",
+			Detail:   "invalid category uuid",
+		}
+	}
+
+	_, err = ucase.categoryGrpcServiceClient.GetCategoryByUUID(
+		ctx, &category_grpc.GetCategoryByUUIDReq{
+			Uuid: parsedCategoryUUID.String(),
+		},
+	)
+
+	grpcCode := status.Code(err)
+
+	switch grpcCode {
+	case codes.OK:
+		return &parsedCategoryUUID, nil
+	case codes.NotFound, codes.InvalidArgument:
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.InvalidArgument,
+			Message:  "invalid input",
+			Detail:   "category not found",
+		}
+	default:
+		logger.Errorf("grpcCode: %v;\nerr: %v", grpcCode, err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+}
+
+func (ucase *BookUcase) GetActiveBorrowTotalByAuthorUUID(
+	ctx context.Context,
+	authorUUID string,
+) (int64, error) {
+	err := validateAuthorUUID(authorUUID)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := ucase.bookRepo.CountActiveBorrowsByAuthorUUID(authorUUID)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return 0, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
+	return count, nil
+}
+
+// ReassignBooksByAuthorUUID moves every book of the author to another author,
+// the new author is expected to exist in author service.
+func (ucase *BookUcase) ReassignBooksByAuthorUUID(
+	ctx context.Context,
+	authorUUID string,
+	newAuthorUUID string,
+) (int64, error) {
+	err := validateAuthorUUID(authorUUID)
+	if err != nil {
+		return 0, err
+	}
+	err = validateAuthorUUID(newAuthorUUID)
+	if err != nil {
+		return 0, err
+	}
+	if authorUUID == newAuthorUUID {
+		return 0, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.InvalidArgument,
+			Message:  "invalid argument",
+			Detail:   "books can not be reassigned to the same author",
+		}
+	}
+
+	count, err := ucase.bookRepo.ReassignByAuthorUUID(authorUUID, newAuthorUUID)
+	if err != nil {
+		return 0, authorBooksErr(err)
+	}
+
+	return count, nil
+}
+
+// DeleteBooksByAuthorUUID deletes every book of the author and cancels their
+// active holds.
+func (ucase *BookUcase) DeleteBooksByAuthorUUID(
+	ctx context.Context,
+	authorUUID string,
+) (int64, error) {
+	err := validateAuthorUUID(authorUUID)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := ucase.bookRepo.DeleteByAuthorUUID(authorUUID, helper.TimeNowUTC())
+	if err != nil {
+		return 0, authorBooksErr(err)
+	}
+
+	return count, nil
+}
+
+func validateAuthorUUID(authorUUID string) error {
+	_, err := uuid.Parse(authorUUID)
+	if err != nil {
+		return &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.InvalidArgument,
+			Message:  "invalid argument",
+			Detail:   "invalid author uuid",
+		}
+	}
+	return nil
+}
+
+func authorBooksErr(err error) error {
+	if err.Error() == "active borrows" {
+		return &error_utils.CustomErr{
+			HttpCode: 409,
+			GrpcCode: codes.FailedPrecondition,
+			Message:  "active borrows",
+			Detail:   "a book of the author is borrowed",
+		}
+	}
+	logger.Errorf("err: %v", err)
+	return &error_utils.CustomErr{
+		HttpCode: 500,
+		GrpcCode: codes.Internal,
+		Message:  "internal server error",
+		Detail:   err,
+	}
+}
@@ -0,0 +1,90 @@
+package ucase
+
+import (
+	category_grpc "book_service/interface/grpc/genproto/category"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeCategoryClient answers GetCategoryByUUID with err, or the category.
+type fakeCategoryClient struct {
+	category_grpc.CategoryServiceClient
+	err error
+}
+
+func (c *fakeCategoryClient) GetCategoryByUUID(
+	ctx context.Context,
+	in *category_grpc.GetCategoryByUUIDReq,
+	opts ...grpc.CallOption,
+) (*category_grpc.GetCategoryByUUIDResp, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &category_grpc.GetCategoryByUUIDResp{Uuid: in.Uuid}, nil
+}
+
+func TestBookUcase_validateCategory(t *testing.T) {
+	categoryUUID := uuid.New().String()
+
+	tests := []struct {
+		name         string
+		categoryUUID string
+		err          error
+		wantHttpCode int // 0 when valid
+		wantGrpcCode codes.Code
+	}{
+		{
+			name:         "found",
+			categoryUUID: categoryUUID,
+		},
+		{
+			name:         "malformed_uuid",
+			categoryUUID: "fiction",
+			wantHttpCode: 400,
+			wantGrpcCode: codes.InvalidArgument,
+		},
+		{
+			name:         "not_found",
+			categoryUUID: categoryUUID,
+			err:          status.Error(codes.NotFound, "category not found"),
+			wantHttpCode: 400,
+			wantGrpcCode: codes.InvalidArgument,
+		},
+		{
+			name:         "category_service_unavailable",
+			categoryUUID: categoryUUID,
+			err:          status.Error(codes.Unavailable, "connection refused"),
+			wantHttpCode: 500,
+			wantGrpcCode: codes.Internal,
+		},
+		{
+			name:         "category_service_deadline_exceeded",
+			categoryUUID: categoryUUID,
+			err:          status.Error(codes.DeadlineExceeded, "deadline exceeded"),
+			wantHttpCode: 500,
+			wantGrpcCode: codes.Internal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ucase := &BookUcase{categoryGrpcServiceClient: &fakeCategoryClient{err: tt.err}}
+
+			got, err := ucase.validateCategory(context.Background(), tt.categoryUUID)
+			if tt.wantHttpCode != 0 {
+				assertCustomErr(t, err, tt.wantHttpCode, tt.wantGrpcCode)
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if got.String() != tt.categoryUUID {
+				t.Errorf("expected category %s, got %s", tt.categoryUUID, got)
+			}
+		})
+	}
+}
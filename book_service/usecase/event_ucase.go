@@ -0,0 +1,87 @@
+package ucase
+
+import (
+	"book_service/config"
+	"book_service/domain/model"
+	"book_service/repository"
+	event_util "book_service/utils/event"
+	"book_service/utils/helper"
+	"context"
+)
+
+type EventUcase struct {
+	bookRepo     repository.IBookRepo
+	bookHoldRepo repository.IBookHoldRepo
+}
+
+type IEventUcase interface {
+	// HandleEvent applies an event of the other services. redelivered events
+	// are skipped, an error makes the broker deliver the event again.
+	HandleEvent(ctx context.Context, event event_util.Event) error
+	// RunConsumer handles the events of the broker until ctx is done.
+	RunConsumer(ctx context.Context, broker event_util.Broker)
+}
+
+func NewEventUcase(
+	bookRepo repository.IBookRepo,
+	bookHoldRepo repository.IBookHoldRepo,
+) IEventUcase {
+	return &EventUcase{
+		bookRepo:     bookRepo,
+		bookHoldRepo: bookHoldRepo,
+	}
+}
+
+func (ucase *EventUcase) HandleEvent(ctx context.Context, event event_util.Event) error {
+	processed := model.NewProcessedEvent(config.Envs.SERVICE_NAME, event)
+
+	var err error
+	switch event.Type {
+	case event_util.TypeUserDeleted:
+		// holds of a deleted user would block the queue until they expire
+		var payload event_util.UserPayload
+		err = event.DecodePayload(&payload)
+		if err != nil {
+			logger.Errorf("invalid %s payload: %v", event.Type, err)
+			return nil
+		}
+
+		var total int64
+		total, err = ucase.bookHoldRepo.CancelByUserUUID(payload.UUID, helper.TimeNowUTC(), processed)
+		if err == nil && total > 0 {
+			logger.Infof("%d holds of deleted user %s cancelled", total, payload.UUID)
+		}
+	case event_util.TypeCategoryDeleted:
+		var payload event_util.CategoryPayload
+		err = event.DecodePayload(&payload)
+		if err != nil {
+			logger.Errorf("invalid %s payload: %v", event.Type, err)
+			return nil
+		}
+
+		var total int64
+		total, err = ucase.bookRepo.ClearCategoryUUID(payload.UUID, processed)
+		if err == nil && total > 0 {
+			logger.Infof("category %s removed from %d books", payload.UUID, total)
+		}
+	default:
+		return nil
+	}
+
+	if err != nil {
+		if err.Error() == "already processed" {
+			logger.Debugf("event %s already processed", event.UUID.String())
+			return nil
+		}
+		logger.Errorf("err: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (ucase *EventUcase) RunConsumer(ctx context.Context, broker event_util.Broker) {
+	err := broker.Subscribe(ctx, config.Envs.SERVICE_NAME, ucase.HandleEvent)
+	if err != nil && ctx.Err() == nil {
+		logger.Errorf("event consumer stopped: %v", err)
+	}
+}
@@ -0,0 +1,252 @@
+package ucase
+
+import (
+	"book_service/domain/dto"
+	"book_service/domain/model"
+	"book_service/repository"
+	error_utils "book_service/utils/error"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+)
+
+type FineUcase struct {
+	fineLedgerRepo repository.IFineLedgerRepo
+	bookBorrowRepo repository.IBookBorrowRepo
+}
+
+type IFineUcase interface {
+	GetMyFines(
+		ctx context.Context,
+		currentUser dto.CurrentUser,
+		params dto.GetMyFinesReq,
+	) (*dto.GetMyFinesRespData, error)
+	WaiveFine(
+		ctx context.Context,
+		currentUser dto.CurrentUser,
+		payload dto.FineAdjustmentReq,
+	) (*dto.FineAdjustmentRespData, error) // fine:manage only
+	RecordFinePayment(
+		ctx context.Context,
+		currentUser dto.CurrentUser,
+		payload dto.FineAdjustmentReq,
+	) (*dto.FineAdjustmentRespData, error) // fine:manage only
+}
+
+func NewFineUcase(
+	fineLedgerRepo repository.IFineLedgerRepo,
+	bookBorrowRepo repository.IBookBorrowRepo,
+) IFineUcase {
+	return &FineUcase{
+		fineLedgerRepo: fineLedgerRepo,
+		bookBorrowRepo: bookBorrowRepo,
+	}
+}
+
+func (ucase *FineUcase) GetMyFines(
+	ctx context.Context,
+	currentUser dto.CurrentUser,
+	params dto.GetMyFinesReq,
+) (*dto.GetMyFinesRespData, error) {
+	// prepare type filter
+	entryType := params.Type
+	if entryType == "any" {
+		entryType = ""
+	}
+
+	repoParams := dto.FineLedgerRepo_GetListParams{
+		UserUUID:  currentUser.UUID,
+		Type:      entryType,
+		Page:      params.Page,
+		Limit:     params.Limit,
+		SortOrder: params.SortOrder,
+		SortBy:    "created_at",
+	}
+
+	entries, err := ucase.fineLedgerRepo.GetList(repoParams)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
+	count, err := ucase.fineLedgerRepo.CountGetList(repoParams)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
+	balance, err := ucase.fineLedgerRepo.GetBalanceByUserUUID(currentUser.UUID)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
+	res := &dto.GetMyFinesRespData{
+		Balance: balance,
+		Data:    []dto.GetMyFinesRespDataItem{},
+	}
+	res.Set(params.Page, params.Limit, count)
+	for _, entry := range entries {
+		res.Data = append(res.Data, dto.GetMyFinesRespDataItem{
+			UUID: entry.UUID.String(),
+			BorrowUUID: func() *string {
+				if entry.BorrowUUID == nil {
+					return nil
+				}
+				tmp := entry.BorrowUUID.String()
+				return &tmp
+			}(),
+			Type:      entry.Type,
+			Amount:    entry.Amount,
+			Note:      entry.Note,
+			CreatedAt: entry.CreatedAt,
+		})
+	}
+
+	return res, nil
+}
+
+func (ucase *FineUcase) WaiveFine(
+	ctx context.Context,
+	currentUser dto.CurrentUser,
+	payload dto.FineAdjustmentReq,
+) (*dto.FineAdjustmentRespData, error) {
+	return ucase.createAdjustment(currentUser, payload, model.FineEntryTypeWaiver)
+}
+
+func (ucase *FineUcase) RecordFinePayment(
+	ctx context.Context,
+	currentUser dto.CurrentUser,
+	payload dto.FineAdjustmentReq,
+) (*dto.FineAdjustmentRespData, error) {
+	return ucase.createAdjustment(currentUser, payload, model.FineEntryTypePayment)
+}
+
+// createAdjustment writes a waiver or payment entry that lowers the user's
+// outstanding balance. adjustments can never bring the balance below zero.
+func (ucase *FineUcase) createAdjustment(
+	currentUser dto.CurrentUser,
+	payload dto.FineAdjustmentReq,
+	entryType string,
+) (*dto.FineAdjustmentRespData, error) {
+	// validate input
+	parsedUserUUID, err := uuid.Parse(payload.UserUUID)
+	if err != nil {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.InvalidArgument,
+			Message:  "invalid input",
+			Detail:   "invalid user uuid",
+		}
+	}
+
+	var parsedBorrowUUID *uuid.UUID = nil
+	if payload.BorrowUUID != nil {
+		bookBorrow, err := ucase.bookBorrowRepo.GetByUUID(*payload.BorrowUUID)
+		if err != nil {
+			if err.Error() == "not found" {
+				return nil, &error_utils.CustomErr{
+					HttpCode: 400,
+					GrpcCode: codes.InvalidArgument,
+					Message:  "invalid input",
+					Detail:   "borrow not found",
+				}
+			}
+			logger.Errorf("err: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: codes.Internal,
+				Message:  "internal server error",
+				Detail:   err,
+			}
+		}
+
+		if bookBorrow.UserUUID != parsedUserUUID {
+			return nil, &error_utils.CustomErr{
+				HttpCode: 400,
+				GrpcCode: codes.InvalidArgument,
+				Message:  "invalid input",
+				Detail:   "borrow does not belong to user",
+			}
+		}
+		parsedBorrowUUID = &bookBorrow.UUID
+	}
+
+	// check balance
+	balance, err := ucase.fineLedgerRepo.GetBalanceByUserUUID(payload.UserUUID)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+	if payload.Amount > balance {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.FailedPrecondition,
+			Message:  "invalid input",
+			Detail:   fmt.Sprintf("amount %d exceeds outstanding balance %d", payload.Amount, balance),
+		}
+	}
+
+	// create entry
+	parsedCurrentUserUUID, _ := uuid.Parse(currentUser.UUID)
+	entry := &model.FineLedgerEntry{
+		UUID:       uuid.New(),
+		UserUUID:   parsedUserUUID,
+		BorrowUUID: parsedBorrowUUID,
+		Type:       entryType,
+		Amount:     payload.Amount,
+		Note:       payload.Note,
+		CreatedBy:  &parsedCurrentUserUUID,
+	}
+
+	err = ucase.fineLedgerRepo.Create(entry)
+	if err != nil {
+		logger.Errorf("err: %v", err)
+		return nil, &error_utils.CustomErr{
+			HttpCode: 500,
+			GrpcCode: codes.Internal,
+			Message:  "internal server error",
+			Detail:   err,
+		}
+	}
+
+	return &dto.FineAdjustmentRespData{
+		UUID:     entry.UUID.String(),
+		UserUUID: entry.UserUUID.String(),
+		BorrowUUID: func() *string {
+			if entry.BorrowUUID == nil {
+				return nil
+			}
+			tmp := entry.BorrowUUID.String()
+			return &tmp
+		}(),
+		Type:      entry.Type,
+		Amount:    entry.Amount,
+		Note:      entry.Note,
+		CreatedBy: currentUser.UUID,
+		Balance:   balance - entry.Amount,
+		CreatedAt: entry.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,67 @@
+package ucase
+
+import (
+	"book_service/config"
+	"book_service/domain/model"
+	"book_service/repository"
+	event_util "book_service/utils/event"
+	"book_service/utils/helper"
+	"context"
+	"time"
+)
+
+type OutboxUcase struct {
+	outboxRepo repository.IOutboxRepo
+	broker     event_util.Broker
+}
+
+type IOutboxUcase interface {
+	// Relay publishes the pending outbox events to the broker, in the order
+	// they were written, and returns how many were published.
+	Relay(ctx context.Context) (int, error)
+	// RunRelay relays every EVENT_RELAY_INTERVAL_SECONDS until ctx is done.
+	RunRelay(ctx context.Context)
+}
+
+func NewOutboxUcase(outboxRepo repository.IOutboxRepo, broker event_util.Broker) IOutboxUcase {
+	return &OutboxUcase{
+		outboxRepo: outboxRepo,
+		broker:     broker,
+	}
+}
+
+func (ucase *OutboxUcase) Relay(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		published, err := ucase.outboxRepo.RelayBatch(config.Envs.EVENT_RELAY_BATCH_SIZE, helper.TimeNowUTC(), func(event model.OutboxEvent) error {
+			return ucase.broker.Publish(ctx, event.ToEvent(config.Envs.SERVICE_NAME))
+		})
+		total += published
+		if err != nil {
+			return total, err
+		}
+		// a full batch means more may be waiting
+		if published < config.Envs.EVENT_RELAY_BATCH_SIZE {
+			return total, nil
+		}
+	}
+}
+
+func (ucase *OutboxUcase) RunRelay(ctx context.Context) {
+	interval := time.Second * time.Duration(config.Envs.EVENT_RELAY_INTERVAL_SECONDS)
+	for {
+		published, err := ucase.Relay(ctx)
+		if err != nil {
+			// the event stays in the outbox and is published on a later run
+			logger.Errorf("error relaying outbox events: %v", err)
+		} else if published > 0 {
+			logger.Debugf("%d outbox events relayed", published)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
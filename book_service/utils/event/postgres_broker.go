@@ -0,0 +1,257 @@
+package event_util
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	postgresBrokerChannel = "library_events"
+	// advisory lock serializing publishes, so events commit in id order and a
+	// consumer never moves past an id committed later
+	postgresBrokerLockKey = 72616301
+	// consumers also look for events this often, in case a notification was
+	// missed while reconnecting
+	postgresBrokerPollInterval = time.Second * 30
+	postgresBrokerBatchSize    = 100
+)
+
+const postgresBrokerSchema = `
+CREATE TABLE IF NOT EXISTS broker_events (
+    id bigserial PRIMARY KEY,
+    uuid uuid NOT NULL UNIQUE,
+    type varchar(100) NOT NULL,
+    source varchar(50) NOT NULL,
+    aggregate_uuid uuid NOT NULL,
+    payload jsonb NOT NULL,
+    occurred_at timestamptz NOT NULL,
+    published_at timestamptz NOT NULL DEFAULT now()
+);
+CREATE TABLE IF NOT EXISTS broker_offsets (
+    consumer varchar(100) PRIMARY KEY,
+    last_event_id bigint NOT NULL,
+    updated_at timestamptz NOT NULL
+);`
+
+// PostgresBroker keeps the events in a table of a database shared by the
+// services and wakes the consumers up with LISTEN/NOTIFY. events and the
+// position of each consumer survive restarts, a consumer subscribing for the
+// first time gets every event from the start.
+type PostgresBroker struct {
+	dsn           string
+	pool          *pgxpool.Pool
+	retryInterval time.Duration
+}
+
+// NewPostgresBroker connects to the database of the broker and creates its
+// tables when missing.
+func NewPostgresBroker(ctx context.Context, dsn string) (*PostgresBroker, error) {
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	err = pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", postgresBrokerLockKey)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, postgresBrokerSchema)
+		return err
+	})
+	if err != nil {
+		pool.Close()
+		return nil, errors.New("failed to create broker tables: " + err.Error())
+	}
+
+	return &PostgresBroker{
+		dsn:           dsn,
+		pool:          pool,
+		retryInterval: defaultRetryInterval,
+	}, nil
+}
+
+func (b *PostgresBroker) Publish(ctx context.Context, event Event) error {
+	return pgx.BeginFunc(ctx, b.pool, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", postgresBrokerLockKey)
+		if err != nil {
+			return err
+		}
+
+		tag, err := tx.Exec(
+			ctx,
+			`INSERT INTO broker_events (uuid, type, source, aggregate_uuid, payload, occurred_at)
+			VALUES ($1::text::uuid, $2, $3, $4::text::uuid, $5::text::jsonb, $6)
+			ON CONFLICT (uuid) DO NOTHING`,
+			event.UUID.String(),
+			event.Type,
+			event.Source,
+			event.AggregateUUID.String(),
+			string(event.Payload),
+			event.OccurredAt,
+		)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return nil
+		}
+
+		// delivered to the listeners once committed
+		_, err = tx.Exec(ctx, "SELECT pg_notify($1, '')", postgresBrokerChannel)
+		return err
+	})
+}
+
+func (b *PostgresBroker) Subscribe(ctx context.Context, consumer string, handler Handler) error {
+	for {
+		err := b.listen(ctx, consumer, handler)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		logger.Errorf("consumer %s lost the broker connection: %v", consumer, err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(b.retryInterval):
+		}
+	}
+}
+
+// listen delivers the pending events, then waits on a dedicated connection
+// for more.
+func (b *PostgresBroker) listen(ctx context.Context, consumer string, handler Handler) error {
+	conn, err := pgx.Connect(ctx, b.dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	_, err = conn.Exec(ctx, "LISTEN "+postgresBrokerChannel)
+	if err != nil {
+		return err
+	}
+
+	for {
+		wait := postgresBrokerPollInterval
+		err := b.deliver(ctx, consumer, handler)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			logger.Errorf("consumer %s failed: %v", consumer, err)
+			wait = b.retryInterval
+		}
+
+		waitCtx, cancel := context.WithTimeout(ctx, wait)
+		_, err = conn.WaitForNotification(waitCtx)
+		timedOut := waitCtx.Err() != nil
+		cancel()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil && !timedOut {
+			return err
+		}
+	}
+}
+
+// deliver hands the events after the position of the consumer to handler,
+// moving the position after each one. instances sharing a consumer name may
+// both handle an event, the position only moves forward.
+func (b *PostgresBroker) deliver(ctx context.Context, consumer string, handler Handler) error {
+	for {
+		var offset int64
+		err := b.pool.QueryRow(
+			ctx, "SELECT last_event_id FROM broker_offsets WHERE consumer = $1", consumer,
+		).Scan(&offset)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return errors.New("failed to get offset: " + err.Error())
+		}
+
+		ids, events, err := b.getEventsAfter(ctx, offset)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		for i, event := range events {
+			err = handler(ctx, event)
+			if err != nil {
+				return errors.New("event " + event.UUID.String() + ": " + err.Error())
+			}
+
+			_, err = b.pool.Exec(
+				ctx,
+				`INSERT INTO broker_offsets (consumer, last_event_id, updated_at)
+				VALUES ($1, $2, now())
+				ON CONFLICT (consumer) DO UPDATE
+				SET last_event_id = GREATEST(broker_offsets.last_event_id, EXCLUDED.last_event_id),
+					updated_at = EXCLUDED.updated_at`,
+				consumer,
+				ids[i],
+			)
+			if err != nil {
+				return errors.New("failed to update offset: " + err.Error())
+			}
+		}
+	}
+}
+
+func (b *PostgresBroker) getEventsAfter(ctx context.Context, offset int64) ([]int64, []Event, error) {
+	rows, err := b.pool.Query(
+		ctx,
+		`SELECT id, uuid::text, type, source, aggregate_uuid::text, payload::text, occurred_at
+		FROM broker_events
+		WHERE id > $1
+		ORDER BY id ASC
+		LIMIT $2`,
+		offset,
+		postgresBrokerBatchSize,
+	)
+	if err != nil {
+		return nil, nil, errors.New("failed to get events: " + err.Error())
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	events := []Event{}
+	for rows.Next() {
+		var id int64
+		var eventUUID, aggregateUUID, payload string
+		var event Event
+		err = rows.Scan(&id, &eventUUID, &event.Type, &event.Source, &aggregateUUID, &payload, &event.OccurredAt)
+		if err != nil {
+			return nil, nil, errors.New("failed to scan event: " + err.Error())
+		}
+		event.UUID, err = uuid.Parse(eventUUID)
+		if err != nil {
+			return nil, nil, err
+		}
+		event.AggregateUUID, err = uuid.Parse(aggregateUUID)
+		if err != nil {
+			return nil, nil, err
+		}
+		event.Payload = []byte(payload)
+
+		ids = append(ids, id)
+		events = append(events, event)
+	}
+	if rows.Err() != nil {
+		return nil, nil, errors.New("failed to get events: " + rows.Err().Error())
+	}
+	return ids, events, nil
+}
+
+func (b *PostgresBroker) Close() error {
+	b.pool.Close()
+	return nil
+}
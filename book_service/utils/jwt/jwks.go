@@ -0,0 +1,158 @@
+package jwt_util
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/op/go-logging"
+)
+
+var logger = logging.MustGetLogger("main")
+
+// unknown kids trigger a refetch, at most once per this interval
+const minRefetchInterval = 10 * time.Second
+
+const fetchTimeout = 5 * time.Second
+
+// JWK is a public signing key published by auth_service.
+type JWK struct {
+	Kty string
+	Kid string
+	Alg string
+	N   string
+	E   string
+	Crv string
+	X   string
+}
+
+type PublicKey struct {
+	KID       string
+	Algorithm string
+	Key       crypto.PublicKey
+}
+
+// KeySet caches the auth_service JWKS. keys are refetched once ttl passed, or
+// early when a token has an unknown kid after a key rotation.
+type KeySet struct {
+	fetch func(ctx context.Context) ([]JWK, error)
+	ttl   time.Duration
+
+	mu        sync.RWMutex
+	keys      map[string]*PublicKey
+	fetchedAt time.Time
+
+	fetchMu     sync.Mutex
+	attemptedAt time.Time
+}
+
+func NewKeySet(fetch func(ctx context.Context) ([]JWK, error), ttl time.Duration) *KeySet {
+	return &KeySet{
+		fetch: fetch,
+		ttl:   ttl,
+		keys:  map[string]*PublicKey{},
+	}
+}
+
+func (ks *KeySet) GetKey(kid string) (*PublicKey, error) {
+	ks.mu.RLock()
+	key, ok := ks.keys[kid]
+	fresh := time.Since(ks.fetchedAt) < ks.ttl
+	ks.mu.RUnlock()
+	if ok && fresh {
+		return key, nil
+	}
+
+	if err := ks.refresh(); err != nil {
+		// keep serving a known key while auth_service is unreachable
+		if ok {
+			return key, nil
+		}
+		return nil, err
+	}
+
+	ks.mu.RLock()
+	key, ok = ks.keys[kid]
+	ks.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown kid: %s", kid)
+	}
+	return key, nil
+}
+
+func (ks *KeySet) refresh() error {
+	ks.fetchMu.Lock()
+	defer ks.fetchMu.Unlock()
+
+	if time.Since(ks.attemptedAt) < minRefetchInterval {
+		return nil
+	}
+	ks.attemptedAt = time.Now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+	jwks, err := ks.fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch jwks: %v", err)
+	}
+
+	keys := map[string]*PublicKey{}
+	for _, jwk := range jwks {
+		key, err := parseJWK(jwk)
+		if err != nil {
+			logger.Warningf("skipping jwk %s: %v", jwk.Kid, err)
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	ks.mu.Lock()
+	ks.keys = keys
+	ks.fetchedAt = time.Now()
+	ks.mu.Unlock()
+
+	return nil
+}
+
+func parseJWK(jwk JWK) (*PublicKey, error) {
+	switch {
+	case jwk.Kty == "RSA" && jwk.Alg == "RS256":
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		return &PublicKey{
+			KID:       jwk.Kid,
+			Algorithm: jwk.Alg,
+			Key: &rsa.PublicKey{
+				N: new(big.Int).SetBytes(n),
+				E: int(new(big.Int).SetBytes(e).Int64()),
+			},
+		}, nil
+	case jwk.Kty == "OKP" && jwk.Crv == "Ed25519" && jwk.Alg == "EdDSA":
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid ed25519 key size")
+		}
+		return &PublicKey{
+			KID:       jwk.Kid,
+			Algorithm: jwk.Alg,
+			Key:       ed25519.PublicKey(x),
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %s with algorithm %s", jwk.Kty, jwk.Alg)
+}
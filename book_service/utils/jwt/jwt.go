@@ -3,45 +3,91 @@ package jwt_util
 import (
 	"book_service/domain/dto"
 	"fmt"
+	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 )
 
-func ValidateJWT(tokenString string, secretKey string) (*dto.CurrentUser, error) {
-	var JWT_SIGNATURE_KEY = []byte(secretKey)
+// ValidateJWT verifies the token against the auth_service public key matching
+// its kid header. the claims are then validated against claimsConfig.
+func ValidateJWT(tokenString string, keySet *KeySet, claimsConfig ClaimsConfig) (*dto.CurrentUser, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			return nil, fmt.Errorf("missing kid")
+		}
 
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if method, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("signing method invalid")
-		} else if method != jwt.SigningMethodHS256 {
+		publicKey, err := keySet.GetKey(kid)
+		if err != nil {
+			return nil, err
+		}
+
+		// the key decides the algorithm, never the token
+		if token.Method.Alg() != publicKey.Algorithm {
 			return nil, fmt.Errorf("signing method invalid")
 		}
 
-		return JWT_SIGNATURE_KEY, nil
-	})
+		return publicKey.Key, nil
+	}, jwt.WithoutClaimsValidation())
 
 	if err != nil {
 		return nil, err
 	}
 
 	if !token.Valid {
-		return nil, err
+		return nil, fmt.Errorf("invalid token")
 	}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
+	err = claims.Validate(claimsConfig, time.Now())
+	if err != nil {
 		return nil, err
 	}
 
-	sub, _ := claims["sub"].(string)
-	username, _ := claims["username"].(string)
-	role, _ := claims["role"].(string)
-	email, _ := claims["email"].(string)
-
 	return &dto.CurrentUser{
-		UUID:     sub,
-		Email:    email,
-		Username: username,
-		Role:     role,
+		UUID:        claims.Subject,
+		Email:       claims.Email,
+		Username:    claims.Username,
+		Role:        claims.Role,
+		Permissions: claims.Permissions,
 	}, nil
 }
+
+// ValidateServiceToken verifies a service token like ValidateJWT and returns
+// the name of the service.
+func ValidateServiceToken(tokenString string, keySet *KeySet, issuer string) (string, error) {
+	claims := &ServiceClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			return nil, fmt.Errorf("missing kid")
+		}
+
+		publicKey, err := keySet.GetKey(kid)
+		if err != nil {
+			return nil, err
+		}
+
+		// the key decides the algorithm, never the token
+		if token.Method.Alg() != publicKey.Algorithm {
+			return nil, fmt.Errorf("signing method invalid")
+		}
+
+		return publicKey.Key, nil
+	}, jwt.WithoutClaimsValidation())
+
+	if err != nil {
+		return "", err
+	}
+
+	if !token.Valid {
+		return "", fmt.Errorf("invalid token")
+	}
+
+	err = claims.Validate(issuer, time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	return claims.Subject, nil
+}
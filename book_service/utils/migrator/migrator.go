@@ -0,0 +1,251 @@
+package migrator
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/op/go-logging"
+	"gorm.io/gorm"
+)
+
+var logger = logging.MustGetLogger("main")
+
+// migration files are named <version>_<name>.<up|down>.sql, e.g. 0001_init.up.sql
+var fileNameRegex = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+}
+
+type SchemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"type:text;not null"`
+	AppliedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Load reads every migration in fsys sorted by version. every version must have
+// both an up and a down file.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %v", err)
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		matches := fileNameRegex.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version: %s", entry.Name())
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %v", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = migration
+		} else if migration.Name != matches[2] {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", version, migration.Name, matches[2])
+		}
+
+		if matches[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := []Migration{}
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", migration.Version, migration.Name)
+		}
+		if migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s has no down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Up applies every pending migration in order. each migration runs in its own
+// transaction together with its schema_migrations row.
+func Up(db *gorm.DB, fsys fs.FS) error {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return err
+	}
+	if err := ensureTable(db); err != nil {
+		return err
+	}
+
+	for _, migration := range migrations {
+		applied := false
+		err := db.Transaction(func(tx *gorm.DB) error {
+			// serialize concurrent runs, the second one will see the version applied
+			if err := lockTable(tx); err != nil {
+				return err
+			}
+
+			var count int64
+			err := tx.Model(&SchemaMigration{}).Where("version = ?", migration.Version).Count(&count).Error
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				return nil
+			}
+
+			if err := tx.Exec(migration.Up).Error; err != nil {
+				return err
+			}
+			applied = true
+			return tx.Create(&SchemaMigration{Version: migration.Version, Name: migration.Name}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %d_%s: %v", migration.Version, migration.Name, err)
+		}
+		if applied {
+			logger.Infof("applied migration %d_%s", migration.Version, migration.Name)
+		}
+	}
+
+	return nil
+}
+
+// Down rolls back the latest applied migration only.
+func Down(db *gorm.DB, fsys fs.FS) error {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return err
+	}
+	if err := ensureTable(db); err != nil {
+		return err
+	}
+
+	var rolledBack *Migration
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := lockTable(tx); err != nil {
+			return err
+		}
+
+		var latest SchemaMigration
+		err := tx.Order("version desc").First(&latest).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		for i := range migrations {
+			if migrations[i].Version == latest.Version {
+				rolledBack = &migrations[i]
+				break
+			}
+		}
+		if rolledBack == nil {
+			return fmt.Errorf("migration file for version %d not found", latest.Version)
+		}
+
+		if err := tx.Exec(rolledBack.Down).Error; err != nil {
+			return err
+		}
+		return tx.Where("version = ?", latest.Version).Delete(&SchemaMigration{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to roll back migration: %v", err)
+	}
+
+	if rolledBack == nil {
+		logger.Info("no migration to roll back")
+	} else {
+		logger.Infof("rolled back migration %d_%s", rolledBack.Version, rolledBack.Name)
+	}
+
+	return nil
+}
+
+// Status lists every known migration with the time it was applied, nil when
+// still pending.
+func Status(db *gorm.DB, fsys fs.FS) ([]MigrationStatus, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureTable(db); err != nil {
+		return nil, err
+	}
+
+	applied := []SchemaMigration{}
+	if err := db.Find(&applied).Error; err != nil {
+		return nil, fmt.Errorf("failed to get applied migrations: %v", err)
+	}
+	appliedAt := map[int64]time.Time{}
+	for _, item := range applied {
+		appliedAt[item.Version] = item.AppliedAt
+	}
+
+	res := []MigrationStatus{}
+	for _, migration := range migrations {
+		status := MigrationStatus{
+			Version: migration.Version,
+			Name:    migration.Name,
+		}
+		if tmp, ok := appliedAt[migration.Version]; ok {
+			status.AppliedAt = &tmp
+		}
+		res = append(res, status)
+	}
+
+	return res, nil
+}
+
+func ensureTable(db *gorm.DB) error {
+	err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version bigint PRIMARY KEY,
+		name text NOT NULL,
+		applied_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`).Error
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %v", err)
+	}
+
+	return nil
+}
+
+func lockTable(tx *gorm.DB) error {
+	return tx.Exec("LOCK TABLE schema_migrations IN EXCLUSIVE MODE").Error
+}
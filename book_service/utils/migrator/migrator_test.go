@@ -0,0 +1,205 @@
+package migrator
+
+import (
+	"book_service/migrations"
+	"fmt"
+	"os"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		want    []int64
+		wantErr bool
+	}{
+		{
+			name: "sorted_by_version",
+			fsys: fstest.MapFS{
+				"0002_add_column.up.sql":   {Data: []byte("ALTER TABLE a ADD COLUMN b text;")},
+				"0002_add_column.down.sql": {Data: []byte("ALTER TABLE a DROP COLUMN b;")},
+				"0001_init.up.sql":         {Data: []byte("CREATE TABLE a (id bigserial);")},
+				"0001_init.down.sql":       {Data: []byte("DROP TABLE a;")},
+				"migrations.go":            {Data: []byte("package migrations")},
+			},
+			want: []int64{1, 2},
+		},
+		{
+			name: "missing_down_file",
+			fsys: fstest.MapFS{
+				"0001_init.up.sql": {Data: []byte("CREATE TABLE a (id bigserial);")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate_version",
+			fsys: fstest.MapFS{
+				"0001_init.up.sql":    {Data: []byte("CREATE TABLE a (id bigserial);")},
+				"0001_init.down.sql":  {Data: []byte("DROP TABLE a;")},
+				"0001_other.up.sql":   {Data: []byte("CREATE TABLE b (id bigserial);")},
+				"0001_other.down.sql": {Data: []byte("DROP TABLE b;")},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(tt.fsys)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Load() got %d migrations, want %d", len(got), len(tt.want))
+			}
+			for i, migration := range got {
+				if migration.Version != tt.want[i] {
+					t.Errorf("Load()[%d].Version = %d, want %d", i, migration.Version, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// every migration shipped with the service must load
+func TestLoad_ServiceMigrations(t *testing.T) {
+	got, err := Load(migrations.FS)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(got) == 0 {
+		t.Fatal("Load() found no migrations")
+	}
+}
+
+// baselineBook and baselineBookBorrow are the models auto migrated before
+// versioned migrations
+type baselineBook struct {
+	gorm.Model
+	UUID         uuid.UUID  `gorm:"type:uuid;unique;not null"`
+	AuthorUUID   uuid.UUID  `gorm:"type:uuid;not null"`
+	CategoryUUID *uuid.UUID `gorm:"type:uuid"`
+	Title        string     `gorm:"type:text;not null"`
+	Stock        int64
+
+	BookBorrows []baselineBookBorrow `gorm:"foreignKey:BookUUID;references:UUID;constraint:OnDelete:CASCADE;"`
+}
+
+func (baselineBook) TableName() string {
+	return "books"
+}
+
+type baselineBookBorrow struct {
+	gorm.Model
+	UUID       uuid.UUID `gorm:"type:uuid;unique;not null"`
+	BookUUID   uuid.UUID `gorm:"type:uuid;not null"`
+	UserUUID   uuid.UUID `gorm:"type:uuid;not null"`
+	BorrowDate *string
+	ReturnDate *string
+}
+
+func (baselineBookBorrow) TableName() string {
+	return "book_borrows"
+}
+
+// TestUp_FromAutoMigratedBaseline needs a postgres database in
+// TEST_POSTGRESQL_DSN, the test runs in a schema of its own and drops it after.
+func TestUp_FromAutoMigratedBaseline(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRESQL_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRESQL_DSN is not set")
+	}
+
+	schema := fmt.Sprintf("migrator_test_%d", time.Now().UnixNano())
+	adminDB, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	if err := adminDB.Exec(fmt.Sprintf("CREATE SCHEMA %q", schema)).Error; err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		adminDB.Exec(fmt.Sprintf("DROP SCHEMA %q CASCADE", schema))
+	})
+
+	db, err := gorm.Open(postgres.Open(dsn+" search_path="+schema), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	if err := db.AutoMigrate(&baselineBook{}, &baselineBookBorrow{}); err != nil {
+		t.Fatalf("failed to auto migrate baseline: %v", err)
+	}
+
+	book := baselineBook{UUID: uuid.New(), AuthorUUID: uuid.New(), Title: "Dune", Stock: 2}
+	borrowDate := "2026-01-02T10:00:00Z"
+	returnDate := "2026-01-05T10:00:00Z"
+	returned := baselineBookBorrow{UUID: uuid.New(), BookUUID: book.UUID, UserUUID: uuid.New(), BorrowDate: &borrowDate, ReturnDate: &returnDate}
+	active := baselineBookBorrow{UUID: uuid.New(), BookUUID: book.UUID, UserUUID: uuid.New()}
+	if err := db.Create(&book).Error; err != nil {
+		t.Fatalf("failed to create book: %v", err)
+	}
+	for _, item := range []*baselineBookBorrow{&returned, &active} {
+		if err := db.Create(item).Error; err != nil {
+			t.Fatalf("failed to create borrow: %v", err)
+		}
+	}
+
+	if err := Up(db, migrations.FS); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	var copies int64
+	db.Table("book_copies").Where("book_uuid = ? AND status = 'available'", book.UUID).Count(&copies)
+	if copies != 2 {
+		t.Errorf("expected the stock turned into 2 copies, got %d", copies)
+	}
+
+	for _, column := range []string{"borrow_date", "return_date"} {
+		if db.Migrator().HasColumn("book_borrows", column) {
+			t.Errorf("expected column %s to be dropped", column)
+		}
+	}
+
+	type borrow struct {
+		UUID       uuid.UUID
+		CopyUUID   *uuid.UUID
+		BorrowedAt time.Time
+		DueAt      time.Time
+		ReturnedAt *time.Time
+		RenewCount int
+	}
+	var got borrow
+	if err := db.Table("book_borrows").Where("uuid = ?", returned.UUID).Take(&got).Error; err != nil {
+		t.Fatalf("failed to get returned borrow: %v", err)
+	}
+	wantBorrowedAt, _ := time.Parse(time.RFC3339, borrowDate)
+	wantReturnedAt, _ := time.Parse(time.RFC3339, returnDate)
+	if !got.BorrowedAt.Equal(wantBorrowedAt) {
+		t.Errorf("expected borrowed_at %v, got %v", wantBorrowedAt, got.BorrowedAt)
+	}
+	if !got.DueAt.Equal(wantBorrowedAt.AddDate(0, 0, 14)) {
+		t.Errorf("expected due_at 14 days after borrowed_at, got %v", got.DueAt)
+	}
+	if got.ReturnedAt == nil || !got.ReturnedAt.Equal(wantReturnedAt) {
+		t.Errorf("expected returned_at %v, got %v", wantReturnedAt, got.ReturnedAt)
+	}
+	if got.CopyUUID != nil || got.RenewCount != 0 {
+		t.Errorf("expected no copy and no renewal, got %+v", got)
+	}
+
+	if err := db.Table("book_borrows").Where("uuid = ?", active.UUID).Take(&got).Error; err != nil {
+		t.Fatalf("failed to get active borrow: %v", err)
+	}
+	if got.ReturnedAt != nil {
+		t.Errorf("expected the borrow without return_date to stay active, got %v", got.ReturnedAt)
+	}
+	if got.BorrowedAt.Sub(active.CreatedAt).Abs() > time.Second {
+		t.Errorf("expected borrowed_at from created_at %v, got %v", active.CreatedAt, got.BorrowedAt)
+	}
+}
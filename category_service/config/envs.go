@@ -5,11 +5,14 @@ import (
 )
 
 type EnvsSchema struct {
-	HOST           string
-	PORT           int
-	GRPC_PORT      int
-	LOG_LEVEL      string
-	JWT_SECRET_KEY string
+	HOST                      string
+	PORT                      int
+	GRPC_PORT                 int
+	LOG_LEVEL                 string
+	JWKS_CACHE_TTL_SECONDS    int
+	API_KEY_CACHE_TTL_SECONDS int // revoked api keys are accepted up to this long
+	JWT_ISSUER                string
+	JWT_AUDIENCE              string
 
 	POSTGRESQL_HOST     string
 	POSTGRESQL_PORT     int
@@ -17,26 +20,55 @@ type EnvsSchema struct {
 	POSTGRESQL_PASSWORD string
 	POSTGRESQL_DB       string
 
-	// AUTH_GRPC_SERVICE    string
+	AUTH_GRPC_SERVICE   string
 	AUTHOR_GRPC_SERVICE string
+	BOOK_GRPC_SERVICE   string
+
+	SERVICE_NAME   string // name this service is known by in auth_service SERVICE_CLIENT_SECRETS
+	SERVICE_SECRET string // empty when the service is identified by its mtls certificate
+
+	GRPC_TLS_CERT_FILE string // with the key, served and presented to other services
+	GRPC_TLS_KEY_FILE  string
+	GRPC_TLS_CA_FILE   string // verifies the certificates of the other services, enables mtls
+
+	EVENT_BROKER                 string // postgres, memory or none
+	EVENT_BROKER_POSTGRESQL_DB   string // shared by every service, notifications do not cross databases
+	EVENT_RELAY_INTERVAL_SECONDS int
+	EVENT_RELAY_BATCH_SIZE       int
 }
 
 var Envs *EnvsSchema
 
 func envInitiator() {
 	Envs = &EnvsSchema{
-		HOST:                viper.GetString("HOST"),
-		PORT:                viper.GetInt("PORT"),
-		GRPC_PORT:           viper.GetInt("GRPC_PORT"),
-		LOG_LEVEL:           viper.GetString("LOG_LEVEL"),
-		JWT_SECRET_KEY:      viper.GetString("JWT_SECRET_KEY"),
-		POSTGRESQL_HOST:     viper.GetString("POSTGRESQL_HOST"),
-		POSTGRESQL_PORT:     viper.GetInt("POSTGRESQL_PORT"),
-		POSTGRESQL_USER:     viper.GetString("POSTGRESQL_USER"),
-		POSTGRESQL_PASSWORD: viper.GetString("POSTGRESQL_PASSWORD"),
-		POSTGRESQL_DB:       viper.GetString("POSTGRESQL_DB"),
-		// AUTH_GRPC_SERVICE:    viper.GetString("AUTH_GRPC_SERVICE"),
-		AUTHOR_GRPC_SERVICE: viper.GetString("AUTHOR_GRPC_SERVICE"),
+		HOST:                      viper.GetString("HOST"),
+		PORT:                      viper.GetInt("PORT"),
+		GRPC_PORT:                 viper.GetInt("GRPC_PORT"),
+		LOG_LEVEL:                 viper.GetString("LOG_LEVEL"),
+		JWKS_CACHE_TTL_SECONDS:    viper.GetInt("JWKS_CACHE_TTL_SECONDS"),
+		API_KEY_CACHE_TTL_SECONDS: viper.GetInt("API_KEY_CACHE_TTL_SECONDS"),
+		JWT_ISSUER:                viper.GetString("JWT_ISSUER"),
+		JWT_AUDIENCE:              viper.GetString("JWT_AUDIENCE"),
+		POSTGRESQL_HOST:           viper.GetString("POSTGRESQL_HOST"),
+		POSTGRESQL_PORT:           viper.GetInt("POSTGRESQL_PORT"),
+		POSTGRESQL_USER:           viper.GetString("POSTGRESQL_USER"),
+		POSTGRESQL_PASSWORD:       viper.GetString("POSTGRESQL_PASSWORD"),
+		POSTGRESQL_DB:             viper.GetString("POSTGRESQL_DB"),
+		AUTH_GRPC_SERVICE:         viper.GetString("AUTH_GRPC_SERVICE"),
+		AUTHOR_GRPC_SERVICE:       viper.GetString("AUTHOR_GRPC_SERVICE"),
+		BOOK_GRPC_SERVICE:         viper.GetString("BOOK_GRPC_SERVICE"),
+
+		SERVICE_NAME:   viper.GetString("SERVICE_NAME"),
+		SERVICE_SECRET: viper.GetString("SERVICE_SECRET"),
+
+		GRPC_TLS_CERT_FILE: viper.GetString("GRPC_TLS_CERT_FILE"),
+		GRPC_TLS_KEY_FILE:  viper.GetString("GRPC_TLS_KEY_FILE"),
+		GRPC_TLS_CA_FILE:   viper.GetString("GRPC_TLS_CA_FILE"),
+
+		EVENT_BROKER:                 viper.GetString("EVENT_BROKER"),
+		EVENT_BROKER_POSTGRESQL_DB:   viper.GetString("EVENT_BROKER_POSTGRESQL_DB"),
+		EVENT_RELAY_INTERVAL_SECONDS: viper.GetInt("EVENT_RELAY_INTERVAL_SECONDS"),
+		EVENT_RELAY_BATCH_SIZE:       viper.GetInt("EVENT_RELAY_BATCH_SIZE"),
 	}
 }
 
@@ -47,5 +79,14 @@ func InitEnv(filepath string) {
 		logger.Warningf("error loading environment variables from %s: %w", filepath, err)
 	}
 	viper.AutomaticEnv()
+	viper.SetDefault("JWKS_CACHE_TTL_SECONDS", 300)
+	viper.SetDefault("API_KEY_CACHE_TTL_SECONDS", 60)
+	viper.SetDefault("JWT_ISSUER", "auth_service")
+	viper.SetDefault("JWT_AUDIENCE", "library_app")
+	viper.SetDefault("SERVICE_NAME", "category_service")
+	viper.SetDefault("EVENT_BROKER", "postgres")
+	viper.SetDefault("EVENT_BROKER_POSTGRESQL_DB", "postgres")
+	viper.SetDefault("EVENT_RELAY_INTERVAL_SECONDS", 2)
+	viper.SetDefault("EVENT_RELAY_BATCH_SIZE", 100)
 	envInitiator()
 }
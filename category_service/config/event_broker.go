@@ -0,0 +1,46 @@
+package config
+
+import (
+	event_util "category_service/utils/event"
+	"context"
+	"time"
+)
+
+// the connection to the event broker is retried with a delay doubling up to
+// this long
+const eventBrokerMaxRetryDelay = time.Minute
+
+// NewEventBroker picks where the outbox events are published from
+// EVENT_BROKER, memory only reaches consumers in the same process. the
+// postgres broker is retried until it connects or ctx is done, the outbox
+// keeps the events meanwhile. nil when the events stay in the outbox.
+func NewEventBroker(ctx context.Context) event_util.Broker {
+	switch Envs.EVENT_BROKER {
+	case "postgres":
+		logger.Debugf("connecting to event broker database: %s", Envs.EVENT_BROKER_POSTGRESQL_DB)
+		retryDelay := time.Second
+		for {
+			broker, err := event_util.NewPostgresBroker(ctx, postgresqlDSN(Envs.EVENT_BROKER_POSTGRESQL_DB))
+			if err == nil {
+				return broker
+			}
+			logger.Errorf("failed to connect to the event broker, retrying in %v: %v", retryDelay, err)
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(retryDelay):
+			}
+			retryDelay *= 2
+			if retryDelay > eventBrokerMaxRetryDelay {
+				retryDelay = eventBrokerMaxRetryDelay
+			}
+		}
+	case "memory":
+		return event_util.NewMemoryBroker()
+	case "none":
+		return nil
+	}
+	logger.Fatalf("invalid EVENT_BROKER: %s", Envs.EVENT_BROKER)
+	return nil
+}
@@ -1,20 +1,27 @@
 package config
 
 import (
+	auth_grpc "category_service/interface/grpc/genproto/auth"
 	book_grpc "category_service/interface/grpc/genproto/book"
+	grpc_interceptor "category_service/interface/grpc/interceptor"
+	"context"
+	"time"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
-// func NewAuthGrpcServiceClient() auth_pb.AuthServiceClient {
-// 	conn, err := grpc.NewClient(Envs.AUTH_GRPC_SERVICE, grpc.WithTransportCredentials(insecure.NewCredentials()))
-// 	if err != nil {
-// 		logger.Fatalf("Failed to connect to auth grpc service: %v", err)
-// 	}
-// 	authServiceClient := auth_pb.NewAuthServiceClient(conn)
-// 	return authServiceClient
-// }
+func NewAuthGrpcServiceClient(tokenSource *grpc_interceptor.ServiceTokenSource) auth_grpc.AuthServiceClient {
+	conn, err := grpc.NewClient(
+		Envs.AUTH_GRPC_SERVICE,
+		grpc.WithTransportCredentials(grpcClientCredentials()),
+		grpc.WithUnaryInterceptor(grpc_interceptor.IdentityUnaryClientInterceptor(tokenSource, auth_grpc.AuthService_GetJWKS_FullMethodName)),
+	)
+	if err != nil {
+		logger.Fatalf("Failed to connect to auth grpc service: %v", err)
+	}
+	authServiceClient := auth_grpc.NewAuthServiceClient(conn)
+	return authServiceClient
+}
 
 // func NewAuthorGrpcServiceClient() author_grpc.AuthorServiceClient {
 // 	conn, err := grpc.NewClient(Envs.AUTHOR_GRPC_SERVCICE, grpc.WithTransportCredentials(insecure.NewCredentials()))
@@ -25,11 +32,41 @@ import (
 // 	return authServiceClient
 // }
 
-func NewBookGrpcServiceClient() book_grpc.BookServiceClient {
-	conn, err := grpc.NewClient(Envs.AUTHOR_GRPC_SERVICE, grpc.WithTransportCredentials(insecure.NewCredentials()))
+func NewBookGrpcServiceClient(tokenSource *grpc_interceptor.ServiceTokenSource) book_grpc.BookServiceClient {
+	conn, err := grpc.NewClient(
+		Envs.BOOK_GRPC_SERVICE,
+		grpc.WithTransportCredentials(grpcClientCredentials()),
+		grpc.WithUnaryInterceptor(grpc_interceptor.IdentityUnaryClientInterceptor(tokenSource)),
+	)
+	if err != nil {
+		logger.Fatalf("Failed to connect to book grpc service: %v", err)
+	}
+	bookServiceClient := book_grpc.NewBookServiceClient(conn)
+	return bookServiceClient
+}
+
+// NewServiceTokenSource gets the service token of this service from
+// auth_service. it is nil when SERVICE_SECRET is empty, the service is then
+// identified by its mtls certificate.
+func NewServiceTokenSource() *grpc_interceptor.ServiceTokenSource {
+	if Envs.SERVICE_SECRET == "" {
+		return nil
+	}
+
+	conn, err := grpc.NewClient(Envs.AUTH_GRPC_SERVICE, grpc.WithTransportCredentials(grpcClientCredentials()))
 	if err != nil {
 		logger.Fatalf("Failed to connect to auth grpc service: %v", err)
 	}
-	authServiceClient := book_grpc.NewBookServiceClient(conn)
-	return authServiceClient
+	authServiceClient := auth_grpc.NewAuthServiceClient(conn)
+
+	return grpc_interceptor.NewServiceTokenSource(func(ctx context.Context) (string, time.Time, error) {
+		resp, err := authServiceClient.IssueServiceToken(ctx, &auth_grpc.IssueServiceTokenRequest{
+			ServiceName:   Envs.SERVICE_NAME,
+			ServiceSecret: Envs.SERVICE_SECRET,
+		})
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		return resp.Token, time.Unix(resp.ExpiredAt, 0), nil
+	})
 }
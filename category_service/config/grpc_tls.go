@@ -0,0 +1,69 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// NewGrpcServerOptions serves tls when a certificate is configured. client
+// certificates are verified against GRPC_TLS_CA_FILE when sent, services
+// without one authenticate with a service token instead.
+func NewGrpcServerOptions() []grpc.ServerOption {
+	if Envs.GRPC_TLS_CERT_FILE == "" || Envs.GRPC_TLS_KEY_FILE == "" {
+		return []grpc.ServerOption{}
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{loadGrpcTLSCertificate()},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if Envs.GRPC_TLS_CA_FILE != "" {
+		tlsConfig.ClientCAs = loadGrpcTLSCertPool()
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsConfig))}
+}
+
+// grpcClientCredentials dials with tls when GRPC_TLS_CA_FILE is set, and
+// presents the certificate of this service when one is configured.
+func grpcClientCredentials() credentials.TransportCredentials {
+	if Envs.GRPC_TLS_CA_FILE == "" {
+		return insecure.NewCredentials()
+	}
+
+	tlsConfig := &tls.Config{
+		RootCAs:    loadGrpcTLSCertPool(),
+		MinVersion: tls.VersionTLS12,
+	}
+	if Envs.GRPC_TLS_CERT_FILE != "" && Envs.GRPC_TLS_KEY_FILE != "" {
+		tlsConfig.Certificates = []tls.Certificate{loadGrpcTLSCertificate()}
+	}
+
+	return credentials.NewTLS(tlsConfig)
+}
+
+func loadGrpcTLSCertificate() tls.Certificate {
+	certificate, err := tls.LoadX509KeyPair(Envs.GRPC_TLS_CERT_FILE, Envs.GRPC_TLS_KEY_FILE)
+	if err != nil {
+		logger.Fatalf("Failed to load grpc tls certificate: %v", err)
+	}
+	return certificate
+}
+
+func loadGrpcTLSCertPool() *x509.CertPool {
+	caPEM, err := os.ReadFile(Envs.GRPC_TLS_CA_FILE)
+	if err != nil {
+		logger.Fatalf("Failed to read grpc tls ca: %v", err)
+	}
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(caPEM) {
+		logger.Fatalf("Failed to parse grpc tls ca: %s", Envs.GRPC_TLS_CA_FILE)
+	}
+	return certPool
+}
@@ -52,6 +52,14 @@ type GetCategoryDetailRespData struct {
 	BookTotal int64     `json:"book_total"`
 }
 
+type GetCategoryByUUIDRespData struct {
+	UUID      string    `json:"uuid"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	CreatedBy string    `json:"created_by"`
+	Name      string    `json:"name"`
+}
+
 type GetCategoryListReq struct {
 	Query     string `form:"query" default:""`
 	QueryBy   string `form:"query_by" default:"any" binding:"oneof=name any"`
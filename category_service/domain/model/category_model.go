@@ -1,6 +1,8 @@
 package model
 
 import (
+	event_util "category_service/utils/event"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -12,6 +14,13 @@ type Category struct {
 	CreatedBy uuid.UUID `gorm:"type:uuid" json:"created_by"`
 }
 
+func (category *Category) EventPayload() event_util.CategoryPayload {
+	return event_util.CategoryPayload{
+		UUID: category.UUID.String(),
+		Name: category.Name,
+	}
+}
+
 func (category *Category) GetQueriableFields() []string {
 	return []string{"name"}
 }
@@ -0,0 +1,48 @@
+package model
+
+import (
+	event_util "category_service/utils/event"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OutboxEvent is a domain event written in the transaction of the change it
+// describes, the relay publishes it to the broker afterwards.
+type OutboxEvent struct {
+	gorm.Model
+	UUID          uuid.UUID  `gorm:"type:uuid;unique;not null" json:"uuid"`
+	Type          string     `gorm:"type:varchar(100);not null" json:"type"`
+	AggregateUUID uuid.UUID  `gorm:"type:uuid;not null" json:"aggregate_uuid"`
+	Payload       string     `gorm:"type:jsonb;not null" json:"payload"`
+	PublishedAt   *time.Time `json:"published_at"` // nil until the broker accepted it
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	LastError     *string    `gorm:"type:text" json:"last_error"`
+}
+
+func NewOutboxEvent(eventType string, aggregateUUID uuid.UUID, payload interface{}) (*OutboxEvent, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &OutboxEvent{
+		UUID:          uuid.New(),
+		Type:          eventType,
+		AggregateUUID: aggregateUUID,
+		Payload:       string(raw),
+	}, nil
+}
+
+// ToEvent returns the event as published by the given service.
+func (e *OutboxEvent) ToEvent(source string) event_util.Event {
+	return event_util.Event{
+		UUID:          e.UUID,
+		Type:          e.Type,
+		Source:        source,
+		AggregateUUID: e.AggregateUUID,
+		Payload:       json.RawMessage(e.Payload),
+		OccurredAt:    e.CreatedAt,
+	}
+}
@@ -3,8 +3,12 @@ package handler
 import (
 	category_grpc "category_service/interface/grpc/genproto/category"
 	ucase "category_service/usecase"
+	error_utils "category_service/utils/error"
+	"context"
 
 	"github.com/op/go-logging"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type CategoryServiceHandler struct {
@@ -18,3 +22,28 @@ func NewCategoryServiceHandler(categoryUcase ucase.ICategoryUcase) *CategoryServ
 	handler := &CategoryServiceHandler{categoryUcase: categoryUcase}
 	return handler
 }
+
+func (r *CategoryServiceHandler) GetCategoryByUUID(
+	ctx context.Context,
+	in *category_grpc.GetCategoryByUUIDReq,
+) (*category_grpc.GetCategoryByUUIDResp, error) {
+	if in.Uuid == "" {
+		logger.Errorf("invalid request: missing uuid")
+		return nil, status.Error(codes.InvalidArgument, "uuid is required")
+	}
+
+	raw, err := r.categoryUcase.GetCategoryByUUID(ctx, in.Uuid)
+	if err != nil {
+		customErr, ok := err.(*error_utils.CustomErr)
+		if ok {
+			return nil, status.Errorf(customErr.GrpcCode, customErr.Error())
+		}
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	return &category_grpc.GetCategoryByUUIDResp{
+		Uuid:      raw.UUID,
+		Name:      raw.Name,
+		CreatedBy: raw.CreatedBy,
+	}, nil
+}
@@ -0,0 +1,73 @@
+package grpc
+
+import (
+	"category_service/domain/dto"
+	interface_pkg "category_service/interface"
+	category_grpc "category_service/interface/grpc/genproto/category"
+	grpc_interceptor "category_service/interface/grpc/interceptor"
+	ucase "category_service/usecase"
+	error_utils "category_service/utils/error"
+	"category_service/utils/jwt/jwttest"
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeCategoryUcase serves the categories of byUUID, calling any method not
+// listed here panics.
+type fakeCategoryUcase struct {
+	ucase.ICategoryUcase
+	byUUID map[string]dto.GetCategoryByUUIDRespData
+}
+
+func (u *fakeCategoryUcase) GetCategoryByUUID(ctx context.Context, categoryUUID string) (*dto.GetCategoryByUUIDRespData, error) {
+	category, ok := u.byUUID[categoryUUID]
+	if !ok {
+		return nil, &error_utils.CustomErr{HttpCode: 404, GrpcCode: codes.NotFound, Message: "category not found"}
+	}
+	return &category, nil
+}
+
+// newTestConn serves categoryUcase on an in-memory listener. calls are signed
+// with a service token of book_service unless anonymous is set.
+func newTestConn(t *testing.T, categoryUcase ucase.ICategoryUcase, anonymous bool) *grpc.ClientConn {
+	signer := jwttest.NewSigner(t)
+
+	lis := bufconn.Listen(1024 * 1024)
+	grpcServer := NewServer(interface_pkg.CommonDependency{
+		CategoryUcase: categoryUcase,
+		KeySet:        signer.KeySet,
+		ClaimsConfig:  jwttest.ClaimsConfig,
+	})
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+
+	var tokenSource *grpc_interceptor.ServiceTokenSource
+	if !anonymous {
+		tokenSource = grpc_interceptor.NewServiceTokenSource(signer.ServiceToken("book_service"))
+	}
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(grpc_interceptor.IdentityUnaryClientInterceptor(tokenSource)),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial bufnet: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func newTestClient(t *testing.T, categoryUcase ucase.ICategoryUcase, anonymous bool) category_grpc.CategoryServiceClient {
+	return category_grpc.NewCategoryServiceClient(newTestConn(t, categoryUcase, anonymous))
+}
@@ -0,0 +1,169 @@
+package grpc_interceptor
+
+import (
+	"category_service/domain/dto"
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// metadata keys carrying the identity of the caller between services
+const (
+	MetadataAuthorization = "authorization"
+	MetadataRequestID     = "x-request-id"
+	MetadataUserUUID      = "x-user-uuid"
+	MetadataUserUsername  = "x-user-username"
+	MetadataUserRole      = "x-user-role"
+)
+
+// request ids longer than this are replaced, they end up in every log line
+const maxRequestIDLength = 64
+
+// Caller is who called the rpc, available to handlers through
+// CallerFromContext.
+type Caller struct {
+	// Service is set when the call was made with a service credential, the
+	// name of the calling service.
+	Service string
+	// User is the end user the call is made for, nil for calls made by a
+	// service on its own.
+	User      *dto.CurrentUser
+	RequestID string
+}
+
+type callerKey struct{}
+
+func ContextWithCaller(ctx context.Context, caller *Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+func CallerFromContext(ctx context.Context) (*Caller, bool) {
+	caller, ok := ctx.Value(callerKey{}).(*Caller)
+	return caller, ok
+}
+
+type ServerAuthConfig struct {
+	// VerifyServiceToken returns the name of the service the token was
+	// issued to.
+	VerifyServiceToken func(ctx context.Context, token string) (string, error)
+	// VerifyUserToken checks an access token forwarded from an end user.
+	VerifyUserToken func(ctx context.Context, token string) (*dto.CurrentUser, error)
+	// PublicMethods are served without any credential.
+	PublicMethods []string
+	// ServiceOnlyMethods refuse forwarded user tokens.
+	ServiceOnlyMethods []string
+}
+
+// AuthUnaryInterceptor requires a service credential, either a client
+// certificate verified by mtls or a service token, or else a forwarded access
+// token of an end user. with a service credential the x-user-* metadata is
+// trusted as the user the call is made for.
+func AuthUnaryInterceptor(authConfig ServerAuthConfig) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		caller := &Caller{RequestID: requestIDFromMetadata(md)}
+
+		if containsMethod(authConfig.PublicMethods, info.FullMethod) {
+			return handler(ContextWithCaller(ctx, caller), req)
+		}
+
+		service := verifiedPeerService(ctx)
+		token := bearerTokenFromMetadata(md)
+		if service == "" && token != "" && authConfig.VerifyServiceToken != nil {
+			service, _ = authConfig.VerifyServiceToken(ctx, token)
+		}
+
+		if service != "" {
+			caller.Service = service
+			caller.User = propagatedUser(md)
+		} else {
+			if token == "" || authConfig.VerifyUserToken == nil {
+				return nil, status.Error(codes.Unauthenticated, "missing credentials")
+			}
+			if containsMethod(authConfig.ServiceOnlyMethods, info.FullMethod) {
+				return nil, status.Error(codes.PermissionDenied, "only available to services")
+			}
+
+			user, err := authConfig.VerifyUserToken(ctx, token)
+			if err != nil {
+				return nil, status.Error(codes.Unauthenticated, "invalid token")
+			}
+			user.AccessToken = token
+			caller.User = user
+		}
+
+		logger.Debugf("%s called by service %q, request id: %s", info.FullMethod, caller.Service, caller.RequestID)
+		return handler(ContextWithCaller(ctx, caller), req)
+	}
+}
+
+// verifiedPeerService is the common name of the client certificate, empty
+// when the connection is not mtls.
+func verifiedPeerService(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	return tlsInfo.State.VerifiedChains[0][0].Subject.CommonName
+}
+
+func propagatedUser(md metadata.MD) *dto.CurrentUser {
+	userUUID := firstMetadataValue(md, MetadataUserUUID)
+	if userUUID == "" {
+		return nil
+	}
+	return &dto.CurrentUser{
+		UUID:     userUUID,
+		Username: firstMetadataValue(md, MetadataUserUsername),
+		Role:     firstMetadataValue(md, MetadataUserRole),
+	}
+}
+
+func requestIDFromMetadata(md metadata.MD) string {
+	requestID := firstMetadataValue(md, MetadataRequestID)
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return uuid.New().String()
+	}
+	return requestID
+}
+
+func bearerTokenFromMetadata(md metadata.MD) string {
+	authorization := firstMetadataValue(md, MetadataAuthorization)
+	if !strings.HasPrefix(authorization, "Bearer ") {
+		return ""
+	}
+	return strings.TrimPrefix(authorization, "Bearer ")
+}
+
+func firstMetadataValue(md metadata.MD, key string) string {
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func containsMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
@@ -2,7 +2,13 @@ package grpc
 
 import (
 	"category_service/config"
+	"category_service/domain/dto"
 	interface_pkg "category_service/interface"
+	category_grpc "category_service/interface/grpc/genproto/category"
+	"category_service/interface/grpc/handler"
+	grpc_interceptor "category_service/interface/grpc/interceptor"
+	jwt_util "category_service/utils/jwt"
+	"context"
 	"fmt"
 	"log"
 	"net"
@@ -13,6 +19,35 @@ import (
 
 var logger = logging.MustGetLogger("main")
 
+// NewServer creates the grpc server with the service handler registered.
+// every rpc requires a service credential or a forwarded access token.
+func NewServer(commonDependencies interface_pkg.CommonDependency, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts, grpc.ChainUnaryInterceptor(
+		grpc_interceptor.RecoveryUnaryInterceptor(),
+		grpc_interceptor.AuthUnaryInterceptor(serverAuthConfig(commonDependencies)),
+	))
+	grpcServer := grpc.NewServer(opts...)
+
+	// register service handler
+	categoryServiceHandler := handler.NewCategoryServiceHandler(commonDependencies.CategoryUcase)
+	category_grpc.RegisterCategoryServiceServer(grpcServer, categoryServiceHandler)
+
+	return grpcServer
+}
+
+// serverAuthConfig verifies service tokens and access tokens locally with the
+// key set of auth_service.
+func serverAuthConfig(commonDependencies interface_pkg.CommonDependency) grpc_interceptor.ServerAuthConfig {
+	return grpc_interceptor.ServerAuthConfig{
+		VerifyServiceToken: func(ctx context.Context, token string) (string, error) {
+			return jwt_util.ValidateServiceToken(token, commonDependencies.KeySet, commonDependencies.ClaimsConfig.Issuer)
+		},
+		VerifyUserToken: func(ctx context.Context, token string) (*dto.CurrentUser, error) {
+			return jwt_util.ValidateJWT(token, commonDependencies.KeySet, commonDependencies.ClaimsConfig)
+		},
+	}
+}
+
 func SetupServer(commonDependencies interface_pkg.CommonDependency) {
 	// setup listener
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%v", config.Envs.GRPC_PORT))
@@ -21,9 +56,7 @@ func SetupServer(commonDependencies interface_pkg.CommonDependency) {
 	}
 
 	// new grpc server
-	grpcServer := grpc.NewServer()
-
-	// register service handler
+	grpcServer := NewServer(commonDependencies, config.NewGrpcServerOptions()...)
 
 	// Start the server
 	fmt.Printf("Starting gRPC server on port :%v...", config.Envs.GRPC_PORT)
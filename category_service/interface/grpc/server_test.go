@@ -0,0 +1,89 @@
+package grpc
+
+import (
+	"category_service/domain/dto"
+	category_grpc "category_service/interface/grpc/genproto/category"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// TestServer_ServesEveryRPC calls every rpc declared in category.proto with a
+// service token. calls that reach a handler may fail, but never with
+// Unimplemented.
+func TestServer_ServesEveryRPC(t *testing.T) {
+	conn := newTestConn(t, &fakeCategoryUcase{}, false)
+
+	services := category_grpc.File_category_proto.Services()
+	if services.Len() == 0 {
+		t.Fatal("no services declared in category.proto")
+	}
+	for i := 0; i < services.Len(); i++ {
+		service := services.Get(i)
+		methods := service.Methods()
+		for j := 0; j < methods.Len(); j++ {
+			method := methods.Get(j)
+			fullMethod := fmt.Sprintf("/%s/%s", service.FullName(), method.Name())
+			t.Run(fullMethod, func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+
+				req := dynamicpb.NewMessage(method.Input())
+				resp := dynamicpb.NewMessage(method.Output())
+				err := conn.Invoke(ctx, fullMethod, req, resp)
+				if status.Code(err) == codes.Unimplemented {
+					t.Errorf("%s is not served: %v", fullMethod, err)
+				}
+				if status.Code(err) == codes.Unauthenticated {
+					t.Errorf("%s rejected the service token: %v", fullMethod, err)
+				}
+			})
+		}
+	}
+}
+
+func TestServer_GetCategoryByUUID(t *testing.T) {
+	category := dto.GetCategoryByUUIDRespData{
+		UUID:      uuid.New().String(),
+		CreatedBy: uuid.New().String(),
+		Name:      "fiction",
+	}
+	client := newTestClient(t, &fakeCategoryUcase{byUUID: map[string]dto.GetCategoryByUUIDRespData{
+		category.UUID: category,
+	}}, false)
+	ctx := context.Background()
+
+	resp, err := client.GetCategoryByUUID(ctx, &category_grpc.GetCategoryByUUIDReq{Uuid: category.UUID})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp.Uuid != category.UUID || resp.Name != "fiction" || resp.CreatedBy != category.CreatedBy {
+		t.Errorf("unexpected response: %v", resp)
+	}
+
+	// the ucase error code is kept
+	_, err = client.GetCategoryByUUID(ctx, &category_grpc.GetCategoryByUUIDReq{Uuid: uuid.New().String()})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+
+	_, err = client.GetCategoryByUUID(ctx, &category_grpc.GetCategoryByUUIDReq{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument without uuid, got %v", err)
+	}
+}
+
+func TestServer_RequiresCredentials(t *testing.T) {
+	client := newTestClient(t, &fakeCategoryUcase{}, true)
+
+	_, err := client.GetCategoryByUUID(context.Background(), &category_grpc.GetCategoryByUUIDReq{Uuid: uuid.New().String()})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated, got %v", err)
+	}
+}
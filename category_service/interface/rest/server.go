@@ -20,7 +20,14 @@ import (
 var logger = logging.MustGetLogger("main")
 
 func SetupServer(commonDependencies interface_pkg.CommonDependency) {
+	router := NewRouter(commonDependencies)
+	router.Run(fmt.Sprintf("%s:%d", config.Envs.HOST, config.Envs.PORT))
+}
+
+// NewRouter registers every route on a new gin engine without running it.
+func NewRouter(commonDependencies interface_pkg.CommonDependency) *gin.Engine {
 	router := gin.Default()
+	router.Use(rest_middleware.RequestIDMiddleware())
 
 	respWriter := http_response.NewHttpResponseWriter()
 
@@ -31,8 +38,7 @@ func SetupServer(commonDependencies interface_pkg.CommonDependency) {
 	)
 
 	// middlewares
-	authMiddleware := rest_middleware.AuthMiddleware(respWriter)
-	authMiddlewareAdminOnly := rest_middleware.AuthAdminOnlyMiddleware(respWriter)
+	authMiddleware := rest_middleware.AuthMiddleware(respWriter, commonDependencies.KeySet, commonDependencies.ClaimsConfig, commonDependencies.APIKeyCache)
 
 	// register routes
 	router.GET("/ping", func(c *gin.Context) {
@@ -49,15 +55,11 @@ func SetupServer(commonDependencies interface_pkg.CommonDependency) {
 		// /categories
 		bookRouter := secureRouter.Group("/categories")
 		{
-			bookRouter.PATCH("/:category_uuid", categoryHandler.PatchCategory)   // owner only
-			bookRouter.DELETE("/:category_uuid", categoryHandler.DeleteCategory) // owner only
+			bookRouter.PATCH("/:category_uuid", categoryHandler.PatchCategory)   // owner or category:update
+			bookRouter.DELETE("/:category_uuid", categoryHandler.DeleteCategory) // owner or category:delete
 			bookRouter.GET("/:category_uuid", categoryHandler.GetCategoryDetail)
 			bookRouter.GET("", categoryHandler.GetCategoryList)
-
-			bookRouterAdminOnly := bookRouter.Group("", authMiddlewareAdminOnly)
-			{
-				bookRouterAdminOnly.POST("", categoryHandler.CreateBook)
-			}
+			bookRouter.POST("", rest_middleware.RequirePermission(respWriter, dto.PermissionCategoryCreate), categoryHandler.CreateBook)
 		}
 	}
 
@@ -67,5 +69,5 @@ func SetupServer(commonDependencies interface_pkg.CommonDependency) {
 		ctx.Redirect(302, "/swagger/index.html")
 	})
 
-	router.Run(fmt.Sprintf("%s:%d", config.Envs.HOST, config.Envs.PORT))
+	return router
 }
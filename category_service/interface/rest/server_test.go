@@ -0,0 +1,90 @@
+package rest
+
+import (
+	"bytes"
+	"category_service/domain/dto"
+	interface_pkg "category_service/interface"
+	ucase "category_service/usecase"
+	"category_service/utils/jwt/jwttest"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeCategoryUcase records the method reached through the router, calling
+// any method not listed here panics.
+type fakeCategoryUcase struct {
+	ucase.ICategoryUcase
+	called string
+}
+
+func (u *fakeCategoryUcase) Create(ctx context.Context, currentUser dto.CurrentUser, payload dto.CreateCategoryReq) (*dto.CreateCategoryRespData, error) {
+	u.called = "Create"
+	return &dto.CreateCategoryRespData{}, nil
+}
+
+// TestRouter_Permissions checks POST /categories asks for category:create, the
+// owner checks of the other routes live in the ucase. token validation itself
+// is covered by book_service.
+func TestRouter_Permissions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	signer := jwttest.NewSigner(t)
+	signToken := func(permissions ...string) string {
+		return signer.Sign(t, jwttest.UserClaims("admin", permissions...))
+	}
+
+	routes := []struct {
+		method     string
+		path       string
+		body       string
+		permission string
+		other      string // a permission that does not grant the route
+		wantCalled string
+	}{
+		{http.MethodPost, "/categories", `{"name":"fiction"}`, dto.PermissionCategoryCreate, dto.PermissionCategoryUpdate, "Create"},
+	}
+
+	for _, route := range routes {
+		testCases := []struct {
+			name       string
+			token      string
+			wantStatus int
+		}{
+			{"without permission", signToken(), 403},
+			{"with another permission", signToken(route.other), 403},
+			{"with permission", signToken(route.permission), 200},
+		}
+
+		for _, testCase := range testCases {
+			t.Run(route.method+" "+route.path+" "+testCase.name, func(t *testing.T) {
+				categoryUcase := &fakeCategoryUcase{}
+				router := NewRouter(interface_pkg.CommonDependency{
+					CategoryUcase: categoryUcase,
+					KeySet:        signer.KeySet,
+					ClaimsConfig:  jwttest.ClaimsConfig,
+				})
+
+				req := httptest.NewRequest(route.method, route.path, bytes.NewReader([]byte(route.body)))
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Authorization", "Bearer "+testCase.token)
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+
+				if rec.Code != testCase.wantStatus {
+					t.Errorf("expected status %d, got %d: %s", testCase.wantStatus, rec.Code, rec.Body.String())
+				}
+				wantCalled := ""
+				if testCase.wantStatus == 200 {
+					wantCalled = route.wantCalled
+				}
+				if categoryUcase.called != wantCalled {
+					t.Errorf("expected ucase call %q, got %q", wantCalled, categoryUcase.called)
+				}
+			})
+		}
+	}
+}
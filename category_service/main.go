@@ -2,18 +2,23 @@ package main
 
 import (
 	"category_service/config"
-	"category_service/domain/model"
 	interface_pkg "category_service/interface"
 	"category_service/interface/grpc"
 	"category_service/interface/rest"
+	"category_service/migrations"
 	"category_service/repository"
 	ucase "category_service/usecase"
 	"category_service/utils/helper"
+	jwt_util "category_service/utils/jwt"
+	"category_service/utils/migrator"
+	"context"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/op/go-logging"
+	"gorm.io/gorm"
 )
 
 func init() {
@@ -30,35 +35,51 @@ var logger = logging.MustGetLogger("main")
 // @description JWT Authorization header using the Bearer scheme (add 'Bearer ' prefix).
 func main() {
 	logger.Debugf("Envs: %v", helper.PrettyJson(config.Envs))
-	gormDB := config.NewPostgresqlDB()
-	bookGrpcServiceClient := config.NewBookGrpcServiceClient()
-	// authGrpcServiceClient := config.NewAuthGrpcServiceClient()
-	// authorGrpcServiceClient := config.NewAuthorGrpcServiceClient()
-
-	// migrations
-	err := gormDB.AutoMigrate(
-		&model.Category{},
-	)
-	if err != nil {
-		logger.Fatalf("failed to migrate database: %v", err)
+	// the database itself is only created when migrating up
+	for _, arg := range os.Args[1:] {
+		if arg == "--migrate=up" {
+			err := config.EnsurePostgresqlDB()
+			if err != nil {
+				logger.Fatalf("failed to ensure database: %v", err)
+			}
+		}
 	}
+	gormDB := config.NewPostgresqlDB()
+	serviceTokenSource := config.NewServiceTokenSource()
+	bookGrpcServiceClient := config.NewBookGrpcServiceClient(serviceTokenSource)
+	authGrpcServiceClient := config.NewAuthGrpcServiceClient(serviceTokenSource)
+	// authorGrpcServiceClient := config.NewAuthorGrpcServiceClient(serviceTokenSource)
 
 	// repositories
 	categoryRepo := repository.NewCategoryRepo(gormDB)
+	outboxRepo := repository.NewOutboxRepo(gormDB)
+
+	// access token verification
+	keySet := config.NewJWKS(authGrpcServiceClient)
+	apiKeyCache := config.NewAPIKeyCache(authGrpcServiceClient)
+	claimsConfig := jwt_util.ClaimsConfig{
+		Issuer:   config.Envs.JWT_ISSUER,
+		Audience: config.Envs.JWT_AUDIENCE,
+	}
 
 	// ucases
 	categoryUcase := ucase.NewCategoryUcase(categoryRepo, bookGrpcServiceClient)
 	dependencies := interface_pkg.CommonDependency{
 		CategoryUcase: categoryUcase,
+
+		KeySet:       keySet,
+		APIKeyCache:  apiKeyCache,
+		ClaimsConfig: claimsConfig,
 	}
 
 	args := os.Args
 	if len(args) == 1 { // run as a rest server
+		startOutboxRelay(outboxRepo)
 		logger.Info("starting rest server...")
 		rest.SetupServer(dependencies)
 	} else if len(args) > 1 {
 		validArgVariables := []string{"server"}
-		validPreRunArgVariables := []string{}
+		validPreRunArgVariables := []string{"migrate"}
 
 		// validate args
 		variables := validArgVariables
@@ -109,15 +130,68 @@ func main() {
 
 				switch value {
 				case "rest":
+					startOutboxRelay(outboxRepo)
 					logger.Info("starting rest server...")
 					rest.SetupServer(dependencies)
 				case "grpc":
+					startOutboxRelay(outboxRepo)
 					logger.Info("starting grpc server...")
 					grpc.SetupServer(dependencies)
 				default:
 					logger.Fatalf("invalid argument: %s", arg)
 				}
+			} else if strings.Contains(arg, fmt.Sprintf("--%s=", "migrate")) {
+				value := strings.Split(arg, "=")[1]
+				runMigration(gormDB, value)
+			}
+		}
+	}
+}
+
+// startOutboxRelay publishes the outbox events in the background while the
+// server runs. the relays of several instances take turns through a lock.
+func startOutboxRelay(outboxRepo repository.IOutboxRepo) {
+	go func() {
+		// blocks until the broker is reachable
+		broker := config.NewEventBroker(context.Background())
+		if broker == nil {
+			logger.Warning("no event broker, domain events stay in the outbox")
+			return
+		}
+		outboxUcase := ucase.NewOutboxUcase(outboxRepo, broker)
+		outboxUcase.RunRelay(context.Background())
+	}()
+}
+
+// runMigration applies, rolls back or lists the versioned sql migrations in
+// ./migrations.
+func runMigration(gormDB *gorm.DB, value string) {
+	switch value {
+	case "up":
+		logger.Info("applying migrations...")
+		err := migrator.Up(gormDB, migrations.FS)
+		if err != nil {
+			logger.Fatalf("failed to apply migrations: %v", err)
+		}
+	case "down":
+		logger.Info("rolling back latest migration...")
+		err := migrator.Down(gormDB, migrations.FS)
+		if err != nil {
+			logger.Fatalf("failed to roll back migration: %v", err)
+		}
+	case "status":
+		statuses, err := migrator.Status(gormDB, migrations.FS)
+		if err != nil {
+			logger.Fatalf("failed to get migration status: %v", err)
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
 			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, appliedAt)
 		}
+	default:
+		logger.Fatalf("invalid migrate argument: %s", value)
 	}
 }
@@ -0,0 +1,12 @@
+-- baseline schema, previously created by gorm AutoMigrate. IF NOT EXISTS keeps
+-- it safe to apply on databases that were already auto migrated.
+CREATE TABLE IF NOT EXISTS categories (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    uuid uuid NOT NULL CONSTRAINT uni_categories_uuid UNIQUE,
+    name varchar(100) NOT NULL,
+    created_by uuid
+);
+CREATE INDEX IF NOT EXISTS idx_categories_deleted_at ON categories (deleted_at);
@@ -3,6 +3,7 @@ package repository
 import (
 	"category_service/domain/dto"
 	"category_service/domain/model"
+	event_util "category_service/utils/event"
 	"errors"
 	"fmt"
 	"strings"
@@ -18,7 +19,7 @@ type ICategoryRepo interface {
 	Create(category *model.Category) error
 	GetByUUID(uuid string) (*model.Category, error)
 	Update(category *model.Category) error
-	Delete(id string) error
+	Delete(uuid string) error
 	GetList(
 		params dto.CategoryRepo_GetListParams,
 	) ([]model.Category, error)
@@ -34,7 +35,13 @@ func NewCategoryRepo(db *gorm.DB) ICategoryRepo {
 }
 
 func (repo *CategoryRepo) Create(category *model.Category) error {
-	err := repo.db.Create(category).Error
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(category).Error
+		if err != nil {
+			return err
+		}
+		return createOutboxEvent(tx, event_util.TypeCategoryCreated, category.UUID, category.EventPayload())
+	})
 	if err != nil {
 		return errors.New("failed to create: " + err.Error())
 	}
@@ -53,12 +60,29 @@ func (repo *CategoryRepo) GetByUUID(uuid string) (*model.Category, error) {
 }
 
 func (repo *CategoryRepo) Update(category *model.Category) error {
-	err := repo.db.Save(category).Error
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Save(category).Error
+		if err != nil {
+			return err
+		}
+		return createOutboxEvent(tx, event_util.TypeCategoryUpdated, category.UUID, category.EventPayload())
+	})
 	return err
 }
 
-func (repo *CategoryRepo) Delete(id string) error {
-	err := repo.db.Delete(&model.Category{}, "id = ?", id).Error
+func (repo *CategoryRepo) Delete(uuid string) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var category model.Category
+		err := tx.First(&category, "uuid = ?", uuid).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Delete(&category).Error
+		if err != nil {
+			return err
+		}
+		return createOutboxEvent(tx, event_util.TypeCategoryDeleted, category.UUID, category.EventPayload())
+	})
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return errors.New("not found")
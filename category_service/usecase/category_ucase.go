@@ -11,6 +11,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/op/go-logging"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var logger = logging.MustGetLogger("main")
@@ -41,6 +42,10 @@ type ICategoryUcase interface {
 		ctx context.Context,
 		params dto.GetCategoryListReq,
 	) (*dto.GetListCategoryRespData, error)
+	GetCategoryByUUID(
+		ctx context.Context,
+		categoryUUID string,
+	) (*dto.GetCategoryByUUIDRespData, error)
 }
 
 func NewCategoryUcase(
@@ -136,8 +141,8 @@ func (ucase *CategoryUcase) PatchCategory(
 		}
 	}
 
-	// validate user
-	if category.CreatedBy.String() != currentUser.UUID {
+	// validate user, category:update allows patching any category
+	if category.CreatedBy.String() != currentUser.UUID && !currentUser.HasPermission(dto.PermissionCategoryUpdate) {
 		logger.Errorf("err: %v", err)
 		return nil, &error_utils.CustomErr{
 			HttpCode: 403,
@@ -199,8 +204,8 @@ func (ucase *CategoryUcase) DeleteCategory(
 		}
 	}
 
-	// validate user
-	if category.CreatedBy.String() != currentUser.UUID {
+	// validate user, category:delete allows deleting any category
+	if category.CreatedBy.String() != currentUser.UUID && !currentUser.HasPermission(dto.PermissionCategoryDelete) {
 		logger.Errorf("err: %v", err)
 		return nil, &error_utils.CustomErr{
 			HttpCode: 403,
@@ -257,14 +262,29 @@ func (ucase *CategoryUcase) GetCategoryDetail(
 		}
 	}
 
-	// TODO: get book total by category uuid through book service
+	// get book total by category uuid through book service, like the list
+	// the category is still served with 0 books when it is unreachable
+	var bookTotal int64
+	resp, err := ucase.bookGrpcServiceClient.GetBookTotalByCategoryUUID(
+		ctx,
+		&book_grpc.GetBookTotalByCategoryUUIDReq{
+			CategoryUuid: category.UUID.String(),
+		},
+	)
+	code := status.Code(err)
+	if code != codes.OK || err != nil {
+		logger.Warningf("failed to get book total by category uuid: %v; set to 0", err)
+	} else if resp != nil {
+		bookTotal = resp.BookTotal
+	}
 
 	return &dto.GetCategoryDetailRespData{
 		UUID:      category.UUID.String(),
-		CreatedBy: category.CreatedAt.String(),
+		CreatedBy: category.CreatedBy.String(),
 		Name:      category.Name,
 		CreatedAt: category.CreatedAt,
 		UpdatedAt: category.UpdatedAt,
+		BookTotal: bookTotal,
 	}, nil
 }
 
@@ -330,19 +350,98 @@ func (ucase *CategoryUcase) GetListCategory(
 		}
 	}
 
-	// TODO: get bulk book total by category uuids through book service
+	// get bulk book total by category uuids through book service
+	bookTotalMapByCategoryUUID := make(map[string]int64)
+	if len(categories) > 0 {
+		categoryUUIDs := []string{}
+		for _, category := range categories {
+			categoryUUIDs = append(categoryUUIDs, category.UUID.String())
+		}
+
+		resp, err := ucase.bookGrpcServiceClient.BulkGetBookTotalByCategoryUUIDs(
+			ctx,
+			&book_grpc.BulkGetBookTotalByCategoryUUIDsReq{
+				CategoryUuids: categoryUUIDs,
+			},
+		)
+		code := status.Code(err)
+		if code != codes.OK || err != nil {
+			logger.Warningf("failed to get book total by category uuids: %v", err)
+		}
+
+		if resp != nil {
+			for _, item := range resp.Data {
+				if item == nil || item.CategoryUuid == "" {
+					logger.Warningf("failed to get book total; category uuid is empty; skip")
+					continue
+				}
+				bookTotalMapByCategoryUUID[item.CategoryUuid] = item.BookTotal
+			}
+		}
+	}
 
 	res := &dto.GetListCategoryRespData{}
 	res.Set(params.Page, params.Limit, count)
 	for _, category := range categories {
+		bookTotal, ok := bookTotalMapByCategoryUUID[category.UUID.String()]
+		if !ok {
+			logger.Warningf("book total not found for category uuid: %s; set to 0", category.UUID.String())
+			bookTotal = 0
+		}
 		res.Data = append(res.Data, dto.GetListCategoryRespDataItem{
 			UUID:      category.UUID.String(),
-			CreatedBy: category.CreatedAt.String(),
+			CreatedBy: category.CreatedBy.String(),
 			Name:      category.Name,
 			CreatedAt: category.CreatedAt,
 			UpdatedAt: category.UpdatedAt,
+			BookTotal: bookTotal,
 		})
 	}
 
 	return res, nil
 }
+
+func (ucase *CategoryUcase) GetCategoryByUUID(
+	ctx context.Context,
+	categoryUUID string,
+) (*dto.GetCategoryByUUIDRespData, error) {
+	// validate input
+	if _, err := uuid.Parse(categoryUUID); err != nil {
+		return nil, &error_utils.CustomErr{
+			HttpCode: 400,
+			GrpcCode: codes.InvalidArgument,
+			Message:  "invalid input",
+			Detail:   "invalid category uuid",
+		}
+	}
+
+	// find category
+	category, err := ucase.categoryRepo.GetByUUID(categoryUUID)
+	if err != nil {
+		if err.Error() == "not found" {
+			logger.Errorf("err: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 404,
+				GrpcCode: codes.NotFound,
+				Message:  "not found",
+				Detail:   err,
+			}
+		} else {
+			logger.Errorf("err: %v", err)
+			return nil, &error_utils.CustomErr{
+				HttpCode: 500,
+				GrpcCode: codes.Internal,
+				Message:  "internal server error",
+				Detail:   err,
+			}
+		}
+	}
+
+	return &dto.GetCategoryByUUIDRespData{
+		UUID:      category.UUID.String(),
+		CreatedBy: category.CreatedBy.String(),
+		Name:      category.Name,
+		CreatedAt: category.CreatedAt,
+		UpdatedAt: category.UpdatedAt,
+	}, nil
+}
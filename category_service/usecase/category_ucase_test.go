@@ -0,0 +1,113 @@
+package ucase
+
+import (
+	"category_service/domain/dto"
+	"category_service/domain/model"
+	book_grpc "category_service/interface/grpc/genproto/book"
+	"category_service/repository"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeCategoryRepo serves categories, calling any method not listed here
+// panics.
+type fakeCategoryRepo struct {
+	repository.ICategoryRepo
+	categories []model.Category
+}
+
+func (repo *fakeCategoryRepo) GetByUUID(uuid string) (*model.Category, error) {
+	for _, category := range repo.categories {
+		if category.UUID.String() == uuid {
+			return &category, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (repo *fakeCategoryRepo) GetList(params dto.CategoryRepo_GetListParams) ([]model.Category, error) {
+	return repo.categories, nil
+}
+
+func (repo *fakeCategoryRepo) CountGetList(params dto.CategoryRepo_GetListParams) (int64, error) {
+	return int64(len(repo.categories)), nil
+}
+
+// fakeBookClient answers the book totals from totals, keyed by category uuid,
+// or fails every call with err.
+type fakeBookClient struct {
+	book_grpc.BookServiceClient
+	totals map[string]int64
+	err    error
+}
+
+func (client *fakeBookClient) GetBookTotalByCategoryUUID(ctx context.Context, in *book_grpc.GetBookTotalByCategoryUUIDReq, opts ...grpc.CallOption) (*book_grpc.GetBookTotalByCategoryUUIDResp, error) {
+	if client.err != nil {
+		return nil, client.err
+	}
+	return &book_grpc.GetBookTotalByCategoryUUIDResp{BookTotal: client.totals[in.CategoryUuid]}, nil
+}
+
+func (client *fakeBookClient) BulkGetBookTotalByCategoryUUIDs(ctx context.Context, in *book_grpc.BulkGetBookTotalByCategoryUUIDsReq, opts ...grpc.CallOption) (*book_grpc.BulkGetBookTotalByCategoryUUIDsResp, error) {
+	if client.err != nil {
+		return nil, client.err
+	}
+	resp := &book_grpc.BulkGetBookTotalByCategoryUUIDsResp{}
+	for _, categoryUUID := range in.CategoryUuids {
+		resp.Data = append(resp.Data, &book_grpc.BulkGetBookTotalByCategoryUUIDsResp_Data{
+			CategoryUuid: categoryUUID,
+			BookTotal:    client.totals[categoryUUID],
+		})
+	}
+	return resp, nil
+}
+
+// TestCategoryUcase_BookTotals checks the detail and the list both serve the
+// categories with 0 books when book_service is unreachable.
+func TestCategoryUcase_BookTotals(t *testing.T) {
+	category := model.Category{UUID: uuid.New(), Name: "fiction", CreatedBy: uuid.New()}
+
+	tests := []struct {
+		name          string
+		bookClient    *fakeBookClient
+		wantBookTotal int64
+	}{
+		{
+			name:          "book_service_reachable",
+			bookClient:    &fakeBookClient{totals: map[string]int64{category.UUID.String(): 3}},
+			wantBookTotal: 3,
+		},
+		{
+			name:          "book_service_unreachable",
+			bookClient:    &fakeBookClient{err: status.Error(codes.Unavailable, "connection refused")},
+			wantBookTotal: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ucase := NewCategoryUcase(&fakeCategoryRepo{categories: []model.Category{category}}, tt.bookClient)
+
+			detail, err := ucase.GetCategoryDetail(context.Background(), category.UUID.String())
+			if err != nil {
+				t.Fatalf("expected no error from the detail, got %v", err)
+			}
+			if detail.UUID != category.UUID.String() || detail.BookTotal != tt.wantBookTotal {
+				t.Errorf("expected category %s with %d books, got %s with %d", category.UUID, tt.wantBookTotal, detail.UUID, detail.BookTotal)
+			}
+
+			list, err := ucase.GetListCategory(context.Background(), dto.GetCategoryListReq{Page: 1, Limit: 10, SortOrder: "asc"})
+			if err != nil {
+				t.Fatalf("expected no error from the list, got %v", err)
+			}
+			if len(list.Data) != 1 || list.Data[0].BookTotal != tt.wantBookTotal {
+				t.Errorf("expected 1 category with %d books, got %+v", tt.wantBookTotal, list.Data)
+			}
+		})
+	}
+}
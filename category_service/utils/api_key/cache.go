@@ -0,0 +1,103 @@
+package api_key_util
+
+import (
+	"category_service/domain/dto"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrInvalidAPIKey wraps the errors of keys auth_service refused, those are
+// cached like valid keys.
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+// entries are dropped once the cache reaches this size, so random keys can
+// not grow it forever
+const maxEntries = 10000
+
+const validateTimeout = 5 * time.Second
+
+type entry struct {
+	currentUser *dto.CurrentUser
+	err         error
+	expiredAt   time.Time
+}
+
+// Cache keeps what auth_service answered for an api key during ttl. a revoked
+// key is accepted until its entry expires.
+type Cache struct {
+	validate func(ctx context.Context, apiKey string) (*dto.CurrentUser, error)
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	entries map[string]entry // by sha256 of the key
+}
+
+func NewCache(validate func(ctx context.Context, apiKey string) (*dto.CurrentUser, error), ttl time.Duration) *Cache {
+	return &Cache{
+		validate: validate,
+		ttl:      ttl,
+		entries:  map[string]entry{},
+	}
+}
+
+// Validate returns the service account of the key. errors other than
+// ErrInvalidAPIKey, e.g. auth_service being unreachable, are not cached.
+func (c *Cache) Validate(apiKey string) (*dto.CurrentUser, error) {
+	sum := sha256.Sum256([]byte(apiKey))
+	cacheKey := hex.EncodeToString(sum[:])
+
+	c.mu.Lock()
+	cached, ok := c.entries[cacheKey]
+	c.mu.Unlock()
+	if ok && time.Now().Before(cached.expiredAt) {
+		return cached.copyResult()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), validateTimeout)
+	defer cancel()
+	currentUser, err := c.validate(ctx, apiKey)
+	if err != nil && !errors.Is(err, ErrInvalidAPIKey) {
+		return nil, err
+	}
+
+	result := entry{
+		currentUser: currentUser,
+		err:         err,
+		expiredAt:   time.Now().Add(c.ttl),
+	}
+
+	c.mu.Lock()
+	if len(c.entries) >= maxEntries {
+		c.dropExpired()
+	}
+	if len(c.entries) >= maxEntries {
+		c.entries = map[string]entry{}
+	}
+	c.entries[cacheKey] = result
+	c.mu.Unlock()
+
+	return result.copyResult()
+}
+
+// dropExpired must be called with mu held.
+func (c *Cache) dropExpired() {
+	timeNow := time.Now()
+	for cacheKey, cached := range c.entries {
+		if !timeNow.Before(cached.expiredAt) {
+			delete(c.entries, cacheKey)
+		}
+	}
+}
+
+// copyResult keeps callers from changing the cached user.
+func (e entry) copyResult() (*dto.CurrentUser, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	currentUser := *e.currentUser
+	return &currentUser, nil
+}
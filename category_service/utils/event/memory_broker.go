@@ -0,0 +1,95 @@
+package event_util
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemoryBroker keeps the events in the process, it only connects publishers
+// and consumers running in the same process and forgets everything on
+// restart. meant for tests and single process runs.
+type MemoryBroker struct {
+	mu            sync.Mutex
+	events        []Event
+	published     map[uuid.UUID]bool
+	offsets       map[string]int
+	wake          chan struct{} // closed on every publish
+	closed        bool
+	retryInterval time.Duration
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		published:     map[uuid.UUID]bool{},
+		offsets:       map[string]int{},
+		wake:          make(chan struct{}),
+		retryInterval: defaultRetryInterval,
+	}
+}
+
+func (b *MemoryBroker) Publish(ctx context.Context, event Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return errors.New("broker closed")
+	}
+	if b.published[event.UUID] {
+		return nil
+	}
+
+	b.published[event.UUID] = true
+	b.events = append(b.events, event)
+	close(b.wake)
+	b.wake = make(chan struct{})
+	return nil
+}
+
+func (b *MemoryBroker) Subscribe(ctx context.Context, consumer string, handler Handler) error {
+	for {
+		b.mu.Lock()
+		offset := b.offsets[consumer]
+		pending := append([]Event{}, b.events[offset:]...)
+		wake := b.wake
+		b.mu.Unlock()
+
+		failed := false
+		for i, event := range pending {
+			err := handler(ctx, event)
+			if err != nil {
+				logger.Errorf("consumer %s failed on event %s: %v", consumer, event.UUID.String(), err)
+				failed = true
+				break
+			}
+
+			b.mu.Lock()
+			b.offsets[consumer] = offset + i + 1
+			b.mu.Unlock()
+		}
+
+		if failed {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(b.retryInterval):
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-wake:
+		}
+	}
+}
+
+func (b *MemoryBroker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	return nil
+}
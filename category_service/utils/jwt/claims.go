@@ -0,0 +1,58 @@
+package jwt_util
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// clockSkew is how far iat and nbf may be in the future, and exp in the past,
+// to tolerate clock drift between auth_service and the other services.
+const clockSkew = time.Second * 30
+
+// ClaimsConfig is the issuer and audience access tokens are issued with and
+// validated against.
+type ClaimsConfig struct {
+	Issuer   string
+	Audience string
+}
+
+// Claims are the access token claims issued by auth_service. every service
+// validates them the same way through Validate.
+type Claims struct {
+	Username  string `json:"username"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid,omitempty"`
+
+	// permissions of the role when the token was issued
+	Permissions []string `json:"perms,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// Validate checks the claims every service relies on, all of them required.
+func (claims *Claims) Validate(claimsConfig ClaimsConfig, now time.Time) error {
+	if claims.Subject == "" {
+		return fmt.Errorf("missing sub")
+	}
+	if claims.Role == "" {
+		return fmt.Errorf("missing role")
+	}
+	if !claims.VerifyIssuer(claimsConfig.Issuer, true) {
+		return fmt.Errorf("invalid issuer")
+	}
+	if !claims.VerifyAudience(claimsConfig.Audience, true) {
+		return fmt.Errorf("invalid audience")
+	}
+	if !claims.VerifyExpiresAt(now.Add(-clockSkew), true) {
+		return fmt.Errorf("token is expired")
+	}
+	if !claims.VerifyIssuedAt(now.Add(clockSkew), true) {
+		return fmt.Errorf("token used before issued")
+	}
+	if !claims.VerifyNotBefore(now.Add(clockSkew), true) {
+		return fmt.Errorf("token is not valid yet")
+	}
+	return nil
+}
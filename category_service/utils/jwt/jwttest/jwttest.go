@@ -0,0 +1,101 @@
+// Package jwttest signs tokens with an ed25519 key of its own, standing in for
+// the auth_service jwks in tests.
+package jwttest
+
+import (
+	jwt_util "category_service/utils/jwt"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+)
+
+const keyID = "test-key"
+
+// ClaimsConfig is the issuer and audience of the tokens signed by Signer.
+var ClaimsConfig = jwt_util.ClaimsConfig{Issuer: "auth_service", Audience: "library_app"}
+
+// Signer signs tokens that KeySet verifies.
+type Signer struct {
+	KeySet     *jwt_util.KeySet
+	privateKey ed25519.PrivateKey
+}
+
+func NewSigner(t testing.TB) *Signer {
+	t.Helper()
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	keySet := jwt_util.NewKeySet(func(ctx context.Context) ([]jwt_util.JWK, error) {
+		return []jwt_util.JWK{{
+			Kty: "OKP",
+			Kid: keyID,
+			Alg: "EdDSA",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(publicKey),
+		}}, nil
+	}, time.Minute)
+	return &Signer{KeySet: keySet, privateKey: privateKey}
+}
+
+func (s *Signer) Sign(t testing.TB, claims jwt.Claims) string {
+	t.Helper()
+	signed, err := s.sign(claims)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return signed
+}
+
+// ServiceToken issues a token of the service, to be used as the issue func of
+// a service token source.
+func (s *Signer) ServiceToken(service string) func(ctx context.Context) (string, time.Time, error) {
+	return func(ctx context.Context) (string, time.Time, error) {
+		claims := ServiceClaims(service)
+		signed, err := s.sign(claims)
+		return signed, claims.ExpiresAt.Time, err
+	}
+}
+
+func (s *Signer) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = keyID
+	return token.SignedString(s.privateKey)
+}
+
+// UserClaims are the claims of an access token valid for an hour.
+func UserClaims(role string, permissions ...string) jwt_util.Claims {
+	return jwt_util.Claims{
+		Username:         "test",
+		Email:            "test@gmail.com",
+		Role:             role,
+		Permissions:      permissions,
+		RegisteredClaims: registeredClaims(uuid.New().String(), ClaimsConfig.Audience),
+	}
+}
+
+// ServiceClaims are the claims of a service token valid for an hour.
+func ServiceClaims(service string) *jwt_util.ServiceClaims {
+	return &jwt_util.ServiceClaims{
+		RegisteredClaims: registeredClaims(service, jwt_util.ServiceAudience),
+	}
+}
+
+func registeredClaims(subject string, audience string) jwt.RegisteredClaims {
+	timeNow := time.Now()
+	return jwt.RegisteredClaims{
+		Subject:   subject,
+		Issuer:    ClaimsConfig.Issuer,
+		Audience:  jwt.ClaimStrings{audience},
+		IssuedAt:  jwt.NewNumericDate(timeNow),
+		NotBefore: jwt.NewNumericDate(timeNow),
+		ExpiresAt: jwt.NewNumericDate(timeNow.Add(time.Hour)),
+	}
+}